@@ -0,0 +1,205 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
+	libio "gitea.xscloud.ru/xscloud/golib/pkg/common/io"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/outbox"
+	"github.com/urfave/cli/v2"
+
+	appmodel "inventory/pkg/inventory/app/model"
+	appservice "inventory/pkg/inventory/app/service"
+	domainservice "inventory/pkg/inventory/domain/service"
+	"inventory/pkg/inventory/infrastructure/catalogfile"
+	"inventory/pkg/inventory/infrastructure/integrationevent"
+	inframysql "inventory/pkg/inventory/infrastructure/mysql"
+	queryservice "inventory/pkg/inventory/infrastructure/mysql/query"
+)
+
+type catalogConfig struct {
+	Service  Service  `envconfig:"service"`
+	Database Database `envconfig:"database" required:"true"`
+}
+
+var catalogFileFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "file",
+		Usage: "path to catalog file, stdin or stdout when omitted",
+	},
+	&cli.StringFlag{
+		Name:  "format",
+		Usage: "csv or jsonl, detected by file extension when omitted",
+	},
+}
+
+func importCatalog(logger logging.Logger) *cli.Command {
+	return &cli.Command{
+		Name:   "import",
+		Usage:  "create or update products by SKU from CSV or JSON Lines file",
+		Before: migrateImpl(logger),
+		Flags: append([]cli.Flag{
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "validate file against catalog without storing anything",
+			},
+			&cli.IntFlag{
+				Name:  "batch-size",
+				Usage: "rows stored in one transaction",
+				Value: 100,
+			},
+		}, catalogFileFlags...),
+		Action: importCatalogImpl(logger),
+	}
+}
+
+func importCatalogImpl(logger logging.Logger) func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		cnf, err := parseEnvs[catalogConfig]()
+		if err != nil {
+			return err
+		}
+
+		closer := libio.NewMultiCloser()
+		defer func() {
+			err = errors.Join(err, closer.Close())
+		}()
+
+		format, err := catalogFileFormat(c)
+		if err != nil {
+			return err
+		}
+		var file io.Reader = os.Stdin
+		if path := c.String("file"); path != "" {
+			f, openErr := os.Open(path)
+			if openErr != nil {
+				return openErr
+			}
+			closer.AddCloser(f)
+			file = f
+		}
+		source, err := catalogfile.NewReader(file, format)
+		if err != nil {
+			return err
+		}
+
+		databaseConnector, err := newDatabaseConnector(cnf.Database)
+		if err != nil {
+			return err
+		}
+		closer.AddCloser(databaseConnector)
+		databaseConnectionPool := mysql.NewConnectionPool(databaseConnector.TransactionalClient())
+
+		libUoW := mysql.NewUnitOfWork(databaseConnectionPool, inframysql.NewRepositoryProvider)
+		libLUow := mysql.NewLockableUnitOfWork(libUoW, mysql.NewLocker(databaseConnectionPool))
+		eventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, integrationevent.NewEventSerializer(), libUoW)
+
+		allocationStrategy, err := domainservice.NewAllocationStrategy(cnf.Service.AllocationStrategy)
+		if err != nil {
+			return err
+		}
+		productService := appservice.NewProductService(
+			inframysql.NewUnitOfWork(libUoW),
+			inframysql.NewLockableUnitOfWork(libLUow),
+			eventDispatcher,
+			allocationStrategy,
+		)
+
+		report, err := productService.ImportProducts(c.Context, source, appmodel.ImportOptions{
+			DryRun:    c.Bool("dry-run"),
+			BatchSize: c.Int("batch-size"),
+		})
+		for _, rowErr := range report.Errors {
+			fmt.Fprintf(c.App.Writer, "row %d (sku %q): %s\n", rowErr.Row, rowErr.SKU, rowErr.Message)
+		}
+		logger.WithFields(logging.Fields{
+			"dry_run": c.Bool("dry-run"),
+			"created": report.Created,
+			"updated": report.Updated,
+			"failed":  len(report.Errors),
+		}).Info("catalog import finished")
+		return err
+	}
+}
+
+// exportCatalog does not run migrations, so nothing but catalog is written to stdout
+func exportCatalog() *cli.Command {
+	return &cli.Command{
+		Name:   "export",
+		Usage:  "dump catalog with stock in import file format",
+		Flags:  catalogFileFlags,
+		Action: exportCatalogImpl,
+	}
+}
+
+func exportCatalogImpl(c *cli.Context) error {
+	cnf, err := parseEnvs[catalogConfig]()
+	if err != nil {
+		return err
+	}
+
+	closer := libio.NewMultiCloser()
+	defer func() {
+		err = errors.Join(err, closer.Close())
+	}()
+
+	format, err := catalogFileFormat(c)
+	if err != nil {
+		return err
+	}
+	var file io.Writer = c.App.Writer
+	if path := c.String("file"); path != "" {
+		f, createErr := os.Create(path)
+		if createErr != nil {
+			return createErr
+		}
+		closer.AddCloser(f)
+		file = f
+	}
+	writer, err := catalogfile.NewWriter(file, format)
+	if err != nil {
+		return err
+	}
+
+	databaseConnector, err := newDatabaseConnector(cnf.Database)
+	if err != nil {
+		return err
+	}
+	closer.AddCloser(databaseConnector)
+	productQueryService := queryservice.NewProductQueryService(databaseConnector.TransactionalClient())
+
+	// parents go first so exported file can be imported back
+	for _, variants := range []bool{false, true} {
+		spec := appmodel.ExportCatalogSpec{Variants: variants}
+		for {
+			page, pageErr := productQueryService.ExportCatalog(c.Context, spec)
+			if pageErr != nil {
+				return pageErr
+			}
+			for _, row := range page.Rows {
+				if err = writer.Write(row); err != nil {
+					return err
+				}
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			spec.Cursor = page.NextCursor
+		}
+	}
+	return writer.Flush()
+}
+
+func catalogFileFormat(c *cli.Context) (catalogfile.Format, error) {
+	if format := c.String("format"); format != "" {
+		return catalogfile.ParseFormat(format)
+	}
+	if path := c.String("file"); path != "" {
+		return catalogfile.FormatFromPath(path)
+	}
+	return "", errors.New("format is required when file is not set")
+}
@@ -18,6 +18,18 @@ type Service struct {
 
 	GRPCAddress string `envconfig:"grpc_address" default:":8081"`
 	HTTPAddress string `envconfig:"http_address" default:":8082"`
+
+	// AllocationStrategy is one of priority, single_location, largest_stock
+	AllocationStrategy string `envconfig:"allocation_strategy" default:"priority"`
+}
+
+type PriceScheduler struct {
+	Interval time.Duration `envconfig:"interval" default:"1m"`
+}
+
+type PurgeProducts struct {
+	// Retention is how long soft deleted products are kept before purge
+	Retention time.Duration `envconfig:"retention" default:"720h"`
 }
 
 type Database struct {
@@ -36,3 +48,9 @@ type AMQP struct {
 	Host           string        `envconfig:"host" required:"true"`
 	ConnectTimeout time.Duration `envconfig:"connect_timeout"`
 }
+
+type Auth struct {
+	// PublicKeys are base64 encoded ed25519 public keys of user service by key ID in "id1:key1,id2:key2" format,
+	// keys of rotated out signing keys are kept until tokens signed by them expire
+	PublicKeys map[string]string `envconfig:"public_keys" required:"true"`
+}
@@ -26,6 +26,10 @@ func main() {
 			migrate(logger),
 			messageHandler(logger),
 			service(logger),
+			priceScheduler(logger),
+			purgeProducts(logger),
+			importCatalog(logger),
+			exportCatalog(),
 		},
 	}
 
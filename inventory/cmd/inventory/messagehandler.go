@@ -14,7 +14,10 @@ import (
 	"github.com/urfave/cli/v2"
 	"golang.org/x/sync/errgroup"
 
+	appservice "inventory/pkg/inventory/app/service"
+	domainservice "inventory/pkg/inventory/domain/service"
 	"inventory/pkg/inventory/infrastructure/integrationevent"
+	inframysql "inventory/pkg/inventory/infrastructure/mysql"
 )
 
 type messageHandlerConfig struct {
@@ -45,6 +48,21 @@ func messageHandler(logger logging.Logger) *cli.Command {
 			closer.AddCloser(databaseConnector)
 			databaseConnectionPool := mysql.NewConnectionPool(databaseConnector.TransactionalClient())
 
+			libUoW := mysql.NewUnitOfWork(databaseConnectionPool, inframysql.NewRepositoryProvider)
+			libLUow := mysql.NewLockableUnitOfWork(libUoW, mysql.NewLocker(databaseConnectionPool))
+			eventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, integrationevent.NewEventSerializer(), libUoW)
+
+			allocationStrategy, err := domainservice.NewAllocationStrategy(cnf.Service.AllocationStrategy)
+			if err != nil {
+				return err
+			}
+			productService := appservice.NewProductService(
+				inframysql.NewUnitOfWork(libUoW),
+				inframysql.NewLockableUnitOfWork(libLUow),
+				eventDispatcher,
+				allocationStrategy,
+			)
+
 			amqpConnection := newAMQPConnection(cnf.AMQP, logger)
 			queueConfig := &amqp.QueueConfig{
 				Name:    integrationevent.QueueName,
@@ -53,7 +71,7 @@ func messageHandler(logger logging.Logger) *cli.Command {
 			bindConfig := &amqp.BindConfig{
 				QueueName:    integrationevent.QueueName,
 				ExchangeName: integrationevent.ExchangeName,
-				RoutingKeys:  []string{integrationevent.RoutingKeyPrefix + "#"},
+				RoutingKeys:  append([]string{integrationevent.RoutingKeyPrefix + "#"}, integrationevent.OrderRoutingKeys...),
 			}
 			amqpEventProducer := amqpConnection.Producer(
 				&amqp.ExchangeConfig{
@@ -64,7 +82,7 @@ func messageHandler(logger logging.Logger) *cli.Command {
 				queueConfig,
 				bindConfig,
 			)
-			amqpTransport := integrationevent.NewAMQPTransport(logger)
+			amqpTransport := integrationevent.NewAMQPTransport(logger, productService)
 			amqpConnection.Consumer(
 				c.Context,
 				amqpTransport.Handler(),
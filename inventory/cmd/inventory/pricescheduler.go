@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
+	libio "gitea.xscloud.ru/xscloud/golib/pkg/common/io"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/outbox"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/sync/errgroup"
+
+	appservice "inventory/pkg/inventory/app/service"
+	domainservice "inventory/pkg/inventory/domain/service"
+	"inventory/pkg/inventory/infrastructure/integrationevent"
+	inframysql "inventory/pkg/inventory/infrastructure/mysql"
+)
+
+type priceSchedulerConfig struct {
+	Service        Service        `envconfig:"service"`
+	Database       Database       `envconfig:"database" required:"true"`
+	PriceScheduler PriceScheduler `envconfig:"price_scheduler"`
+}
+
+// priceScheduler activates scheduled prices and ends sales, ProductPriceChanged is published by message-handler via outbox
+func priceScheduler(logger logging.Logger) *cli.Command {
+	return &cli.Command{
+		Name:   "price-scheduler",
+		Before: migrateImpl(logger),
+		Action: func(c *cli.Context) error {
+			cnf, err := parseEnvs[priceSchedulerConfig]()
+			if err != nil {
+				return err
+			}
+
+			closer := libio.NewMultiCloser()
+			defer func() {
+				err = errors.Join(err, closer.Close())
+			}()
+
+			databaseConnector, err := newDatabaseConnector(cnf.Database)
+			if err != nil {
+				return err
+			}
+			closer.AddCloser(databaseConnector)
+			databaseConnectionPool := mysql.NewConnectionPool(databaseConnector.TransactionalClient())
+
+			libUoW := mysql.NewUnitOfWork(databaseConnectionPool, inframysql.NewRepositoryProvider)
+			libLUow := mysql.NewLockableUnitOfWork(libUoW, mysql.NewLocker(databaseConnectionPool))
+			eventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, integrationevent.NewEventSerializer(), libUoW)
+
+			allocationStrategy, err := domainservice.NewAllocationStrategy(cnf.Service.AllocationStrategy)
+			if err != nil {
+				return err
+			}
+			productService := appservice.NewProductService(
+				inframysql.NewUnitOfWork(libUoW),
+				inframysql.NewLockableUnitOfWork(libLUow),
+				eventDispatcher,
+				allocationStrategy,
+			)
+
+			errGroup := errgroup.Group{}
+			errGroup.Go(func() error {
+				runPriceScheduler(c.Context, logger, productService, cnf.PriceScheduler.Interval)
+				return nil
+			})
+			errGroup.Go(func() error {
+				router := mux.NewRouter()
+				registerHealthcheck(router)
+				router.Handle("/metrics", promhttp.Handler())
+				// nolint:gosec
+				server := http.Server{
+					Addr:    cnf.Service.HTTPAddress,
+					Handler: router,
+				}
+				graceCallback(c.Context, logger, cnf.Service.GracePeriod, server.Shutdown)
+				return server.ListenAndServe()
+			})
+
+			return errGroup.Wait()
+		},
+	}
+}
+
+func runPriceScheduler(ctx context.Context, logger logging.Logger, productService appservice.ProductService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := productService.ApplyDuePrices(ctx, time.Now())
+		if err != nil {
+			logger.Error(err, "failed to apply scheduled prices")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
+	libio "gitea.xscloud.ru/xscloud/golib/pkg/common/io"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/outbox"
+	"github.com/urfave/cli/v2"
+
+	appservice "inventory/pkg/inventory/app/service"
+	domainservice "inventory/pkg/inventory/domain/service"
+	"inventory/pkg/inventory/infrastructure/integrationevent"
+	inframysql "inventory/pkg/inventory/infrastructure/mysql"
+)
+
+type purgeProductsConfig struct {
+	Service       Service       `envconfig:"service"`
+	Database      Database      `envconfig:"database" required:"true"`
+	PurgeProducts PurgeProducts `envconfig:"purge_products"`
+}
+
+// purgeProducts removes products soft deleted longer than retention period ago, meant to be run by cron,
+// ProductPurged is published by message-handler via outbox
+func purgeProducts(logger logging.Logger) *cli.Command {
+	return &cli.Command{
+		Name:   "purge-products",
+		Before: migrateImpl(logger),
+		Action: func(c *cli.Context) error {
+			cnf, err := parseEnvs[purgeProductsConfig]()
+			if err != nil {
+				return err
+			}
+
+			closer := libio.NewMultiCloser()
+			defer func() {
+				err = errors.Join(err, closer.Close())
+			}()
+
+			databaseConnector, err := newDatabaseConnector(cnf.Database)
+			if err != nil {
+				return err
+			}
+			closer.AddCloser(databaseConnector)
+			databaseConnectionPool := mysql.NewConnectionPool(databaseConnector.TransactionalClient())
+
+			libUoW := mysql.NewUnitOfWork(databaseConnectionPool, inframysql.NewRepositoryProvider)
+			libLUow := mysql.NewLockableUnitOfWork(libUoW, mysql.NewLocker(databaseConnectionPool))
+			eventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, integrationevent.NewEventSerializer(), libUoW)
+
+			allocationStrategy, err := domainservice.NewAllocationStrategy(cnf.Service.AllocationStrategy)
+			if err != nil {
+				return err
+			}
+			productService := appservice.NewProductService(
+				inframysql.NewUnitOfWork(libUoW),
+				inframysql.NewLockableUnitOfWork(libLUow),
+				eventDispatcher,
+				allocationStrategy,
+			)
+
+			deletedBefore := time.Now().Add(-cnf.PurgeProducts.Retention)
+			purged, err := productService.PurgeDeletedProducts(c.Context, deletedBefore)
+			logger.WithFields(logging.Fields{
+				"deleted_before": deletedBefore,
+				"purged":         purged,
+			}).Info("deleted products purged")
+			return err
+		},
+	}
+}
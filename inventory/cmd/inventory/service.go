@@ -18,6 +18,7 @@ import (
 
 	"inventory/api/server/inventorypublicapi"
 	appservice "inventory/pkg/inventory/app/service"
+	domainservice "inventory/pkg/inventory/domain/service"
 	"inventory/pkg/inventory/infrastructure/integrationevent"
 	inframysql "inventory/pkg/inventory/infrastructure/mysql"
 	queryservice "inventory/pkg/inventory/infrastructure/mysql/query"
@@ -25,9 +26,34 @@ import (
 	"inventory/pkg/inventory/infrastructure/transport/middlewares"
 )
 
+// methodPermissions are permissions required in access token
+var methodPermissions = map[string]string{
+	"/Inventory.InventoryPublicAPI/StoreProduct":        "inventory.catalog.write",
+	"/Inventory.InventoryPublicAPI/FindProduct":         "inventory.catalog.read",
+	"/Inventory.InventoryPublicAPI/ListProducts":        "inventory.catalog.read",
+	"/Inventory.InventoryPublicAPI/DeleteProduct":       "inventory.catalog.write",
+	"/Inventory.InventoryPublicAPI/RestoreProduct":      "inventory.catalog.write",
+	"/Inventory.InventoryPublicAPI/CreateBundle":        "inventory.catalog.write",
+	"/Inventory.InventoryPublicAPI/ImportProducts":      "inventory.catalog.write",
+	"/Inventory.InventoryPublicAPI/IncreaseStock":       "inventory.stock.write",
+	"/Inventory.InventoryPublicAPI/DecreaseStock":       "inventory.stock.write",
+	"/Inventory.InventoryPublicAPI/ListStockMovements":  "inventory.stock.read",
+	"/Inventory.InventoryPublicAPI/TransferStock":       "inventory.stock.write",
+	"/Inventory.InventoryPublicAPI/AllocateStock":       "inventory.stock.write",
+	"/Inventory.InventoryPublicAPI/StoreWarehouse":      "inventory.stock.write",
+	"/Inventory.InventoryPublicAPI/ListWarehouses":      "inventory.stock.read",
+	"/Inventory.InventoryPublicAPI/FindProductBySKU":    "inventory.catalog.read",
+	"/Inventory.InventoryPublicAPI/StoreCategory":       "inventory.catalog.write",
+	"/Inventory.InventoryPublicAPI/ListCategories":      "inventory.catalog.read",
+	"/Inventory.InventoryPublicAPI/SchedulePriceChange": "inventory.catalog.write",
+	"/Inventory.InventoryPublicAPI/GetPriceAt":          "inventory.catalog.read",
+	"/Inventory.InventoryPublicAPI/ListPriceHistory":    "inventory.catalog.read",
+}
+
 type serviceConfig struct {
 	Service  Service  `envconfig:"service"`
 	Database Database `envconfig:"database" required:"true"`
+	Auth     Auth     `envconfig:"auth" required:"true"`
 }
 
 func service(logger logging.Logger) *cli.Command {
@@ -45,6 +71,11 @@ func service(logger logging.Logger) *cli.Command {
 				err = errors.Join(err, closer.Close())
 			}()
 
+			publicKeys, err := middlewares.ParsePublicKeys(cnf.Auth.PublicKeys)
+			if err != nil {
+				return err
+			}
+
 			databaseConnector, err := newDatabaseConnector(cnf.Database)
 			if err != nil {
 				return err
@@ -58,9 +89,20 @@ func service(logger logging.Logger) *cli.Command {
 			luow := inframysql.NewLockableUnitOfWork(libLUow)
 			eventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, integrationevent.NewEventSerializer(), libUoW)
 
+			allocationStrategy, err := domainservice.NewAllocationStrategy(cnf.Service.AllocationStrategy)
+			if err != nil {
+				return err
+			}
+
 			inventoryPublicAPIServer := transport.NewInventoryInternalAPI(
 				queryservice.NewProductQueryService(databaseConnector.TransactionalClient()),
-				appservice.NewProductService(uow, luow, eventDispatcher),
+				queryservice.NewStockMovementQueryService(databaseConnector.TransactionalClient()),
+				queryservice.NewWarehouseQueryService(databaseConnector.TransactionalClient()),
+				queryservice.NewCategoryQueryService(databaseConnector.TransactionalClient()),
+				queryservice.NewPriceQueryService(databaseConnector.TransactionalClient()),
+				appservice.NewProductService(uow, luow, eventDispatcher, allocationStrategy),
+				appservice.NewWarehouseService(uow, eventDispatcher),
+				appservice.NewCategoryService(luow, eventDispatcher),
 			)
 
 			errGroup := errgroup.Group{}
@@ -72,6 +114,11 @@ func service(logger logging.Logger) *cli.Command {
 				grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
 					middlewares.NewGRPCLoggingMiddleware(logger),
 					middlewares.NewGRPCMetricsMiddleware(),
+					middlewares.NewGRPCAuthMiddleware(publicKeys),
+					middlewares.NewGRPCPermissionMiddleware(methodPermissions),
+				), grpc.ChainStreamInterceptor(
+					middlewares.NewGRPCAuthStreamMiddleware(publicKeys),
+					middlewares.NewGRPCPermissionStreamMiddleware(methodPermissions),
 				))
 				inventorypublicapi.RegisterInventoryPublicAPIServer(grpcServer, inventoryPublicAPIServer)
 				graceCallback(c.Context, logger, cnf.Service.GracePeriod, func(_ context.Context) error {
@@ -0,0 +1,18 @@
+package model
+
+import "github.com/google/uuid"
+
+type Bundle struct {
+	SKU        string
+	CategoryID uuid.UUID
+	Attributes []Attribute
+	Name       string
+	Price      float64
+	Components []BundleComponent
+}
+
+// BundleComponent is a product included into bundle, Quantity is per one bundle
+type BundleComponent struct {
+	ProductID uuid.UUID
+	Quantity  int
+}
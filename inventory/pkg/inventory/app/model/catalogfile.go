@@ -0,0 +1,53 @@
+package model
+
+import "github.com/google/uuid"
+
+// CatalogRow is a product line of catalog import and export files, products are matched by SKU
+type CatalogRow struct {
+	SKU string
+	// ParentSKU is set for variants, parent must go before its variants
+	ParentSKU  string
+	CategoryID uuid.UUID
+	Name       string
+	Price      float64
+	// Quantity is total stock, import applies it only to new products
+	Quantity         int
+	ReorderThreshold int
+	Attributes       []Attribute
+}
+
+type ImportOptions struct {
+	// DryRun validates and applies rows in a transaction that is rolled back
+	DryRun bool
+	// BatchSize is number of rows stored in one unit of work
+	BatchSize int
+}
+
+type ImportReport struct {
+	// Created and Updated are product counts, in dry run they show what import would do
+	Created int
+	Updated int
+	Errors  []ImportRowError
+	// Warnings are reported for stored rows with values import did not apply
+	Warnings []ImportRowError
+}
+
+type ImportRowError struct {
+	// Row is 1-based number of data row in import file
+	Row     int
+	SKU     string
+	Message string
+}
+
+type ExportCatalogSpec struct {
+	// Variants selects variants instead of parent products, so exporting parents first keeps file importable
+	Variants bool
+	// Cursor is an opaque value from CatalogPage.NextCursor of the previous page
+	Cursor string
+	Limit  int
+}
+
+type CatalogPage struct {
+	Rows       []CatalogRow
+	NextCursor string
+}
@@ -1,12 +1,84 @@
 package model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
 type Product struct {
+	ID         uuid.UUID
+	SKU        string
+	ParentID   uuid.UUID
+	CategoryID uuid.UUID
+	Attributes []Attribute
+	Name       string
+	Price      float64
+	// Quantity is total quantity over all warehouses
+	Quantity         int
+	ReorderThreshold int
+	Stock            []WarehouseStock
+	// Variants are filled only for parent products
+	Variants []Product
+	// Components are filled only for bundles, bundle quantity is computed from their stock
+	Components []BundleComponent
+	// DeletedAt is set for soft deleted products
+	DeletedAt *time.Time
+}
+
+type Attribute struct {
+	Name string
+	// Type is one of "string", "number" or "boolean"
+	Type  string
+	Value string
+}
+
+type Category struct {
 	ID       uuid.UUID
+	ParentID uuid.UUID
 	Name     string
-	Price    float64
-	Quantity int
+}
+
+type WarehouseStock struct {
+	WarehouseID   uuid.UUID
+	WarehouseName string
+	Quantity      int
+}
+
+type ListProductsSpec struct {
+	SKU       string
+	NameQuery string
+	// CategoryID matches products of category and all its subcategories
+	CategoryID  uuid.UUID
+	MinPrice    *float64
+	MaxPrice    *float64
+	InStockOnly bool
+	// IncludeDeleted also lists soft deleted products
+	IncludeDeleted bool
+	// Cursor is an opaque value from ProductList.NextCursor of the previous page
+	Cursor string
+	Limit  int
+}
+
+type ProductList struct {
+	Products   []Product
+	NextCursor string
+}
+
+type PriceChange struct {
+	ProductID     uuid.UUID
+	Price         float64
+	EffectiveFrom time.Time
+	// EffectiveTo is set for time-boxed sale price
+	EffectiveTo *time.Time
+}
+
+type ProductPrice struct {
+	ID            uuid.UUID
+	Kind          string
+	Price         float64
+	EffectiveFrom time.Time
+	EffectiveTo   *time.Time
+	// Active is false for scheduled prices not yet in effect
+	Active bool
 }
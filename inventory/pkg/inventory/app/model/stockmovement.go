@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StockMovement struct {
+	ID            uuid.UUID
+	ProductID     uuid.UUID
+	WarehouseID   uuid.UUID
+	Delta         int
+	QuantityAfter int
+	Reason        string
+	ReferenceID   uuid.UUID
+	Actor         string
+	CreatedAt     time.Time
+}
+
+type StockMovementList struct {
+	Movements  []StockMovement
+	NextCursor string
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+type Warehouse struct {
+	ID        uuid.UUID
+	Name      string
+	Priority  int
+	IsDefault bool
+}
+
+type StockTransfer struct {
+	ProductID       uuid.UUID
+	FromWarehouseID uuid.UUID
+	ToWarehouseID   uuid.UUID
+	Quantity        int
+	Actor           string
+}
+
+type Allocation struct {
+	ProductID   uuid.UUID
+	WarehouseID uuid.UUID
+	Quantity    int
+}
@@ -0,0 +1,11 @@
+package query
+
+import (
+	"context"
+
+	"inventory/pkg/inventory/app/model"
+)
+
+type CategoryQueryService interface {
+	ListCategories(ctx context.Context) ([]model.Category, error)
+}
@@ -0,0 +1,19 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"inventory/pkg/inventory/app/model"
+)
+
+var ErrPriceNotFound = errors.New("price not found")
+
+type PriceQueryService interface {
+	// FindPriceAt returns price customer paid at the moment, sale price overrides regular one
+	FindPriceAt(ctx context.Context, productID uuid.UUID, at time.Time) (float64, error)
+	ListPriceHistory(ctx context.Context, productID uuid.UUID) ([]model.ProductPrice, error)
+}
@@ -2,13 +2,20 @@ package query
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 
 	"inventory/pkg/inventory/app/model"
 )
 
+var ErrInvalidCursor = errors.New("invalid cursor")
+
 type ProductQueryService interface {
-	ListProducts(ctx context.Context) ([]model.Product, error)
-	FindProduct(ctx context.Context, id uuid.UUID) (*model.Product, error)
+	ListProducts(ctx context.Context, spec model.ListProductsSpec) (model.ProductList, error)
+	// FindProduct finds product, soft deleted product is found only with includeDeleted
+	FindProduct(ctx context.Context, id uuid.UUID, includeDeleted bool) (*model.Product, error)
+	FindProductBySKU(ctx context.Context, sku string) (*model.Product, error)
+	// ExportCatalog pages through not deleted products in catalog file form
+	ExportCatalog(ctx context.Context, spec model.ExportCatalogSpec) (model.CatalogPage, error)
 }
@@ -0,0 +1,14 @@
+package query
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"inventory/pkg/inventory/app/model"
+)
+
+type StockMovementQueryService interface {
+	// ListStockMovements returns product movements from the newest to the oldest
+	ListStockMovements(ctx context.Context, productID uuid.UUID, cursor string, limit int) (model.StockMovementList, error)
+}
@@ -0,0 +1,11 @@
+package query
+
+import (
+	"context"
+
+	"inventory/pkg/inventory/app/model"
+)
+
+type WarehouseQueryService interface {
+	ListWarehouses(ctx context.Context) ([]model.Warehouse, error)
+}
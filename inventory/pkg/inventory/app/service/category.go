@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+
+	"inventory/pkg/common/domain"
+	appmodel "inventory/pkg/inventory/app/model"
+	"inventory/pkg/inventory/domain/service"
+)
+
+type CategoryService interface {
+	StoreCategory(ctx context.Context, category appmodel.Category) (uuid.UUID, error)
+}
+
+func NewCategoryService(
+	luow LockableUnitOfWork,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+) CategoryService {
+	return &categoryService{
+		luow:            luow,
+		eventDispatcher: eventDispatcher,
+	}
+}
+
+type categoryService struct {
+	luow            LockableUnitOfWork
+	eventDispatcher outbox.EventDispatcher[outbox.Event]
+}
+
+// categoryTreeLock serializes category moves so concurrent updates can not create cycle
+const categoryTreeLock = "category_tree"
+
+func (c categoryService) StoreCategory(ctx context.Context, category appmodel.Category) (uuid.UUID, error) {
+	categoryID := category.ID
+	err := c.luow.Execute(ctx, []string{categoryTreeLock}, func(provider RepositoryProvider) error {
+		domainService := service.NewCategoryService(provider.CategoryRepository(ctx), c.domainEventDispatcher(ctx))
+
+		if category.ID == uuid.Nil {
+			id, err := domainService.CreateCategory(category.Name, category.ParentID)
+			if err != nil {
+				return err
+			}
+			categoryID = id
+			return nil
+		}
+
+		return domainService.UpdateCategory(category.ID, category.Name, category.ParentID)
+	})
+	return categoryID, err
+}
+
+func (c categoryService) domainEventDispatcher(ctx context.Context) domain.EventDispatcher {
+	return &domainEventDispatcher{
+		ctx:             ctx,
+		eventDispatcher: c.eventDispatcher,
+	}
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/google/uuid"
+
+	appmodel "inventory/pkg/inventory/app/model"
+	"inventory/pkg/inventory/domain/model"
+)
+
+// ErrInvalidImportRow is wrapped by errors of rows that can not be parsed or validated,
+// such rows are reported and import goes on
+var ErrInvalidImportRow = errors.New("invalid import row")
+
+var errDryRunRollback = errors.New("dry run rollback")
+
+const defaultImportBatchSize = 100
+
+// CatalogRowSource yields rows of import file one by one and returns io.EOF after the last row
+type CatalogRowSource interface {
+	Next() (appmodel.CatalogRow, error)
+}
+
+type importRow struct {
+	number int
+	row    appmodel.CatalogRow
+}
+
+type importResult struct {
+	created bool
+	// quantityIgnored is set when row quantity differs from stock of existing product
+	quantityIgnored bool
+}
+
+// ImportProducts upserts products by SKU in batches, batch with failed rows is stored row by row
+func (p productService) ImportProducts(ctx context.Context, source CatalogRowSource, options appmodel.ImportOptions) (appmodel.ImportReport, error) {
+	batchSize := options.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultImportBatchSize
+	}
+
+	var report appmodel.ImportReport
+	var batch []importRow
+	for number := 1; ; number++ {
+		row, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err == nil {
+			err = validateCatalogRow(row)
+		}
+		if errors.Is(err, ErrInvalidImportRow) {
+			report.Errors = append(report.Errors, newImportRowError(number, row, err))
+			continue
+		}
+		if err != nil {
+			return report, err
+		}
+
+		batch = append(batch, importRow{number: number, row: row})
+		// dry run checks all rows in one transaction, so variants see parents from earlier rows
+		if !options.DryRun && len(batch) == batchSize {
+			err = p.importBatch(ctx, batch, &report)
+			if err != nil {
+				return report, err
+			}
+			batch = nil
+		}
+	}
+
+	if options.DryRun {
+		return report, p.dryRunImport(ctx, batch, &report)
+	}
+	return report, p.importBatch(ctx, batch, &report)
+}
+
+func (p productService) importBatch(ctx context.Context, batch []importRow, report *appmodel.ImportReport) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	var batchReport appmodel.ImportReport
+	err := p.luow.Execute(ctx, importLockNames(batch), func(provider RepositoryProvider) error {
+		batchReport = appmodel.ImportReport{}
+		for _, r := range batch {
+			result, err := p.importRow(ctx, provider, r.row)
+			if err != nil {
+				return err
+			}
+			addImportedRow(&batchReport, r, result)
+		}
+		return nil
+	})
+	if err == nil {
+		report.Created += batchReport.Created
+		report.Updated += batchReport.Updated
+		report.Warnings = append(report.Warnings, batchReport.Warnings...)
+		return nil
+	}
+	if !isImportRowFailure(err) {
+		return err
+	}
+
+	// some row is invalid, store rows one by one to find it and keep the rest
+	for _, r := range batch {
+		var result importResult
+		err = p.luow.Execute(ctx, importLockNames([]importRow{r}), func(provider RepositoryProvider) error {
+			var err error
+			result, err = p.importRow(ctx, provider, r.row)
+			return err
+		})
+		switch {
+		case err == nil:
+			addImportedRow(report, r, result)
+		case isImportRowFailure(err):
+			report.Errors = append(report.Errors, newImportRowError(r.number, r.row, err))
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+func (p productService) dryRunImport(ctx context.Context, rows []importRow, report *appmodel.ImportReport) error {
+	err := p.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		for _, r := range rows {
+			result, err := p.importRow(ctx, provider, r.row)
+			switch {
+			case err == nil:
+				addImportedRow(report, r, result)
+			case isImportRowFailure(err):
+				report.Errors = append(report.Errors, newImportRowError(r.number, r.row, err))
+			default:
+				return err
+			}
+		}
+		return errDryRunRollback
+	})
+	if errors.Is(err, errDryRunRollback) {
+		return nil
+	}
+	return err
+}
+
+// importRow stores product of the row, quantity is applied to new products only,
+// stock of existing products is changed by stock movements with a reason
+func (p productService) importRow(ctx context.Context, provider RepositoryProvider, row appmodel.CatalogRow) (importResult, error) {
+	product := appmodel.Product{
+		SKU:              row.SKU,
+		CategoryID:       row.CategoryID,
+		Attributes:       row.Attributes,
+		Name:             row.Name,
+		Price:            row.Price,
+		Quantity:         row.Quantity,
+		ReorderThreshold: row.ReorderThreshold,
+	}
+	if row.ParentSKU != "" {
+		parent, err := provider.ProductRepository(ctx).FindBySKU(row.ParentSKU)
+		if err != nil {
+			return importResult{}, fmt.Errorf("parent %q: %w", row.ParentSKU, err)
+		}
+		product.ParentID = parent.ID
+		if product.Name == "" {
+			product.Name = parent.Name
+		}
+		if product.CategoryID == uuid.Nil {
+			product.CategoryID = parent.CategoryID
+		}
+	}
+
+	existing, err := provider.ProductRepository(ctx).FindBySKU(row.SKU)
+	if err != nil && !errors.Is(err, model.ErrProductNotFound) {
+		return importResult{}, err
+	}
+	if existing != nil {
+		product.ID = existing.ID
+	}
+
+	_, created, err := p.storeProduct(ctx, provider, product)
+	return importResult{
+		created:         created,
+		quantityIgnored: existing != nil && existing.Quantity != row.Quantity,
+	}, err
+}
+
+func addImportedRow(report *appmodel.ImportReport, r importRow, result importResult) {
+	if result.created {
+		report.Created++
+		return
+	}
+	report.Updated++
+	if result.quantityIgnored {
+		report.Warnings = append(report.Warnings, appmodel.ImportRowError{
+			Row:     r.number,
+			SKU:     r.row.SKU,
+			Message: "quantity of existing product is not changed by import, adjust stock instead",
+		})
+	}
+}
+
+func validateCatalogRow(row appmodel.CatalogRow) error {
+	switch {
+	case row.SKU == "":
+		return fmt.Errorf("%w: sku is required", ErrInvalidImportRow)
+	case row.Name == "" && row.ParentSKU == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidImportRow)
+	case row.ParentSKU == row.SKU:
+		return fmt.Errorf("%w: product can not be variant of itself", ErrInvalidImportRow)
+	case row.Price < 0:
+		return fmt.Errorf("%w: price must not be negative", ErrInvalidImportRow)
+	case row.Quantity < 0:
+		return fmt.Errorf("%w: quantity must not be negative", ErrInvalidImportRow)
+	case row.ReorderThreshold < 0:
+		return fmt.Errorf("%w: reorder threshold must not be negative", ErrInvalidImportRow)
+	}
+	return nil
+}
+
+// isImportRowFailure tells errors caused by row content from infrastructure failures
+func isImportRowFailure(err error) bool {
+	for _, rowErr := range []error{
+		ErrInvalidImportRow,
+		model.ErrProductNotFound,
+		model.ErrCategoryNotFound,
+		model.ErrEmptySKU,
+		model.ErrSKUAlreadyExists,
+		model.ErrNestedVariant,
+		model.ErrInvalidAttribute,
+		model.ErrDuplicateAttribute,
+		model.ErrUnknownAttributeType,
+		model.ErrInvalidPrice,
+		model.ErrInvalidReorderThreshold,
+		model.ErrInvalidStockAdjustment,
+	} {
+		if errors.Is(err, rowErr) {
+			return true
+		}
+	}
+	return false
+}
+
+func newImportRowError(number int, row appmodel.CatalogRow, err error) appmodel.ImportRowError {
+	return appmodel.ImportRowError{
+		Row:     number,
+		SKU:     row.SKU,
+		Message: err.Error(),
+	}
+}
+
+// importLockNames locks SKUs of the batch in sorted order to avoid deadlocks between imports
+func importLockNames(batch []importRow) []string {
+	lockNames := make([]string, 0, len(batch))
+	for _, r := range batch {
+		lockNames = append(lockNames, skuLockName(r.row.SKU))
+	}
+	slices.Sort(lockNames)
+	return slices.Compact(lockNames)
+}
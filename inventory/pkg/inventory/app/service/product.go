@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"time"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
 	"github.com/google/uuid"
@@ -14,71 +17,328 @@ import (
 
 type ProductService interface {
 	StoreProduct(ctx context.Context, product appmodel.Product) (uuid.UUID, error)
-	IncreaseQuantity(ctx context.Context, ID uuid.UUID, quantity int) error
-	DecreaseQuantity(ctx context.Context, ID uuid.UUID, quantity int) error
+	ImportProducts(ctx context.Context, source CatalogRowSource, options appmodel.ImportOptions) (appmodel.ImportReport, error)
+	CreateBundle(ctx context.Context, bundle appmodel.Bundle) (uuid.UUID, error)
+	IncreaseQuantity(ctx context.Context, ID, warehouseID uuid.UUID, quantity int, origin model.StockChangeOrigin) error
+	DecreaseQuantity(ctx context.Context, ID, warehouseID uuid.UUID, quantity int, origin model.StockChangeOrigin) error
+	TransferStock(ctx context.Context, transfer appmodel.StockTransfer) (uuid.UUID, error)
+	AllocateStock(ctx context.Context, productID uuid.UUID, quantity int, origin model.StockChangeOrigin) ([]appmodel.Allocation, error)
+	// AllocateOrder takes stock for paid order, order that can not be fulfilled is reported with StockAllocationFailed
+	AllocateOrder(ctx context.Context, orderID uuid.UUID, items []model.OrderItem) error
+	// ReleaseOrder returns stock allocated for cancelled order
+	ReleaseOrder(ctx context.Context, orderID uuid.UUID, items []model.OrderItem) error
+	SchedulePrice(ctx context.Context, change appmodel.PriceChange) (uuid.UUID, error)
+	// ApplyDuePrices activates scheduled prices and ends expired sales of all products
+	ApplyDuePrices(ctx context.Context, at time.Time) error
 	FindProduct(ctx context.Context, productID uuid.UUID) (appmodel.Product, error)
+	DeleteProduct(ctx context.Context, productID uuid.UUID) error
+	RestoreProduct(ctx context.Context, productID uuid.UUID) error
+	// PurgeDeletedProducts removes products soft deleted before the moment and returns how many were removed
+	PurgeDeletedProducts(ctx context.Context, deletedBefore time.Time) (int, error)
 }
 
 func NewProductService(
 	uow UnitOfWork,
 	luow LockableUnitOfWork,
 	eventDispatcher outbox.EventDispatcher[outbox.Event],
+	allocationStrategy service.AllocationStrategy,
 ) ProductService {
 	return &productService{
-		uow:             uow,
-		luow:            luow,
-		eventDispatcher: eventDispatcher,
+		uow:                uow,
+		luow:               luow,
+		eventDispatcher:    eventDispatcher,
+		allocationStrategy: allocationStrategy,
 	}
 }
 
 type productService struct {
-	uow             UnitOfWork
-	luow            LockableUnitOfWork
-	eventDispatcher outbox.EventDispatcher[outbox.Event]
+	uow                UnitOfWork
+	luow               LockableUnitOfWork
+	eventDispatcher    outbox.EventDispatcher[outbox.Event]
+	allocationStrategy service.AllocationStrategy
 }
 
+// StoreProduct creates or updates product, product without ID is looked up by SKU
 func (p productService) StoreProduct(ctx context.Context, product appmodel.Product) (uuid.UUID, error) {
-	productID := product.ID
-	err := p.luow.Execute(ctx, []string{product.ID.String()}, func(provider RepositoryProvider) error {
-		domainService := p.domainService(ctx, provider.ProductRepository(ctx))
+	lockName := product.ID.String()
+	if product.ID == uuid.Nil && product.SKU != "" {
+		lockName = skuLockName(product.SKU)
+	}
+	var productID uuid.UUID
+	err := p.luow.Execute(ctx, []string{lockName}, func(provider RepositoryProvider) error {
+		var err error
+		productID, _, err = p.storeProduct(ctx, provider, product)
+		return err
+	})
 
-		if product.ID == uuid.Nil {
-			uID, err := domainService.CreateProduct(product.Name, product.Quantity, product.Price)
-			if err != nil {
-				return err
-			}
-			productID = uID
-			return nil
+	return productID, err
+}
+
+// storeProduct stores product within the unit of work and reports whether it was created
+func (p productService) storeProduct(ctx context.Context, provider RepositoryProvider, product appmodel.Product) (uuid.UUID, bool, error) {
+	attributes, err := toDomainAttributes(product.Attributes)
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+	info := model.CatalogInfo{
+		SKU:        product.SKU,
+		CategoryID: product.CategoryID,
+		Attributes: attributes,
+	}
+
+	domainService := p.domainService(ctx, provider)
+	productID := product.ID
+	if productID == uuid.Nil && product.SKU != "" {
+		existing, err := provider.ProductRepository(ctx).FindBySKU(product.SKU)
+		if err != nil && !errors.Is(err, model.ErrProductNotFound) {
+			return uuid.Nil, false, err
+		}
+		if existing != nil {
+			productID = existing.ID
 		}
+	}
 
-		err := domainService.UpdateProductName(productID, product.Name)
+	if productID == uuid.Nil {
+		if product.ParentID != uuid.Nil {
+			productID, err = domainService.CreateVariant(product.ParentID, model.VariantSpec{
+				SKU:        product.SKU,
+				Attributes: attributes,
+				Quantity:   product.Quantity,
+				Price:      product.Price,
+			})
+		} else {
+			productID, err = domainService.CreateProduct(product.Name, product.Quantity, product.Price, info)
+		}
 		if err != nil {
-			return err
+			return uuid.Nil, false, err
 		}
+		return productID, true, domainService.SetReorderThreshold(productID, product.ReorderThreshold)
+	}
+
+	err = domainService.UpdateProductName(productID, product.Name)
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+
+	err = domainService.UpdateProductPrice(productID, product.Price)
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+
+	err = domainService.UpdateCatalogInfo(productID, info)
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+
+	return productID, false, domainService.SetReorderThreshold(productID, product.ReorderThreshold)
+}
+
+// CreateBundle locks components so they can not be deleted while bundle is created
+func (p productService) CreateBundle(ctx context.Context, bundle appmodel.Bundle) (uuid.UUID, error) {
+	attributes, err := toDomainAttributes(bundle.Attributes)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	components := make([]model.BundleComponent, 0, len(bundle.Components))
+	lockNames := make([]string, 0, len(bundle.Components)+1)
+	for _, component := range bundle.Components {
+		components = append(components, model.BundleComponent{
+			ProductID: component.ProductID,
+			Quantity:  component.Quantity,
+		})
+		lockNames = append(lockNames, component.ProductID.String())
+	}
+	if bundle.SKU != "" {
+		lockNames = append(lockNames, skuLockName(bundle.SKU))
+	}
+	slices.Sort(lockNames)
+
+	var bundleID uuid.UUID
+	err = p.luow.Execute(ctx, slices.Compact(lockNames), func(provider RepositoryProvider) error {
+		bundleID, err = p.domainService(ctx, provider).CreateBundle(bundle.Name, bundle.Price, model.CatalogInfo{
+			SKU:        bundle.SKU,
+			CategoryID: bundle.CategoryID,
+			Attributes: attributes,
+		}, components)
+		return err
+	})
+	return bundleID, err
+}
+
+func (p productService) IncreaseQuantity(ctx context.Context, productID, warehouseID uuid.UUID, quantity int, origin model.StockChangeOrigin) error {
+	lockNames, err := p.stockLockNames(ctx, []uuid.UUID{productID})
+	if err != nil {
+		return err
+	}
+	return p.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
+		return p.domainService(ctx, provider).IncreaseQuantity(productID, warehouseID, quantity, origin)
+	})
+}
+
+func (p productService) DecreaseQuantity(ctx context.Context, productID, warehouseID uuid.UUID, quantity int, origin model.StockChangeOrigin) error {
+	lockNames, err := p.stockLockNames(ctx, []uuid.UUID{productID})
+	if err != nil {
+		return err
+	}
+	return p.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
+		return p.domainService(ctx, provider).DecreaseQuantity(productID, warehouseID, quantity, origin)
+	})
+}
 
-		err = domainService.UpdateProductPrice(productID, product.Price)
+func (p productService) TransferStock(ctx context.Context, transfer appmodel.StockTransfer) (uuid.UUID, error) {
+	var transferID uuid.UUID
+	err := p.luow.Execute(ctx, []string{transfer.ProductID.String()}, func(provider RepositoryProvider) error {
+		var err error
+		transferID, err = p.domainService(ctx, provider).TransferStock(
+			transfer.ProductID,
+			transfer.FromWarehouseID,
+			transfer.ToWarehouseID,
+			transfer.Quantity,
+			transfer.Actor,
+		)
+		return err
+	})
+	return transferID, err
+}
+
+func (p productService) AllocateStock(ctx context.Context, productID uuid.UUID, quantity int, origin model.StockChangeOrigin) ([]appmodel.Allocation, error) {
+	lockNames, err := p.stockLockNames(ctx, []uuid.UUID{productID})
+	if err != nil {
+		return nil, err
+	}
+	var allocations []appmodel.Allocation
+	err = p.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
+		domainAllocations, err := p.domainService(ctx, provider).AllocateStock(productID, quantity, origin)
 		if err != nil {
 			return err
 		}
+		allocations = make([]appmodel.Allocation, 0, len(domainAllocations))
+		for _, allocation := range domainAllocations {
+			allocations = append(allocations, appmodel.Allocation{
+				ProductID:   allocation.ProductID,
+				WarehouseID: allocation.WarehouseID,
+				Quantity:    allocation.Quantity,
+			})
+		}
+		return nil
+	})
+	return allocations, err
+}
 
+func (p productService) AllocateOrder(ctx context.Context, orderID uuid.UUID, items []model.OrderItem) error {
+	items = service.MergeOrderItems(items)
+	if len(items) == 0 {
 		return nil
+	}
+	lockNames, err := p.stockLockNames(ctx, orderProductIDs(items))
+	if err != nil {
+		return err
+	}
+	err = p.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
+		return p.domainService(ctx, provider).AllocateOrder(orderID, items)
 	})
+	if isAllocationFailure(err) {
+		// allocation transaction is rolled back, so failure is published on its own
+		return p.eventDispatcher.Dispatch(ctx, &model.StockAllocationFailed{
+			OrderID: orderID,
+			Items:   items,
+			Reason:  err.Error(),
+		})
+	}
+	return err
+}
 
-	return productID, err
+func (p productService) ReleaseOrder(ctx context.Context, orderID uuid.UUID, items []model.OrderItem) error {
+	items = service.MergeOrderItems(items)
+	if len(items) == 0 {
+		return nil
+	}
+	lockNames, err := p.stockLockNames(ctx, orderProductIDs(items))
+	if err != nil {
+		return err
+	}
+	return p.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
+		return p.domainService(ctx, provider).ReleaseOrder(orderID)
+	})
+}
+
+// SchedulePrice plans regular price change, or sale price when change has end
+func (p productService) SchedulePrice(ctx context.Context, change appmodel.PriceChange) (uuid.UUID, error) {
+	var priceID uuid.UUID
+	err := p.luow.Execute(ctx, []string{change.ProductID.String()}, func(provider RepositoryProvider) error {
+		var err error
+		domainService := p.domainService(ctx, provider)
+		if change.EffectiveTo != nil {
+			priceID, err = domainService.ScheduleSale(change.ProductID, change.Price, change.EffectiveFrom, *change.EffectiveTo)
+			return err
+		}
+		priceID, err = domainService.SchedulePrice(change.ProductID, change.Price, change.EffectiveFrom)
+		return err
+	})
+	return priceID, err
 }
 
-func (p productService) IncreaseQuantity(ctx context.Context, productID uuid.UUID, quantity int) error {
+func (p productService) ApplyDuePrices(ctx context.Context, at time.Time) error {
+	var productIDs []uuid.UUID
+	err := p.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		productIDs, err = provider.ProductPriceRepository(ctx).ListProductsWithDuePrices(at)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, productID := range productIDs {
+		err = p.luow.Execute(ctx, []string{productID.String()}, func(provider RepositoryProvider) error {
+			return p.domainService(ctx, provider).ApplyDuePrices(productID, at)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p productService) DeleteProduct(ctx context.Context, productID uuid.UUID) error {
 	return p.luow.Execute(ctx, []string{productID.String()}, func(provider RepositoryProvider) error {
-		return p.domainService(ctx, provider.ProductRepository(ctx)).IncreaseQuantity(productID, quantity)
+		return p.domainService(ctx, provider).DeleteProduct(productID)
 	})
 }
 
-func (p productService) DecreaseQuantity(ctx context.Context, productID uuid.UUID, quantity int) error {
+func (p productService) RestoreProduct(ctx context.Context, productID uuid.UUID) error {
 	return p.luow.Execute(ctx, []string{productID.String()}, func(provider RepositoryProvider) error {
-		return p.domainService(ctx, provider.ProductRepository(ctx)).DecreaseQuantity(productID, quantity)
+		return p.domainService(ctx, provider).RestoreProduct(productID)
 	})
 }
 
+func (p productService) PurgeDeletedProducts(ctx context.Context, deletedBefore time.Time) (int, error) {
+	var productIDs []uuid.UUID
+	err := p.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		productIDs, err = provider.ProductRepository(ctx).ListDeletedBefore(deletedBefore)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, productID := range productIDs {
+		err = p.luow.Execute(ctx, []string{productID.String()}, func(provider RepositoryProvider) error {
+			ok, err := p.domainService(ctx, provider).PurgeProduct(productID, deletedBefore)
+			if ok {
+				purged++
+			}
+			return err
+		})
+		if err != nil {
+			return purged, err
+		}
+	}
+	return purged, nil
+}
+
 func (p productService) FindProduct(ctx context.Context, productID uuid.UUID) (appmodel.Product, error) {
 	var product appmodel.Product
 	err := p.luow.Execute(ctx, []string{productID.String()}, func(provider RepositoryProvider) error {
@@ -88,10 +348,15 @@ func (p productService) FindProduct(ctx context.Context, productID uuid.UUID) (a
 		}
 
 		product = appmodel.Product{
-			ID:       productID,
-			Name:     domainProduct.Name,
-			Quantity: domainProduct.Quantity,
-			Price:    domainProduct.Price,
+			ID:               productID,
+			SKU:              domainProduct.SKU,
+			ParentID:         domainProduct.ParentID,
+			CategoryID:       domainProduct.CategoryID,
+			Attributes:       toAppAttributes(domainProduct.Attributes),
+			Name:             domainProduct.Name,
+			Quantity:         domainProduct.Quantity,
+			Price:            domainProduct.Price,
+			ReorderThreshold: domainProduct.ReorderThreshold,
 		}
 		return nil
 	})
@@ -99,8 +364,18 @@ func (p productService) FindProduct(ctx context.Context, productID uuid.UUID) (a
 	return product, err
 }
 
-func (p productService) domainService(ctx context.Context, repository model.ProductRepository) service.ProductService {
-	return service.NewProductService(repository, p.domainEventDispatcher(ctx))
+func (p productService) domainService(ctx context.Context, provider RepositoryProvider) service.ProductService {
+	return service.NewProductService(
+		provider.ProductRepository(ctx),
+		provider.CategoryRepository(ctx),
+		provider.ProductPriceRepository(ctx),
+		provider.BundleRepository(ctx),
+		provider.StockMovementRepository(ctx),
+		provider.StockLevelRepository(ctx),
+		provider.WarehouseRepository(ctx),
+		p.allocationStrategy,
+		p.domainEventDispatcher(ctx),
+	)
 }
 
 func (p productService) domainEventDispatcher(ctx context.Context) domain.EventDispatcher {
@@ -109,3 +384,73 @@ func (p productService) domainEventDispatcher(ctx context.Context) domain.EventD
 		eventDispatcher: p.eventDispatcher,
 	}
 }
+
+// stockLockNames locks products together with components of bundles among them,
+// names are sorted so locks are always taken in the same order
+func (p productService) stockLockNames(ctx context.Context, productIDs []uuid.UUID) ([]string, error) {
+	lockNames := make([]string, 0, len(productIDs))
+	err := p.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		for _, productID := range productIDs {
+			lockNames = append(lockNames, productID.String())
+			components, err := provider.BundleRepository(ctx).FindComponents(productID)
+			if err != nil {
+				return err
+			}
+			for _, component := range components {
+				lockNames = append(lockNames, component.ProductID.String())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(lockNames)
+	return slices.Compact(lockNames), nil
+}
+
+func orderProductIDs(items []model.OrderItem) []uuid.UUID {
+	productIDs := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	return productIDs
+}
+
+func isAllocationFailure(err error) bool {
+	return errors.Is(err, model.ErrProductQuantityLessThanZero) ||
+		errors.Is(err, model.ErrProductNotFound) ||
+		errors.Is(err, model.ErrInvalidStockAdjustment)
+}
+
+func skuLockName(sku string) string {
+	return "sku_" + sku
+}
+
+func toDomainAttributes(attributes []appmodel.Attribute) ([]model.Attribute, error) {
+	result := make([]model.Attribute, 0, len(attributes))
+	for _, attribute := range attributes {
+		attributeType, err := model.ParseAttributeType(attribute.Type)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, model.Attribute{
+			Name:  attribute.Name,
+			Type:  attributeType,
+			Value: attribute.Value,
+		})
+	}
+	return result, nil
+}
+
+func toAppAttributes(attributes []model.Attribute) []appmodel.Attribute {
+	result := make([]appmodel.Attribute, 0, len(attributes))
+	for _, attribute := range attributes {
+		result = append(result, appmodel.Attribute{
+			Name:  attribute.Name,
+			Type:  attribute.Type.String(),
+			Value: attribute.Value,
+		})
+	}
+	return result
+}
@@ -0,0 +1,448 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	appmodel "inventory/pkg/inventory/app/model"
+	"inventory/pkg/inventory/app/service"
+	"inventory/pkg/inventory/domain/model"
+	domainservice "inventory/pkg/inventory/domain/service"
+)
+
+func TestProductService_ImportProducts(t *testing.T) {
+	t.Run("Create and update products by SKU", func(t *testing.T) {
+		storage := newStorage()
+		productService := storage.productService(t)
+		_, err := productService.StoreProduct(context.Background(), appmodel.Product{SKU: "SHIRT", Name: "Shirt", Price: 10, Quantity: 3})
+		require.NoError(t, err)
+
+		report, err := productService.ImportProducts(context.Background(), newRowSource(
+			appmodel.CatalogRow{SKU: "SHIRT", Name: "Cotton shirt", Price: 12, Quantity: 3},
+			appmodel.CatalogRow{SKU: "SHIRT-M", ParentSKU: "SHIRT", Price: 12, Quantity: 5},
+			appmodel.CatalogRow{SKU: "CAP", Name: "Cap", Price: 5},
+		), appmodel.ImportOptions{BatchSize: 2})
+		require.NoError(t, err)
+		require.Equal(t, 2, report.Created)
+		require.Equal(t, 1, report.Updated)
+		require.Empty(t, report.Errors)
+		require.Empty(t, report.Warnings)
+
+		shirt := storage.productBySKU(t, "SHIRT")
+		require.Equal(t, "Cotton shirt", shirt.Name)
+		require.Equal(t, 12.0, shirt.Price)
+		// variant takes name of the parent imported in previous batch
+		variant := storage.productBySKU(t, "SHIRT-M")
+		require.Equal(t, shirt.ID, variant.ParentID)
+		require.Equal(t, "Cotton shirt", variant.Name)
+		require.Equal(t, 5, variant.Quantity)
+	})
+
+	t.Run("Quantity of existing product is reported and kept", func(t *testing.T) {
+		storage := newStorage()
+		productService := storage.productService(t)
+		_, err := productService.StoreProduct(context.Background(), appmodel.Product{SKU: "SHIRT", Name: "Shirt", Price: 10, Quantity: 3})
+		require.NoError(t, err)
+
+		report, err := productService.ImportProducts(context.Background(), newRowSource(
+			appmodel.CatalogRow{SKU: "SHIRT", Name: "Shirt", Price: 10, Quantity: 100},
+		), appmodel.ImportOptions{})
+		require.NoError(t, err)
+		require.Equal(t, 1, report.Updated)
+		require.Len(t, report.Warnings, 1)
+		require.Equal(t, 1, report.Warnings[0].Row)
+		require.Equal(t, "SHIRT", report.Warnings[0].SKU)
+		require.Equal(t, 3, storage.productBySKU(t, "SHIRT").Quantity)
+	})
+
+	t.Run("Invalid rows are reported and the rest is stored", func(t *testing.T) {
+		storage := newStorage()
+		report, err := storage.productService(t).ImportProducts(context.Background(), newRowSource(
+			appmodel.CatalogRow{SKU: "SHIRT", Name: "Shirt", Price: 10},
+			appmodel.CatalogRow{SKU: "", Name: "No SKU"},
+			appmodel.CatalogRow{SKU: "SOCKS-M", ParentSKU: "SOCKS", Price: 2},
+			appmodel.CatalogRow{SKU: "CAP", Name: "Cap", Price: -1},
+			appmodel.CatalogRow{SKU: "SCARF", Name: "Scarf", Price: 7},
+		), appmodel.ImportOptions{BatchSize: 10})
+		require.NoError(t, err)
+		require.Equal(t, 2, report.Created)
+		require.Len(t, report.Errors, 3)
+		require.Equal(t, []int{2, 4, 3}, []int{report.Errors[0].Row, report.Errors[1].Row, report.Errors[2].Row})
+		require.Equal(t, "SOCKS-M", report.Errors[2].SKU)
+
+		// batch with unknown parent is stored row by row
+		storage.productBySKU(t, "SHIRT")
+		storage.productBySKU(t, "SCARF")
+		_, err = storage.products.FindBySKU("SOCKS-M")
+		require.ErrorIs(t, err, model.ErrProductNotFound)
+	})
+
+	t.Run("Dry run reports rows without storing them", func(t *testing.T) {
+		storage := newStorage()
+		report, err := storage.productService(t).ImportProducts(context.Background(), newRowSource(
+			appmodel.CatalogRow{SKU: "SHIRT", Name: "Shirt", Price: 10, Quantity: 3},
+			appmodel.CatalogRow{SKU: "SHIRT-M", ParentSKU: "SHIRT", Price: 12},
+			appmodel.CatalogRow{SKU: "SOCKS-M", ParentSKU: "SOCKS", Price: 2},
+		), appmodel.ImportOptions{DryRun: true, BatchSize: 1})
+		require.NoError(t, err)
+		require.Equal(t, 2, report.Created)
+		require.Len(t, report.Errors, 1)
+		require.Equal(t, 3, report.Errors[0].Row)
+
+		products, err := storage.products.List()
+		require.NoError(t, err)
+		require.Empty(t, products)
+		require.Empty(t, storage.events.events)
+	})
+}
+
+func newRowSource(rows ...appmodel.CatalogRow) service.CatalogRowSource {
+	return &rowSource{rows: rows}
+}
+
+type rowSource struct {
+	rows []appmodel.CatalogRow
+}
+
+func (s *rowSource) Next() (appmodel.CatalogRow, error) {
+	if len(s.rows) == 0 {
+		return appmodel.CatalogRow{}, io.EOF
+	}
+	row := s.rows[0]
+	s.rows = s.rows[1:]
+	return row, nil
+}
+
+// storage keeps repositories in memory, changes of failed unit of work are rolled back
+type storage struct {
+	products   *productRepository
+	prices     *productPriceRepository
+	movements  *stockMovementRepository
+	levels     *stockLevelRepository
+	warehouses *warehouseRepository
+	events     *eventDispatcher
+}
+
+func newStorage() *storage {
+	defaultWarehouse := &model.Warehouse{ID: uuid.Must(uuid.NewV7()), Name: "Main", IsDefault: true}
+	return &storage{
+		products:   &productRepository{store: make(map[uuid.UUID]*model.Product)},
+		prices:     &productPriceRepository{store: make(map[uuid.UUID]*model.ProductPrice)},
+		movements:  &stockMovementRepository{},
+		levels:     &stockLevelRepository{store: make(map[uuid.UUID]map[uuid.UUID]int)},
+		warehouses: &warehouseRepository{store: map[uuid.UUID]*model.Warehouse{defaultWarehouse.ID: defaultWarehouse}},
+		events:     &eventDispatcher{},
+	}
+}
+
+func (s *storage) productService(t *testing.T) service.ProductService {
+	t.Helper()
+	allocationStrategy, err := domainservice.NewAllocationStrategy(domainservice.PriorityAllocation)
+	require.NoError(t, err)
+	return service.NewProductService(s, lockableStorage{storage: s}, s.events, allocationStrategy)
+}
+
+func (s *storage) productBySKU(t *testing.T, sku string) *model.Product {
+	t.Helper()
+	product, err := s.products.FindBySKU(sku)
+	require.NoError(t, err)
+	return product
+}
+
+func (s *storage) Execute(ctx context.Context, f func(provider service.RepositoryProvider) error) error {
+	products := make(map[uuid.UUID]*model.Product, len(s.products.store))
+	for id, product := range s.products.store {
+		stored := *product
+		products[id] = &stored
+	}
+	prices := make(map[uuid.UUID]*model.ProductPrice, len(s.prices.store))
+	for id, price := range s.prices.store {
+		prices[id] = price
+	}
+	levels := make(map[uuid.UUID]map[uuid.UUID]int, len(s.levels.store))
+	for productID, warehouses := range s.levels.store {
+		levels[productID] = make(map[uuid.UUID]int, len(warehouses))
+		for warehouseID, quantity := range warehouses {
+			levels[productID][warehouseID] = quantity
+		}
+	}
+	movements := len(s.movements.movements)
+	events := len(s.events.events)
+
+	err := f(s)
+	if err != nil {
+		s.products.store = products
+		s.prices.store = prices
+		s.levels.store = levels
+		s.movements.movements = s.movements.movements[:movements]
+		s.events.events = s.events.events[:events]
+	}
+	return err
+}
+
+func (s *storage) ProductRepository(context.Context) model.ProductRepository {
+	return s.products
+}
+
+func (s *storage) StockMovementRepository(context.Context) model.StockMovementRepository {
+	return s.movements
+}
+
+func (s *storage) StockLevelRepository(context.Context) model.StockLevelRepository {
+	return s.levels
+}
+
+func (s *storage) WarehouseRepository(context.Context) model.WarehouseRepository {
+	return s.warehouses
+}
+
+func (s *storage) CategoryRepository(context.Context) model.CategoryRepository {
+	return &categoryRepository{}
+}
+
+func (s *storage) ProductPriceRepository(context.Context) model.ProductPriceRepository {
+	return s.prices
+}
+
+func (s *storage) BundleRepository(context.Context) model.BundleRepository {
+	return &bundleRepository{}
+}
+
+var (
+	_ service.UnitOfWork         = &storage{}
+	_ service.LockableUnitOfWork = lockableStorage{}
+)
+
+// lockableStorage ignores locks, tests run imports one at a time
+type lockableStorage struct {
+	*storage
+}
+
+func (s lockableStorage) Execute(ctx context.Context, _ []string, f func(provider service.RepositoryProvider) error) error {
+	return s.storage.Execute(ctx, f)
+}
+
+type productRepository struct {
+	store map[uuid.UUID]*model.Product
+}
+
+func (r *productRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (r *productRepository) Store(product *model.Product) error {
+	r.store[product.ID] = product
+	return nil
+}
+
+func (r *productRepository) Find(id uuid.UUID) (*model.Product, error) {
+	product, ok := r.store[id]
+	if !ok || product.DeletedAt != nil {
+		return nil, model.ErrProductNotFound
+	}
+	return product, nil
+}
+
+func (r *productRepository) FindBySKU(sku string) (*model.Product, error) {
+	for _, product := range r.store {
+		if product.SKU == sku && product.DeletedAt == nil {
+			return product, nil
+		}
+	}
+	return nil, model.ErrProductNotFound
+}
+
+func (r *productRepository) List() ([]model.Product, error) {
+	var res []model.Product
+	for _, product := range r.store {
+		if product.DeletedAt == nil {
+			res = append(res, *product)
+		}
+	}
+	return res, nil
+}
+
+func (r *productRepository) Delete(uuid.UUID) error {
+	panic("not implemented")
+}
+
+func (r *productRepository) FindWithDeleted(id uuid.UUID) (*model.Product, error) {
+	product, ok := r.store[id]
+	if !ok {
+		return nil, model.ErrProductNotFound
+	}
+	return product, nil
+}
+
+func (r *productRepository) FindBySKUWithDeleted(sku string) (*model.Product, error) {
+	for _, product := range r.store {
+		if product.SKU == sku {
+			return product, nil
+		}
+	}
+	return nil, model.ErrProductNotFound
+}
+
+func (r *productRepository) ListDeletedBefore(time.Time) ([]uuid.UUID, error) {
+	panic("not implemented")
+}
+
+func (r *productRepository) HasVariants(uuid.UUID) (bool, error) {
+	panic("not implemented")
+}
+
+func (r *productRepository) Purge(uuid.UUID) error {
+	panic("not implemented")
+}
+
+type productPriceRepository struct {
+	store map[uuid.UUID]*model.ProductPrice
+}
+
+func (r *productPriceRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (r *productPriceRepository) Store(price *model.ProductPrice) error {
+	stored := *price
+	r.store[price.ID] = &stored
+	return nil
+}
+
+func (r *productPriceRepository) ListByProduct(productID uuid.UUID) ([]model.ProductPrice, error) {
+	var res []model.ProductPrice
+	for _, price := range r.store {
+		if price.ProductID == productID {
+			res = append(res, *price)
+		}
+	}
+	return res, nil
+}
+
+func (r *productPriceRepository) ListProductsWithDuePrices(time.Time) ([]uuid.UUID, error) {
+	panic("not implemented")
+}
+
+type stockMovementRepository struct {
+	movements []model.StockMovement
+}
+
+func (r *stockMovementRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (r *stockMovementRepository) Append(movement model.StockMovement) error {
+	r.movements = append(r.movements, movement)
+	return nil
+}
+
+func (r *stockMovementRepository) ListByReference(uuid.UUID) ([]model.StockMovement, error) {
+	panic("not implemented")
+}
+
+type stockLevelRepository struct {
+	store map[uuid.UUID]map[uuid.UUID]int
+}
+
+func (r *stockLevelRepository) ListByProduct(productID uuid.UUID) ([]model.StockLevel, error) {
+	var res []model.StockLevel
+	for warehouseID, quantity := range r.store[productID] {
+		res = append(res, model.StockLevel{ProductID: productID, WarehouseID: warehouseID, Quantity: quantity})
+	}
+	return res, nil
+}
+
+func (r *stockLevelRepository) Store(level model.StockLevel) error {
+	if _, ok := r.store[level.ProductID]; !ok {
+		r.store[level.ProductID] = make(map[uuid.UUID]int)
+	}
+	r.store[level.ProductID][level.WarehouseID] = level.Quantity
+	return nil
+}
+
+type warehouseRepository struct {
+	store map[uuid.UUID]*model.Warehouse
+}
+
+func (r *warehouseRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (r *warehouseRepository) Store(warehouse *model.Warehouse) error {
+	r.store[warehouse.ID] = warehouse
+	return nil
+}
+
+func (r *warehouseRepository) Find(id uuid.UUID) (*model.Warehouse, error) {
+	warehouse, ok := r.store[id]
+	if !ok {
+		return nil, model.ErrWarehouseNotFound
+	}
+	return warehouse, nil
+}
+
+func (r *warehouseRepository) FindDefault() (*model.Warehouse, error) {
+	for _, warehouse := range r.store {
+		if warehouse.IsDefault {
+			return warehouse, nil
+		}
+	}
+	return nil, model.ErrDefaultWarehouseNotFound
+}
+
+func (r *warehouseRepository) List() ([]model.Warehouse, error) {
+	res := make([]model.Warehouse, 0, len(r.store))
+	for _, warehouse := range r.store {
+		res = append(res, *warehouse)
+	}
+	return res, nil
+}
+
+// categoryRepository has no categories, imported rows are not categorised
+type categoryRepository struct{}
+
+func (r *categoryRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (r *categoryRepository) Store(*model.Category) error {
+	panic("not implemented")
+}
+
+func (r *categoryRepository) Find(uuid.UUID) (*model.Category, error) {
+	return nil, model.ErrCategoryNotFound
+}
+
+// bundleRepository has no bundles, bundles are not imported
+type bundleRepository struct{}
+
+func (r *bundleRepository) Store(uuid.UUID, []model.BundleComponent) error {
+	panic("not implemented")
+}
+
+func (r *bundleRepository) FindComponents(uuid.UUID) ([]model.BundleComponent, error) {
+	return nil, nil
+}
+
+func (r *bundleRepository) ListBundlesByComponent(uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+func (r *bundleRepository) ListBundlesByComponentWithDeleted(uuid.UUID) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+type eventDispatcher struct {
+	events []outbox.Event
+}
+
+func (d *eventDispatcher) Dispatch(_ context.Context, event outbox.Event) error {
+	d.events = append(d.events, event)
+	return nil
+}
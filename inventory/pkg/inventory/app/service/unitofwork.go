@@ -8,6 +8,12 @@ import (
 
 type RepositoryProvider interface {
 	ProductRepository(ctx context.Context) model.ProductRepository
+	StockMovementRepository(ctx context.Context) model.StockMovementRepository
+	StockLevelRepository(ctx context.Context) model.StockLevelRepository
+	WarehouseRepository(ctx context.Context) model.WarehouseRepository
+	CategoryRepository(ctx context.Context) model.CategoryRepository
+	ProductPriceRepository(ctx context.Context) model.ProductPriceRepository
+	BundleRepository(ctx context.Context) model.BundleRepository
 }
 
 type LockableUnitOfWork interface {
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+
+	"inventory/pkg/common/domain"
+	appmodel "inventory/pkg/inventory/app/model"
+	"inventory/pkg/inventory/domain/service"
+)
+
+type WarehouseService interface {
+	StoreWarehouse(ctx context.Context, warehouse appmodel.Warehouse) (uuid.UUID, error)
+}
+
+func NewWarehouseService(
+	uow UnitOfWork,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+) WarehouseService {
+	return &warehouseService{
+		uow:             uow,
+		eventDispatcher: eventDispatcher,
+	}
+}
+
+type warehouseService struct {
+	uow             UnitOfWork
+	eventDispatcher outbox.EventDispatcher[outbox.Event]
+}
+
+func (w warehouseService) StoreWarehouse(ctx context.Context, warehouse appmodel.Warehouse) (uuid.UUID, error) {
+	warehouseID := warehouse.ID
+	err := w.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		domainService := service.NewWarehouseService(provider.WarehouseRepository(ctx), w.domainEventDispatcher(ctx))
+
+		if warehouse.ID == uuid.Nil {
+			id, err := domainService.CreateWarehouse(warehouse.Name, warehouse.Priority)
+			if err != nil {
+				return err
+			}
+			warehouseID = id
+			return nil
+		}
+
+		return domainService.UpdateWarehouse(warehouse.ID, warehouse.Name, warehouse.Priority)
+	})
+	return warehouseID, err
+}
+
+func (w warehouseService) domainEventDispatcher(ctx context.Context) domain.EventDispatcher {
+	return &domainEventDispatcher{
+		ctx:             ctx,
+		eventDispatcher: w.eventDispatcher,
+	}
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEmptyBundle            = errors.New("bundle must have components")
+	ErrInvalidBundleComponent = errors.New("bundle component must be other product with positive quantity")
+	ErrNestedBundle           = errors.New("bundle can not contain other bundle")
+	ErrBundleStock            = errors.New("bundle stock is computed from its components")
+	ErrProductInBundle        = errors.New("product is component of bundle")
+	ErrBundleComponentDeleted = errors.New("bundle component is deleted")
+)
+
+// BundleComponent is a product included into bundle, Quantity is per one bundle
+type BundleComponent struct {
+	ProductID uuid.UUID
+	Quantity  int
+}
+
+// BundleRepository keeps bundle composition, it is set once on bundle creation
+type BundleRepository interface {
+	Store(bundleID uuid.UUID, components []BundleComponent) error
+	// FindComponents returns nothing for products that are not bundles
+	FindComponents(bundleID uuid.UUID) ([]BundleComponent, error)
+	// ListBundlesByComponent returns not deleted bundles containing the product
+	ListBundlesByComponent(productID uuid.UUID) ([]uuid.UUID, error)
+	// ListBundlesByComponentWithDeleted also returns deleted bundles which are not purged yet
+	ListBundlesByComponentWithDeleted(productID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// ExpandBundle returns component quantities needed for the number of bundles
+func ExpandBundle(components []BundleComponent, quantity int) []BundleComponent {
+	expansion := make([]BundleComponent, 0, len(components))
+	for _, component := range components {
+		expansion = append(expansion, BundleComponent{
+			ProductID: component.ProductID,
+			Quantity:  component.Quantity * quantity,
+		})
+	}
+	return expansion
+}
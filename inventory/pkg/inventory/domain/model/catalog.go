@@ -0,0 +1,128 @@
+package model
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCategoryNotFound     = errors.New("category not found")
+	ErrCategoryCycle        = errors.New("category can not be moved under itself")
+	ErrEmptySKU             = errors.New("sku must not be empty")
+	ErrSKUAlreadyExists     = errors.New("sku already exists")
+	ErrNestedVariant        = errors.New("variant can not have own variants")
+	ErrInvalidAttribute     = errors.New("invalid attribute")
+	ErrDuplicateAttribute   = errors.New("duplicate attribute")
+	ErrUnknownAttributeType = errors.New("unknown attribute type")
+)
+
+type Category struct {
+	ID uuid.UUID
+	// ParentID is uuid.Nil for root categories
+	ParentID  uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type CategoryRepository interface {
+	NextID() (uuid.UUID, error)
+	Store(category *Category) error
+	Find(id uuid.UUID) (*Category, error)
+}
+
+// AttributeType defines how attribute value must be interpreted
+type AttributeType int
+
+const (
+	StringAttribute AttributeType = iota
+	NumberAttribute
+	BooleanAttribute
+)
+
+func (t AttributeType) Valid() bool {
+	return t >= StringAttribute && t <= BooleanAttribute
+}
+
+func (t AttributeType) String() string {
+	switch t {
+	case StringAttribute:
+		return "string"
+	case NumberAttribute:
+		return "number"
+	case BooleanAttribute:
+		return "boolean"
+	default:
+		return "unknown"
+	}
+}
+
+func ParseAttributeType(s string) (AttributeType, error) {
+	for t := StringAttribute; t <= BooleanAttribute; t++ {
+		if t.String() == s {
+			return t, nil
+		}
+	}
+	return 0, ErrUnknownAttributeType
+}
+
+// Attribute is free-form product characteristic like size, colour or weight
+type Attribute struct {
+	Name  string
+	Type  AttributeType
+	Value string
+}
+
+func (a Attribute) Validate() error {
+	if a.Name == "" {
+		return ErrInvalidAttribute
+	}
+	switch a.Type {
+	case StringAttribute:
+		return nil
+	case NumberAttribute:
+		if _, err := strconv.ParseFloat(a.Value, 64); err != nil {
+			return ErrInvalidAttribute
+		}
+		return nil
+	case BooleanAttribute:
+		if _, err := strconv.ParseBool(a.Value); err != nil {
+			return ErrInvalidAttribute
+		}
+		return nil
+	default:
+		return ErrUnknownAttributeType
+	}
+}
+
+func ValidateAttributes(attributes []Attribute) error {
+	names := make(map[string]struct{}, len(attributes))
+	for _, attribute := range attributes {
+		if err := attribute.Validate(); err != nil {
+			return err
+		}
+		if _, ok := names[attribute.Name]; ok {
+			return ErrDuplicateAttribute
+		}
+		names[attribute.Name] = struct{}{}
+	}
+	return nil
+}
+
+// CatalogInfo describes product position in catalog
+type CatalogInfo struct {
+	SKU        string
+	CategoryID uuid.UUID
+	Attributes []Attribute
+}
+
+// VariantSpec describes new variant of existing product, variant shares name and category with parent
+type VariantSpec struct {
+	SKU        string
+	Attributes []Attribute
+	Quantity   int
+	Price      float64
+}
@@ -7,9 +7,14 @@ import (
 )
 
 type ProductCreated struct {
-	ID        uuid.UUID
-	Name      string
-	CreatedAt time.Time
+	ID         uuid.UUID
+	SKU        string
+	ParentID   uuid.UUID
+	CategoryID uuid.UUID
+	Attributes []Attribute
+	Name       string
+	Price      float64
+	CreatedAt  time.Time
 }
 
 func (e ProductCreated) Type() string {
@@ -24,10 +29,33 @@ func (e ProductDeleted) Type() string {
 	return "ProductDeleted"
 }
 
+type ProductRestored struct {
+	ProductID uuid.UUID
+}
+
+func (e ProductRestored) Type() string {
+	return "ProductRestored"
+}
+
+// ProductPurged is published when soft deleted product is removed permanently after retention period
+type ProductPurged struct {
+	ProductID uuid.UUID
+	SKU       string
+	DeletedAt time.Time
+}
+
+func (e ProductPurged) Type() string {
+	return "ProductPurged"
+}
+
 type ProductQuantityChanged struct {
 	ID           uuid.UUID
+	SKU          string
+	ParentID     uuid.UUID
+	WarehouseID  uuid.UUID
 	NewQuantity  int
 	PrevQuantity int
+	Reason       StockChangeReason
 }
 
 func (e ProductQuantityChanged) Type() string {
@@ -42,10 +70,124 @@ type ProductNameChanged struct {
 func (e ProductNameChanged) Type() string { return "ProductNameChanged" }
 
 type ProductPriceChanged struct {
-	ID    uuid.UUID
-	Price float64
+	ID       uuid.UUID
+	SKU      string
+	ParentID uuid.UUID
+	Price    float64
 }
 
 func (e ProductPriceChanged) Type() string {
 	return "ProductPriceChanged"
 }
+
+type ProductCatalogInfoChanged struct {
+	ID         uuid.UUID
+	SKU        string
+	ParentID   uuid.UUID
+	CategoryID uuid.UUID
+	Attributes []Attribute
+}
+
+func (e ProductCatalogInfoChanged) Type() string {
+	return "ProductCatalogInfoChanged"
+}
+
+type CategoryCreated struct {
+	ID       uuid.UUID
+	ParentID uuid.UUID
+	Name     string
+}
+
+func (e CategoryCreated) Type() string {
+	return "CategoryCreated"
+}
+
+type BundleCreated struct {
+	ID         uuid.UUID
+	SKU        string
+	Name       string
+	Components []BundleComponent
+}
+
+func (e BundleCreated) Type() string {
+	return "BundleCreated"
+}
+
+// BundleStockChanged is published after bundle quantity change was applied to components
+type BundleStockChanged struct {
+	BundleID uuid.UUID
+	// WarehouseID is uuid.Nil when components were allocated with allocation strategy
+	WarehouseID uuid.UUID
+	// Delta is change in number of bundles
+	Delta       int
+	Reason      StockChangeReason
+	ReferenceID uuid.UUID
+	// Expansion is applied change of each component
+	Expansion []BundleComponent
+}
+
+func (e BundleStockChanged) Type() string {
+	return "BundleStockChanged"
+}
+
+type StockTransferred struct {
+	TransferID      uuid.UUID
+	ProductID       uuid.UUID
+	FromWarehouseID uuid.UUID
+	ToWarehouseID   uuid.UUID
+	Quantity        int
+}
+
+func (e StockTransferred) Type() string {
+	return "StockTransferred"
+}
+
+type StockAllocated struct {
+	ProductID   uuid.UUID
+	ReferenceID uuid.UUID
+	Allocations []Allocation
+}
+
+func (e StockAllocated) Type() string {
+	return "StockAllocated"
+}
+
+type WarehouseCreated struct {
+	ID   uuid.UUID
+	Name string
+}
+
+func (e WarehouseCreated) Type() string {
+	return "WarehouseCreated"
+}
+
+type StockLow struct {
+	ProductID        uuid.UUID
+	ProductName      string
+	Quantity         int
+	ReorderThreshold int
+}
+
+func (e StockLow) Type() string {
+	return "stock_low"
+}
+
+type OutOfStock struct {
+	ProductID   uuid.UUID
+	ProductName string
+}
+
+func (e OutOfStock) Type() string {
+	return "out_of_stock"
+}
+
+// StockAllocationFailed is published when paid order can not be fulfilled from stock
+type StockAllocationFailed struct {
+	OrderID uuid.UUID
+	Items   []OrderItem
+	Reason  string
+}
+
+func (e StockAllocationFailed) Type() string {
+	return "stock_allocation_failed"
+}
@@ -0,0 +1,9 @@
+package model
+
+import "github.com/google/uuid"
+
+// OrderItem is a product line of an order placed in order service
+type OrderItem struct {
+	ProductID uuid.UUID
+	Quantity  int
+}
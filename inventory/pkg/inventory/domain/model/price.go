@@ -0,0 +1,102 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidPrice       = errors.New("price must not be negative")
+	ErrInvalidPricePeriod = errors.New("price period end must be after its start")
+	ErrOverlappingSale    = errors.New("sale overlaps with another sale")
+)
+
+// PriceKind distinguishes regular price from time-boxed sale price
+type PriceKind int
+
+const (
+	RegularPrice PriceKind = iota
+	SalePrice
+)
+
+func (k PriceKind) String() string {
+	switch k {
+	case RegularPrice:
+		return "regular"
+	case SalePrice:
+		return "sale"
+	default:
+		return "unknown"
+	}
+}
+
+// ProductPrice is price history row, scheduled prices have EffectiveFrom in future and empty ActivatedAt
+type ProductPrice struct {
+	ID            uuid.UUID
+	ProductID     uuid.UUID
+	Kind          PriceKind
+	Price         float64
+	EffectiveFrom time.Time
+	// EffectiveTo is nil for regular price until next regular price takes effect
+	EffectiveTo *time.Time
+	ActivatedAt *time.Time
+	// ExpiredAt is set when ended sale was processed
+	ExpiredAt *time.Time
+	CreatedAt time.Time
+}
+
+func (p ProductPrice) ActiveAt(at time.Time) bool {
+	if p.EffectiveFrom.After(at) {
+		return false
+	}
+	return p.EffectiveTo == nil || p.EffectiveTo.After(at)
+}
+
+type ProductPriceRepository interface {
+	NextID() (uuid.UUID, error)
+	Store(price *ProductPrice) error
+	// ListByProduct returns prices ordered by EffectiveFrom
+	ListByProduct(productID uuid.UUID) ([]ProductPrice, error)
+	// ListProductsWithDuePrices returns products having prices to activate or sales to end at the moment
+	ListProductsWithDuePrices(at time.Time) ([]uuid.UUID, error)
+}
+
+// RegularPriceAt returns latest regular price started before the moment
+func RegularPriceAt(prices []ProductPrice, at time.Time) (ProductPrice, bool) {
+	var (
+		result ProductPrice
+		found  bool
+	)
+	for _, price := range prices {
+		if price.Kind != RegularPrice || price.EffectiveFrom.After(at) {
+			continue
+		}
+		if !found || !price.EffectiveFrom.Before(result.EffectiveFrom) {
+			result, found = price, true
+		}
+	}
+	return result, found
+}
+
+// EffectivePrice returns price customer pays at the moment, active sale overrides regular price
+func EffectivePrice(prices []ProductPrice, at time.Time) (float64, bool) {
+	var (
+		sale  ProductPrice
+		found bool
+	)
+	for _, price := range prices {
+		if price.Kind != SalePrice || !price.ActiveAt(at) {
+			continue
+		}
+		if !found || !price.EffectiveFrom.Before(sale.EffectiveFrom) {
+			sale, found = price, true
+		}
+	}
+	if found {
+		return sale.Price, true
+	}
+	regular, ok := RegularPriceAt(prices, at)
+	return regular.Price, ok
+}
@@ -8,24 +8,82 @@ import (
 )
 
 type Product struct {
-	ID        uuid.UUID
-	Name      string
-	Price     float64
-	Quantity  int
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt *time.Time
+	ID uuid.UUID
+	// SKU is unique stock keeping unit code, empty for products not yet registered in catalog
+	SKU string
+	// ParentID is set for variants and points to product sharing name and category with them
+	ParentID   uuid.UUID
+	CategoryID uuid.UUID
+	Attributes []Attribute
+	Name       string
+	Price      float64
+	Quantity   int
+	// ReorderThreshold is stock level below which product must be reordered, zero disables the check
+	ReorderThreshold int
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        *time.Time
 }
 
 var (
 	ErrProductQuantityLessThanZero = errors.New("product quantity must be greater than zero")
 	ErrProductNotFound             = errors.New("product not found")
+	ErrInvalidStockAdjustment      = errors.New("stock adjustment quantity must be positive")
+	ErrUnknownStockChangeReason    = errors.New("unknown stock change reason")
+	ErrInvalidReorderThreshold     = errors.New("reorder threshold must not be negative")
+	ErrParentProductDeleted        = errors.New("parent product is deleted")
 )
 
+// StockChangeReason explains why product quantity was changed
+type StockChangeReason int
+
+const (
+	Receipt StockChangeReason = iota
+	Sale
+	Reservation
+	Return
+	ManualAdjustment
+	Transfer
+)
+
+func (r StockChangeReason) Valid() bool {
+	return r >= Receipt && r <= Transfer
+}
+
+func (r StockChangeReason) String() string {
+	switch r {
+	case Receipt:
+		return "receipt"
+	case Sale:
+		return "sale"
+	case Reservation:
+		return "reservation"
+	case Return:
+		return "return"
+	case ManualAdjustment:
+		return "manual_adjustment"
+	case Transfer:
+		return "transfer"
+	default:
+		return "unknown"
+	}
+}
+
 type ProductRepository interface {
 	NextID() (uuid.UUID, error)
 	Store(product *Product) error
 	Find(id uuid.UUID) (*Product, error)
+	FindBySKU(sku string) (*Product, error)
 	List() ([]Product, error)
 	Delete(id uuid.UUID) error
+	// FindWithDeleted finds product regardless of soft delete
+	FindWithDeleted(id uuid.UUID) (*Product, error)
+	// FindBySKUWithDeleted finds product by sku regardless of soft delete, sku stays taken until product is purged
+	FindBySKUWithDeleted(sku string) (*Product, error)
+	// ListDeletedBefore returns soft deleted products, variants go before their parents
+	ListDeletedBefore(before time.Time) ([]uuid.UUID, error)
+	// HasVariants reports whether product has variants including soft deleted ones
+	HasVariants(id uuid.UUID) (bool, error)
+	// Purge removes product with its stock levels and prices permanently
+	Purge(id uuid.UUID) error
 }
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockChangeOrigin describes who changed product quantity and why
+type StockChangeOrigin struct {
+	Reason StockChangeReason
+	// ReferenceID links movement to its source, e.g. order ID, uuid.Nil if there is none
+	ReferenceID uuid.UUID
+	Actor       string
+}
+
+// StockMovement is an append-only record of a single product quantity change
+type StockMovement struct {
+	ID          uuid.UUID
+	ProductID   uuid.UUID
+	WarehouseID uuid.UUID
+	Delta       int
+	// QuantityAfter is product quantity at the warehouse after the movement
+	QuantityAfter int
+	Reason        StockChangeReason
+	ReferenceID   uuid.UUID
+	Actor         string
+	CreatedAt     time.Time
+}
+
+type StockMovementRepository interface {
+	NextID() (uuid.UUID, error)
+	Append(movement StockMovement) error
+	// ListByReference returns movements linked to the reference in order they were made
+	ListByReference(referenceID uuid.UUID) ([]StockMovement, error)
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrWarehouseNotFound        = errors.New("warehouse not found")
+	ErrDefaultWarehouseNotFound = errors.New("default warehouse not found")
+	ErrSameWarehouseTransfer    = errors.New("transfer source and destination warehouses must differ")
+)
+
+type Warehouse struct {
+	ID   uuid.UUID
+	Name string
+	// Priority is used by allocation strategies, lower value is preferred
+	Priority int
+	// IsDefault warehouse receives stock when no location is specified
+	IsDefault bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type WarehouseRepository interface {
+	NextID() (uuid.UUID, error)
+	Store(warehouse *Warehouse) error
+	Find(id uuid.UUID) (*Warehouse, error)
+	FindDefault() (*Warehouse, error)
+	List() ([]Warehouse, error)
+}
+
+// StockLevel is product quantity at one warehouse, Product.Quantity is the sum over all warehouses
+type StockLevel struct {
+	ProductID   uuid.UUID
+	WarehouseID uuid.UUID
+	Quantity    int
+}
+
+type StockLevelRepository interface {
+	ListByProduct(productID uuid.UUID) ([]StockLevel, error)
+	Store(level StockLevel) error
+}
+
+type Allocation struct {
+	// ProductID is allocated product, bundle allocation consists of its components allocations
+	ProductID   uuid.UUID
+	WarehouseID uuid.UUID
+	Quantity    int
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"sort"
+
+	"inventory/pkg/inventory/domain/model"
+)
+
+var ErrUnknownAllocationStrategy = errors.New("unknown allocation strategy")
+
+const (
+	// PriorityAllocation takes stock from warehouses in priority order, splitting between them if needed
+	PriorityAllocation = "priority"
+	// SingleLocationAllocation prefers one warehouse able to fulfil the whole quantity,
+	// otherwise splits starting from the warehouse with the largest stock
+	SingleLocationAllocation = "single_location"
+	// LargestStockAllocation takes stock from warehouses with the largest quantity first
+	LargestStockAllocation = "largest_stock"
+)
+
+type AllocationCandidate struct {
+	Warehouse model.Warehouse
+	Available int
+}
+
+type AllocationStrategy interface {
+	Allocate(quantity int, candidates []AllocationCandidate) ([]model.Allocation, error)
+}
+
+func NewAllocationStrategy(name string) (AllocationStrategy, error) {
+	switch name {
+	case PriorityAllocation:
+		return priorityStrategy{}, nil
+	case SingleLocationAllocation:
+		return singleLocationStrategy{}, nil
+	case LargestStockAllocation:
+		return largestStockStrategy{}, nil
+	default:
+		return nil, ErrUnknownAllocationStrategy
+	}
+}
+
+type priorityStrategy struct{}
+
+func (s priorityStrategy) Allocate(quantity int, candidates []AllocationCandidate) ([]model.Allocation, error) {
+	sorted := sortCandidates(candidates, byPriority)
+	return allocateGreedy(quantity, sorted)
+}
+
+type singleLocationStrategy struct{}
+
+func (s singleLocationStrategy) Allocate(quantity int, candidates []AllocationCandidate) ([]model.Allocation, error) {
+	for _, candidate := range sortCandidates(candidates, byPriority) {
+		if candidate.Available >= quantity {
+			return []model.Allocation{{WarehouseID: candidate.Warehouse.ID, Quantity: quantity}}, nil
+		}
+	}
+	return allocateGreedy(quantity, sortCandidates(candidates, byAvailable))
+}
+
+type largestStockStrategy struct{}
+
+func (s largestStockStrategy) Allocate(quantity int, candidates []AllocationCandidate) ([]model.Allocation, error) {
+	return allocateGreedy(quantity, sortCandidates(candidates, byAvailable))
+}
+
+func allocateGreedy(quantity int, candidates []AllocationCandidate) ([]model.Allocation, error) {
+	var allocations []model.Allocation
+	rest := quantity
+	for _, candidate := range candidates {
+		if rest == 0 {
+			break
+		}
+		if candidate.Available <= 0 {
+			continue
+		}
+		taken := min(candidate.Available, rest)
+		allocations = append(allocations, model.Allocation{
+			WarehouseID: candidate.Warehouse.ID,
+			Quantity:    taken,
+		})
+		rest -= taken
+	}
+	if rest > 0 {
+		return nil, model.ErrProductQuantityLessThanZero
+	}
+	return allocations, nil
+}
+
+func byPriority(a, b AllocationCandidate) bool {
+	if a.Warehouse.Priority != b.Warehouse.Priority {
+		return a.Warehouse.Priority < b.Warehouse.Priority
+	}
+	return a.Warehouse.ID.String() < b.Warehouse.ID.String()
+}
+
+func byAvailable(a, b AllocationCandidate) bool {
+	if a.Available != b.Available {
+		return a.Available > b.Available
+	}
+	return byPriority(a, b)
+}
+
+func sortCandidates(candidates []AllocationCandidate, less func(a, b AllocationCandidate) bool) []AllocationCandidate {
+	sorted := make([]AllocationCandidate, len(candidates))
+	copy(sorted, candidates)
+	sort.Slice(sorted, func(i, j int) bool {
+		return less(sorted[i], sorted[j])
+	})
+	return sorted
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"inventory/pkg/common/domain"
+	"inventory/pkg/inventory/domain/model"
+)
+
+type CategoryService interface {
+	CreateCategory(name string, parentID uuid.UUID) (uuid.UUID, error)
+	// UpdateCategory renames category and moves it under another parent, uuid.Nil makes it root category
+	UpdateCategory(categoryID uuid.UUID, name string, parentID uuid.UUID) error
+}
+
+func NewCategoryService(repo model.CategoryRepository, d domain.EventDispatcher) CategoryService {
+	return &categoryService{
+		repo:            repo,
+		eventDispatcher: d,
+	}
+}
+
+type categoryService struct {
+	repo            model.CategoryRepository
+	eventDispatcher domain.EventDispatcher
+}
+
+func (c categoryService) CreateCategory(name string, parentID uuid.UUID) (uuid.UUID, error) {
+	if parentID != uuid.Nil {
+		if _, err := c.repo.Find(parentID); err != nil {
+			return uuid.Nil, err
+		}
+	}
+	categoryID, err := c.repo.NextID()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	currentTime := time.Now()
+	err = c.repo.Store(&model.Category{
+		ID:        categoryID,
+		ParentID:  parentID,
+		Name:      name,
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return categoryID, c.eventDispatcher.Dispatch(&model.CategoryCreated{
+		ID:       categoryID,
+		ParentID: parentID,
+		Name:     name,
+	})
+}
+
+func (c categoryService) UpdateCategory(categoryID uuid.UUID, name string, parentID uuid.UUID) error {
+	category, err := c.repo.Find(categoryID)
+	if err != nil {
+		return err
+	}
+
+	// walk up from new parent to make sure category is not moved under own subtree
+	for ancestorID := parentID; ancestorID != uuid.Nil; {
+		if ancestorID == categoryID {
+			return model.ErrCategoryCycle
+		}
+		ancestor, err2 := c.repo.Find(ancestorID)
+		if err2 != nil {
+			return err2
+		}
+		ancestorID = ancestor.ParentID
+	}
+
+	category.Name = name
+	category.ParentID = parentID
+	category.UpdatedAt = time.Now()
+	return c.repo.Store(category)
+}
@@ -1,6 +1,9 @@
 package service
 
 import (
+	"errors"
+	"slices"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,26 +12,154 @@ import (
 	"inventory/pkg/inventory/domain/model"
 )
 
+// orderActor marks stock movements made on behalf of order service
+const orderActor = "order"
+
 type ProductService interface {
-	CreateProduct(name string, quantity int, price float64) (uuid.UUID, error)
-	IncreaseQuantity(productID uuid.UUID, quantity int) error
-	DecreaseQuantity(productID uuid.UUID, quantity int) error
+	CreateProduct(name string, quantity int, price float64, info model.CatalogInfo) (uuid.UUID, error)
+	// CreateVariant creates product variant with own stock and price
+	CreateVariant(parentID uuid.UUID, spec model.VariantSpec) (uuid.UUID, error)
+	// CreateBundle creates product made of other products, stock changes of bundle are applied to its components
+	CreateBundle(name string, price float64, info model.CatalogInfo, components []model.BundleComponent) (uuid.UUID, error)
+	// IncreaseQuantity and DecreaseQuantity change stock at the warehouse, uuid.Nil means the default warehouse
+	IncreaseQuantity(productID, warehouseID uuid.UUID, quantity int, origin model.StockChangeOrigin) error
+	DecreaseQuantity(productID, warehouseID uuid.UUID, quantity int, origin model.StockChangeOrigin) error
+	TransferStock(productID, fromWarehouseID, toWarehouseID uuid.UUID, quantity int, actor string) (uuid.UUID, error)
+	// AllocateStock decreases quantity picking warehouses with allocation strategy
+	AllocateStock(productID uuid.UUID, quantity int, origin model.StockChangeOrigin) ([]model.Allocation, error)
+	// AllocateOrder takes stock for all order items, already allocated order is skipped
+	AllocateOrder(orderID uuid.UUID, items []model.OrderItem) error
+	// ReleaseOrder returns stock allocated for the order to the warehouses it was taken from
+	ReleaseOrder(orderID uuid.UUID) error
 	UpdateProductName(productID uuid.UUID, newName string) error
+	// UpdateProductPrice changes regular price starting from now
 	UpdateProductPrice(productID uuid.UUID, newPrice float64) error
+	// SchedulePrice plans regular price change, past effectiveFrom applies price immediately
+	SchedulePrice(productID uuid.UUID, price float64, effectiveFrom time.Time) (uuid.UUID, error)
+	// ScheduleSale plans sale price overriding regular price within period
+	ScheduleSale(productID uuid.UUID, price float64, from, to time.Time) (uuid.UUID, error)
+	// ApplyDuePrices activates scheduled prices and ends expired sales at the moment
+	ApplyDuePrices(productID uuid.UUID, at time.Time) error
+	SetReorderThreshold(productID uuid.UUID, threshold int) error
+	UpdateCatalogInfo(productID uuid.UUID, info model.CatalogInfo) error
 
 	DeleteProduct(id uuid.UUID) error
+	// RestoreProduct reverts soft delete, variant can be restored only with its parent
+	RestoreProduct(id uuid.UUID) error
+	// PurgeProduct removes product soft deleted before the moment, returns false when product was skipped
+	PurgeProduct(id uuid.UUID, deletedBefore time.Time) (bool, error)
 }
 
-func NewProductService(repo model.ProductRepository, d domain.EventDispatcher) ProductService {
-	return &productService{repo, d}
+func NewProductService(
+	repo model.ProductRepository,
+	categoryRepo model.CategoryRepository,
+	priceRepo model.ProductPriceRepository,
+	bundleRepo model.BundleRepository,
+	movementRepo model.StockMovementRepository,
+	stockRepo model.StockLevelRepository,
+	warehouseRepo model.WarehouseRepository,
+	allocationStrategy AllocationStrategy,
+	d domain.EventDispatcher,
+) ProductService {
+	return &productService{
+		repo:               repo,
+		categoryRepo:       categoryRepo,
+		priceRepo:          priceRepo,
+		bundleRepo:         bundleRepo,
+		movementRepo:       movementRepo,
+		stockRepo:          stockRepo,
+		warehouseRepo:      warehouseRepo,
+		allocationStrategy: allocationStrategy,
+		eventDispatcher:    d,
+	}
 }
 
 type productService struct {
-	repo            model.ProductRepository
-	eventDispatcher domain.EventDispatcher
+	repo               model.ProductRepository
+	categoryRepo       model.CategoryRepository
+	priceRepo          model.ProductPriceRepository
+	bundleRepo         model.BundleRepository
+	movementRepo       model.StockMovementRepository
+	stockRepo          model.StockLevelRepository
+	warehouseRepo      model.WarehouseRepository
+	allocationStrategy AllocationStrategy
+	eventDispatcher    domain.EventDispatcher
+}
+
+func (p productService) CreateProduct(name string, quantity int, price float64, info model.CatalogInfo) (uuid.UUID, error) {
+	err := p.checkCatalogInfo(uuid.Nil, info)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return p.createProduct(&model.Product{
+		SKU:        info.SKU,
+		CategoryID: info.CategoryID,
+		Attributes: info.Attributes,
+		Name:       name,
+		Price:      price,
+	}, quantity)
+}
+
+func (p productService) CreateVariant(parentID uuid.UUID, spec model.VariantSpec) (uuid.UUID, error) {
+	parent, err := p.repo.Find(parentID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if parent.ParentID != uuid.Nil {
+		return uuid.Nil, model.ErrNestedVariant
+	}
+	if spec.SKU == "" {
+		return uuid.Nil, model.ErrEmptySKU
+	}
+	err = p.checkCatalogInfo(uuid.Nil, model.CatalogInfo{SKU: spec.SKU, Attributes: spec.Attributes})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return p.createProduct(&model.Product{
+		SKU:        spec.SKU,
+		ParentID:   parent.ID,
+		CategoryID: parent.CategoryID,
+		Attributes: spec.Attributes,
+		Name:       parent.Name,
+		Price:      spec.Price,
+	}, spec.Quantity)
+}
+
+func (p productService) CreateBundle(name string, price float64, info model.CatalogInfo, components []model.BundleComponent) (uuid.UUID, error) {
+	components, err := p.checkBundleComponents(components)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	err = p.checkCatalogInfo(uuid.Nil, info)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	bundleID, err := p.createProduct(&model.Product{
+		SKU:        info.SKU,
+		CategoryID: info.CategoryID,
+		Attributes: info.Attributes,
+		Name:       name,
+		Price:      price,
+	}, 0)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	err = p.bundleRepo.Store(bundleID, components)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return bundleID, p.eventDispatcher.Dispatch(&model.BundleCreated{
+		ID:         bundleID,
+		SKU:        info.SKU,
+		Name:       name,
+		Components: components,
+	})
 }
 
-func (p productService) CreateProduct(name string, quantity int, price float64) (uuid.UUID, error) {
+func (p productService) createProduct(product *model.Product, quantity int) (uuid.UUID, error) {
 	newProductID, err := p.repo.NextID()
 	if err != nil {
 		return uuid.Nil, err
@@ -37,64 +168,262 @@ func (p productService) CreateProduct(name string, quantity int, price float64)
 		return uuid.Nil, model.ErrProductQuantityLessThanZero
 	}
 	currentTime := time.Now()
-	err = p.repo.Store(&model.Product{
-		ID:        newProductID,
-		Name:      name,
-		Quantity:  quantity,
-		Price:     price,
-		CreatedAt: currentTime,
-		UpdatedAt: currentTime,
-	})
+	product.ID = newProductID
+	product.CreatedAt = currentTime
+	product.UpdatedAt = currentTime
+	err = p.repo.Store(product)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	initialPrice, err := p.newPrice(product.ID, model.RegularPrice, product.Price, currentTime, nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	initialPrice.ActivatedAt = &currentTime
+	err = p.priceRepo.Store(&initialPrice)
 	if err != nil {
 		return uuid.Nil, err
 	}
 
+	if quantity > 0 {
+		var warehouseID uuid.UUID
+		warehouseID, err = p.resolveWarehouse(uuid.Nil)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		err = p.changeStock(product, warehouseID, quantity, model.StockChangeOrigin{Reason: model.Receipt})
+		if err != nil {
+			return uuid.Nil, err
+		}
+	}
+
 	return newProductID, p.eventDispatcher.Dispatch(&model.ProductCreated{
-		ID:        newProductID,
-		Name:      name,
-		CreatedAt: currentTime,
+		ID:         newProductID,
+		SKU:        product.SKU,
+		ParentID:   product.ParentID,
+		CategoryID: product.CategoryID,
+		Attributes: product.Attributes,
+		Name:       product.Name,
+		Price:      product.Price,
+		CreatedAt:  currentTime,
 	})
 }
 
-func (p productService) IncreaseQuantity(productID uuid.UUID, quantity int) error {
-	product, err := p.repo.Find(productID)
+func (p productService) IncreaseQuantity(productID, warehouseID uuid.UUID, quantity int, origin model.StockChangeOrigin) error {
+	err := validateStockAdjustment(quantity, origin.Reason)
 	if err != nil {
 		return err
 	}
+	components, err := p.bundleRepo.FindComponents(productID)
+	if err != nil {
+		return err
+	}
+	if len(components) > 0 {
+		return p.adjustBundle(productID, warehouseID, quantity, components, origin)
+	}
+	return p.adjustQuantity(productID, warehouseID, quantity, origin)
+}
 
-	product.Quantity += quantity
-	err = p.repo.Store(product)
+func (p productService) DecreaseQuantity(productID, warehouseID uuid.UUID, quantity int, origin model.StockChangeOrigin) error {
+	err := validateStockAdjustment(quantity, origin.Reason)
 	if err != nil {
 		return err
 	}
+	components, err := p.bundleRepo.FindComponents(productID)
+	if err != nil {
+		return err
+	}
+	if len(components) > 0 {
+		return p.adjustBundle(productID, warehouseID, -quantity, components, origin)
+	}
+	return p.adjustQuantity(productID, warehouseID, -quantity, origin)
+}
 
-	return p.eventDispatcher.Dispatch(&model.ProductQuantityChanged{
-		ID:           productID,
-		NewQuantity:  product.Quantity,
-		PrevQuantity: product.Quantity - quantity,
+func (p productService) TransferStock(productID, fromWarehouseID, toWarehouseID uuid.UUID, quantity int, actor string) (uuid.UUID, error) {
+	if quantity <= 0 {
+		return uuid.Nil, model.ErrInvalidStockAdjustment
+	}
+	if fromWarehouseID == toWarehouseID {
+		return uuid.Nil, model.ErrSameWarehouseTransfer
+	}
+	product, err := p.repo.Find(productID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	components, err := p.bundleRepo.FindComponents(productID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if len(components) > 0 {
+		return uuid.Nil, model.ErrBundleStock
+	}
+	for _, warehouseID := range []uuid.UUID{fromWarehouseID, toWarehouseID} {
+		if _, err = p.warehouseRepo.Find(warehouseID); err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	transferID, err := p.movementRepo.NextID()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	origin := model.StockChangeOrigin{
+		Reason:      model.Transfer,
+		ReferenceID: transferID,
+		Actor:       actor,
+	}
+	err = p.changeStock(product, fromWarehouseID, -quantity, origin)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	err = p.changeStock(product, toWarehouseID, quantity, origin)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return transferID, p.eventDispatcher.Dispatch(&model.StockTransferred{
+		TransferID:      transferID,
+		ProductID:       productID,
+		FromWarehouseID: fromWarehouseID,
+		ToWarehouseID:   toWarehouseID,
+		Quantity:        quantity,
 	})
 }
 
-func (p productService) DecreaseQuantity(productID uuid.UUID, quantity int) error {
+func (p productService) AllocateStock(productID uuid.UUID, quantity int, origin model.StockChangeOrigin) ([]model.Allocation, error) {
+	err := validateStockAdjustment(quantity, origin.Reason)
+	if err != nil {
+		return nil, err
+	}
 	product, err := p.repo.Find(productID)
+	if err != nil {
+		return nil, err
+	}
+	components, err := p.bundleRepo.FindComponents(productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(components) > 0 {
+		return p.allocateBundle(productID, quantity, components, origin)
+	}
+
+	levels, err := p.stockRepo.ListByProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+	warehouses, err := p.warehouseRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]AllocationCandidate, 0, len(warehouses))
+	for _, warehouse := range warehouses {
+		candidates = append(candidates, AllocationCandidate{
+			Warehouse: warehouse,
+			Available: findStockLevel(levels, productID, warehouse.ID).Quantity,
+		})
+	}
+
+	allocations, err := p.allocationStrategy.Allocate(quantity, candidates)
+	if err != nil {
+		return nil, err
+	}
+	totalBefore := product.Quantity
+	for i, allocation := range allocations {
+		allocations[i].ProductID = productID
+		prevQuantity := product.Quantity
+		err = p.changeStock(product, allocation.WarehouseID, -allocation.Quantity, origin)
+		if err != nil {
+			return nil, err
+		}
+		err = p.eventDispatcher.Dispatch(&model.ProductQuantityChanged{
+			ID:           productID,
+			SKU:          product.SKU,
+			ParentID:     product.ParentID,
+			WarehouseID:  allocation.WarehouseID,
+			NewQuantity:  product.Quantity,
+			PrevQuantity: prevQuantity,
+			Reason:       origin.Reason,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = p.eventDispatcher.Dispatch(&model.StockAllocated{
+		ProductID:   productID,
+		ReferenceID: origin.ReferenceID,
+		Allocations: allocations,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return allocations, p.checkReorderThreshold(product, totalBefore)
+}
+
+func (p productService) AllocateOrder(orderID uuid.UUID, items []model.OrderItem) error {
+	movements, err := p.movementRepo.ListByReference(orderID)
 	if err != nil {
 		return err
 	}
-	if (product.Quantity - quantity) < 0 {
-		return model.ErrProductQuantityLessThanZero
+	if hasMovementWithReason(movements, model.Sale) {
+		return nil
 	}
 
-	product.Quantity -= quantity
-	err = p.repo.Store(product)
+	origin := model.StockChangeOrigin{
+		Reason:      model.Sale,
+		ReferenceID: orderID,
+		Actor:       orderActor,
+	}
+	for _, item := range MergeOrderItems(items) {
+		_, err = p.AllocateStock(item.ProductID, item.Quantity, origin)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p productService) ReleaseOrder(orderID uuid.UUID) error {
+	movements, err := p.movementRepo.ListByReference(orderID)
 	if err != nil {
 		return err
 	}
+	if hasMovementWithReason(movements, model.Return) {
+		return nil
+	}
 
-	return p.eventDispatcher.Dispatch(&model.ProductQuantityChanged{
-		ID:           productID,
-		NewQuantity:  product.Quantity,
-		PrevQuantity: product.Quantity + quantity,
-	})
+	type stockKey struct {
+		productID   uuid.UUID
+		warehouseID uuid.UUID
+	}
+	var keys []stockKey
+	allocated := make(map[stockKey]int)
+	for _, movement := range movements {
+		if movement.Reason != model.Sale {
+			continue
+		}
+		key := stockKey{productID: movement.ProductID, warehouseID: movement.WarehouseID}
+		if _, ok := allocated[key]; !ok {
+			keys = append(keys, key)
+		}
+		allocated[key] -= movement.Delta
+	}
+
+	origin := model.StockChangeOrigin{
+		Reason:      model.Return,
+		ReferenceID: orderID,
+		Actor:       orderActor,
+	}
+	for _, key := range keys {
+		if allocated[key] <= 0 {
+			continue
+		}
+		err = p.adjustQuantity(key.productID, key.warehouseID, allocated[key], origin)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (p productService) UpdateProductName(productID uuid.UUID, newName string) error {
@@ -116,25 +445,184 @@ func (p productService) UpdateProductName(productID uuid.UUID, newName string) e
 }
 
 func (p productService) UpdateProductPrice(productID uuid.UUID, newPrice float64) error {
+	if newPrice < 0 {
+		return model.ErrInvalidPrice
+	}
 	product, err := p.repo.Find(productID)
 	if err != nil {
 		return err
 	}
+	prices, err := p.priceRepo.ListByProduct(productID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	currentPrice := product.Price
+	if regular, ok := model.RegularPriceAt(prices, now); ok {
+		currentPrice = regular.Price
+	}
+	if currentPrice == newPrice {
+		return nil
+	}
 
-	product.Price = newPrice
+	price, err := p.newPrice(productID, model.RegularPrice, newPrice, now, nil)
+	if err != nil {
+		return err
+	}
+	err = p.priceRepo.Store(&price)
+	if err != nil {
+		return err
+	}
+	return p.applyDuePrices(product, append(prices, price), now)
+}
+
+func (p productService) SchedulePrice(productID uuid.UUID, price float64, effectiveFrom time.Time) (uuid.UUID, error) {
+	if price < 0 {
+		return uuid.Nil, model.ErrInvalidPrice
+	}
+	product, err := p.repo.Find(productID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	prices, err := p.priceRepo.ListByProduct(productID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	// history must not be rewritten, so past changes start now
+	now := time.Now()
+	if effectiveFrom.Before(now) {
+		effectiveFrom = now
+	}
+	scheduled, err := p.newPrice(productID, model.RegularPrice, price, effectiveFrom, nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	err = p.priceRepo.Store(&scheduled)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return scheduled.ID, p.applyDuePrices(product, append(prices, scheduled), now)
+}
+
+func (p productService) ScheduleSale(productID uuid.UUID, price float64, from, to time.Time) (uuid.UUID, error) {
+	if price < 0 {
+		return uuid.Nil, model.ErrInvalidPrice
+	}
+	now := time.Now()
+	if from.Before(now) {
+		from = now
+	}
+	if !to.After(from) {
+		return uuid.Nil, model.ErrInvalidPricePeriod
+	}
+	product, err := p.repo.Find(productID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	prices, err := p.priceRepo.ListByProduct(productID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	for _, existing := range prices {
+		if existing.Kind == model.SalePrice && existing.EffectiveTo != nil && existing.EffectiveFrom.Before(to) && existing.EffectiveTo.After(from) {
+			return uuid.Nil, model.ErrOverlappingSale
+		}
+	}
+
+	sale, err := p.newPrice(productID, model.SalePrice, price, from, &to)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	err = p.priceRepo.Store(&sale)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return sale.ID, p.applyDuePrices(product, append(prices, sale), now)
+}
+
+func (p productService) ApplyDuePrices(productID uuid.UUID, at time.Time) error {
+	product, err := p.repo.Find(productID)
+	if err != nil {
+		return err
+	}
+	prices, err := p.priceRepo.ListByProduct(productID)
+	if err != nil {
+		return err
+	}
+	return p.applyDuePrices(product, prices, at)
+}
+
+func (p productService) SetReorderThreshold(productID uuid.UUID, threshold int) error {
+	if threshold < 0 {
+		return model.ErrInvalidReorderThreshold
+	}
+	product, err := p.repo.Find(productID)
+	if err != nil {
+		return err
+	}
+	if product.ReorderThreshold == threshold {
+		return nil
+	}
+
+	product.ReorderThreshold = threshold
+	product.UpdatedAt = time.Now()
+	return p.repo.Store(product)
+}
+
+func (p productService) UpdateCatalogInfo(productID uuid.UUID, info model.CatalogInfo) error {
+	product, err := p.repo.Find(productID)
+	if err != nil {
+		return err
+	}
+	if product.ParentID != uuid.Nil {
+		// variants always stay in parent category
+		info.CategoryID = product.CategoryID
+		if info.SKU == "" {
+			return model.ErrEmptySKU
+		}
+	}
+	err = p.checkCatalogInfo(productID, info)
+	if err != nil {
+		return err
+	}
+	if product.SKU == info.SKU && product.CategoryID == info.CategoryID && slices.Equal(product.Attributes, info.Attributes) {
+		return nil
+	}
+
+	product.SKU = info.SKU
+	product.CategoryID = info.CategoryID
+	product.Attributes = info.Attributes
+	product.UpdatedAt = time.Now()
 	err = p.repo.Store(product)
 	if err != nil {
 		return err
 	}
 
-	return p.eventDispatcher.Dispatch(&model.ProductPriceChanged{
-		ID:    product.ID,
-		Price: product.Price,
+	return p.eventDispatcher.Dispatch(&model.ProductCatalogInfoChanged{
+		ID:         product.ID,
+		SKU:        product.SKU,
+		ParentID:   product.ParentID,
+		CategoryID: product.CategoryID,
+		Attributes: product.Attributes,
 	})
 }
 
 func (p productService) DeleteProduct(productID uuid.UUID) error {
-	err := p.repo.Delete(productID)
+	_, err := p.repo.Find(productID)
+	if err != nil {
+		return err
+	}
+	bundleIDs, err := p.bundleRepo.ListBundlesByComponent(productID)
+	if err != nil {
+		return err
+	}
+	if len(bundleIDs) > 0 {
+		return model.ErrProductInBundle
+	}
+
+	err = p.repo.Delete(productID)
 	if err != nil {
 		return err
 	}
@@ -143,3 +631,435 @@ func (p productService) DeleteProduct(productID uuid.UUID) error {
 		ProductID: productID,
 	})
 }
+
+func (p productService) RestoreProduct(productID uuid.UUID) error {
+	product, err := p.repo.FindWithDeleted(productID)
+	if err != nil {
+		return err
+	}
+	if product.DeletedAt == nil {
+		return nil
+	}
+	if product.ParentID != uuid.Nil {
+		_, err = p.repo.Find(product.ParentID)
+		if errors.Is(err, model.ErrProductNotFound) {
+			return model.ErrParentProductDeleted
+		}
+		if err != nil {
+			return err
+		}
+	}
+	components, err := p.bundleRepo.FindComponents(productID)
+	if err != nil {
+		return err
+	}
+	for _, component := range components {
+		_, err = p.repo.Find(component.ProductID)
+		if errors.Is(err, model.ErrProductNotFound) {
+			return model.ErrBundleComponentDeleted
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	product.DeletedAt = nil
+	product.UpdatedAt = time.Now()
+	err = p.repo.Store(product)
+	if err != nil {
+		return err
+	}
+
+	return p.eventDispatcher.Dispatch(&model.ProductRestored{
+		ProductID: productID,
+	})
+}
+
+func (p productService) PurgeProduct(productID uuid.UUID, deletedBefore time.Time) (bool, error) {
+	product, err := p.repo.FindWithDeleted(productID)
+	if err != nil {
+		return false, err
+	}
+	if product.DeletedAt == nil || !product.DeletedAt.Before(deletedBefore) {
+		return false, nil
+	}
+	// parent is purged after its variants, otherwise variants would point to nothing
+	hasVariants, err := p.repo.HasVariants(productID)
+	if err != nil || hasVariants {
+		return false, err
+	}
+	// component is purged after deleted bundles containing it, so they can be restored until then
+	bundleIDs, err := p.bundleRepo.ListBundlesByComponentWithDeleted(productID)
+	if err != nil || len(bundleIDs) > 0 {
+		return false, err
+	}
+
+	err = p.repo.Purge(productID)
+	if err != nil {
+		return false, err
+	}
+
+	return true, p.eventDispatcher.Dispatch(&model.ProductPurged{
+		ProductID: productID,
+		SKU:       product.SKU,
+		DeletedAt: *product.DeletedAt,
+	})
+}
+
+func (p productService) adjustQuantity(productID, warehouseID uuid.UUID, delta int, origin model.StockChangeOrigin) error {
+	product, err := p.repo.Find(productID)
+	if err != nil {
+		return err
+	}
+	warehouseID, err = p.resolveWarehouse(warehouseID)
+	if err != nil {
+		return err
+	}
+
+	prevQuantity := product.Quantity
+	err = p.changeStock(product, warehouseID, delta, origin)
+	if err != nil {
+		return err
+	}
+
+	err = p.eventDispatcher.Dispatch(&model.ProductQuantityChanged{
+		ID:           productID,
+		SKU:          product.SKU,
+		ParentID:     product.ParentID,
+		WarehouseID:  warehouseID,
+		NewQuantity:  product.Quantity,
+		PrevQuantity: prevQuantity,
+		Reason:       origin.Reason,
+	})
+	if err != nil {
+		return err
+	}
+	if delta < 0 {
+		return p.checkReorderThreshold(product, prevQuantity)
+	}
+	return nil
+}
+
+// adjustBundle applies change of bundle quantity to every component at the warehouse
+func (p productService) adjustBundle(bundleID, warehouseID uuid.UUID, delta int, components []model.BundleComponent, origin model.StockChangeOrigin) error {
+	_, err := p.repo.Find(bundleID)
+	if err != nil {
+		return err
+	}
+	warehouseID, err = p.resolveWarehouse(warehouseID)
+	if err != nil {
+		return err
+	}
+
+	expansion := model.ExpandBundle(components, delta)
+	for _, component := range expansion {
+		err = p.adjustQuantity(component.ProductID, warehouseID, component.Quantity, origin)
+		if err != nil {
+			return err
+		}
+	}
+
+	return p.eventDispatcher.Dispatch(&model.BundleStockChanged{
+		BundleID:    bundleID,
+		WarehouseID: warehouseID,
+		Delta:       delta,
+		Reason:      origin.Reason,
+		ReferenceID: origin.ReferenceID,
+		Expansion:   expansion,
+	})
+}
+
+// allocateBundle allocates every component with allocation strategy on its own
+func (p productService) allocateBundle(bundleID uuid.UUID, quantity int, components []model.BundleComponent, origin model.StockChangeOrigin) ([]model.Allocation, error) {
+	expansion := model.ExpandBundle(components, quantity)
+	var allocations []model.Allocation
+	for _, component := range expansion {
+		componentAllocations, err := p.AllocateStock(component.ProductID, component.Quantity, origin)
+		if err != nil {
+			return nil, err
+		}
+		allocations = append(allocations, componentAllocations...)
+	}
+
+	err := p.eventDispatcher.Dispatch(&model.BundleStockChanged{
+		BundleID:    bundleID,
+		Delta:       -quantity,
+		Reason:      origin.Reason,
+		ReferenceID: origin.ReferenceID,
+		Expansion:   model.ExpandBundle(components, -quantity),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return allocations, nil
+}
+
+// checkBundleComponents returns components sorted by product ID, so they are always changed in the same order
+func (p productService) checkBundleComponents(components []model.BundleComponent) ([]model.BundleComponent, error) {
+	if len(components) == 0 {
+		return nil, model.ErrEmptyBundle
+	}
+	result := make([]model.BundleComponent, 0, len(components))
+	seen := make(map[uuid.UUID]struct{}, len(components))
+	for _, component := range components {
+		if _, ok := seen[component.ProductID]; ok || component.Quantity <= 0 {
+			return nil, model.ErrInvalidBundleComponent
+		}
+		seen[component.ProductID] = struct{}{}
+
+		_, err := p.repo.Find(component.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		nested, err := p.bundleRepo.FindComponents(component.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if len(nested) > 0 {
+			return nil, model.ErrNestedBundle
+		}
+		result = append(result, component)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ProductID.String() < result[j].ProductID.String()
+	})
+	return result, nil
+}
+
+// checkReorderThreshold notifies when product total stock crossed reorder threshold or ran out
+func (p productService) checkReorderThreshold(product *model.Product, prevQuantity int) error {
+	if product.Quantity == 0 && prevQuantity > 0 {
+		return p.eventDispatcher.Dispatch(&model.OutOfStock{
+			ProductID:   product.ID,
+			ProductName: product.Name,
+		})
+	}
+	if product.Quantity < product.ReorderThreshold && prevQuantity >= product.ReorderThreshold {
+		return p.eventDispatcher.Dispatch(&model.StockLow{
+			ProductID:        product.ID,
+			ProductName:      product.Name,
+			Quantity:         product.Quantity,
+			ReorderThreshold: product.ReorderThreshold,
+		})
+	}
+	return nil
+}
+
+// changeStock updates warehouse stock level together with product total and records the movement
+func (p productService) changeStock(product *model.Product, warehouseID uuid.UUID, delta int, origin model.StockChangeOrigin) error {
+	levels, err := p.stockRepo.ListByProduct(product.ID)
+	if err != nil {
+		return err
+	}
+	level := findStockLevel(levels, product.ID, warehouseID)
+	if level.Quantity+delta < 0 {
+		return model.ErrProductQuantityLessThanZero
+	}
+
+	level.Quantity += delta
+	err = p.stockRepo.Store(level)
+	if err != nil {
+		return err
+	}
+
+	product.Quantity += delta
+	product.UpdatedAt = time.Now()
+	err = p.repo.Store(product)
+	if err != nil {
+		return err
+	}
+
+	movementID, err := p.movementRepo.NextID()
+	if err != nil {
+		return err
+	}
+	return p.movementRepo.Append(model.StockMovement{
+		ID:            movementID,
+		ProductID:     product.ID,
+		WarehouseID:   warehouseID,
+		Delta:         delta,
+		QuantityAfter: level.Quantity,
+		Reason:        origin.Reason,
+		ReferenceID:   origin.ReferenceID,
+		Actor:         origin.Actor,
+		CreatedAt:     product.UpdatedAt,
+	})
+}
+
+// applyDuePrices marks due prices activated, closes replaced regular prices
+// and updates product price when effective price changed
+func (p productService) applyDuePrices(product *model.Product, prices []model.ProductPrice, at time.Time) error {
+	sort.SliceStable(prices, func(i, j int) bool {
+		return prices[i].EffectiveFrom.Before(prices[j].EffectiveFrom)
+	})
+	for i := range prices {
+		price := &prices[i]
+		changed := false
+		if price.ActivatedAt == nil && !price.EffectiveFrom.After(at) {
+			activatedAt := at
+			price.ActivatedAt = &activatedAt
+			changed = true
+			if price.Kind == model.RegularPrice {
+				err := p.closeRegularPrices(prices[:i], price.EffectiveFrom)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if price.Kind == model.SalePrice && price.ExpiredAt == nil && price.EffectiveTo != nil && !price.EffectiveTo.After(at) {
+			expiredAt := at
+			price.ExpiredAt = &expiredAt
+			changed = true
+		}
+		if changed {
+			err := p.priceRepo.Store(price)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	effectivePrice, ok := model.EffectivePrice(prices, at)
+	if !ok || effectivePrice == product.Price {
+		return nil
+	}
+	product.Price = effectivePrice
+	product.UpdatedAt = time.Now()
+	err := p.repo.Store(product)
+	if err != nil {
+		return err
+	}
+
+	return p.eventDispatcher.Dispatch(&model.ProductPriceChanged{
+		ID:       product.ID,
+		SKU:      product.SKU,
+		ParentID: product.ParentID,
+		Price:    product.Price,
+	})
+}
+
+func (p productService) closeRegularPrices(prices []model.ProductPrice, to time.Time) error {
+	for i := range prices {
+		price := &prices[i]
+		if price.Kind != model.RegularPrice || price.EffectiveTo != nil {
+			continue
+		}
+		effectiveTo := to
+		price.EffectiveTo = &effectiveTo
+		err := p.priceRepo.Store(price)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p productService) newPrice(productID uuid.UUID, kind model.PriceKind, price float64, from time.Time, to *time.Time) (model.ProductPrice, error) {
+	priceID, err := p.priceRepo.NextID()
+	if err != nil {
+		return model.ProductPrice{}, err
+	}
+	return model.ProductPrice{
+		ID:            priceID,
+		ProductID:     productID,
+		Kind:          kind,
+		Price:         price,
+		EffectiveFrom: from,
+		EffectiveTo:   to,
+		CreatedAt:     time.Now(),
+	}, nil
+}
+
+// checkCatalogInfo validates attributes, category existence and sku uniqueness,
+// sku of soft deleted product is still taken so it can be restored
+func (p productService) checkCatalogInfo(productID uuid.UUID, info model.CatalogInfo) error {
+	err := model.ValidateAttributes(info.Attributes)
+	if err != nil {
+		return err
+	}
+	if info.CategoryID != uuid.Nil {
+		if _, err = p.categoryRepo.Find(info.CategoryID); err != nil {
+			return err
+		}
+	}
+	if info.SKU == "" {
+		return nil
+	}
+	existing, err := p.repo.FindBySKUWithDeleted(info.SKU)
+	if err != nil {
+		if errors.Is(err, model.ErrProductNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != productID {
+		return model.ErrSKUAlreadyExists
+	}
+	return nil
+}
+
+func (p productService) resolveWarehouse(warehouseID uuid.UUID) (uuid.UUID, error) {
+	if warehouseID == uuid.Nil {
+		warehouse, err := p.warehouseRepo.FindDefault()
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return warehouse.ID, nil
+	}
+	warehouse, err := p.warehouseRepo.Find(warehouseID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return warehouse.ID, nil
+}
+
+// MergeOrderItems sums quantities of repeated products and sorts items by product ID,
+// the same order must be used for product locks to avoid deadlocks
+func MergeOrderItems(items []model.OrderItem) []model.OrderItem {
+	quantities := make(map[uuid.UUID]int, len(items))
+	result := make([]model.OrderItem, 0, len(items))
+	for _, item := range items {
+		if _, ok := quantities[item.ProductID]; !ok {
+			result = append(result, model.OrderItem{ProductID: item.ProductID})
+		}
+		quantities[item.ProductID] += item.Quantity
+	}
+	for i := range result {
+		result[i].Quantity = quantities[result[i].ProductID]
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ProductID.String() < result[j].ProductID.String()
+	})
+	return result
+}
+
+func hasMovementWithReason(movements []model.StockMovement, reason model.StockChangeReason) bool {
+	for _, movement := range movements {
+		if movement.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+func findStockLevel(levels []model.StockLevel, productID, warehouseID uuid.UUID) model.StockLevel {
+	for _, level := range levels {
+		if level.WarehouseID == warehouseID {
+			return level
+		}
+	}
+	return model.StockLevel{
+		ProductID:   productID,
+		WarehouseID: warehouseID,
+	}
+}
+
+func validateStockAdjustment(quantity int, reason model.StockChangeReason) error {
+	if quantity <= 0 {
+		return model.ErrInvalidStockAdjustment
+	}
+	if !reason.Valid() {
+		return model.ErrUnknownStockChangeReason
+	}
+	return nil
+}
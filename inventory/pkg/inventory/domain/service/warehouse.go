@@ -0,0 +1,63 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"inventory/pkg/common/domain"
+	"inventory/pkg/inventory/domain/model"
+)
+
+type WarehouseService interface {
+	CreateWarehouse(name string, priority int) (uuid.UUID, error)
+	UpdateWarehouse(warehouseID uuid.UUID, name string, priority int) error
+}
+
+func NewWarehouseService(repo model.WarehouseRepository, d domain.EventDispatcher) WarehouseService {
+	return &warehouseService{
+		repo:            repo,
+		eventDispatcher: d,
+	}
+}
+
+type warehouseService struct {
+	repo            model.WarehouseRepository
+	eventDispatcher domain.EventDispatcher
+}
+
+func (w warehouseService) CreateWarehouse(name string, priority int) (uuid.UUID, error) {
+	warehouseID, err := w.repo.NextID()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	currentTime := time.Now()
+	err = w.repo.Store(&model.Warehouse{
+		ID:        warehouseID,
+		Name:      name,
+		Priority:  priority,
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return warehouseID, w.eventDispatcher.Dispatch(&model.WarehouseCreated{
+		ID:   warehouseID,
+		Name: name,
+	})
+}
+
+func (w warehouseService) UpdateWarehouse(warehouseID uuid.UUID, name string, priority int) error {
+	warehouse, err := w.repo.Find(warehouseID)
+	if err != nil {
+		return err
+	}
+
+	warehouse.Name = name
+	warehouse.Priority = priority
+	warehouse.UpdatedAt = time.Now()
+	return w.repo.Store(warehouse)
+}
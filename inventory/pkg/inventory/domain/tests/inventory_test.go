@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"sort"
 	"testing"
 	"time"
 
@@ -13,6 +14,58 @@ import (
 	"inventory/pkg/inventory/domain/service"
 )
 
+func TestAllocationStrategies(t *testing.T) {
+	first := model2.Warehouse{ID: uuid.New(), Priority: 1}
+	second := model2.Warehouse{ID: uuid.New(), Priority: 2}
+	third := model2.Warehouse{ID: uuid.New(), Priority: 3}
+	candidates := []service.AllocationCandidate{
+		{Warehouse: third, Available: 10},
+		{Warehouse: first, Available: 2},
+		{Warehouse: second, Available: 5},
+	}
+
+	testCases := []struct {
+		strategy string
+		quantity int
+		expected []model2.Allocation
+	}{
+		{
+			strategy: service.PriorityAllocation,
+			quantity: 4,
+			expected: []model2.Allocation{{WarehouseID: first.ID, Quantity: 2}, {WarehouseID: second.ID, Quantity: 2}},
+		},
+		{
+			strategy: service.SingleLocationAllocation,
+			quantity: 4,
+			expected: []model2.Allocation{{WarehouseID: second.ID, Quantity: 4}},
+		},
+		{
+			strategy: service.SingleLocationAllocation,
+			quantity: 12,
+			expected: []model2.Allocation{{WarehouseID: third.ID, Quantity: 10}, {WarehouseID: second.ID, Quantity: 2}},
+		},
+		{
+			strategy: service.LargestStockAllocation,
+			quantity: 4,
+			expected: []model2.Allocation{{WarehouseID: third.ID, Quantity: 4}},
+		},
+	}
+	for _, tc := range testCases {
+		strategy, err := service.NewAllocationStrategy(tc.strategy)
+		require.NoError(t, err)
+
+		allocations, err := strategy.Allocate(tc.quantity, candidates)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, allocations, tc.strategy)
+
+		_, err = strategy.Allocate(100, candidates)
+		require.ErrorIs(t, err, model2.ErrProductQuantityLessThanZero)
+	}
+
+	_, err := service.NewAllocationStrategy("unknown")
+	require.ErrorIs(t, err, service.ErrUnknownAllocationStrategy)
+}
+
 func TestProductService(t *testing.T) {
 	repo := &mockProductRepository{
 		store: make(map[uuid.UUID]*model2.Product),
@@ -21,14 +74,42 @@ func TestProductService(t *testing.T) {
 		events: make([]domain.Event, 0),
 	}
 
-	productService := service.NewProductService(repo, eventDispatcher)
+	movementRepo := &mockStockMovementRepository{}
+	stockRepo := &mockStockLevelRepository{
+		store: make(map[uuid.UUID]map[uuid.UUID]int),
+	}
+	defaultWarehouse := model2.Warehouse{ID: uuid.New(), Name: "Main", Priority: 10, IsDefault: true}
+	secondWarehouse := model2.Warehouse{ID: uuid.New(), Name: "Second", Priority: 1}
+	warehouseRepo := &mockWarehouseRepository{
+		store: map[uuid.UUID]*model2.Warehouse{
+			defaultWarehouse.ID: &defaultWarehouse,
+			secondWarehouse.ID:  &secondWarehouse,
+		},
+	}
+	allocationStrategy, err := service.NewAllocationStrategy(service.PriorityAllocation)
+	require.NoError(t, err)
+
+	categoryRepo := &mockCategoryRepository{
+		store: make(map[uuid.UUID]*model2.Category),
+	}
+
+	priceRepo := &mockProductPriceRepository{
+		store: make(map[uuid.UUID]*model2.ProductPrice),
+	}
+
+	bundleRepo := &mockBundleRepository{
+		store:    make(map[uuid.UUID][]model2.BundleComponent),
+		products: repo,
+	}
+
+	productService := service.NewProductService(repo, categoryRepo, priceRepo, bundleRepo, movementRepo, stockRepo, warehouseRepo, allocationStrategy, eventDispatcher)
 
 	name := "Test ProductService"
 	quantity := 1
 	price := 24.9
 
 	t.Run("Create product", func(t *testing.T) {
-		productID, err := productService.CreateProduct(name, quantity, price)
+		productID, err := productService.CreateProduct(name, quantity, price, model2.CatalogInfo{})
 		require.NoError(t, err)
 
 		require.NotNil(t, repo.store[productID])
@@ -42,10 +123,10 @@ func TestProductService(t *testing.T) {
 
 	// nolint:dupl
 	t.Run("Increase product quantity", func(t *testing.T) {
-		productID, err := productService.CreateProduct(name, quantity, price)
+		productID, err := productService.CreateProduct(name, quantity, price, model2.CatalogInfo{})
 		require.NoError(t, err)
 
-		err = productService.IncreaseQuantity(productID, 10)
+		err = productService.IncreaseQuantity(productID, uuid.Nil, 10, model2.StockChangeOrigin{Reason: model2.Receipt})
 		require.NoError(t, err)
 
 		require.NotNil(t, repo.store[productID])
@@ -55,11 +136,39 @@ func TestProductService(t *testing.T) {
 		require.Len(t, eventDispatcher.events, 2)
 		require.Equal(t, model2.ProductCreated{}.Type(), eventDispatcher.events[0].Type())
 		require.Equal(t, model2.ProductQuantityChanged{}.Type(), eventDispatcher.events[1].Type())
+		require.Equal(t, model2.Receipt, eventDispatcher.events[1].(*model2.ProductQuantityChanged).Reason)
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Quantity changes are recorded as stock movements", func(t *testing.T) {
+		productID, err := productService.CreateProduct(name, quantity, price, model2.CatalogInfo{})
+		require.NoError(t, err)
+
+		orderID := uuid.New()
+		err = productService.DecreaseQuantity(productID, uuid.Nil, 1, model2.StockChangeOrigin{
+			Reason:      model2.Sale,
+			ReferenceID: orderID,
+			Actor:       "order",
+		})
+		require.NoError(t, err)
+		err = productService.IncreaseQuantity(productID, uuid.Nil, 5, model2.StockChangeOrigin{Reason: model2.ManualAdjustment, Actor: "warehouse"})
+		require.NoError(t, err)
+
+		movements := movementRepo.ListByProduct(productID)
+		require.Len(t, movements, 3)
+		require.Equal(t, model2.Receipt, movements[0].Reason)
+		require.Equal(t, 1, movements[0].Delta)
+		require.Equal(t, -1, movements[1].Delta)
+		require.Equal(t, 0, movements[1].QuantityAfter)
+		require.Equal(t, orderID, movements[1].ReferenceID)
+		require.Equal(t, "order", movements[1].Actor)
+		require.Equal(t, 5, movements[2].Delta)
+		require.Equal(t, 5, movements[2].QuantityAfter)
 	})
 	eventDispatcher.Reset()
 
 	t.Run("Increase non existed product quantity", func(t *testing.T) {
-		err := productService.IncreaseQuantity(uuid.New(), 10)
+		err := productService.IncreaseQuantity(uuid.New(), uuid.Nil, 10, model2.StockChangeOrigin{Reason: model2.Receipt})
 		require.ErrorIs(t, err, model2.ErrProductNotFound)
 
 		require.Len(t, eventDispatcher.events, 0)
@@ -68,27 +177,61 @@ func TestProductService(t *testing.T) {
 
 	// nolint:dupl
 	t.Run("Decrease product quantity", func(t *testing.T) {
-		productID, err := productService.CreateProduct(name, quantity, price)
+		productID, err := productService.CreateProduct(name, quantity, price, model2.CatalogInfo{})
 		require.NoError(t, err)
 
-		err = productService.DecreaseQuantity(productID, 1)
+		err = productService.DecreaseQuantity(productID, uuid.Nil, 1, model2.StockChangeOrigin{Reason: model2.Sale})
 		require.NoError(t, err)
 
 		require.NotNil(t, repo.store[productID])
 		require.Equal(t, "Test ProductService", repo.store[productID].Name)
 		require.Equal(t, 0, repo.store[productID].Quantity)
 		require.Equal(t, 24.9, repo.store[productID].Price)
-		require.Len(t, eventDispatcher.events, 2)
+		require.Len(t, eventDispatcher.events, 3)
 		require.Equal(t, model2.ProductCreated{}.Type(), eventDispatcher.events[0].Type())
 		require.Equal(t, model2.ProductQuantityChanged{}.Type(), eventDispatcher.events[1].Type())
+		require.Equal(t, model2.OutOfStock{}.Type(), eventDispatcher.events[2].Type())
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Decrease product quantity below reorder threshold", func(t *testing.T) {
+		productID, err := productService.CreateProduct(name, 10, price, model2.CatalogInfo{})
+		require.NoError(t, err)
+		err = productService.SetReorderThreshold(productID, 5)
+		require.NoError(t, err)
+		eventDispatcher.Reset()
+
+		err = productService.DecreaseQuantity(productID, uuid.Nil, 5, model2.StockChangeOrigin{Reason: model2.Sale})
+		require.NoError(t, err)
+		require.Len(t, eventDispatcher.events, 1)
+
+		err = productService.DecreaseQuantity(productID, uuid.Nil, 1, model2.StockChangeOrigin{Reason: model2.Sale})
+		require.NoError(t, err)
+		require.Len(t, eventDispatcher.events, 3)
+		require.Equal(t, model2.StockLow{}.Type(), eventDispatcher.events[2].Type())
+		stockLow := eventDispatcher.events[2].(*model2.StockLow)
+		require.Equal(t, 4, stockLow.Quantity)
+		require.Equal(t, 5, stockLow.ReorderThreshold)
+
+		// already below threshold, no repeated notification
+		err = productService.DecreaseQuantity(productID, uuid.Nil, 1, model2.StockChangeOrigin{Reason: model2.Sale})
+		require.NoError(t, err)
+		require.Len(t, eventDispatcher.events, 4)
+
+		_, err = productService.AllocateStock(productID, 3, model2.StockChangeOrigin{Reason: model2.Sale})
+		require.NoError(t, err)
+		require.Equal(t, model2.OutOfStock{}.Type(), eventDispatcher.events[len(eventDispatcher.events)-1].Type())
+
+		err = productService.SetReorderThreshold(productID, -1)
+		require.ErrorIs(t, err, model2.ErrInvalidReorderThreshold)
 	})
 	eventDispatcher.Reset()
 
 	t.Run("Decrease product quantity to less than zero", func(t *testing.T) {
-		productID, err := productService.CreateProduct(name, quantity, price)
+		productID, err := productService.CreateProduct(name, quantity, price, model2.CatalogInfo{})
 		require.NoError(t, err)
 
-		err = productService.DecreaseQuantity(productID, 2)
+		err = productService.DecreaseQuantity(productID, uuid.Nil, 2, model2.StockChangeOrigin{Reason: model2.Sale})
 		require.ErrorIs(t, err, model2.ErrProductQuantityLessThanZero)
 
 		require.NotNil(t, repo.store[productID])
@@ -101,15 +244,325 @@ func TestProductService(t *testing.T) {
 	eventDispatcher.Reset()
 
 	t.Run("Decrease non existed product quantity", func(t *testing.T) {
-		err := productService.DecreaseQuantity(uuid.New(), 10)
+		err := productService.DecreaseQuantity(uuid.New(), uuid.Nil, 10, model2.StockChangeOrigin{Reason: model2.Sale})
 		require.ErrorIs(t, err, model2.ErrProductNotFound)
 
 		require.Len(t, eventDispatcher.events, 0)
 	})
 	eventDispatcher.Reset()
 
+	t.Run("Adjust product quantity with invalid arguments", func(t *testing.T) {
+		productID, err := productService.CreateProduct(name, quantity, price, model2.CatalogInfo{})
+		require.NoError(t, err)
+
+		err = productService.IncreaseQuantity(productID, uuid.Nil, 0, model2.StockChangeOrigin{Reason: model2.Receipt})
+		require.ErrorIs(t, err, model2.ErrInvalidStockAdjustment)
+
+		err = productService.DecreaseQuantity(productID, uuid.Nil, -1, model2.StockChangeOrigin{Reason: model2.Sale})
+		require.ErrorIs(t, err, model2.ErrInvalidStockAdjustment)
+
+		err = productService.IncreaseQuantity(productID, uuid.Nil, 1, model2.StockChangeOrigin{Reason: model2.StockChangeReason(100)})
+		require.ErrorIs(t, err, model2.ErrUnknownStockChangeReason)
+
+		require.Equal(t, 1, repo.store[productID].Quantity)
+		require.Len(t, eventDispatcher.events, 1)
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Stock is tracked per warehouse", func(t *testing.T) {
+		productID, err := productService.CreateProduct(name, quantity, price, model2.CatalogInfo{})
+		require.NoError(t, err)
+
+		err = productService.IncreaseQuantity(productID, secondWarehouse.ID, 4, model2.StockChangeOrigin{Reason: model2.Receipt})
+		require.NoError(t, err)
+
+		require.Equal(t, 5, repo.store[productID].Quantity)
+		require.Equal(t, 1, stockRepo.store[productID][defaultWarehouse.ID])
+		require.Equal(t, 4, stockRepo.store[productID][secondWarehouse.ID])
+
+		err = productService.DecreaseQuantity(productID, defaultWarehouse.ID, 2, model2.StockChangeOrigin{Reason: model2.Sale})
+		require.ErrorIs(t, err, model2.ErrProductQuantityLessThanZero)
+
+		err = productService.IncreaseQuantity(productID, uuid.New(), 1, model2.StockChangeOrigin{Reason: model2.Receipt})
+		require.ErrorIs(t, err, model2.ErrWarehouseNotFound)
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Transfer stock between warehouses", func(t *testing.T) {
+		productID, err := productService.CreateProduct(name, 3, price, model2.CatalogInfo{})
+		require.NoError(t, err)
+
+		transferID, err := productService.TransferStock(productID, defaultWarehouse.ID, secondWarehouse.ID, 2, "warehouse")
+		require.NoError(t, err)
+
+		require.Equal(t, 3, repo.store[productID].Quantity)
+		require.Equal(t, 1, stockRepo.store[productID][defaultWarehouse.ID])
+		require.Equal(t, 2, stockRepo.store[productID][secondWarehouse.ID])
+
+		movements := movementRepo.ListByProduct(productID)
+		require.Len(t, movements, 3)
+		require.Equal(t, -2, movements[1].Delta)
+		require.Equal(t, 2, movements[2].Delta)
+		require.Equal(t, transferID, movements[1].ReferenceID)
+		require.Equal(t, transferID, movements[2].ReferenceID)
+		require.Equal(t, model2.StockTransferred{}.Type(), eventDispatcher.events[len(eventDispatcher.events)-1].Type())
+
+		_, err = productService.TransferStock(productID, defaultWarehouse.ID, defaultWarehouse.ID, 1, "warehouse")
+		require.ErrorIs(t, err, model2.ErrSameWarehouseTransfer)
+		_, err = productService.TransferStock(productID, defaultWarehouse.ID, secondWarehouse.ID, 5, "warehouse")
+		require.ErrorIs(t, err, model2.ErrProductQuantityLessThanZero)
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Allocate stock by warehouse priority", func(t *testing.T) {
+		productID, err := productService.CreateProduct(name, 3, price, model2.CatalogInfo{})
+		require.NoError(t, err)
+		err = productService.IncreaseQuantity(productID, secondWarehouse.ID, 2, model2.StockChangeOrigin{Reason: model2.Receipt})
+		require.NoError(t, err)
+
+		orderID := uuid.New()
+		allocations, err := productService.AllocateStock(productID, 4, model2.StockChangeOrigin{Reason: model2.Sale, ReferenceID: orderID})
+		require.NoError(t, err)
+		require.Equal(t, []model2.Allocation{
+			{ProductID: productID, WarehouseID: secondWarehouse.ID, Quantity: 2},
+			{ProductID: productID, WarehouseID: defaultWarehouse.ID, Quantity: 2},
+		}, allocations)
+		require.Equal(t, 1, repo.store[productID].Quantity)
+
+		_, err = productService.AllocateStock(productID, 2, model2.StockChangeOrigin{Reason: model2.Sale})
+		require.ErrorIs(t, err, model2.ErrProductQuantityLessThanZero)
+		require.Equal(t, 1, repo.store[productID].Quantity)
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Allocate and release order", func(t *testing.T) {
+		firstProductID, err := productService.CreateProduct(name, 5, price, model2.CatalogInfo{})
+		require.NoError(t, err)
+		secondProductID, err := productService.CreateProduct(name, 5, price, model2.CatalogInfo{})
+		require.NoError(t, err)
+
+		orderID := uuid.New()
+		items := []model2.OrderItem{
+			{ProductID: firstProductID, Quantity: 1},
+			{ProductID: secondProductID, Quantity: 2},
+			{ProductID: firstProductID, Quantity: 2},
+		}
+		require.NoError(t, productService.AllocateOrder(orderID, items))
+		require.Equal(t, 2, repo.store[firstProductID].Quantity)
+		require.Equal(t, 3, repo.store[secondProductID].Quantity)
+
+		// redelivered order_paid must not allocate stock twice
+		require.NoError(t, productService.AllocateOrder(orderID, items))
+		require.Equal(t, 2, repo.store[firstProductID].Quantity)
+
+		require.NoError(t, productService.ReleaseOrder(orderID))
+		require.Equal(t, 5, repo.store[firstProductID].Quantity)
+		require.Equal(t, 5, repo.store[secondProductID].Quantity)
+		movements := movementRepo.ListByProduct(firstProductID)
+		require.Equal(t, model2.Return, movements[len(movements)-1].Reason)
+		require.Equal(t, orderID, movements[len(movements)-1].ReferenceID)
+
+		require.NoError(t, productService.ReleaseOrder(orderID))
+		require.Equal(t, 5, repo.store[firstProductID].Quantity)
+
+		err = productService.AllocateOrder(uuid.New(), []model2.OrderItem{{ProductID: firstProductID, Quantity: 6}})
+		require.ErrorIs(t, err, model2.ErrProductQuantityLessThanZero)
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Release not allocated order", func(t *testing.T) {
+		require.NoError(t, productService.ReleaseOrder(uuid.New()))
+		require.Empty(t, eventDispatcher.events)
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Create product with catalog info", func(t *testing.T) {
+		categoryID := uuid.New()
+		categoryRepo.store[categoryID] = &model2.Category{ID: categoryID, Name: "Shirts"}
+		info := model2.CatalogInfo{
+			SKU:        "SHIRT",
+			CategoryID: categoryID,
+			Attributes: []model2.Attribute{{Name: "material", Type: model2.StringAttribute, Value: "cotton"}},
+		}
+
+		productID, err := productService.CreateProduct(name, 0, price, info)
+		require.NoError(t, err)
+		require.Equal(t, "SHIRT", repo.store[productID].SKU)
+		require.Equal(t, categoryID, repo.store[productID].CategoryID)
+		created := eventDispatcher.events[0].(*model2.ProductCreated)
+		require.Equal(t, "SHIRT", created.SKU)
+		require.Equal(t, info.Attributes, created.Attributes)
+
+		_, err = productService.CreateProduct(name, 0, price, model2.CatalogInfo{SKU: "SHIRT"})
+		require.ErrorIs(t, err, model2.ErrSKUAlreadyExists)
+
+		_, err = productService.CreateProduct(name, 0, price, model2.CatalogInfo{CategoryID: uuid.New()})
+		require.ErrorIs(t, err, model2.ErrCategoryNotFound)
+
+		_, err = productService.CreateProduct(name, 0, price, model2.CatalogInfo{
+			Attributes: []model2.Attribute{{Name: "weight", Type: model2.NumberAttribute, Value: "heavy"}},
+		})
+		require.ErrorIs(t, err, model2.ErrInvalidAttribute)
+
+		_, err = productService.CreateProduct(name, 0, price, model2.CatalogInfo{
+			Attributes: []model2.Attribute{
+				{Name: "size", Type: model2.StringAttribute, Value: "M"},
+				{Name: "size", Type: model2.StringAttribute, Value: "L"},
+			},
+		})
+		require.ErrorIs(t, err, model2.ErrDuplicateAttribute)
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Create product variants", func(t *testing.T) {
+		categoryID := uuid.New()
+		categoryRepo.store[categoryID] = &model2.Category{ID: categoryID, Name: "Hoodies"}
+		parentID, err := productService.CreateProduct("Hoodie", 0, price, model2.CatalogInfo{SKU: "HOODIE", CategoryID: categoryID})
+		require.NoError(t, err)
+
+		variantID, err := productService.CreateVariant(parentID, model2.VariantSpec{
+			SKU:        "HOODIE-RED-M",
+			Attributes: []model2.Attribute{{Name: "colour", Type: model2.StringAttribute, Value: "red"}},
+			Quantity:   5,
+			Price:      30,
+		})
+		require.NoError(t, err)
+		variant := repo.store[variantID]
+		require.Equal(t, parentID, variant.ParentID)
+		require.Equal(t, categoryID, variant.CategoryID)
+		require.Equal(t, "Hoodie", variant.Name)
+		require.Equal(t, 5, variant.Quantity)
+		require.Equal(t, 30.0, variant.Price)
+		require.Equal(t, 0, repo.store[parentID].Quantity)
+		created := eventDispatcher.events[len(eventDispatcher.events)-1].(*model2.ProductCreated)
+		require.Equal(t, parentID, created.ParentID)
+		require.Equal(t, "HOODIE-RED-M", created.SKU)
+
+		err = productService.DecreaseQuantity(variantID, uuid.Nil, 2, model2.StockChangeOrigin{Reason: model2.Sale})
+		require.NoError(t, err)
+		changed := eventDispatcher.events[len(eventDispatcher.events)-1].(*model2.ProductQuantityChanged)
+		require.Equal(t, "HOODIE-RED-M", changed.SKU)
+		require.Equal(t, parentID, changed.ParentID)
+
+		_, err = productService.CreateVariant(parentID, model2.VariantSpec{SKU: ""})
+		require.ErrorIs(t, err, model2.ErrEmptySKU)
+		_, err = productService.CreateVariant(parentID, model2.VariantSpec{SKU: "HOODIE-RED-M"})
+		require.ErrorIs(t, err, model2.ErrSKUAlreadyExists)
+		_, err = productService.CreateVariant(variantID, model2.VariantSpec{SKU: "HOODIE-RED-M-2"})
+		require.ErrorIs(t, err, model2.ErrNestedVariant)
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Update catalog info", func(t *testing.T) {
+		productID, err := productService.CreateProduct(name, 0, price, model2.CatalogInfo{})
+		require.NoError(t, err)
+
+		info := model2.CatalogInfo{SKU: "MUG", Attributes: []model2.Attribute{{Name: "fragile", Type: model2.BooleanAttribute, Value: "true"}}}
+		err = productService.UpdateCatalogInfo(productID, info)
+		require.NoError(t, err)
+		require.Equal(t, "MUG", repo.store[productID].SKU)
+		require.Len(t, eventDispatcher.events, 2)
+		require.Equal(t, model2.ProductCatalogInfoChanged{}.Type(), eventDispatcher.events[1].Type())
+
+		// unchanged catalog info does not produce event
+		err = productService.UpdateCatalogInfo(productID, info)
+		require.NoError(t, err)
+		require.Len(t, eventDispatcher.events, 2)
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Update product price keeps history", func(t *testing.T) {
+		productID, err := productService.CreateProduct(name, 0, 10, model2.CatalogInfo{})
+		require.NoError(t, err)
+
+		err = productService.UpdateProductPrice(productID, 12)
+		require.NoError(t, err)
+		require.Equal(t, 12.0, repo.store[productID].Price)
+		require.Equal(t, model2.ProductPriceChanged{}.Type(), eventDispatcher.events[1].Type())
+
+		prices, err := priceRepo.ListByProduct(productID)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+		require.NotNil(t, prices[0].EffectiveTo)
+		require.Nil(t, prices[1].EffectiveTo)
+
+		// same price does not create history row
+		err = productService.UpdateProductPrice(productID, 12)
+		require.NoError(t, err)
+		require.Len(t, priceRepo.ListByProductIgnoringErr(productID), 2)
+		require.Len(t, eventDispatcher.events, 2)
+
+		err = productService.UpdateProductPrice(productID, -1)
+		require.ErrorIs(t, err, model2.ErrInvalidPrice)
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Scheduled price is activated when due", func(t *testing.T) {
+		productID, err := productService.CreateProduct(name, 0, 10, model2.CatalogInfo{})
+		require.NoError(t, err)
+		effectiveFrom := time.Now().Add(time.Hour)
+
+		_, err = productService.SchedulePrice(productID, 15, effectiveFrom)
+		require.NoError(t, err)
+		require.Equal(t, 10.0, repo.store[productID].Price)
+		require.Len(t, eventDispatcher.events, 1)
+
+		dueProducts, err := priceRepo.ListProductsWithDuePrices(effectiveFrom)
+		require.NoError(t, err)
+		require.Contains(t, dueProducts, productID)
+
+		err = productService.ApplyDuePrices(productID, effectiveFrom)
+		require.NoError(t, err)
+		require.Equal(t, 15.0, repo.store[productID].Price)
+		require.Len(t, eventDispatcher.events, 2)
+		require.Equal(t, 15.0, eventDispatcher.events[1].(*model2.ProductPriceChanged).Price)
+
+		dueProducts, err = priceRepo.ListProductsWithDuePrices(effectiveFrom)
+		require.NoError(t, err)
+		require.NotContains(t, dueProducts, productID)
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Sale price overrides regular price within period", func(t *testing.T) {
+		productID, err := productService.CreateProduct(name, 0, 10, model2.CatalogInfo{})
+		require.NoError(t, err)
+		from := time.Now().Add(time.Hour)
+		to := from.Add(24 * time.Hour)
+
+		_, err = productService.ScheduleSale(productID, 7, from, to)
+		require.NoError(t, err)
+		_, err = productService.ScheduleSale(productID, 5, from.Add(time.Hour), to.Add(time.Hour))
+		require.ErrorIs(t, err, model2.ErrOverlappingSale)
+		_, err = productService.ScheduleSale(productID, 5, to.Add(time.Hour), to)
+		require.ErrorIs(t, err, model2.ErrInvalidPricePeriod)
+
+		err = productService.ApplyDuePrices(productID, from)
+		require.NoError(t, err)
+		require.Equal(t, 7.0, repo.store[productID].Price)
+
+		// regular price changed during sale is applied after sale ends
+		_, err = productService.SchedulePrice(productID, 11, from.Add(30*time.Minute))
+		require.NoError(t, err)
+		err = productService.ApplyDuePrices(productID, from.Add(time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, 7.0, repo.store[productID].Price)
+
+		err = productService.ApplyDuePrices(productID, to)
+		require.NoError(t, err)
+		require.Equal(t, 11.0, repo.store[productID].Price)
+
+		prices := priceRepo.ListByProductIgnoringErr(productID)
+		price, ok := model2.EffectivePrice(prices, from.Add(time.Minute))
+		require.True(t, ok)
+		require.Equal(t, 7.0, price)
+		price, ok = model2.EffectivePrice(prices, to)
+		require.True(t, ok)
+		require.Equal(t, 11.0, price)
+	})
+	eventDispatcher.Reset()
+
 	t.Run("Delete product", func(t *testing.T) {
-		productID, err := productService.CreateProduct(name, quantity, price)
+		productID, err := productService.CreateProduct(name, quantity, price, model2.CatalogInfo{})
 		require.NoError(t, err)
 
 		err = productService.DeleteProduct(productID)
@@ -123,6 +576,215 @@ func TestProductService(t *testing.T) {
 	})
 	eventDispatcher.Reset()
 
+	t.Run("Restore deleted product", func(t *testing.T) {
+		productID, err := productService.CreateProduct(name, quantity, price, model2.CatalogInfo{SKU: "restore-parent"})
+		require.NoError(t, err)
+		variantID, err := productService.CreateVariant(productID, model2.VariantSpec{SKU: "restore-variant"})
+		require.NoError(t, err)
+		require.NoError(t, productService.DeleteProduct(variantID))
+		require.NoError(t, productService.DeleteProduct(productID))
+		eventDispatcher.Reset()
+
+		err = productService.RestoreProduct(variantID)
+		require.ErrorIs(t, err, model2.ErrParentProductDeleted)
+
+		require.NoError(t, productService.RestoreProduct(productID))
+		require.NoError(t, productService.RestoreProduct(variantID))
+		require.Nil(t, repo.store[productID].DeletedAt)
+		require.Nil(t, repo.store[variantID].DeletedAt)
+		require.Len(t, eventDispatcher.events, 2)
+		require.Equal(t, model2.ProductRestored{}.Type(), eventDispatcher.events[0].Type())
+
+		// restoring not deleted product changes nothing
+		require.NoError(t, productService.RestoreProduct(productID))
+		require.Len(t, eventDispatcher.events, 2)
+	})
+	eventDispatcher.Reset()
+
+	t.Run("SKU of deleted product stays taken", func(t *testing.T) {
+		productID, err := productService.CreateProduct(name, quantity, price, model2.CatalogInfo{SKU: "deleted-sku"})
+		require.NoError(t, err)
+		require.NoError(t, productService.DeleteProduct(productID))
+
+		_, err = productService.CreateProduct(name, quantity, price, model2.CatalogInfo{SKU: "deleted-sku"})
+		require.ErrorIs(t, err, model2.ErrSKUAlreadyExists)
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Purge products deleted before retention period", func(t *testing.T) {
+		productID, err := productService.CreateProduct(name, quantity, price, model2.CatalogInfo{SKU: "purge-parent"})
+		require.NoError(t, err)
+		variantID, err := productService.CreateVariant(productID, model2.VariantSpec{SKU: "purge-variant"})
+		require.NoError(t, err)
+		require.NoError(t, productService.DeleteProduct(productID))
+		require.NoError(t, productService.DeleteProduct(variantID))
+		eventDispatcher.Reset()
+
+		purged, err := productService.PurgeProduct(productID, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		require.False(t, purged)
+
+		// parent waits until its variants are purged
+		purged, err = productService.PurgeProduct(productID, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.False(t, purged)
+
+		purged, err = productService.PurgeProduct(variantID, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.True(t, purged)
+		purged, err = productService.PurgeProduct(productID, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.True(t, purged)
+
+		require.Nil(t, repo.store[productID])
+		require.Nil(t, repo.store[variantID])
+		require.Len(t, eventDispatcher.events, 2)
+		require.Equal(t, model2.ProductPurged{}.Type(), eventDispatcher.events[0].Type())
+		require.Equal(t, variantID, eventDispatcher.events[0].(*model2.ProductPurged).ProductID)
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Create bundle", func(t *testing.T) {
+		firstProductID, err := productService.CreateProduct(name, 10, price, model2.CatalogInfo{})
+		require.NoError(t, err)
+		secondProductID, err := productService.CreateProduct(name, 10, price, model2.CatalogInfo{})
+		require.NoError(t, err)
+		eventDispatcher.Reset()
+
+		bundleID, err := productService.CreateBundle("Kit", price, model2.CatalogInfo{SKU: "kit"}, []model2.BundleComponent{
+			{ProductID: firstProductID, Quantity: 2},
+			{ProductID: secondProductID, Quantity: 1},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 0, repo.store[bundleID].Quantity)
+		require.Len(t, bundleRepo.store[bundleID], 2)
+		require.Equal(t, model2.BundleCreated{}.Type(), eventDispatcher.events[len(eventDispatcher.events)-1].Type())
+
+		_, err = productService.CreateBundle("Empty kit", price, model2.CatalogInfo{}, nil)
+		require.ErrorIs(t, err, model2.ErrEmptyBundle)
+		_, err = productService.CreateBundle("Invalid kit", price, model2.CatalogInfo{}, []model2.BundleComponent{
+			{ProductID: firstProductID, Quantity: 0},
+		})
+		require.ErrorIs(t, err, model2.ErrInvalidBundleComponent)
+		_, err = productService.CreateBundle("Nested kit", price, model2.CatalogInfo{}, []model2.BundleComponent{
+			{ProductID: bundleID, Quantity: 1},
+		})
+		require.ErrorIs(t, err, model2.ErrNestedBundle)
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Bundle stock changes are applied to components", func(t *testing.T) {
+		firstProductID, err := productService.CreateProduct(name, 10, price, model2.CatalogInfo{})
+		require.NoError(t, err)
+		secondProductID, err := productService.CreateProduct(name, 3, price, model2.CatalogInfo{})
+		require.NoError(t, err)
+		bundleID, err := productService.CreateBundle("Kit", price, model2.CatalogInfo{}, []model2.BundleComponent{
+			{ProductID: firstProductID, Quantity: 2},
+			{ProductID: secondProductID, Quantity: 1},
+		})
+		require.NoError(t, err)
+		eventDispatcher.Reset()
+
+		orderID := uuid.New()
+		err = productService.DecreaseQuantity(bundleID, uuid.Nil, 2, model2.StockChangeOrigin{Reason: model2.Reservation, ReferenceID: orderID})
+		require.NoError(t, err)
+		require.Equal(t, 6, repo.store[firstProductID].Quantity)
+		require.Equal(t, 1, repo.store[secondProductID].Quantity)
+		require.Equal(t, 0, repo.store[bundleID].Quantity)
+
+		changed := eventDispatcher.events[len(eventDispatcher.events)-1].(*model2.BundleStockChanged)
+		require.Equal(t, bundleID, changed.BundleID)
+		require.Equal(t, -2, changed.Delta)
+		require.ElementsMatch(t, []model2.BundleComponent{
+			{ProductID: firstProductID, Quantity: -4},
+			{ProductID: secondProductID, Quantity: -2},
+		}, changed.Expansion)
+
+		require.NoError(t, productService.IncreaseQuantity(bundleID, uuid.Nil, 1, model2.StockChangeOrigin{Reason: model2.Return, ReferenceID: orderID}))
+		require.Equal(t, 8, repo.store[firstProductID].Quantity)
+		require.Equal(t, 2, repo.store[secondProductID].Quantity)
+
+		_, err = productService.TransferStock(bundleID, defaultWarehouse.ID, secondWarehouse.ID, 1, "warehouse")
+		require.ErrorIs(t, err, model2.ErrBundleStock)
+
+		err = productService.DecreaseQuantity(bundleID, uuid.Nil, 3, model2.StockChangeOrigin{Reason: model2.Reservation})
+		require.ErrorIs(t, err, model2.ErrProductQuantityLessThanZero)
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Allocate bundle", func(t *testing.T) {
+		firstProductID, err := productService.CreateProduct(name, 4, price, model2.CatalogInfo{})
+		require.NoError(t, err)
+		secondProductID, err := productService.CreateProduct(name, 4, price, model2.CatalogInfo{})
+		require.NoError(t, err)
+		bundleID, err := productService.CreateBundle("Kit", price, model2.CatalogInfo{}, []model2.BundleComponent{
+			{ProductID: firstProductID, Quantity: 1},
+			{ProductID: secondProductID, Quantity: 2},
+		})
+		require.NoError(t, err)
+
+		allocations, err := productService.AllocateStock(bundleID, 2, model2.StockChangeOrigin{Reason: model2.Sale, ReferenceID: uuid.New()})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []model2.Allocation{
+			{ProductID: firstProductID, WarehouseID: defaultWarehouse.ID, Quantity: 2},
+			{ProductID: secondProductID, WarehouseID: defaultWarehouse.ID, Quantity: 4},
+		}, allocations)
+		require.Equal(t, 2, repo.store[firstProductID].Quantity)
+		require.Equal(t, 0, repo.store[secondProductID].Quantity)
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Delete bundle component", func(t *testing.T) {
+		productID, err := productService.CreateProduct(name, quantity, price, model2.CatalogInfo{})
+		require.NoError(t, err)
+		bundleID, err := productService.CreateBundle("Kit", price, model2.CatalogInfo{}, []model2.BundleComponent{
+			{ProductID: productID, Quantity: 1},
+		})
+		require.NoError(t, err)
+
+		err = productService.DeleteProduct(productID)
+		require.ErrorIs(t, err, model2.ErrProductInBundle)
+
+		require.NoError(t, productService.DeleteProduct(bundleID))
+		require.NoError(t, productService.DeleteProduct(productID))
+	})
+	eventDispatcher.Reset()
+
+	t.Run("Restore and purge deleted bundle", func(t *testing.T) {
+		productID, err := productService.CreateProduct(name, quantity, price, model2.CatalogInfo{})
+		require.NoError(t, err)
+		bundleID, err := productService.CreateBundle("Kit", price, model2.CatalogInfo{}, []model2.BundleComponent{
+			{ProductID: productID, Quantity: 1},
+		})
+		require.NoError(t, err)
+		require.NoError(t, productService.DeleteProduct(bundleID))
+		require.NoError(t, productService.DeleteProduct(productID))
+		eventDispatcher.Reset()
+
+		err = productService.RestoreProduct(bundleID)
+		require.ErrorIs(t, err, model2.ErrBundleComponentDeleted)
+		require.NotNil(t, repo.store[bundleID].DeletedAt)
+
+		// component waits until deleted bundle is purged
+		purged, err := productService.PurgeProduct(productID, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.False(t, purged)
+
+		require.NoError(t, productService.RestoreProduct(productID))
+		require.NoError(t, productService.RestoreProduct(bundleID))
+		require.Nil(t, repo.store[bundleID].DeletedAt)
+
+		require.NoError(t, productService.DeleteProduct(bundleID))
+		require.NoError(t, productService.DeleteProduct(productID))
+		purged, err = productService.PurgeProduct(bundleID, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.True(t, purged)
+		purged, err = productService.PurgeProduct(productID, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.True(t, purged)
+	})
+	eventDispatcher.Reset()
+
 	t.Run("Delete non existed product", func(t *testing.T) {
 		newID, _ := repo.NextID()
 		err := productService.DeleteProduct(newID)
@@ -159,6 +821,15 @@ func (m *mockProductRepository) Find(id uuid.UUID) (*model2.Product, error) {
 	return product, nil
 }
 
+func (m *mockProductRepository) FindBySKU(sku string) (*model2.Product, error) {
+	for _, product := range m.store {
+		if product.SKU == sku && product.DeletedAt == nil {
+			return product, nil
+		}
+	}
+	return nil, model2.ErrProductNotFound
+}
+
 func (m *mockProductRepository) List() ([]model2.Product, error) {
 	var res []model2.Product
 	for _, product := range m.store {
@@ -179,6 +850,259 @@ func (m *mockProductRepository) Delete(id uuid.UUID) error {
 	return nil
 }
 
+func (m *mockProductRepository) FindWithDeleted(id uuid.UUID) (*model2.Product, error) {
+	product, ok := m.store[id]
+	if !ok {
+		return nil, model2.ErrProductNotFound
+	}
+	return product, nil
+}
+
+func (m *mockProductRepository) FindBySKUWithDeleted(sku string) (*model2.Product, error) {
+	for _, product := range m.store {
+		if product.SKU == sku {
+			return product, nil
+		}
+	}
+	return nil, model2.ErrProductNotFound
+}
+
+func (m *mockProductRepository) ListDeletedBefore(before time.Time) ([]uuid.UUID, error) {
+	var res []uuid.UUID
+	for _, product := range m.store {
+		if product.DeletedAt != nil && product.DeletedAt.Before(before) {
+			res = append(res, product.ID)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockProductRepository) HasVariants(id uuid.UUID) (bool, error) {
+	for _, product := range m.store {
+		if product.ParentID == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockProductRepository) Purge(id uuid.UUID) error {
+	delete(m.store, id)
+	return nil
+}
+
+var _ model2.StockMovementRepository = &mockStockMovementRepository{}
+
+type mockStockMovementRepository struct {
+	movements []model2.StockMovement
+}
+
+func (m *mockStockMovementRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (m *mockStockMovementRepository) Append(movement model2.StockMovement) error {
+	m.movements = append(m.movements, movement)
+	return nil
+}
+
+func (m *mockStockMovementRepository) ListByReference(referenceID uuid.UUID) ([]model2.StockMovement, error) {
+	var res []model2.StockMovement
+	for _, movement := range m.movements {
+		if movement.ReferenceID == referenceID {
+			res = append(res, movement)
+		}
+	}
+	return res, nil
+}
+
+func (m *mockStockMovementRepository) ListByProduct(productID uuid.UUID) []model2.StockMovement {
+	var res []model2.StockMovement
+	for _, movement := range m.movements {
+		if movement.ProductID == productID {
+			res = append(res, movement)
+		}
+	}
+	return res
+}
+
+var _ model2.StockLevelRepository = &mockStockLevelRepository{}
+
+type mockStockLevelRepository struct {
+	store map[uuid.UUID]map[uuid.UUID]int
+}
+
+func (m *mockStockLevelRepository) ListByProduct(productID uuid.UUID) ([]model2.StockLevel, error) {
+	var res []model2.StockLevel
+	for warehouseID, quantity := range m.store[productID] {
+		res = append(res, model2.StockLevel{ProductID: productID, WarehouseID: warehouseID, Quantity: quantity})
+	}
+	return res, nil
+}
+
+func (m *mockStockLevelRepository) Store(level model2.StockLevel) error {
+	if _, ok := m.store[level.ProductID]; !ok {
+		m.store[level.ProductID] = make(map[uuid.UUID]int)
+	}
+	m.store[level.ProductID][level.WarehouseID] = level.Quantity
+	return nil
+}
+
+var _ model2.WarehouseRepository = &mockWarehouseRepository{}
+
+type mockWarehouseRepository struct {
+	store map[uuid.UUID]*model2.Warehouse
+}
+
+func (m *mockWarehouseRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (m *mockWarehouseRepository) Store(warehouse *model2.Warehouse) error {
+	m.store[warehouse.ID] = warehouse
+	return nil
+}
+
+func (m *mockWarehouseRepository) Find(id uuid.UUID) (*model2.Warehouse, error) {
+	warehouse, ok := m.store[id]
+	if !ok {
+		return nil, model2.ErrWarehouseNotFound
+	}
+	return warehouse, nil
+}
+
+func (m *mockWarehouseRepository) FindDefault() (*model2.Warehouse, error) {
+	for _, warehouse := range m.store {
+		if warehouse.IsDefault {
+			return warehouse, nil
+		}
+	}
+	return nil, model2.ErrDefaultWarehouseNotFound
+}
+
+func (m *mockWarehouseRepository) List() ([]model2.Warehouse, error) {
+	res := make([]model2.Warehouse, 0, len(m.store))
+	for _, warehouse := range m.store {
+		res = append(res, *warehouse)
+	}
+	return res, nil
+}
+
+var _ model2.ProductPriceRepository = &mockProductPriceRepository{}
+
+type mockProductPriceRepository struct {
+	store map[uuid.UUID]*model2.ProductPrice
+}
+
+func (m *mockProductPriceRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (m *mockProductPriceRepository) Store(price *model2.ProductPrice) error {
+	stored := *price
+	m.store[price.ID] = &stored
+	return nil
+}
+
+func (m *mockProductPriceRepository) ListByProduct(productID uuid.UUID) ([]model2.ProductPrice, error) {
+	return m.ListByProductIgnoringErr(productID), nil
+}
+
+func (m *mockProductPriceRepository) ListByProductIgnoringErr(productID uuid.UUID) []model2.ProductPrice {
+	var res []model2.ProductPrice
+	for _, price := range m.store {
+		if price.ProductID == productID {
+			res = append(res, *price)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].EffectiveFrom.Before(res[j].EffectiveFrom)
+	})
+	return res
+}
+
+func (m *mockProductPriceRepository) ListProductsWithDuePrices(at time.Time) ([]uuid.UUID, error) {
+	var res []uuid.UUID
+	for _, price := range m.store {
+		due := price.ActivatedAt == nil && !price.EffectiveFrom.After(at)
+		ended := price.Kind == model2.SalePrice && price.ExpiredAt == nil && !price.EffectiveTo.After(at)
+		if due || ended {
+			res = append(res, price.ProductID)
+		}
+	}
+	return res, nil
+}
+
+var _ model2.BundleRepository = &mockBundleRepository{}
+
+type mockBundleRepository struct {
+	store    map[uuid.UUID][]model2.BundleComponent
+	products *mockProductRepository
+}
+
+func (m *mockBundleRepository) Store(bundleID uuid.UUID, components []model2.BundleComponent) error {
+	m.store[bundleID] = components
+	return nil
+}
+
+func (m *mockBundleRepository) FindComponents(bundleID uuid.UUID) ([]model2.BundleComponent, error) {
+	return m.store[bundleID], nil
+}
+
+func (m *mockBundleRepository) ListBundlesByComponent(productID uuid.UUID) ([]uuid.UUID, error) {
+	var res []uuid.UUID
+	for bundleID, components := range m.store {
+		if bundle, ok := m.products.store[bundleID]; !ok || bundle.DeletedAt != nil {
+			continue
+		}
+		for _, component := range components {
+			if component.ProductID == productID {
+				res = append(res, bundleID)
+			}
+		}
+	}
+	return res, nil
+}
+
+func (m *mockBundleRepository) ListBundlesByComponentWithDeleted(productID uuid.UUID) ([]uuid.UUID, error) {
+	var res []uuid.UUID
+	for bundleID, components := range m.store {
+		// composition of purged bundle is purged with it
+		if _, ok := m.products.store[bundleID]; !ok {
+			continue
+		}
+		for _, component := range components {
+			if component.ProductID == productID {
+				res = append(res, bundleID)
+			}
+		}
+	}
+	return res, nil
+}
+
+var _ model2.CategoryRepository = &mockCategoryRepository{}
+
+type mockCategoryRepository struct {
+	store map[uuid.UUID]*model2.Category
+}
+
+func (m *mockCategoryRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (m *mockCategoryRepository) Store(category *model2.Category) error {
+	m.store[category.ID] = category
+	return nil
+}
+
+func (m *mockCategoryRepository) Find(id uuid.UUID) (*model2.Category, error) {
+	category, ok := m.store[id]
+	if !ok {
+		return nil, model2.ErrCategoryNotFound
+	}
+	return category, nil
+}
+
 type mockEventDispatcher struct {
 	events []domain.Event
 }
@@ -195,3 +1119,39 @@ func (m *mockEventDispatcher) Dispatch(evt domain.Event) error {
 	m.events = append(m.events, evt)
 	return nil
 }
+
+func TestCategoryService(t *testing.T) {
+	repo := &mockCategoryRepository{
+		store: make(map[uuid.UUID]*model2.Category),
+	}
+	eventDispatcher := &mockEventDispatcher{
+		events: make([]domain.Event, 0),
+	}
+	categoryService := service.NewCategoryService(repo, eventDispatcher)
+
+	clothesID, err := categoryService.CreateCategory("Clothes", uuid.Nil)
+	require.NoError(t, err)
+	shirtsID, err := categoryService.CreateCategory("Shirts", clothesID)
+	require.NoError(t, err)
+	require.Equal(t, clothesID, repo.store[shirtsID].ParentID)
+	require.Len(t, eventDispatcher.events, 2)
+
+	t.Run("Create category under unknown parent", func(t *testing.T) {
+		_, err := categoryService.CreateCategory("Unknown", uuid.New())
+		require.ErrorIs(t, err, model2.ErrCategoryNotFound)
+	})
+
+	t.Run("Move category under own subtree", func(t *testing.T) {
+		err := categoryService.UpdateCategory(clothesID, "Clothes", shirtsID)
+		require.ErrorIs(t, err, model2.ErrCategoryCycle)
+		err = categoryService.UpdateCategory(clothesID, "Clothes", clothesID)
+		require.ErrorIs(t, err, model2.ErrCategoryCycle)
+	})
+
+	t.Run("Move category to root", func(t *testing.T) {
+		err := categoryService.UpdateCategory(shirtsID, "T-Shirts", uuid.Nil)
+		require.NoError(t, err)
+		require.Equal(t, uuid.Nil, repo.store[shirtsID].ParentID)
+		require.Equal(t, "T-Shirts", repo.store[shirtsID].Name)
+	})
+}
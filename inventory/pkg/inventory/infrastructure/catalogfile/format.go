@@ -0,0 +1,52 @@
+package catalogfile
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+var ErrUnknownFormat = errors.New("unknown catalog file format")
+
+// Format of catalog import and export files
+type Format string
+
+const (
+	CSV       Format = "csv"
+	JSONLines Format = "jsonl"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case CSV:
+		return CSV, nil
+	case JSONLines, "ndjson":
+		return JSONLines, nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+// FormatFromPath detects format by file extension
+func FormatFromPath(path string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+var csvHeader = []string{"sku", "parent_sku", "category_id", "name", "price", "quantity", "reorder_threshold", "attributes"}
+
+type jsonRow struct {
+	SKU              string          `json:"sku"`
+	ParentSKU        string          `json:"parent_sku,omitempty"`
+	CategoryID       string          `json:"category_id,omitempty"`
+	Name             string          `json:"name"`
+	Price            float64         `json:"price"`
+	Quantity         int             `json:"quantity"`
+	ReorderThreshold int             `json:"reorder_threshold"`
+	Attributes       []jsonAttribute `json:"attributes,omitempty"`
+}
+
+type jsonAttribute struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
@@ -0,0 +1,159 @@
+package catalogfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appmodel "inventory/pkg/inventory/app/model"
+	"inventory/pkg/inventory/app/service"
+)
+
+const maxJSONLineSize = 1 << 20
+
+// NewReader reads import rows, CSV file must start with header naming its columns
+func NewReader(r io.Reader, format Format) (service.CatalogRowSource, error) {
+	switch format {
+	case CSV:
+		csvReader := csv.NewReader(r)
+		csvReader.ReuseRecord = true
+		header, err := csvReader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return &csvSource{reader: csvReader}, nil
+			}
+			return nil, errors.WithStack(err)
+		}
+		columns := make(map[string]int, len(header))
+		for i, name := range header {
+			columns[name] = i
+		}
+		if _, ok := columns["sku"]; !ok {
+			return nil, errors.New("csv header must contain sku column")
+		}
+		return &csvSource{reader: csvReader, columns: columns}, nil
+	case JSONLines:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, maxJSONLineSize)
+		return &jsonLinesSource{scanner: scanner}, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+type csvSource struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func (s *csvSource) Next() (appmodel.CatalogRow, error) {
+	record, err := s.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return appmodel.CatalogRow{}, fmt.Errorf("%w: %s", service.ErrInvalidImportRow, parseErr.Err)
+		}
+		if errors.Is(err, io.EOF) {
+			return appmodel.CatalogRow{}, io.EOF
+		}
+		return appmodel.CatalogRow{}, errors.WithStack(err)
+	}
+
+	get := func(name string) string {
+		i, ok := s.columns[name]
+		if !ok {
+			return ""
+		}
+		return record[i]
+	}
+	row := jsonRow{
+		SKU:        get("sku"),
+		ParentSKU:  get("parent_sku"),
+		CategoryID: get("category_id"),
+		Name:       get("name"),
+	}
+	if row.Price, err = parseFloat(get("price")); err != nil {
+		return appmodel.CatalogRow{SKU: row.SKU}, invalidValue("price", get("price"))
+	}
+	if row.Quantity, err = parseInt(get("quantity")); err != nil {
+		return appmodel.CatalogRow{SKU: row.SKU}, invalidValue("quantity", get("quantity"))
+	}
+	if row.ReorderThreshold, err = parseInt(get("reorder_threshold")); err != nil {
+		return appmodel.CatalogRow{SKU: row.SKU}, invalidValue("reorder_threshold", get("reorder_threshold"))
+	}
+	if attributes := get("attributes"); attributes != "" {
+		if err = json.Unmarshal([]byte(attributes), &row.Attributes); err != nil {
+			return appmodel.CatalogRow{SKU: row.SKU}, invalidValue("attributes", attributes)
+		}
+	}
+	return row.toAppModel()
+}
+
+type jsonLinesSource struct {
+	scanner *bufio.Scanner
+}
+
+func (s *jsonLinesSource) Next() (appmodel.CatalogRow, error) {
+	for s.scanner.Scan() {
+		line := bytes.TrimSpace(s.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var row jsonRow
+		if err := json.Unmarshal(line, &row); err != nil {
+			return appmodel.CatalogRow{SKU: row.SKU}, fmt.Errorf("%w: %s", service.ErrInvalidImportRow, err)
+		}
+		return row.toAppModel()
+	}
+	if err := s.scanner.Err(); err != nil {
+		return appmodel.CatalogRow{}, errors.WithStack(err)
+	}
+	return appmodel.CatalogRow{}, io.EOF
+}
+
+func (r jsonRow) toAppModel() (appmodel.CatalogRow, error) {
+	row := appmodel.CatalogRow{
+		SKU:              r.SKU,
+		ParentSKU:        r.ParentSKU,
+		Name:             r.Name,
+		Price:            r.Price,
+		Quantity:         r.Quantity,
+		ReorderThreshold: r.ReorderThreshold,
+	}
+	if r.CategoryID != "" {
+		categoryID, err := uuid.Parse(r.CategoryID)
+		if err != nil {
+			return row, invalidValue("category_id", r.CategoryID)
+		}
+		row.CategoryID = categoryID
+	}
+	for _, attribute := range r.Attributes {
+		row.Attributes = append(row.Attributes, appmodel.Attribute(attribute))
+	}
+	return row, nil
+}
+
+func parseFloat(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+func parseInt(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(s)
+}
+
+func invalidValue(column, value string) error {
+	return fmt.Errorf("%w: invalid %s %q", service.ErrInvalidImportRow, column, value)
+}
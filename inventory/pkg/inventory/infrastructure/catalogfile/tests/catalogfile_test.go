@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	appmodel "inventory/pkg/inventory/app/model"
+	"inventory/pkg/inventory/app/service"
+	"inventory/pkg/inventory/infrastructure/catalogfile"
+)
+
+func TestCatalogFileRoundTrip(t *testing.T) {
+	rows := []appmodel.CatalogRow{
+		{
+			SKU:              "HOODIE",
+			CategoryID:       uuid.New(),
+			Name:             "Hoodie, \"classic\"",
+			Price:            49.9,
+			Quantity:         10,
+			ReorderThreshold: 2,
+			Attributes: []appmodel.Attribute{
+				{Name: "material", Type: "string", Value: "cotton"},
+				{Name: "weight", Type: "number", Value: "0.5"},
+			},
+		},
+		{
+			SKU:       "HOODIE-RED-M",
+			ParentSKU: "HOODIE",
+			Price:     52,
+			Quantity:  3,
+			Attributes: []appmodel.Attribute{
+				{Name: "size", Type: "string", Value: "M"},
+			},
+		},
+		{
+			SKU:  "MUG",
+			Name: "Mug",
+		},
+	}
+
+	for _, format := range []catalogfile.Format{catalogfile.CSV, catalogfile.JSONLines} {
+		t.Run(string(format), func(t *testing.T) {
+			var file bytes.Buffer
+			writer, err := catalogfile.NewWriter(&file, format)
+			require.NoError(t, err)
+			for _, row := range rows {
+				require.NoError(t, writer.Write(row))
+			}
+			require.NoError(t, writer.Flush())
+
+			reader, err := catalogfile.NewReader(&file, format)
+			require.NoError(t, err)
+			require.Equal(t, rows, readAll(t, reader))
+		})
+	}
+}
+
+func TestCatalogFileMalformedRows(t *testing.T) {
+	t.Run("CSV", func(t *testing.T) {
+		file := strings.Join([]string{
+			"sku,name,price,quantity,category_id,attributes",
+			"A,First,1.5,1,,",
+			"B,Second,cheap,1,,",
+			"C,Third,1,-,,",
+			"D,Fourth,1,1,not-uuid,",
+			"E,Fifth,1,1,,{broken",
+			"F,Sixth",
+			"G,Seventh,2,2,,",
+		}, "\n")
+		reader, err := catalogfile.NewReader(strings.NewReader(file), catalogfile.CSV)
+		require.NoError(t, err)
+
+		rows, invalidRows := readRows(t, reader)
+		require.Equal(t, []string{"A", "G"}, skus(rows))
+		require.Equal(t, []int{2, 3, 4, 5, 6}, invalidRows)
+	})
+
+	t.Run("CSV without sku column", func(t *testing.T) {
+		_, err := catalogfile.NewReader(strings.NewReader("name,price\nMug,1\n"), catalogfile.CSV)
+		require.Error(t, err)
+	})
+
+	t.Run("JSON lines", func(t *testing.T) {
+		file := strings.Join([]string{
+			`{"sku":"A","name":"First","price":1.5}`,
+			`{"sku":"B","name":"Second","price":"cheap"}`,
+			``,
+			`{"sku":"C","name":"Third","category_id":"not-uuid"}`,
+			`not json`,
+			`{"sku":"D","name":"Fourth","quantity":2}`,
+		}, "\n")
+		reader, err := catalogfile.NewReader(strings.NewReader(file), catalogfile.JSONLines)
+		require.NoError(t, err)
+
+		rows, invalidRows := readRows(t, reader)
+		require.Equal(t, []string{"A", "D"}, skus(rows))
+		require.Equal(t, 2, rows[1].Quantity)
+		require.Equal(t, []int{2, 3, 4}, invalidRows)
+	})
+
+	t.Run("Unknown format", func(t *testing.T) {
+		_, err := catalogfile.NewReader(strings.NewReader(""), catalogfile.Format("xml"))
+		require.ErrorIs(t, err, catalogfile.ErrUnknownFormat)
+		_, err = catalogfile.NewWriter(io.Discard, catalogfile.Format("xml"))
+		require.ErrorIs(t, err, catalogfile.ErrUnknownFormat)
+	})
+}
+
+func readAll(t *testing.T, reader service.CatalogRowSource) []appmodel.CatalogRow {
+	t.Helper()
+	rows, invalidRows := readRows(t, reader)
+	require.Empty(t, invalidRows)
+	return rows
+}
+
+// readRows reads rows the way import does, invalid rows are reported by 1-based number and skipped
+func readRows(t *testing.T, reader service.CatalogRowSource) ([]appmodel.CatalogRow, []int) {
+	t.Helper()
+	var rows []appmodel.CatalogRow
+	var invalidRows []int
+	for number := 1; ; number++ {
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return rows, invalidRows
+		}
+		if errors.Is(err, service.ErrInvalidImportRow) {
+			invalidRows = append(invalidRows, number)
+			continue
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+}
+
+func skus(rows []appmodel.CatalogRow) []string {
+	result := make([]string, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, row.SKU)
+	}
+	return result
+}
@@ -0,0 +1,96 @@
+package catalogfile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appmodel "inventory/pkg/inventory/app/model"
+)
+
+// Writer writes catalog rows in the same form NewReader reads them
+type Writer interface {
+	Write(row appmodel.CatalogRow) error
+	Flush() error
+}
+
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case CSV:
+		csvWriter := csv.NewWriter(w)
+		err := csvWriter.Write(csvHeader)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return &csvCatalogWriter{writer: csvWriter}, nil
+	case JSONLines:
+		return &jsonLinesCatalogWriter{encoder: json.NewEncoder(w)}, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+type csvCatalogWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvCatalogWriter) Write(row appmodel.CatalogRow) error {
+	r := toJSONRow(row)
+	var attributes string
+	if len(r.Attributes) > 0 {
+		b, err := json.Marshal(r.Attributes)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		attributes = string(b)
+	}
+	return errors.WithStack(w.writer.Write([]string{
+		r.SKU,
+		r.ParentSKU,
+		r.CategoryID,
+		r.Name,
+		strconv.FormatFloat(r.Price, 'f', -1, 64),
+		strconv.Itoa(r.Quantity),
+		strconv.Itoa(r.ReorderThreshold),
+		attributes,
+	}))
+}
+
+func (w *csvCatalogWriter) Flush() error {
+	w.writer.Flush()
+	return errors.WithStack(w.writer.Error())
+}
+
+type jsonLinesCatalogWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *jsonLinesCatalogWriter) Write(row appmodel.CatalogRow) error {
+	return errors.WithStack(w.encoder.Encode(toJSONRow(row)))
+}
+
+func (w *jsonLinesCatalogWriter) Flush() error {
+	return nil
+}
+
+func toJSONRow(row appmodel.CatalogRow) jsonRow {
+	r := jsonRow{
+		SKU:              row.SKU,
+		ParentSKU:        row.ParentSKU,
+		Name:             row.Name,
+		Price:            row.Price,
+		Quantity:         row.Quantity,
+		ReorderThreshold: row.ReorderThreshold,
+	}
+	if row.CategoryID != uuid.Nil {
+		r.CategoryID = row.CategoryID.String()
+	}
+	for _, attribute := range row.Attributes {
+		r.Attributes = append(r.Attributes, jsonAttribute(attribute))
+	}
+	return r
+}
@@ -8,13 +8,27 @@ import (
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/amqp"
+	"github.com/google/uuid"
+	pkgerrors "github.com/pkg/errors"
+
+	appservice "inventory/pkg/inventory/app/service"
+	"inventory/pkg/inventory/domain/model"
+)
+
+const (
+	orderPaidRoutingKey      = "order.order_paid"
+	orderCancelledRoutingKey = "order.order_cancelled"
 )
 
+// OrderRoutingKeys lists order service events inventory reacts to
+var OrderRoutingKeys = []string{orderPaidRoutingKey, orderCancelledRoutingKey}
+
 var errUnhandledDelivery = errors.New("unhandled delivery")
 
-func NewAMQPTransport(logger logging.Logger) AMQPTransport {
+func NewAMQPTransport(logger logging.Logger, productService appservice.ProductService) AMQPTransport {
 	return &amqpTransport{
-		logger: logger,
+		logger:         logger,
+		productService: productService,
 	}
 }
 
@@ -23,15 +37,39 @@ type AMQPTransport interface {
 }
 
 type amqpTransport struct {
-	logger logging.Logger
+	logger         logging.Logger
+	productService appservice.ProductService
 }
 
 func (t *amqpTransport) Handler() amqp.Handler {
 	return t.withLog(t.handle)
 }
 
-func (t *amqpTransport) handle(context.Context, amqp.Delivery) error {
-	return nil
+func (t *amqpTransport) handle(ctx context.Context, delivery amqp.Delivery) error {
+	switch delivery.RoutingKey {
+	case orderPaidRoutingKey:
+		var event orderEvent
+		if err := json.Unmarshal(delivery.Body, &event); err != nil {
+			return pkgerrors.Wrap(err, "failed to unmarshal order_paid")
+		}
+		orderID, items, err := event.parse()
+		if err != nil {
+			return err
+		}
+		return t.productService.AllocateOrder(ctx, orderID, items)
+	case orderCancelledRoutingKey:
+		var event orderEvent
+		if err := json.Unmarshal(delivery.Body, &event); err != nil {
+			return pkgerrors.Wrap(err, "failed to unmarshal order_cancelled")
+		}
+		orderID, items, err := event.parse()
+		if err != nil {
+			return err
+		}
+		return t.productService.ReleaseOrder(ctx, orderID, items)
+	default:
+		return errUnhandledDelivery
+	}
 }
 
 func (t *amqpTransport) withLog(handler amqp.Handler) amqp.Handler {
@@ -63,3 +101,30 @@ func (t *amqpTransport) withLog(handler amqp.Handler) amqp.Handler {
 		return err
 	}
 }
+
+type orderEvent struct {
+	OrderID string `json:"order_id"`
+	Items   []struct {
+		ProductID string `json:"product_id"`
+		Quantity  int    `json:"quantity"`
+	} `json:"items"`
+}
+
+func (e orderEvent) parse() (uuid.UUID, []model.OrderItem, error) {
+	orderID, err := uuid.Parse(e.OrderID)
+	if err != nil {
+		return uuid.Nil, nil, pkgerrors.Wrapf(err, "invalid order id %q", e.OrderID)
+	}
+	items := make([]model.OrderItem, 0, len(e.Items))
+	for _, item := range e.Items {
+		productID, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			return uuid.Nil, nil, pkgerrors.Wrapf(err, "invalid product id %q", item.ProductID)
+		}
+		items = append(items, model.OrderItem{
+			ProductID: productID,
+			Quantity:  item.Quantity,
+		})
+	}
+	return orderID, items, nil
+}
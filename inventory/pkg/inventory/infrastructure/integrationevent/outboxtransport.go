@@ -14,7 +14,7 @@ const (
 	ExchangeKind     = "topic"
 	QueueName        = "inventory_domain_event"
 	RoutingKeyPrefix = "inventory."
-	ContentType      = "app/json"
+	ContentType      = "application/json"
 )
 
 func NewOutboxTransport(logger logging.Logger, producer amqp.Producer) outbox.Transport {
@@ -1,7 +1,13 @@
 package integrationevent
 
 import (
+	"encoding/json"
+
 	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"inventory/pkg/inventory/domain/model"
 )
 
 func NewEventSerializer() outbox.EventSerializer[outbox.Event] {
@@ -11,5 +17,318 @@ func NewEventSerializer() outbox.EventSerializer[outbox.Event] {
 type eventSerializer struct{}
 
 func (s eventSerializer) Serialize(event outbox.Event) (string, error) {
-	return event.Type(), nil
+	switch e := event.(type) {
+	case *model.ProductCreated:
+		b, err := json.Marshal(ProductCreated{
+			ProductID:  e.ID.String(),
+			SKU:        e.SKU,
+			ParentID:   optionalUUIDString(e.ParentID),
+			CategoryID: optionalUUIDString(e.CategoryID),
+			Attributes: toAttributes(e.Attributes),
+			Name:       e.Name,
+			Price:      e.Price,
+			CreatedAt:  e.CreatedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.ProductDeleted:
+		b, err := json.Marshal(ProductLifecycleChanged{
+			ProductID: e.ProductID.String(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.ProductRestored:
+		b, err := json.Marshal(ProductLifecycleChanged{
+			ProductID: e.ProductID.String(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.ProductPurged:
+		b, err := json.Marshal(ProductPurged{
+			ProductID: e.ProductID.String(),
+			SKU:       e.SKU,
+			DeletedAt: e.DeletedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.CategoryCreated:
+		b, err := json.Marshal(CategoryCreated{
+			CategoryID: e.ID.String(),
+			ParentID:   optionalUUIDString(e.ParentID),
+			Name:       e.Name,
+		})
+		return string(b), errors.WithStack(err)
+	case *model.BundleCreated:
+		b, err := json.Marshal(BundleCreated{
+			BundleID:   e.ID.String(),
+			SKU:        e.SKU,
+			Name:       e.Name,
+			Components: toBundleComponents(e.Components),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.BundleStockChanged:
+		b, err := json.Marshal(BundleStockChanged{
+			BundleID:    e.BundleID.String(),
+			WarehouseID: optionalUUIDString(e.WarehouseID),
+			Delta:       e.Delta,
+			Reason:      e.Reason.String(),
+			ReferenceID: optionalUUIDString(e.ReferenceID),
+			Expansion:   toBundleComponents(e.Expansion),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.ProductCatalogInfoChanged:
+		b, err := json.Marshal(ProductCatalogInfoChanged{
+			ProductID:  e.ID.String(),
+			SKU:        e.SKU,
+			ParentID:   optionalUUIDString(e.ParentID),
+			CategoryID: optionalUUIDString(e.CategoryID),
+			Attributes: toAttributes(e.Attributes),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.ProductNameChanged:
+		b, err := json.Marshal(ProductNameChanged{
+			ProductID: e.ID.String(),
+			Name:      e.Name,
+		})
+		return string(b), errors.WithStack(err)
+	case *model.ProductPriceChanged:
+		b, err := json.Marshal(ProductPriceChanged{
+			ProductID: e.ID.String(),
+			SKU:       e.SKU,
+			ParentID:  optionalUUIDString(e.ParentID),
+			Price:     e.Price,
+		})
+		return string(b), errors.WithStack(err)
+	case *model.ProductQuantityChanged:
+		b, err := json.Marshal(ProductQuantityChanged{
+			ProductID:    e.ID.String(),
+			SKU:          e.SKU,
+			ParentID:     optionalUUIDString(e.ParentID),
+			WarehouseID:  e.WarehouseID.String(),
+			NewQuantity:  e.NewQuantity,
+			PrevQuantity: e.PrevQuantity,
+			Reason:       e.Reason.String(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.StockTransferred:
+		b, err := json.Marshal(StockTransferred{
+			TransferID:      e.TransferID.String(),
+			ProductID:       e.ProductID.String(),
+			FromWarehouseID: e.FromWarehouseID.String(),
+			ToWarehouseID:   e.ToWarehouseID.String(),
+			Quantity:        e.Quantity,
+		})
+		return string(b), errors.WithStack(err)
+	case *model.StockAllocated:
+		allocations := make([]Allocation, 0, len(e.Allocations))
+		for _, allocation := range e.Allocations {
+			allocations = append(allocations, Allocation{
+				ProductID:   allocation.ProductID.String(),
+				WarehouseID: allocation.WarehouseID.String(),
+				Quantity:    allocation.Quantity,
+			})
+		}
+		b, err := json.Marshal(StockAllocated{
+			ProductID:   e.ProductID.String(),
+			ReferenceID: optionalUUIDString(e.ReferenceID),
+			Allocations: allocations,
+		})
+		return string(b), errors.WithStack(err)
+	case *model.WarehouseCreated:
+		b, err := json.Marshal(WarehouseCreated{
+			WarehouseID: e.ID.String(),
+			Name:        e.Name,
+		})
+		return string(b), errors.WithStack(err)
+	case *model.StockLow:
+		b, err := json.Marshal(StockLow{
+			ProductID:        e.ProductID.String(),
+			ProductName:      e.ProductName,
+			Quantity:         e.Quantity,
+			ReorderThreshold: e.ReorderThreshold,
+		})
+		return string(b), errors.WithStack(err)
+	case *model.OutOfStock:
+		b, err := json.Marshal(OutOfStock{
+			ProductID:   e.ProductID.String(),
+			ProductName: e.ProductName,
+		})
+		return string(b), errors.WithStack(err)
+	case *model.StockAllocationFailed:
+		items := make([]OrderItem, 0, len(e.Items))
+		for _, item := range e.Items {
+			items = append(items, OrderItem{
+				ProductID: item.ProductID.String(),
+				Quantity:  item.Quantity,
+			})
+		}
+		b, err := json.Marshal(StockAllocationFailed{
+			OrderID: e.OrderID.String(),
+			Items:   items,
+			Reason:  e.Reason,
+		})
+		return string(b), errors.WithStack(err)
+	default:
+		return "", errors.Errorf("unknown event %q", event.Type())
+	}
+}
+
+type Attribute struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type ProductCreated struct {
+	ProductID  string      `json:"product_id"`
+	SKU        string      `json:"sku,omitempty"`
+	ParentID   string      `json:"parent_id,omitempty"`
+	CategoryID string      `json:"category_id,omitempty"`
+	Attributes []Attribute `json:"attributes"`
+	Name       string      `json:"name"`
+	Price      float64     `json:"price"`
+	CreatedAt  int64       `json:"created_at"`
+}
+
+// ProductLifecycleChanged is payload of ProductDeleted and ProductRestored
+type ProductLifecycleChanged struct {
+	ProductID string `json:"product_id"`
+}
+
+type ProductPurged struct {
+	ProductID string `json:"product_id"`
+	SKU       string `json:"sku,omitempty"`
+	DeletedAt int64  `json:"deleted_at"`
+}
+
+type CategoryCreated struct {
+	CategoryID string `json:"category_id"`
+	ParentID   string `json:"parent_id,omitempty"`
+	Name       string `json:"name"`
+}
+
+type BundleComponent struct {
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+type BundleCreated struct {
+	BundleID   string            `json:"bundle_id"`
+	SKU        string            `json:"sku,omitempty"`
+	Name       string            `json:"name"`
+	Components []BundleComponent `json:"components"`
+}
+
+// BundleStockChanged carries expansion, i.e. change of every component caused by bundle change
+type BundleStockChanged struct {
+	BundleID    string            `json:"bundle_id"`
+	WarehouseID string            `json:"warehouse_id,omitempty"`
+	Delta       int               `json:"delta"`
+	Reason      string            `json:"reason"`
+	ReferenceID string            `json:"reference_id,omitempty"`
+	Expansion   []BundleComponent `json:"expansion"`
+}
+
+type ProductCatalogInfoChanged struct {
+	ProductID  string      `json:"product_id"`
+	SKU        string      `json:"sku,omitempty"`
+	ParentID   string      `json:"parent_id,omitempty"`
+	CategoryID string      `json:"category_id,omitempty"`
+	Attributes []Attribute `json:"attributes"`
+}
+
+type ProductNameChanged struct {
+	ProductID string `json:"product_id"`
+	Name      string `json:"name"`
+}
+
+type ProductPriceChanged struct {
+	ProductID string  `json:"product_id"`
+	SKU       string  `json:"sku,omitempty"`
+	ParentID  string  `json:"parent_id,omitempty"`
+	Price     float64 `json:"price"`
+}
+
+type ProductQuantityChanged struct {
+	ProductID    string `json:"product_id"`
+	SKU          string `json:"sku,omitempty"`
+	ParentID     string `json:"parent_id,omitempty"`
+	WarehouseID  string `json:"warehouse_id"`
+	NewQuantity  int    `json:"new_quantity"`
+	PrevQuantity int    `json:"prev_quantity"`
+	Reason       string `json:"reason"`
+}
+
+type StockTransferred struct {
+	TransferID      string `json:"transfer_id"`
+	ProductID       string `json:"product_id"`
+	FromWarehouseID string `json:"from_warehouse_id"`
+	ToWarehouseID   string `json:"to_warehouse_id"`
+	Quantity        int    `json:"quantity"`
+}
+
+type Allocation struct {
+	ProductID   string `json:"product_id"`
+	WarehouseID string `json:"warehouse_id"`
+	Quantity    int    `json:"quantity"`
+}
+
+type StockAllocated struct {
+	ProductID   string       `json:"product_id"`
+	ReferenceID string       `json:"reference_id,omitempty"`
+	Allocations []Allocation `json:"allocations"`
+}
+
+type WarehouseCreated struct {
+	WarehouseID string `json:"warehouse_id"`
+	Name        string `json:"name"`
+}
+
+type StockLow struct {
+	ProductID        string `json:"product_id"`
+	ProductName      string `json:"product_name"`
+	Quantity         int    `json:"quantity"`
+	ReorderThreshold int    `json:"reorder_threshold"`
+}
+
+type OutOfStock struct {
+	ProductID   string `json:"product_id"`
+	ProductName string `json:"product_name"`
+}
+
+type OrderItem struct {
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+type StockAllocationFailed struct {
+	OrderID string      `json:"order_id"`
+	Items   []OrderItem `json:"items"`
+	Reason  string      `json:"reason"`
+}
+
+func toAttributes(attributes []model.Attribute) []Attribute {
+	result := make([]Attribute, 0, len(attributes))
+	for _, attribute := range attributes {
+		result = append(result, Attribute{
+			Name:  attribute.Name,
+			Type:  attribute.Type.String(),
+			Value: attribute.Value,
+		})
+	}
+	return result
+}
+
+func toBundleComponents(components []model.BundleComponent) []BundleComponent {
+	result := make([]BundleComponent, 0, len(components))
+	for _, component := range components {
+		result = append(result, BundleComponent{
+			ProductID: component.ProductID.String(),
+			Quantity:  component.Quantity,
+		})
+	}
+	return result
+}
+
+func optionalUUIDString(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
 }
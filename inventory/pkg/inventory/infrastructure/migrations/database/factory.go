@@ -44,4 +44,11 @@ func NewDatabaseMigrator(
 
 var builderFunctions = []MigrationBuilderFunc{
 	NewVersion1722266003,
+	NewVersion1760950000,
+	NewVersion1761210000,
+	NewVersion1761470000,
+	NewVersion1761730000,
+	NewVersion1761990000,
+	NewVersion1762250000,
+	NewVersion1762510000,
 }
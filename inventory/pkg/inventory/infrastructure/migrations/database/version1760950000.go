@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1760950000(client mysql.ClientContext) migrator.Migration {
+	return &version1760950000{
+		client: client,
+	}
+}
+
+type version1760950000 struct {
+	client mysql.ClientContext
+}
+
+func (v version1760950000) Version() int64 {
+	return 1760950000
+}
+
+func (v version1760950000) Description() string {
+	return "Create 'stock_movement' table"
+}
+
+func (v version1760950000) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE stock_movement
+		(
+			id             BINARY(16)   NOT NULL PRIMARY KEY,
+			product_id     BINARY(16)   NOT NULL,
+			delta          INT          NOT NULL,
+			quantity_after INT          NOT NULL,
+			reason         TINYINT      NOT NULL COMMENT '0: Receipt, 1: Sale, 2: Reservation, 3: Return, 4: ManualAdjustment, 5: Transfer',
+			reference_id   BINARY(16)   NULL,
+			actor          VARCHAR(255) NOT NULL DEFAULT '',
+			created_at     DATETIME     NOT NULL,
+			INDEX stock_movement_product_id_id_index (product_id, id)
+		) ENGINE = InnoDB
+		  DEFAULT CHARSET = utf8mb4
+		  COLLATE = utf8mb4_unicode_ci;
+	`)
+	return errors.WithStack(err)
+}
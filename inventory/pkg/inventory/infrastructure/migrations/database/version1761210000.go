@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1761210000(client mysql.ClientContext) migrator.Migration {
+	return &version1761210000{
+		client: client,
+	}
+}
+
+type version1761210000 struct {
+	client mysql.ClientContext
+}
+
+func (v version1761210000) Version() int64 {
+	return 1761210000
+}
+
+func (v version1761210000) Description() string {
+	return "Create 'warehouse' and 'stock_level' tables, move product quantity to default warehouse"
+}
+
+func (v version1761210000) Up(ctx context.Context) error {
+	queries := []string{
+		`
+		CREATE TABLE warehouse
+		(
+			id         BINARY(16)   NOT NULL PRIMARY KEY,
+			name       VARCHAR(255) NOT NULL,
+			priority   INT          NOT NULL DEFAULT 0,
+			is_default BOOLEAN      NOT NULL DEFAULT FALSE,
+			created_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+		) ENGINE = InnoDB
+		  DEFAULT CHARSET = utf8mb4
+		  COLLATE = utf8mb4_unicode_ci;
+		`,
+		`
+		CREATE TABLE stock_level
+		(
+			product_id   BINARY(16) NOT NULL,
+			warehouse_id BINARY(16) NOT NULL,
+			quantity     INT        NOT NULL DEFAULT 0,
+			PRIMARY KEY (product_id, warehouse_id)
+		) ENGINE = InnoDB
+		  DEFAULT CHARSET = utf8mb4
+		  COLLATE = utf8mb4_unicode_ci;
+		`,
+		`INSERT INTO warehouse (id, name, priority, is_default) VALUES (UUID_TO_BIN(UUID()), 'Main', 0, TRUE)`,
+		`
+		INSERT INTO stock_level (product_id, warehouse_id, quantity)
+		SELECT p.id, w.id, p.quantity FROM product p INNER JOIN warehouse w ON w.is_default
+		WHERE p.quantity > 0
+		`,
+		`ALTER TABLE stock_movement ADD COLUMN warehouse_id BINARY(16) NULL AFTER product_id`,
+		`UPDATE stock_movement SET warehouse_id = (SELECT id FROM warehouse WHERE is_default)`,
+		`ALTER TABLE stock_movement MODIFY warehouse_id BINARY(16) NOT NULL`,
+	}
+	for _, query := range queries {
+		_, err := v.client.ExecContext(ctx, query)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
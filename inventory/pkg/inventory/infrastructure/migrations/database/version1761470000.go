@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1761470000(client mysql.ClientContext) migrator.Migration {
+	return &version1761470000{
+		client: client,
+	}
+}
+
+type version1761470000 struct {
+	client mysql.ClientContext
+}
+
+func (v version1761470000) Version() int64 {
+	return 1761470000
+}
+
+func (v version1761470000) Description() string {
+	return "Add 'reorder_threshold' column to 'product' table"
+}
+
+func (v version1761470000) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE product
+			ADD COLUMN reorder_threshold INT NOT NULL DEFAULT 0 AFTER quantity
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1761730000(client mysql.ClientContext) migrator.Migration {
+	return &version1761730000{
+		client: client,
+	}
+}
+
+type version1761730000 struct {
+	client mysql.ClientContext
+}
+
+func (v version1761730000) Version() int64 {
+	return 1761730000
+}
+
+func (v version1761730000) Description() string {
+	return "Create 'category' table, add sku, variant and attributes columns to 'product' table"
+}
+
+func (v version1761730000) Up(ctx context.Context) error {
+	queries := []string{
+		`
+		CREATE TABLE category
+		(
+			id         BINARY(16)   NOT NULL PRIMARY KEY,
+			parent_id  BINARY(16)   NULL,
+			name       VARCHAR(255) NOT NULL,
+			created_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			INDEX category_parent_id_index (parent_id)
+		) ENGINE = InnoDB
+		  DEFAULT CHARSET = utf8mb4
+		  COLLATE = utf8mb4_unicode_ci;
+		`,
+		`
+		ALTER TABLE product
+			ADD COLUMN sku         VARCHAR(64) NULL AFTER id,
+			ADD COLUMN parent_id   BINARY(16)  NULL AFTER sku,
+			ADD COLUMN category_id BINARY(16)  NULL AFTER parent_id,
+			ADD COLUMN attributes  JSON        NULL AFTER category_id,
+			ADD UNIQUE INDEX product_sku_unique_index (sku),
+			ADD INDEX product_parent_id_index (parent_id),
+			ADD INDEX product_category_id_index (category_id)
+		`,
+	}
+	for _, query := range queries {
+		_, err := v.client.ExecContext(ctx, query)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
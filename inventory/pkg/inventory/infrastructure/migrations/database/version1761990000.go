@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1761990000(client mysql.ClientContext) migrator.Migration {
+	return &version1761990000{
+		client: client,
+	}
+}
+
+type version1761990000 struct {
+	client mysql.ClientContext
+}
+
+func (v version1761990000) Version() int64 {
+	return 1761990000
+}
+
+func (v version1761990000) Description() string {
+	return "Create 'product_price' table with current prices as history start"
+}
+
+func (v version1761990000) Up(ctx context.Context) error {
+	queries := []string{
+		`
+		CREATE TABLE product_price
+		(
+			id             BINARY(16)     NOT NULL PRIMARY KEY,
+			product_id     BINARY(16)     NOT NULL,
+			kind           TINYINT        NOT NULL COMMENT '0: Regular, 1: Sale',
+			price          DECIMAL(10, 2) NOT NULL,
+			effective_from DATETIME       NOT NULL,
+			effective_to   DATETIME       NULL,
+			activated_at   DATETIME       NULL,
+			expired_at     DATETIME       NULL,
+			created_at     DATETIME       NOT NULL,
+			INDEX product_price_product_id_effective_from_index (product_id, effective_from),
+			INDEX product_price_activated_at_effective_from_index (activated_at, effective_from)
+		) ENGINE = InnoDB
+		  DEFAULT CHARSET = utf8mb4
+		  COLLATE = utf8mb4_unicode_ci;
+		`,
+		`
+		INSERT INTO product_price (id, product_id, kind, price, effective_from, activated_at, created_at)
+		SELECT UUID_TO_BIN(UUID()), id, 0, price, created_at, created_at, NOW() FROM product
+		`,
+	}
+	for _, query := range queries {
+		_, err := v.client.ExecContext(ctx, query)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1762250000(client mysql.ClientContext) migrator.Migration {
+	return &version1762250000{
+		client: client,
+	}
+}
+
+type version1762250000 struct {
+	client mysql.ClientContext
+}
+
+func (v version1762250000) Version() int64 {
+	return 1762250000
+}
+
+func (v version1762250000) Description() string {
+	return "Add 'reference_id' index to 'stock_movement' table"
+}
+
+func (v version1762250000) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE stock_movement
+			ADD INDEX stock_movement_reference_id_index (reference_id)
+	`)
+	return errors.WithStack(err)
+}
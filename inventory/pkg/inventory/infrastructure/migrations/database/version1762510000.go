@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1762510000(client mysql.ClientContext) migrator.Migration {
+	return &version1762510000{
+		client: client,
+	}
+}
+
+type version1762510000 struct {
+	client mysql.ClientContext
+}
+
+func (v version1762510000) Version() int64 {
+	return 1762510000
+}
+
+func (v version1762510000) Description() string {
+	return "Create 'bundle_component' table"
+}
+
+func (v version1762510000) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE bundle_component
+		(
+			bundle_id    BINARY(16) NOT NULL,
+			component_id BINARY(16) NOT NULL,
+			quantity     INT        NOT NULL,
+			PRIMARY KEY (bundle_id, component_id),
+			INDEX bundle_component_component_id_index (component_id)
+		) ENGINE = InnoDB
+		  DEFAULT CHARSET = utf8mb4
+		  COLLATE = utf8mb4_unicode_ci;
+	`)
+	return errors.WithStack(err)
+}
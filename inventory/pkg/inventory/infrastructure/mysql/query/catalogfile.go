@@ -0,0 +1,92 @@
+package queryservice
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appmodel "inventory/pkg/inventory/app/model"
+)
+
+const defaultExportCatalogLimit = 500
+
+func (p *productQueryService) ExportCatalog(ctx context.Context, spec appmodel.ExportCatalogSpec) (appmodel.CatalogPage, error) {
+	limit := spec.Limit
+	if limit <= 0 {
+		limit = defaultExportCatalogLimit
+	}
+
+	// catalog file has no place for bundle composition, so bundles are not exported
+	conditions := []string{
+		"p.deleted_at IS NULL",
+		"NOT EXISTS (SELECT 1 FROM bundle_component bc WHERE bc.bundle_id = p.id)",
+	}
+	if spec.Variants {
+		conditions = append(conditions, "p.parent_id IS NOT NULL")
+	} else {
+		conditions = append(conditions, "p.parent_id IS NULL")
+	}
+	var args []interface{}
+	if spec.Cursor != "" {
+		afterID, err := decodeCursor(spec.Cursor)
+		if err != nil {
+			return appmodel.CatalogPage{}, err
+		}
+		conditions = append(conditions, "p.id > ?")
+		args = append(args, afterID[:])
+	}
+	args = append(args, limit+1)
+
+	var rows []struct {
+		sqlxProduct
+		ParentSKU sql.NullString `db:"parent_sku"`
+	}
+	err := p.client.SelectContext(
+		ctx,
+		&rows,
+		`SELECT p.id, p.sku, p.parent_id, p.category_id, p.attributes, p.name, p.price, p.quantity, p.reorder_threshold, p.deleted_at,
+		        parent.sku AS parent_sku
+		 FROM product p
+		 LEFT JOIN product parent ON parent.id = p.parent_id`+whereClause(conditions)+`
+		 ORDER BY p.id
+		 LIMIT ?`,
+		args...,
+	)
+	if err != nil {
+		return appmodel.CatalogPage{}, errors.WithStack(err)
+	}
+
+	var page appmodel.CatalogPage
+	if len(rows) > limit {
+		rows = rows[:limit]
+		page.NextCursor = encodeCursor(rows[limit-1].ID)
+	}
+	page.Rows = make([]appmodel.CatalogRow, 0, len(rows))
+	for _, row := range rows {
+		product, err2 := row.toAppModel()
+		if err2 != nil {
+			return appmodel.CatalogPage{}, err2
+		}
+		page.Rows = append(page.Rows, appmodel.CatalogRow{
+			SKU:              product.SKU,
+			ParentSKU:        row.ParentSKU.String,
+			CategoryID:       exportedCategoryID(product),
+			Name:             product.Name,
+			Price:            product.Price,
+			Quantity:         product.Quantity,
+			ReorderThreshold: product.ReorderThreshold,
+			Attributes:       product.Attributes,
+		})
+	}
+	return page, nil
+}
+
+// exportedCategoryID omits category of variants, it is inherited from parent on import
+func exportedCategoryID(product *appmodel.Product) uuid.UUID {
+	if product.ParentID != uuid.Nil {
+		return uuid.Nil
+	}
+	return product.CategoryID
+}
@@ -0,0 +1,44 @@
+package queryservice
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appmodel "inventory/pkg/inventory/app/model"
+	"inventory/pkg/inventory/app/query"
+)
+
+func NewCategoryQueryService(client mysql.ClientContext) query.CategoryQueryService {
+	return &categoryQueryService{
+		client: client,
+	}
+}
+
+type categoryQueryService struct {
+	client mysql.ClientContext
+}
+
+func (c *categoryQueryService) ListCategories(ctx context.Context) ([]appmodel.Category, error) {
+	var rows []struct {
+		ID       uuid.UUID `db:"id"`
+		ParentID uuid.UUID `db:"parent_id"`
+		Name     string    `db:"name"`
+	}
+	err := c.client.SelectContext(
+		ctx,
+		&rows,
+		`SELECT id, parent_id, name FROM category ORDER BY name, id`,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	categories := make([]appmodel.Category, 0, len(rows))
+	for _, row := range rows {
+		categories = append(categories, appmodel.Category(row))
+	}
+	return categories, nil
+}
@@ -0,0 +1,102 @@
+package queryservice
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appmodel "inventory/pkg/inventory/app/model"
+	"inventory/pkg/inventory/app/query"
+	"inventory/pkg/inventory/domain/model"
+)
+
+func NewPriceQueryService(client mysql.ClientContext) query.PriceQueryService {
+	return &priceQueryService{
+		client: client,
+	}
+}
+
+type priceQueryService struct {
+	client mysql.ClientContext
+}
+
+func (p *priceQueryService) FindPriceAt(ctx context.Context, productID uuid.UUID, at time.Time) (float64, error) {
+	var price float64
+	err := p.client.GetContext(
+		ctx,
+		&price,
+		`SELECT price FROM product_price
+		 WHERE product_id = ? AND kind = ? AND effective_from <= ? AND effective_to > ?
+		 ORDER BY effective_from DESC
+		 LIMIT 1`,
+		productID[:],
+		model.SalePrice,
+		at,
+		at,
+	)
+	if err == nil {
+		return price, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, errors.WithStack(err)
+	}
+
+	err = p.client.GetContext(
+		ctx,
+		&price,
+		`SELECT price FROM product_price
+		 WHERE product_id = ? AND kind = ? AND effective_from <= ?
+		 ORDER BY effective_from DESC, id DESC
+		 LIMIT 1`,
+		productID[:],
+		model.RegularPrice,
+		at,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errors.WithStack(query.ErrPriceNotFound)
+		}
+		return 0, errors.WithStack(err)
+	}
+	return price, nil
+}
+
+func (p *priceQueryService) ListPriceHistory(ctx context.Context, productID uuid.UUID) ([]appmodel.ProductPrice, error) {
+	var rows []struct {
+		ID            uuid.UUID       `db:"id"`
+		Kind          model.PriceKind `db:"kind"`
+		Price         float64         `db:"price"`
+		EffectiveFrom time.Time       `db:"effective_from"`
+		EffectiveTo   *time.Time      `db:"effective_to"`
+		ActivatedAt   *time.Time      `db:"activated_at"`
+	}
+	err := p.client.SelectContext(
+		ctx,
+		&rows,
+		`SELECT id, kind, price, effective_from, effective_to, activated_at
+		 FROM product_price
+		 WHERE product_id = ?
+		 ORDER BY effective_from DESC, id DESC`,
+		productID[:],
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	prices := make([]appmodel.ProductPrice, 0, len(rows))
+	for _, row := range rows {
+		prices = append(prices, appmodel.ProductPrice{
+			ID:            row.ID,
+			Kind:          row.Kind.String(),
+			Price:         row.Price,
+			EffectiveFrom: row.EffectiveFrom,
+			EffectiveTo:   row.EffectiveTo,
+			Active:        row.ActivatedAt != nil,
+		})
+	}
+	return prices, nil
+}
@@ -3,6 +3,10 @@ package queryservice
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
@@ -20,54 +24,321 @@ func NewProductQueryService(client mysql.ClientContext) query.ProductQueryServic
 	}
 }
 
+const (
+	defaultListProductsLimit = 20
+	maxListProductsLimit     = 100
+)
+
 type productQueryService struct {
 	client mysql.ClientContext
 }
 
-func (p *productQueryService) ListProducts(ctx context.Context) ([]appmodel.Product, error) {
-	var products []appmodel.Product
+func (p *productQueryService) ListProducts(ctx context.Context, spec appmodel.ListProductsSpec) (appmodel.ProductList, error) {
+	limit := spec.Limit
+	if limit <= 0 || limit > maxListProductsLimit {
+		limit = defaultListProductsLimit
+	}
+
+	var conditions []string
+	var args []interface{}
+	if !spec.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+	if spec.SKU != "" {
+		conditions = append(conditions, "sku = ?")
+		args = append(args, spec.SKU)
+	}
+	if spec.CategoryID != uuid.Nil {
+		conditions = append(conditions, `category_id IN (
+			WITH RECURSIVE subcategory (id) AS (
+				SELECT id FROM category WHERE id = ?
+				UNION ALL
+				SELECT c.id FROM category c INNER JOIN subcategory s ON c.parent_id = s.id
+			)
+			SELECT id FROM subcategory
+		)`)
+		args = append(args, spec.CategoryID[:])
+	}
+	if spec.NameQuery != "" {
+		conditions = append(conditions, "name LIKE ?")
+		args = append(args, "%"+escapeLike(spec.NameQuery)+"%")
+	}
+	if spec.MinPrice != nil {
+		conditions = append(conditions, "price >= ?")
+		args = append(args, *spec.MinPrice)
+	}
+	if spec.MaxPrice != nil {
+		conditions = append(conditions, "price <= ?")
+		args = append(args, *spec.MaxPrice)
+	}
+	if spec.InStockOnly {
+		conditions = append(conditions, productQuantity+" > 0")
+	}
+	if spec.Cursor != "" {
+		afterID, err := decodeCursor(spec.Cursor)
+		if err != nil {
+			return appmodel.ProductList{}, err
+		}
+		conditions = append(conditions, "id > ?")
+		args = append(args, afterID[:])
+	}
+	// fetch one extra row to know whether next page exists
+	args = append(args, limit+1)
 
+	var rows []sqlxProduct
 	err := p.client.SelectContext(
 		ctx,
-		&products,
-		`SELECT product_id, name, price, quantity, created_at 
-		 FROM product 
-		 WHERE deleted_at IS NULL`,
+		&rows,
+		selectProduct+whereClause(conditions)+` ORDER BY id LIMIT ?`,
+		args...,
 	)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return appmodel.ProductList{}, errors.WithStack(err)
+	}
+
+	var result appmodel.ProductList
+	if len(rows) > limit {
+		rows = rows[:limit]
+		result.NextCursor = encodeCursor(rows[limit-1].ID)
+	}
+	result.Products = make([]appmodel.Product, 0, len(rows))
+	for _, row := range rows {
+		product, err2 := row.toAppModel()
+		if err2 != nil {
+			return appmodel.ProductList{}, err2
+		}
+		result.Products = append(result.Products, *product)
 	}
+	return result, nil
+}
 
-	return products, nil
+func (p *productQueryService) FindProduct(ctx context.Context, id uuid.UUID, includeDeleted bool) (*appmodel.Product, error) {
+	if includeDeleted {
+		return p.findProduct(ctx, selectProduct+` WHERE id = ?`, id[:])
+	}
+	return p.findProduct(ctx, selectProduct+` WHERE id = ? AND deleted_at IS NULL`, id[:])
 }
 
-func (p *productQueryService) FindProduct(ctx context.Context, id uuid.UUID) (*appmodel.Product, error) {
-	row := struct {
-		ID       uuid.UUID `db:"product_id"`
-		Name     string    `db:"name"`
-		Price    float64   `db:"price"`
-		Quantity int64     `db:"quantity"`
-	}{}
+func (p *productQueryService) FindProductBySKU(ctx context.Context, sku string) (*appmodel.Product, error) {
+	return p.findProduct(ctx, selectProduct+` WHERE sku = ? AND deleted_at IS NULL`, sku)
+}
 
-	err := p.client.GetContext(
-		ctx,
-		&row,
-		`SELECT product_id, name, price, quantity, created_at 
-		 FROM product 
-		 WHERE product_id = ? AND deleted_at IS NULL`,
-		id,
-	)
+func (p *productQueryService) findProduct(ctx context.Context, query string, args ...interface{}) (*appmodel.Product, error) {
+	var row sqlxProduct
+	err := p.client.GetContext(ctx, &row, query, args...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.WithStack(model.ErrProductNotFound)
 		}
 		return nil, errors.WithStack(err)
 	}
+	product, err := row.toAppModel()
+	if err != nil {
+		return nil, err
+	}
+	product.Components, err = p.listComponents(ctx, product.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(product.Components) > 0 {
+		product.Stock, err = p.listBundleStock(ctx, product.ID)
+	} else {
+		product.Stock, err = p.listStock(ctx, product.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if product.ParentID != uuid.Nil {
+		return product, nil
+	}
+	// variants of deleted product are shown together with it
+	variantQuery := selectProduct + ` WHERE parent_id = ? AND deleted_at IS NULL ORDER BY sku`
+	if product.DeletedAt != nil {
+		variantQuery = selectProduct + ` WHERE parent_id = ? ORDER BY sku`
+	}
+	var variantRows []sqlxProduct
+	err = p.client.SelectContext(
+		ctx,
+		&variantRows,
+		variantQuery,
+		product.ID[:],
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, variantRow := range variantRows {
+		variant, err2 := variantRow.toAppModel()
+		if err2 != nil {
+			return nil, err2
+		}
+		variant.Stock, err2 = p.listStock(ctx, variant.ID)
+		if err2 != nil {
+			return nil, err2
+		}
+		product.Variants = append(product.Variants, *variant)
+	}
+	return product, nil
+}
+
+func (p *productQueryService) listStock(ctx context.Context, productID uuid.UUID) ([]appmodel.WarehouseStock, error) {
+	var stock []struct {
+		WarehouseID   uuid.UUID `db:"warehouse_id"`
+		WarehouseName string    `db:"warehouse_name"`
+		Quantity      int       `db:"quantity"`
+	}
+	err := p.client.SelectContext(
+		ctx,
+		&stock,
+		`SELECT sl.warehouse_id, w.name AS warehouse_name, sl.quantity
+		 FROM stock_level sl
+		 INNER JOIN warehouse w ON w.id = sl.warehouse_id
+		 WHERE sl.product_id = ?
+		 ORDER BY w.priority, w.id`,
+		productID[:],
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]appmodel.WarehouseStock, 0, len(stock))
+	for _, s := range stock {
+		result = append(result, appmodel.WarehouseStock(s))
+	}
+	return result, nil
+}
+
+// listComponents returns components of the bundle with quantity of each component in one bundle
+func (p *productQueryService) listComponents(ctx context.Context, bundleID uuid.UUID) ([]appmodel.BundleComponent, error) {
+	var components []struct {
+		ProductID uuid.UUID `db:"component_id"`
+		Quantity  int       `db:"quantity"`
+	}
+	err := p.client.SelectContext(
+		ctx,
+		&components,
+		`SELECT component_id, quantity FROM bundle_component WHERE bundle_id = ? ORDER BY component_id`,
+		bundleID[:],
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]appmodel.BundleComponent, 0, len(components))
+	for _, c := range components {
+		result = append(result, appmodel.BundleComponent(c))
+	}
+	return result, nil
+}
+
+// listBundleStock returns how many bundles can be assembled at every warehouse holding all components
+func (p *productQueryService) listBundleStock(ctx context.Context, bundleID uuid.UUID) ([]appmodel.WarehouseStock, error) {
+	var stock []struct {
+		WarehouseID   uuid.UUID `db:"warehouse_id"`
+		WarehouseName string    `db:"warehouse_name"`
+		Quantity      int       `db:"quantity"`
+	}
+	err := p.client.SelectContext(
+		ctx,
+		&stock,
+		`SELECT w.id AS warehouse_id, w.name AS warehouse_name, MIN(COALESCE(sl.quantity, 0) DIV bc.quantity) AS quantity
+		 FROM warehouse w
+		 CROSS JOIN bundle_component bc
+		 LEFT JOIN stock_level sl ON sl.product_id = bc.component_id AND sl.warehouse_id = w.id
+		 WHERE bc.bundle_id = ?
+		 GROUP BY w.id, w.name, w.priority
+		 HAVING quantity > 0
+		 ORDER BY w.priority, w.id`,
+		bundleID[:],
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 
+	result := make([]appmodel.WarehouseStock, 0, len(stock))
+	for _, s := range stock {
+		result = append(result, appmodel.WarehouseStock(s))
+	}
+	return result, nil
+}
+
+// productQuantity is how many bundles can be assembled from components stock, own quantity for other products
+const productQuantity = `COALESCE((
+	SELECT MIN(c.quantity DIV bc.quantity)
+	FROM bundle_component bc
+	INNER JOIN product c ON c.id = bc.component_id
+	WHERE bc.bundle_id = product.id
+), product.quantity)`
+
+const selectProduct = `SELECT id, sku, parent_id, category_id, attributes, name, price, ` + productQuantity + ` AS quantity, reorder_threshold, deleted_at FROM product`
+
+type sqlxProduct struct {
+	ID               uuid.UUID      `db:"id"`
+	SKU              sql.NullString `db:"sku"`
+	ParentID         uuid.UUID      `db:"parent_id"`
+	CategoryID       uuid.UUID      `db:"category_id"`
+	Attributes       []byte         `db:"attributes"`
+	Name             string         `db:"name"`
+	Price            float64        `db:"price"`
+	Quantity         int            `db:"quantity"`
+	ReorderThreshold int            `db:"reorder_threshold"`
+	DeletedAt        *time.Time     `db:"deleted_at"`
+}
+
+func (r sqlxProduct) toAppModel() (*appmodel.Product, error) {
+	var attributes []appmodel.Attribute
+	if len(r.Attributes) > 0 {
+		var rows []struct {
+			Name  string `json:"name"`
+			Type  string `json:"type"`
+			Value string `json:"value"`
+		}
+		err := json.Unmarshal(r.Attributes, &rows)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		attributes = make([]appmodel.Attribute, 0, len(rows))
+		for _, row := range rows {
+			attributes = append(attributes, appmodel.Attribute(row))
+		}
+	}
 	return &appmodel.Product{
-		ID:       row.ID,
-		Name:     row.Name,
-		Price:    row.Price,
-		Quantity: int(row.Quantity),
+		ID:               r.ID,
+		SKU:              r.SKU.String,
+		ParentID:         r.ParentID,
+		CategoryID:       r.CategoryID,
+		Attributes:       attributes,
+		Name:             r.Name,
+		Price:            r.Price,
+		Quantity:         r.Quantity,
+		ReorderThreshold: r.ReorderThreshold,
+		DeletedAt:        r.DeletedAt,
 	}, nil
 }
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return ` WHERE ` + strings.Join(conditions, " AND ")
+}
+
+func encodeCursor(id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString(id[:])
+}
+
+func decodeCursor(cursor string) (uuid.UUID, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return uuid.Nil, errors.WithStack(query.ErrInvalidCursor)
+	}
+	id, err := uuid.FromBytes(b)
+	if err != nil {
+		return uuid.Nil, errors.WithStack(query.ErrInvalidCursor)
+	}
+	return id, nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
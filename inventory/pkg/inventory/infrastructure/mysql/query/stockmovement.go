@@ -0,0 +1,88 @@
+package queryservice
+
+import (
+	"context"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appmodel "inventory/pkg/inventory/app/model"
+	"inventory/pkg/inventory/app/query"
+	"inventory/pkg/inventory/domain/model"
+)
+
+func NewStockMovementQueryService(client mysql.ClientContext) query.StockMovementQueryService {
+	return &stockMovementQueryService{
+		client: client,
+	}
+}
+
+type stockMovementQueryService struct {
+	client mysql.ClientContext
+}
+
+func (s *stockMovementQueryService) ListStockMovements(
+	ctx context.Context,
+	productID uuid.UUID,
+	cursor string,
+	limit int,
+) (appmodel.StockMovementList, error) {
+	if limit <= 0 || limit > maxListProductsLimit {
+		limit = defaultListProductsLimit
+	}
+
+	sqlQuery := `SELECT id, product_id, warehouse_id, delta, quantity_after, reason, reference_id, actor, created_at FROM stock_movement WHERE product_id = ?`
+	args := []interface{}{productID[:]}
+	if cursor != "" {
+		beforeID, err := decodeCursor(cursor)
+		if err != nil {
+			return appmodel.StockMovementList{}, err
+		}
+		sqlQuery += ` AND id < ?`
+		args = append(args, beforeID[:])
+	}
+	sqlQuery += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, limit+1)
+
+	var rows []struct {
+		ID            uuid.UUID  `db:"id"`
+		ProductID     uuid.UUID  `db:"product_id"`
+		WarehouseID   uuid.UUID  `db:"warehouse_id"`
+		Delta         int        `db:"delta"`
+		QuantityAfter int        `db:"quantity_after"`
+		Reason        int        `db:"reason"`
+		ReferenceID   *uuid.UUID `db:"reference_id"`
+		Actor         string     `db:"actor"`
+		CreatedAt     time.Time  `db:"created_at"`
+	}
+	err := s.client.SelectContext(ctx, &rows, sqlQuery, args...)
+	if err != nil {
+		return appmodel.StockMovementList{}, errors.WithStack(err)
+	}
+
+	var result appmodel.StockMovementList
+	if len(rows) > limit {
+		rows = rows[:limit]
+		result.NextCursor = encodeCursor(rows[limit-1].ID)
+	}
+	result.Movements = make([]appmodel.StockMovement, 0, len(rows))
+	for _, row := range rows {
+		movement := appmodel.StockMovement{
+			ID:            row.ID,
+			ProductID:     row.ProductID,
+			WarehouseID:   row.WarehouseID,
+			Delta:         row.Delta,
+			QuantityAfter: row.QuantityAfter,
+			Reason:        model.StockChangeReason(row.Reason).String(),
+			Actor:         row.Actor,
+			CreatedAt:     row.CreatedAt,
+		}
+		if row.ReferenceID != nil {
+			movement.ReferenceID = *row.ReferenceID
+		}
+		result.Movements = append(result.Movements, movement)
+	}
+	return result, nil
+}
@@ -0,0 +1,45 @@
+package queryservice
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appmodel "inventory/pkg/inventory/app/model"
+	"inventory/pkg/inventory/app/query"
+)
+
+func NewWarehouseQueryService(client mysql.ClientContext) query.WarehouseQueryService {
+	return &warehouseQueryService{
+		client: client,
+	}
+}
+
+type warehouseQueryService struct {
+	client mysql.ClientContext
+}
+
+func (w *warehouseQueryService) ListWarehouses(ctx context.Context) ([]appmodel.Warehouse, error) {
+	var rows []struct {
+		ID        uuid.UUID `db:"id"`
+		Name      string    `db:"name"`
+		Priority  int       `db:"priority"`
+		IsDefault bool      `db:"is_default"`
+	}
+	err := w.client.SelectContext(
+		ctx,
+		&rows,
+		`SELECT id, name, priority, is_default FROM warehouse ORDER BY priority, id`,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	warehouses := make([]appmodel.Warehouse, 0, len(rows))
+	for _, row := range rows {
+		warehouses = append(warehouses, appmodel.Warehouse(row))
+	}
+	return warehouses, nil
+}
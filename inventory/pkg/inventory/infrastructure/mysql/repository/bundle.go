@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"inventory/pkg/inventory/domain/model"
+)
+
+func NewBundleRepository(ctx context.Context, client mysql.ClientContext) model.BundleRepository {
+	return &bundleRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type bundleRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (b *bundleRepository) Store(bundleID uuid.UUID, components []model.BundleComponent) error {
+	for _, component := range components {
+		_, err := b.client.ExecContext(b.ctx,
+			`INSERT INTO bundle_component (bundle_id, component_id, quantity) VALUES (?, ?, ?)`,
+			bundleID[:],
+			component.ProductID[:],
+			component.Quantity,
+		)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func (b *bundleRepository) FindComponents(bundleID uuid.UUID) ([]model.BundleComponent, error) {
+	var rows []struct {
+		ComponentID uuid.UUID `db:"component_id"`
+		Quantity    int       `db:"quantity"`
+	}
+	err := b.client.SelectContext(b.ctx,
+		&rows,
+		`SELECT component_id, quantity FROM bundle_component WHERE bundle_id = ? ORDER BY component_id`,
+		bundleID[:],
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	components := make([]model.BundleComponent, 0, len(rows))
+	for _, row := range rows {
+		components = append(components, model.BundleComponent{
+			ProductID: row.ComponentID,
+			Quantity:  row.Quantity,
+		})
+	}
+	return components, nil
+}
+
+func (b *bundleRepository) ListBundlesByComponent(productID uuid.UUID) ([]uuid.UUID, error) {
+	var bundleIDs []uuid.UUID
+	err := b.client.SelectContext(b.ctx,
+		&bundleIDs,
+		`SELECT bc.bundle_id
+		 FROM bundle_component bc
+		 INNER JOIN product p ON p.id = bc.bundle_id
+		 WHERE bc.component_id = ? AND p.deleted_at IS NULL`,
+		productID[:],
+	)
+	return bundleIDs, errors.WithStack(err)
+}
+
+func (b *bundleRepository) ListBundlesByComponentWithDeleted(productID uuid.UUID) ([]uuid.UUID, error) {
+	var bundleIDs []uuid.UUID
+	err := b.client.SelectContext(b.ctx,
+		&bundleIDs,
+		`SELECT bundle_id FROM bundle_component WHERE component_id = ?`,
+		productID[:],
+	)
+	return bundleIDs, errors.WithStack(err)
+}
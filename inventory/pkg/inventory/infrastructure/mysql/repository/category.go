@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"inventory/pkg/inventory/domain/model"
+)
+
+func NewCategoryRepository(ctx context.Context, client mysql.ClientContext) model.CategoryRepository {
+	return &categoryRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type categoryRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (c *categoryRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (c *categoryRepository) Store(category *model.Category) error {
+	_, err := c.client.ExecContext(c.ctx,
+		`
+		INSERT INTO category (id, parent_id, name, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			parent_id=VALUES(parent_id),
+			name=VALUES(name),
+			updated_at=VALUES(updated_at)
+		`,
+		category.ID[:],
+		toSQLNullUUID(category.ParentID),
+		category.Name,
+		category.CreatedAt,
+		category.UpdatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (c *categoryRepository) Find(id uuid.UUID) (*model.Category, error) {
+	var row struct {
+		ID        uuid.UUID `db:"id"`
+		ParentID  uuid.UUID `db:"parent_id"`
+		Name      string    `db:"name"`
+		CreatedAt time.Time `db:"created_at"`
+		UpdatedAt time.Time `db:"updated_at"`
+	}
+	err := c.client.GetContext(c.ctx, &row, `SELECT id, parent_id, name, created_at, updated_at FROM category WHERE id = ?`, id[:])
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrCategoryNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+	category := model.Category(row)
+	return &category, nil
+}
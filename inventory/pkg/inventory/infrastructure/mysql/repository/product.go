@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
@@ -24,6 +25,30 @@ type productRepository struct {
 	client mysql.ClientContext
 }
 
+const selectProduct = `SELECT id, sku, parent_id, category_id, attributes, name, price, quantity, reorder_threshold, created_at, updated_at, deleted_at FROM product`
+
+type sqlxProduct struct {
+	ID         uuid.UUID      `db:"id"`
+	SKU        sql.NullString `db:"sku"`
+	ParentID   uuid.UUID      `db:"parent_id"`
+	CategoryID uuid.UUID      `db:"category_id"`
+	Attributes []byte         `db:"attributes"`
+	Name       string         `db:"name"`
+	Price      float64        `db:"price"`
+	Quantity   int            `db:"quantity"`
+	Threshold  int            `db:"reorder_threshold"`
+	CreatedAt  time.Time      `db:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at"`
+	DeletedAt  *time.Time     `db:"deleted_at"`
+}
+
+// sqlxAttribute is json representation of attribute stored in 'attributes' column
+type sqlxAttribute struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
 func (p *productRepository) NextID() (uuid.UUID, error) {
 	return uuid.NewV7()
 }
@@ -32,22 +57,35 @@ func (p *productRepository) Store(product *model.Product) error {
 	if product.Quantity < 0 {
 		return errors.WithStack(model.ErrProductQuantityLessThanZero)
 	}
+	attributes, err := marshalAttributes(product.Attributes)
+	if err != nil {
+		return err
+	}
 
-	_, err := p.client.ExecContext(p.ctx,
+	_, err = p.client.ExecContext(p.ctx,
 		`
-		INSERT INTO product (id, name, price, quantity, created_at, updated_at, deleted_at) 
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO product (id, sku, parent_id, category_id, attributes, name, price, quantity, reorder_threshold, created_at, updated_at, deleted_at) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON DUPLICATE KEY UPDATE
+			sku=VALUES(sku),
+			category_id=VALUES(category_id),
+			attributes=VALUES(attributes),
 			name=VALUES(name),
 			price=VALUES(price),
 			quantity=VALUES(quantity),
+			reorder_threshold=VALUES(reorder_threshold),
 			updated_at=VALUES(updated_at),
 			deleted_at=VALUES(deleted_at)
 		`,
-		product.ID,
+		product.ID[:],
+		sql.NullString{String: product.SKU, Valid: product.SKU != ""},
+		toSQLNullUUID(product.ParentID),
+		toSQLNullUUID(product.CategoryID),
+		attributes,
 		product.Name,
 		product.Price,
 		product.Quantity,
+		product.ReorderThreshold,
 		product.CreatedAt,
 		product.UpdatedAt,
 		toSQLNullTime(product.DeletedAt),
@@ -56,55 +94,20 @@ func (p *productRepository) Store(product *model.Product) error {
 }
 
 func (p *productRepository) Find(id uuid.UUID) (*model.Product, error) {
-	row := struct {
-		ID        uuid.UUID  `db:"id"`
-		Name      string     `db:"name"`
-		Price     float64    `db:"price"`
-		Quantity  int        `db:"quantity"`
-		CreatedAt time.Time  `db:"created_at"`
-		UpdatedAt time.Time  `db:"updated_at"`
-		DeletedAt *time.Time `db:"deleted_at"`
-	}{}
-
-	err := p.client.GetContext(
-		p.ctx,
-		&row,
-		`SELECT id, name, price, quantity, created_at, updated_at, deleted_at FROM product WHERE id = ? AND deleted_at IS NULL`,
-		id,
-	)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.WithStack(model.ErrProductNotFound)
-		}
-		return nil, errors.WithStack(err)
-	}
+	return p.findOne(selectProduct+` WHERE id = ? AND deleted_at IS NULL`, id[:])
+}
 
-	return &model.Product{
-		ID:        row.ID,
-		Name:      row.Name,
-		Price:     row.Price,
-		Quantity:  row.Quantity,
-		CreatedAt: row.CreatedAt,
-		UpdatedAt: row.UpdatedAt,
-		DeletedAt: row.DeletedAt,
-	}, nil
+func (p *productRepository) FindBySKU(sku string) (*model.Product, error) {
+	return p.findOne(selectProduct+` WHERE sku = ? AND deleted_at IS NULL`, sku)
 }
 
 func (p *productRepository) List() ([]model.Product, error) {
-	var rows []struct {
-		ID        uuid.UUID  `db:"id"`
-		Name      string     `db:"name"`
-		Price     float64    `db:"price"`
-		Quantity  int        `db:"quantity"`
-		CreatedAt time.Time  `db:"created_at"`
-		UpdatedAt time.Time  `db:"updated_at"`
-		DeletedAt *time.Time `db:"deleted_at"`
-	}
+	var rows []sqlxProduct
 
 	err := p.client.SelectContext(
 		p.ctx,
 		&rows,
-		`SELECT id, name, price, quantity, created_at, updated_at, deleted_at FROM product WHERE deleted_at IS NULL ORDER BY created_at DESC`,
+		selectProduct+` WHERE deleted_at IS NULL ORDER BY created_at DESC`,
 	)
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -112,15 +115,11 @@ func (p *productRepository) List() ([]model.Product, error) {
 
 	products := make([]model.Product, len(rows))
 	for i, r := range rows {
-		products[i] = model.Product{
-			ID:        r.ID,
-			Name:      r.Name,
-			Price:     r.Price,
-			Quantity:  r.Quantity,
-			CreatedAt: r.CreatedAt,
-			UpdatedAt: r.UpdatedAt,
-			DeletedAt: r.DeletedAt,
+		product, err2 := r.toModel()
+		if err2 != nil {
+			return nil, err2
 		}
+		products[i] = *product
 	}
 
 	return products, nil
@@ -131,11 +130,127 @@ func (p *productRepository) Delete(id uuid.UUID) error {
 	_, err := p.client.ExecContext(p.ctx,
 		`UPDATE product SET deleted_at = ? WHERE id = ?`,
 		now,
-		id,
+		id[:],
 	)
 	return errors.WithStack(err)
 }
 
+func (p *productRepository) FindWithDeleted(id uuid.UUID) (*model.Product, error) {
+	return p.findOne(selectProduct+` WHERE id = ?`, id[:])
+}
+
+func (p *productRepository) FindBySKUWithDeleted(sku string) (*model.Product, error) {
+	return p.findOne(selectProduct+` WHERE sku = ?`, sku)
+}
+
+func (p *productRepository) ListDeletedBefore(before time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := p.client.SelectContext(
+		p.ctx,
+		&ids,
+		`SELECT id FROM product WHERE deleted_at < ? ORDER BY parent_id IS NULL, deleted_at`,
+		before,
+	)
+	return ids, errors.WithStack(err)
+}
+
+func (p *productRepository) HasVariants(id uuid.UUID) (bool, error) {
+	var exists bool
+	err := p.client.GetContext(
+		p.ctx,
+		&exists,
+		`SELECT EXISTS(SELECT 1 FROM product WHERE parent_id = ?)`,
+		id[:],
+	)
+	return exists, errors.WithStack(err)
+}
+
+func (p *productRepository) Purge(id uuid.UUID) error {
+	for _, query := range []string{
+		`DELETE FROM stock_level WHERE product_id = ?`,
+		`DELETE FROM product_price WHERE product_id = ?`,
+		`DELETE FROM bundle_component WHERE bundle_id = ?`,
+		`DELETE FROM bundle_component WHERE component_id = ?`,
+		`DELETE FROM product WHERE id = ?`,
+	} {
+		_, err := p.client.ExecContext(p.ctx, query, id[:])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func (p *productRepository) findOne(query string, args ...interface{}) (*model.Product, error) {
+	var row sqlxProduct
+	err := p.client.GetContext(p.ctx, &row, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrProductNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+	return row.toModel()
+}
+
+func (r sqlxProduct) toModel() (*model.Product, error) {
+	attributes, err := unmarshalAttributes(r.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Product{
+		ID:               r.ID,
+		SKU:              r.SKU.String,
+		ParentID:         r.ParentID,
+		CategoryID:       r.CategoryID,
+		Attributes:       attributes,
+		Name:             r.Name,
+		Price:            r.Price,
+		Quantity:         r.Quantity,
+		ReorderThreshold: r.Threshold,
+		CreatedAt:        r.CreatedAt,
+		UpdatedAt:        r.UpdatedAt,
+		DeletedAt:        r.DeletedAt,
+	}, nil
+}
+
+func marshalAttributes(attributes []model.Attribute) ([]byte, error) {
+	rows := make([]sqlxAttribute, 0, len(attributes))
+	for _, attribute := range attributes {
+		rows = append(rows, sqlxAttribute{
+			Name:  attribute.Name,
+			Type:  attribute.Type.String(),
+			Value: attribute.Value,
+		})
+	}
+	b, err := json.Marshal(rows)
+	return b, errors.WithStack(err)
+}
+
+func unmarshalAttributes(b []byte) ([]model.Attribute, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	var rows []sqlxAttribute
+	err := json.Unmarshal(b, &rows)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	attributes := make([]model.Attribute, 0, len(rows))
+	for _, row := range rows {
+		attributeType, err2 := model.ParseAttributeType(row.Type)
+		if err2 != nil {
+			return nil, errors.WithStack(err2)
+		}
+		attributes = append(attributes, model.Attribute{
+			Name:  row.Name,
+			Type:  attributeType,
+			Value: row.Value,
+		})
+	}
+	return attributes, nil
+}
+
 func toSQLNullTime(t *time.Time) sql.Null[time.Time] {
 	if t == nil {
 		return sql.Null[time.Time]{}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"inventory/pkg/inventory/domain/model"
+)
+
+func NewProductPriceRepository(ctx context.Context, client mysql.ClientContext) model.ProductPriceRepository {
+	return &productPriceRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type productPriceRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (p *productPriceRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (p *productPriceRepository) Store(price *model.ProductPrice) error {
+	_, err := p.client.ExecContext(p.ctx,
+		`
+		INSERT INTO product_price (id, product_id, kind, price, effective_from, effective_to, activated_at, expired_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			effective_to=VALUES(effective_to),
+			activated_at=VALUES(activated_at),
+			expired_at=VALUES(expired_at)
+		`,
+		price.ID[:],
+		price.ProductID[:],
+		price.Kind,
+		price.Price,
+		price.EffectiveFrom,
+		toSQLNullTime(price.EffectiveTo),
+		toSQLNullTime(price.ActivatedAt),
+		toSQLNullTime(price.ExpiredAt),
+		price.CreatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (p *productPriceRepository) ListByProduct(productID uuid.UUID) ([]model.ProductPrice, error) {
+	var rows []struct {
+		ID            uuid.UUID       `db:"id"`
+		ProductID     uuid.UUID       `db:"product_id"`
+		Kind          model.PriceKind `db:"kind"`
+		Price         float64         `db:"price"`
+		EffectiveFrom time.Time       `db:"effective_from"`
+		EffectiveTo   *time.Time      `db:"effective_to"`
+		ActivatedAt   *time.Time      `db:"activated_at"`
+		ExpiredAt     *time.Time      `db:"expired_at"`
+		CreatedAt     time.Time       `db:"created_at"`
+	}
+	err := p.client.SelectContext(
+		p.ctx,
+		&rows,
+		`SELECT id, product_id, kind, price, effective_from, effective_to, activated_at, expired_at, created_at
+		 FROM product_price
+		 WHERE product_id = ?
+		 ORDER BY effective_from, id`,
+		productID[:],
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	prices := make([]model.ProductPrice, 0, len(rows))
+	for _, row := range rows {
+		prices = append(prices, model.ProductPrice(row))
+	}
+	return prices, nil
+}
+
+func (p *productPriceRepository) ListProductsWithDuePrices(at time.Time) ([]uuid.UUID, error) {
+	var productIDs []uuid.UUID
+	err := p.client.SelectContext(
+		p.ctx,
+		&productIDs,
+		`SELECT DISTINCT product_id
+		 FROM product_price
+		 WHERE (activated_at IS NULL AND effective_from <= ?)
+		    OR (kind = ? AND expired_at IS NULL AND effective_to <= ?)`,
+		at,
+		model.SalePrice,
+		at,
+	)
+	return productIDs, errors.WithStack(err)
+}
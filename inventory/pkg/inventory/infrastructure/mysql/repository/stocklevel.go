@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"inventory/pkg/inventory/domain/model"
+)
+
+func NewStockLevelRepository(ctx context.Context, client mysql.ClientContext) model.StockLevelRepository {
+	return &stockLevelRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type stockLevelRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (s *stockLevelRepository) ListByProduct(productID uuid.UUID) ([]model.StockLevel, error) {
+	var rows []struct {
+		ProductID   uuid.UUID `db:"product_id"`
+		WarehouseID uuid.UUID `db:"warehouse_id"`
+		Quantity    int       `db:"quantity"`
+	}
+	err := s.client.SelectContext(
+		s.ctx,
+		&rows,
+		`SELECT product_id, warehouse_id, quantity FROM stock_level WHERE product_id = ?`,
+		productID[:],
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	levels := make([]model.StockLevel, 0, len(rows))
+	for _, row := range rows {
+		levels = append(levels, model.StockLevel(row))
+	}
+	return levels, nil
+}
+
+func (s *stockLevelRepository) Store(level model.StockLevel) error {
+	if level.Quantity < 0 {
+		return errors.WithStack(model.ErrProductQuantityLessThanZero)
+	}
+
+	_, err := s.client.ExecContext(s.ctx,
+		`
+		INSERT INTO stock_level (product_id, warehouse_id, quantity) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			quantity=VALUES(quantity)
+		`,
+		level.ProductID[:],
+		level.WarehouseID[:],
+		level.Quantity,
+	)
+	return errors.WithStack(err)
+}
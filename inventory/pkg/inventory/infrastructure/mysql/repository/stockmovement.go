@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"inventory/pkg/inventory/domain/model"
+)
+
+func NewStockMovementRepository(ctx context.Context, client mysql.ClientContext) model.StockMovementRepository {
+	return &stockMovementRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type stockMovementRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (s *stockMovementRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (s *stockMovementRepository) Append(movement model.StockMovement) error {
+	_, err := s.client.ExecContext(s.ctx,
+		`
+		INSERT INTO stock_movement (id, product_id, warehouse_id, delta, quantity_after, reason, reference_id, actor, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`,
+		movement.ID[:],
+		movement.ProductID[:],
+		movement.WarehouseID[:],
+		movement.Delta,
+		movement.QuantityAfter,
+		movement.Reason,
+		toSQLNullUUID(movement.ReferenceID),
+		movement.Actor,
+		movement.CreatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (s *stockMovementRepository) ListByReference(referenceID uuid.UUID) ([]model.StockMovement, error) {
+	var movements []sqlxStockMovement
+	err := s.client.SelectContext(s.ctx,
+		&movements,
+		`
+		SELECT id, product_id, warehouse_id, delta, quantity_after, reason, reference_id, actor, created_at
+		FROM stock_movement
+		WHERE reference_id = ?
+		ORDER BY created_at, id
+		`,
+		referenceID[:],
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]model.StockMovement, 0, len(movements))
+	for _, movement := range movements {
+		result = append(result, model.StockMovement{
+			ID:            movement.ID,
+			ProductID:     movement.ProductID,
+			WarehouseID:   movement.WarehouseID,
+			Delta:         movement.Delta,
+			QuantityAfter: movement.QuantityAfter,
+			Reason:        model.StockChangeReason(movement.Reason),
+			ReferenceID:   movement.ReferenceID,
+			Actor:         movement.Actor,
+			CreatedAt:     movement.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+type sqlxStockMovement struct {
+	ID            uuid.UUID `db:"id"`
+	ProductID     uuid.UUID `db:"product_id"`
+	WarehouseID   uuid.UUID `db:"warehouse_id"`
+	Delta         int       `db:"delta"`
+	QuantityAfter int       `db:"quantity_after"`
+	Reason        int       `db:"reason"`
+	ReferenceID   uuid.UUID `db:"reference_id"`
+	Actor         string    `db:"actor"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+func toSQLNullUUID(id uuid.UUID) sql.Null[[]byte] {
+	if id == uuid.Nil {
+		return sql.Null[[]byte]{}
+	}
+	return sql.Null[[]byte]{
+		V:     id[:],
+		Valid: true,
+	}
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"inventory/pkg/inventory/domain/model"
+)
+
+func NewWarehouseRepository(ctx context.Context, client mysql.ClientContext) model.WarehouseRepository {
+	return &warehouseRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type warehouseRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+type sqlxWarehouse struct {
+	ID        uuid.UUID `db:"id"`
+	Name      string    `db:"name"`
+	Priority  int       `db:"priority"`
+	IsDefault bool      `db:"is_default"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (w *warehouseRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (w *warehouseRepository) Store(warehouse *model.Warehouse) error {
+	_, err := w.client.ExecContext(w.ctx,
+		`
+		INSERT INTO warehouse (id, name, priority, is_default, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			name=VALUES(name),
+			priority=VALUES(priority),
+			updated_at=VALUES(updated_at)
+		`,
+		warehouse.ID[:],
+		warehouse.Name,
+		warehouse.Priority,
+		warehouse.IsDefault,
+		warehouse.CreatedAt,
+		warehouse.UpdatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (w *warehouseRepository) Find(id uuid.UUID) (*model.Warehouse, error) {
+	return w.findOne(model.ErrWarehouseNotFound, `SELECT id, name, priority, is_default, created_at, updated_at FROM warehouse WHERE id = ?`, id[:])
+}
+
+func (w *warehouseRepository) FindDefault() (*model.Warehouse, error) {
+	return w.findOne(model.ErrDefaultWarehouseNotFound, `SELECT id, name, priority, is_default, created_at, updated_at FROM warehouse WHERE is_default = TRUE LIMIT 1`)
+}
+
+func (w *warehouseRepository) List() ([]model.Warehouse, error) {
+	var rows []sqlxWarehouse
+	err := w.client.SelectContext(
+		w.ctx,
+		&rows,
+		`SELECT id, name, priority, is_default, created_at, updated_at FROM warehouse ORDER BY priority, id`,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	warehouses := make([]model.Warehouse, 0, len(rows))
+	for _, row := range rows {
+		warehouses = append(warehouses, model.Warehouse(row))
+	}
+	return warehouses, nil
+}
+
+func (w *warehouseRepository) findOne(notFoundErr error, query string, args ...interface{}) (*model.Warehouse, error) {
+	var row sqlxWarehouse
+	err := w.client.GetContext(w.ctx, &row, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(notFoundErr)
+		}
+		return nil, errors.WithStack(err)
+	}
+	warehouse := model.Warehouse(row)
+	return &warehouse, nil
+}
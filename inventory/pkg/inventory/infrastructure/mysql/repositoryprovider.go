@@ -22,3 +22,27 @@ type repositoryProvider struct {
 func (r *repositoryProvider) ProductRepository(ctx context.Context) model.ProductRepository {
 	return repository.NewProductRepository(ctx, r.client)
 }
+
+func (r *repositoryProvider) StockMovementRepository(ctx context.Context) model.StockMovementRepository {
+	return repository.NewStockMovementRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) StockLevelRepository(ctx context.Context) model.StockLevelRepository {
+	return repository.NewStockLevelRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) WarehouseRepository(ctx context.Context) model.WarehouseRepository {
+	return repository.NewWarehouseRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) CategoryRepository(ctx context.Context) model.CategoryRepository {
+	return repository.NewCategoryRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) ProductPriceRepository(ctx context.Context) model.ProductPriceRepository {
+	return repository.NewProductPriceRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) BundleRepository(ctx context.Context) model.BundleRepository {
+	return repository.NewBundleRepository(ctx, r.client)
+}
@@ -0,0 +1,70 @@
+package transport
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"inventory/api/server/inventorypublicapi"
+	appmodel "inventory/pkg/inventory/app/model"
+	"inventory/pkg/inventory/domain/model"
+)
+
+func (u inventoryInternalAPI) CreateBundle(ctx context.Context, request *inventorypublicapi.CreateBundleRequest) (*inventorypublicapi.CreateBundleResponse, error) {
+	categoryID, err := parseOptionalUUID(request.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	components := make([]appmodel.BundleComponent, 0, len(request.Components))
+	for _, component := range request.Components {
+		productID, err2 := uuid.Parse(component.ProductID)
+		if err2 != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", component.ProductID)
+		}
+		components = append(components, appmodel.BundleComponent{
+			ProductID: productID,
+			Quantity:  int(component.Quantity),
+		})
+	}
+
+	bundleID, err := u.inventoryService.CreateBundle(ctx, appmodel.Bundle{
+		SKU:        request.Sku,
+		CategoryID: categoryID,
+		Attributes: toAppAttributes(request.Attributes),
+		Name:       request.Name,
+		Price:      request.Price,
+		Components: components,
+	})
+	if err != nil {
+		return nil, bundleError(err)
+	}
+	return &inventorypublicapi.CreateBundleResponse{
+		BundleID: bundleID.String(),
+	}, nil
+}
+
+func bundleError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrProductNotFound):
+		return status.Error(codes.NotFound, "bundle component not found")
+	case errors.Is(err, model.ErrEmptyBundle),
+		errors.Is(err, model.ErrInvalidBundleComponent),
+		errors.Is(err, model.ErrNestedBundle):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return catalogError(err)
+}
+
+func toAPIBundleComponents(components []appmodel.BundleComponent) []*inventorypublicapi.BundleComponent {
+	result := make([]*inventorypublicapi.BundleComponent, 0, len(components))
+	for _, component := range components {
+		result = append(result, &inventorypublicapi.BundleComponent{
+			ProductID: component.ProductID.String(),
+			Quantity:  int64(component.Quantity),
+		})
+	}
+	return result
+}
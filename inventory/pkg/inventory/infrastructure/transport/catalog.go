@@ -0,0 +1,162 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"inventory/api/server/inventorypublicapi"
+	appmodel "inventory/pkg/inventory/app/model"
+	"inventory/pkg/inventory/domain/model"
+)
+
+func (u inventoryInternalAPI) FindProductBySKU(ctx context.Context, request *inventorypublicapi.FindProductBySKURequest) (*inventorypublicapi.FindProductResponse, error) {
+	if request.Sku == "" {
+		return nil, status.Error(codes.InvalidArgument, "sku is required")
+	}
+	product, err := u.inventoryQueryService.FindProductBySKU(ctx, request.Sku)
+	if err != nil {
+		if errors.Is(err, model.ErrProductNotFound) {
+			return nil, status.Errorf(codes.NotFound, "product with sku %q not found", request.Sku)
+		}
+		return nil, err
+	}
+	return toAPIProductResponse(*product), nil
+}
+
+func (u inventoryInternalAPI) StoreCategory(ctx context.Context, request *inventorypublicapi.StoreCategoryRequest) (*inventorypublicapi.StoreCategoryResponse, error) {
+	categoryID, err := parseOptionalUUID(request.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	parentID, err := parseOptionalUUID(request.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "category name is required")
+	}
+
+	categoryID, err = u.categoryService.StoreCategory(ctx, appmodel.Category{
+		ID:       categoryID,
+		ParentID: parentID,
+		Name:     request.Name,
+	})
+	if err != nil {
+		return nil, catalogError(err)
+	}
+	return &inventorypublicapi.StoreCategoryResponse{
+		CategoryID: categoryID.String(),
+	}, nil
+}
+
+func (u inventoryInternalAPI) ListCategories(ctx context.Context, _ *inventorypublicapi.ListCategoriesRequest) (*inventorypublicapi.ListCategoriesResponse, error) {
+	categories, err := u.categoryQueryService.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*inventorypublicapi.Category, 0, len(categories))
+	for _, category := range categories {
+		result = append(result, &inventorypublicapi.Category{
+			CategoryID: category.ID.String(),
+			ParentID:   optionalUUIDString(category.ParentID),
+			Name:       category.Name,
+		})
+	}
+	return &inventorypublicapi.ListCategoriesResponse{
+		Categories: result,
+	}, nil
+}
+
+func catalogError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrCategoryNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, model.ErrSKUAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, model.ErrCategoryCycle),
+		errors.Is(err, model.ErrEmptySKU),
+		errors.Is(err, model.ErrNestedVariant),
+		errors.Is(err, model.ErrInvalidAttribute),
+		errors.Is(err, model.ErrDuplicateAttribute),
+		errors.Is(err, model.ErrUnknownAttributeType),
+		errors.Is(err, model.ErrInvalidReorderThreshold),
+		errors.Is(err, model.ErrInvalidPrice):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return err
+}
+
+func toAppAttributes(attributes []*inventorypublicapi.Attribute) []appmodel.Attribute {
+	result := make([]appmodel.Attribute, 0, len(attributes))
+	for _, attribute := range attributes {
+		result = append(result, appmodel.Attribute{
+			Name:  attribute.Name,
+			Type:  model.AttributeType(attribute.Type).String(),
+			Value: attribute.Value,
+		})
+	}
+	return result
+}
+
+func toAPIAttributes(attributes []appmodel.Attribute) []*inventorypublicapi.Attribute {
+	result := make([]*inventorypublicapi.Attribute, 0, len(attributes))
+	for _, attribute := range attributes {
+		attributeType, _ := model.ParseAttributeType(attribute.Type)
+		result = append(result, &inventorypublicapi.Attribute{
+			Name:  attribute.Name,
+			Type:  inventorypublicapi.AttributeType(attributeType),
+			Value: attribute.Value,
+		})
+	}
+	return result
+}
+
+func toAPIProductResponse(product appmodel.Product) *inventorypublicapi.FindProductResponse {
+	stock := make([]*inventorypublicapi.WarehouseStock, 0, len(product.Stock))
+	for _, s := range product.Stock {
+		stock = append(stock, &inventorypublicapi.WarehouseStock{
+			WarehouseID:   s.WarehouseID.String(),
+			WarehouseName: s.WarehouseName,
+			Quantity:      int64(s.Quantity),
+		})
+	}
+	variants := make([]*inventorypublicapi.FindProductResponse, 0, len(product.Variants))
+	for _, variant := range product.Variants {
+		variants = append(variants, toAPIProductResponse(variant))
+	}
+	return &inventorypublicapi.FindProductResponse{
+		ProductID:        product.ID.String(),
+		Name:             product.Name,
+		Price:            product.Price,
+		Quantity:         int64(product.Quantity),
+		Stock:            stock,
+		ReorderThreshold: int64(product.ReorderThreshold),
+		Sku:              product.SKU,
+		ParentID:         optionalUUIDString(product.ParentID),
+		CategoryID:       optionalUUIDString(product.CategoryID),
+		Attributes:       toAPIAttributes(product.Attributes),
+		Variants:         variants,
+		DeletedAt:        optionalUnix(product.DeletedAt),
+		Components:       toAPIBundleComponents(product.Components),
+	}
+}
+
+func optionalUnix(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.Unix()
+}
+
+func optionalUUIDString(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
@@ -0,0 +1,83 @@
+package transport
+
+import (
+	"errors"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"inventory/api/server/inventorypublicapi"
+	appmodel "inventory/pkg/inventory/app/model"
+	"inventory/pkg/inventory/infrastructure/catalogfile"
+)
+
+var catalogFileFormats = map[inventorypublicapi.CatalogFileFormat]catalogfile.Format{
+	inventorypublicapi.CatalogFileFormat_CSV:        catalogfile.CSV,
+	inventorypublicapi.CatalogFileFormat_JSON_LINES: catalogfile.JSONLines,
+}
+
+func (u inventoryInternalAPI) ImportProducts(stream inventorypublicapi.InventoryPublicAPI_ImportProductsServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return status.Error(codes.InvalidArgument, "import file is empty")
+		}
+		return err
+	}
+	format, ok := catalogFileFormats[first.Format]
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "unknown format %v", first.Format)
+	}
+
+	source, err := catalogfile.NewReader(&importStreamReader{stream: stream, chunk: first.Chunk}, format)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	report, err := u.inventoryService.ImportProducts(stream.Context(), source, appmodel.ImportOptions{
+		DryRun:    first.DryRun,
+		BatchSize: int(first.BatchSize),
+	})
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(&inventorypublicapi.ImportProductsResponse{
+		Created:  int64(report.Created),
+		Updated:  int64(report.Updated),
+		Errors:   toAPIImportRowErrors(report.Errors),
+		DryRun:   first.DryRun,
+		Warnings: toAPIImportRowErrors(report.Warnings),
+	})
+}
+
+func toAPIImportRowErrors(rowErrors []appmodel.ImportRowError) []*inventorypublicapi.ImportRowError {
+	result := make([]*inventorypublicapi.ImportRowError, 0, len(rowErrors))
+	for _, rowErr := range rowErrors {
+		result = append(result, &inventorypublicapi.ImportRowError{
+			Row:     int64(rowErr.Row),
+			Sku:     rowErr.SKU,
+			Message: rowErr.Message,
+		})
+	}
+	return result
+}
+
+// importStreamReader joins chunks of client stream into import file
+type importStreamReader struct {
+	stream inventorypublicapi.InventoryPublicAPI_ImportProductsServer
+	chunk  []byte
+}
+
+func (r *importStreamReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		request, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.chunk = request.Chunk
+	}
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
@@ -0,0 +1,99 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"inventory/api/server/inventorypublicapi"
+	appmodel "inventory/pkg/inventory/app/model"
+	"inventory/pkg/inventory/app/query"
+	"inventory/pkg/inventory/domain/model"
+)
+
+func (u inventoryInternalAPI) SchedulePriceChange(ctx context.Context, request *inventorypublicapi.SchedulePriceChangeRequest) (*inventorypublicapi.SchedulePriceChangeResponse, error) {
+	productID, err := uuid.Parse(request.ProductID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.ProductID)
+	}
+
+	change := appmodel.PriceChange{
+		ProductID:     productID,
+		Price:         request.Price,
+		EffectiveFrom: time.Unix(request.EffectiveFrom, 0),
+	}
+	if request.EffectiveTo != 0 {
+		effectiveTo := time.Unix(request.EffectiveTo, 0)
+		change.EffectiveTo = &effectiveTo
+	}
+
+	priceID, err := u.inventoryService.SchedulePrice(ctx, change)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrProductNotFound):
+			return nil, status.Errorf(codes.NotFound, "product %q not found", request.ProductID)
+		case errors.Is(err, model.ErrInvalidPrice),
+			errors.Is(err, model.ErrInvalidPricePeriod):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, model.ErrOverlappingSale):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, err
+	}
+	return &inventorypublicapi.SchedulePriceChangeResponse{
+		PriceID: priceID.String(),
+	}, nil
+}
+
+func (u inventoryInternalAPI) GetPriceAt(ctx context.Context, request *inventorypublicapi.GetPriceAtRequest) (*inventorypublicapi.GetPriceAtResponse, error) {
+	productID, err := uuid.Parse(request.ProductID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.ProductID)
+	}
+
+	price, err := u.priceQueryService.FindPriceAt(ctx, productID, time.Unix(request.At, 0))
+	if err != nil {
+		if errors.Is(err, query.ErrPriceNotFound) {
+			return nil, status.Errorf(codes.NotFound, "product %q had no price at %d", request.ProductID, request.At)
+		}
+		return nil, err
+	}
+	return &inventorypublicapi.GetPriceAtResponse{
+		Price: price,
+	}, nil
+}
+
+func (u inventoryInternalAPI) ListPriceHistory(ctx context.Context, request *inventorypublicapi.ListPriceHistoryRequest) (*inventorypublicapi.ListPriceHistoryResponse, error) {
+	productID, err := uuid.Parse(request.ProductID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.ProductID)
+	}
+
+	prices, err := u.priceQueryService.ListPriceHistory(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*inventorypublicapi.ProductPrice, 0, len(prices))
+	for _, price := range prices {
+		var effectiveTo int64
+		if price.EffectiveTo != nil {
+			effectiveTo = price.EffectiveTo.Unix()
+		}
+		result = append(result, &inventorypublicapi.ProductPrice{
+			PriceID:       price.ID.String(),
+			Kind:          price.Kind,
+			Price:         price.Price,
+			EffectiveFrom: price.EffectiveFrom.Unix(),
+			EffectiveTo:   effectiveTo,
+			Active:        price.Active,
+		})
+	}
+	return &inventorypublicapi.ListPriceHistoryResponse{
+		Prices: result,
+	}, nil
+}
@@ -2,6 +2,7 @@ package transport
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
@@ -10,47 +11,96 @@ import (
 	appmodel "inventory/pkg/inventory/app/model"
 	"inventory/pkg/inventory/app/query"
 	"inventory/pkg/inventory/app/service"
+	"inventory/pkg/inventory/domain/model"
+	"inventory/pkg/inventory/infrastructure/transport/middlewares"
 
 	"inventory/api/server/inventorypublicapi"
 )
 
 func NewInventoryInternalAPI(
 	inventoryQueryService query.ProductQueryService,
+	stockMovementQueryService query.StockMovementQueryService,
+	warehouseQueryService query.WarehouseQueryService,
+	categoryQueryService query.CategoryQueryService,
+	priceQueryService query.PriceQueryService,
 	inventoryService service.ProductService,
+	warehouseService service.WarehouseService,
+	categoryService service.CategoryService,
 ) inventorypublicapi.InventoryPublicAPIServer {
 	return &inventoryInternalAPI{
-		inventoryQueryService: inventoryQueryService,
-		inventoryService:      inventoryService,
+		inventoryQueryService:     inventoryQueryService,
+		stockMovementQueryService: stockMovementQueryService,
+		warehouseQueryService:     warehouseQueryService,
+		categoryQueryService:      categoryQueryService,
+		priceQueryService:         priceQueryService,
+		inventoryService:          inventoryService,
+		warehouseService:          warehouseService,
+		categoryService:           categoryService,
 	}
 }
 
+var stockChangeReasons = map[string]inventorypublicapi.StockChangeReason{
+	model.Receipt.String():          inventorypublicapi.StockChangeReason_RECEIPT,
+	model.Sale.String():             inventorypublicapi.StockChangeReason_SALE,
+	model.Reservation.String():      inventorypublicapi.StockChangeReason_RESERVATION,
+	model.Return.String():           inventorypublicapi.StockChangeReason_RETURN,
+	model.ManualAdjustment.String(): inventorypublicapi.StockChangeReason_MANUAL_ADJUSTMENT,
+	model.Transfer.String():         inventorypublicapi.StockChangeReason_TRANSFER,
+}
+
+// domainStockChangeReasons decouples API values from stored ones, STOCK_CHANGE_REASON_UNSPECIFIED is not mapped
+var domainStockChangeReasons = map[inventorypublicapi.StockChangeReason]model.StockChangeReason{
+	inventorypublicapi.StockChangeReason_RECEIPT:           model.Receipt,
+	inventorypublicapi.StockChangeReason_SALE:              model.Sale,
+	inventorypublicapi.StockChangeReason_RESERVATION:       model.Reservation,
+	inventorypublicapi.StockChangeReason_RETURN:            model.Return,
+	inventorypublicapi.StockChangeReason_MANUAL_ADJUSTMENT: model.ManualAdjustment,
+	inventorypublicapi.StockChangeReason_TRANSFER:          model.Transfer,
+}
+
 type inventoryInternalAPI struct {
-	inventoryQueryService query.ProductQueryService
-	inventoryService      service.ProductService
+	inventoryQueryService     query.ProductQueryService
+	stockMovementQueryService query.StockMovementQueryService
+	warehouseQueryService     query.WarehouseQueryService
+	categoryQueryService      query.CategoryQueryService
+	priceQueryService         query.PriceQueryService
+	inventoryService          service.ProductService
+	warehouseService          service.WarehouseService
+	categoryService           service.CategoryService
 
 	inventorypublicapi.UnimplementedInventoryPublicAPIServer
 }
 
-func (u inventoryInternalAPI) StoreInventory(ctx context.Context, request *inventorypublicapi.StoreProductRequest) (*inventorypublicapi.StoreProductResponse, error) {
-	var (
-		productID uuid.UUID
-		err       error
-	)
-	if request.ProductID != "" {
-		productID, err = uuid.Parse(request.ProductID)
-		if err != nil {
-			return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.ProductID)
-		}
+func (u inventoryInternalAPI) StoreProduct(ctx context.Context, request *inventorypublicapi.StoreProductRequest) (*inventorypublicapi.StoreProductResponse, error) {
+	productID, err := parseOptionalUUID(request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	parentID, err := parseOptionalUUID(request.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	categoryID, err := parseOptionalUUID(request.CategoryID)
+	if err != nil {
+		return nil, err
 	}
 
 	productID, err = u.inventoryService.StoreProduct(ctx, appmodel.Product{
-		ID:       productID,
-		Name:     request.Name,
-		Price:    request.Price,
-		Quantity: int(request.Quantity),
+		ID:               productID,
+		SKU:              request.Sku,
+		ParentID:         parentID,
+		CategoryID:       categoryID,
+		Attributes:       toAppAttributes(request.Attributes),
+		Name:             request.Name,
+		Price:            request.Price,
+		Quantity:         int(request.Quantity),
+		ReorderThreshold: int(request.ReorderThreshold),
 	})
 	if err != nil {
-		return nil, err
+		if errors.Is(err, model.ErrProductNotFound) {
+			return nil, status.Errorf(codes.NotFound, "product %q not found", request.ProductID)
+		}
+		return nil, catalogError(err)
 	}
 
 	return &inventorypublicapi.StoreProductResponse{
@@ -58,22 +108,232 @@ func (u inventoryInternalAPI) StoreInventory(ctx context.Context, request *inven
 	}, nil
 }
 
-func (u inventoryInternalAPI) FindInventory(ctx context.Context, request *inventorypublicapi.FindProductRequest) (*inventorypublicapi.FindProductResponse, error) {
+func (u inventoryInternalAPI) FindProduct(ctx context.Context, request *inventorypublicapi.FindProductRequest) (*inventorypublicapi.FindProductResponse, error) {
 	productID, err := uuid.Parse(request.ProductID)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.ProductID)
 	}
-	product, err := u.inventoryQueryService.FindProduct(ctx, productID)
+	product, err := u.inventoryQueryService.FindProduct(ctx, productID, request.IncludeDeleted)
 	if err != nil {
+		if errors.Is(err, model.ErrProductNotFound) {
+			return nil, status.Errorf(codes.NotFound, "product %q not found", request.ProductID)
+		}
 		return nil, err
 	}
 	if product == nil {
 		return nil, status.Errorf(codes.NotFound, "product %q not found", request.ProductID)
 	}
-	return &inventorypublicapi.FindProductResponse{
+	return toAPIProductResponse(*product), nil
+}
+
+func (u inventoryInternalAPI) ListProducts(ctx context.Context, request *inventorypublicapi.ListProductsRequest) (*inventorypublicapi.ListProductsResponse, error) {
+	if request.MinPrice != nil && request.MaxPrice != nil && *request.MinPrice > *request.MaxPrice {
+		return nil, status.Errorf(codes.InvalidArgument, "minPrice %v is greater than maxPrice %v", *request.MinPrice, *request.MaxPrice)
+	}
+
+	categoryID, err := parseOptionalUUID(request.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := u.inventoryQueryService.ListProducts(ctx, appmodel.ListProductsSpec{
+		SKU:            request.Sku,
+		CategoryID:     categoryID,
+		NameQuery:      request.NameQuery,
+		MinPrice:       request.MinPrice,
+		MaxPrice:       request.MaxPrice,
+		InStockOnly:    request.InStockOnly,
+		IncludeDeleted: request.IncludeDeleted,
+		Cursor:         request.Cursor,
+		Limit:          int(request.Limit),
+	})
+	if err != nil {
+		if errors.Is(err, query.ErrInvalidCursor) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid cursor %q", request.Cursor)
+		}
+		return nil, err
+	}
+
+	result := make([]*inventorypublicapi.Product, 0, len(products.Products))
+	for _, product := range products.Products {
+		result = append(result, &inventorypublicapi.Product{
+			ProductID:  product.ID.String(),
+			Name:       product.Name,
+			Price:      product.Price,
+			Quantity:   int64(product.Quantity),
+			Sku:        product.SKU,
+			ParentID:   optionalUUIDString(product.ParentID),
+			CategoryID: optionalUUIDString(product.CategoryID),
+			Attributes: toAPIAttributes(product.Attributes),
+			DeletedAt:  optionalUnix(product.DeletedAt),
+		})
+	}
+	return &inventorypublicapi.ListProductsResponse{
+		Products:   result,
+		NextCursor: products.NextCursor,
+	}, nil
+}
+
+func (u inventoryInternalAPI) DeleteProduct(ctx context.Context, request *inventorypublicapi.DeleteProductRequest) (*inventorypublicapi.DeleteProductResponse, error) {
+	productID, err := uuid.Parse(request.ProductID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.ProductID)
+	}
+
+	err = u.inventoryService.DeleteProduct(ctx, productID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrProductNotFound):
+			return nil, status.Errorf(codes.NotFound, "product %q not found", request.ProductID)
+		case errors.Is(err, model.ErrProductInBundle):
+			return nil, status.Errorf(codes.FailedPrecondition, "product %q is component of bundle", request.ProductID)
+		}
+		return nil, err
+	}
+	return &inventorypublicapi.DeleteProductResponse{}, nil
+}
+
+func (u inventoryInternalAPI) RestoreProduct(ctx context.Context, request *inventorypublicapi.RestoreProductRequest) (*inventorypublicapi.RestoreProductResponse, error) {
+	productID, err := uuid.Parse(request.ProductID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.ProductID)
+	}
+
+	err = u.inventoryService.RestoreProduct(ctx, productID)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrProductNotFound):
+			return nil, status.Errorf(codes.NotFound, "product %q not found", request.ProductID)
+		case errors.Is(err, model.ErrParentProductDeleted):
+			return nil, status.Errorf(codes.FailedPrecondition, "parent of product %q is deleted", request.ProductID)
+		case errors.Is(err, model.ErrBundleComponentDeleted):
+			return nil, status.Errorf(codes.FailedPrecondition, "component of bundle %q is deleted", request.ProductID)
+		}
+		return nil, err
+	}
+	return &inventorypublicapi.RestoreProductResponse{}, nil
+}
+
+func (u inventoryInternalAPI) IncreaseStock(ctx context.Context, request *inventorypublicapi.AdjustStockRequest) (*inventorypublicapi.AdjustStockResponse, error) {
+	return u.adjustStock(ctx, request, u.inventoryService.IncreaseQuantity)
+}
+
+func (u inventoryInternalAPI) DecreaseStock(ctx context.Context, request *inventorypublicapi.AdjustStockRequest) (*inventorypublicapi.AdjustStockResponse, error) {
+	return u.adjustStock(ctx, request, u.inventoryService.DecreaseQuantity)
+}
+
+func (u inventoryInternalAPI) adjustStock(
+	ctx context.Context,
+	request *inventorypublicapi.AdjustStockRequest,
+	adjust func(ctx context.Context, productID, warehouseID uuid.UUID, quantity int, origin model.StockChangeOrigin) error,
+) (*inventorypublicapi.AdjustStockResponse, error) {
+	productID, err := uuid.Parse(request.ProductID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.ProductID)
+	}
+	if request.Reason == inventorypublicapi.StockChangeReason_STOCK_CHANGE_REASON_UNSPECIFIED {
+		return nil, status.Error(codes.InvalidArgument, "stock change reason is required")
+	}
+	reason, ok := domainStockChangeReasons[request.Reason]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown stock change reason %v", request.Reason)
+	}
+
+	referenceID, err := parseOptionalUUID(request.ReferenceID)
+	if err != nil {
+		return nil, err
+	}
+	warehouseID, err := parseOptionalUUID(request.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = adjust(ctx, productID, warehouseID, int(request.Quantity), model.StockChangeOrigin{
+		Reason:      reason,
+		ReferenceID: referenceID,
+		Actor:       actorFromContext(ctx),
+	})
+	if err != nil {
+		return nil, stockError(err, request.ProductID)
+	}
+	return &inventorypublicapi.AdjustStockResponse{
 		ProductID: productID.String(),
-		Name:      product.Name,
-		Price:     product.Price,
-		Quantity:  int64(product.Quantity),
 	}, nil
 }
+
+func (u inventoryInternalAPI) ListStockMovements(ctx context.Context, request *inventorypublicapi.ListStockMovementsRequest) (*inventorypublicapi.ListStockMovementsResponse, error) {
+	productID, err := uuid.Parse(request.ProductID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.ProductID)
+	}
+
+	movements, err := u.stockMovementQueryService.ListStockMovements(ctx, productID, request.Cursor, int(request.Limit))
+	if err != nil {
+		if errors.Is(err, query.ErrInvalidCursor) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid cursor %q", request.Cursor)
+		}
+		return nil, err
+	}
+
+	result := make([]*inventorypublicapi.StockMovement, 0, len(movements.Movements))
+	for _, movement := range movements.Movements {
+		var referenceID string
+		if movement.ReferenceID != uuid.Nil {
+			referenceID = movement.ReferenceID.String()
+		}
+		result = append(result, &inventorypublicapi.StockMovement{
+			MovementID:    movement.ID.String(),
+			ProductID:     movement.ProductID.String(),
+			WarehouseID:   movement.WarehouseID.String(),
+			Delta:         int64(movement.Delta),
+			QuantityAfter: int64(movement.QuantityAfter),
+			Reason:        stockChangeReasons[movement.Reason],
+			ReferenceID:   referenceID,
+			Actor:         movement.Actor,
+			CreatedAt:     movement.CreatedAt.Unix(),
+		})
+	}
+	return &inventorypublicapi.ListStockMovementsResponse{
+		Movements:  result,
+		NextCursor: movements.NextCursor,
+	}, nil
+}
+
+func stockError(err error, productID string) error {
+	switch {
+	case errors.Is(err, model.ErrProductNotFound):
+		return status.Errorf(codes.NotFound, "product %q not found", productID)
+	case errors.Is(err, model.ErrWarehouseNotFound),
+		errors.Is(err, model.ErrDefaultWarehouseNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, model.ErrInvalidStockAdjustment),
+		errors.Is(err, model.ErrUnknownStockChangeReason),
+		errors.Is(err, model.ErrSameWarehouseTransfer):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, model.ErrProductQuantityLessThanZero):
+		return status.Errorf(codes.FailedPrecondition, "not enough stock for product %q", productID)
+	case errors.Is(err, model.ErrBundleStock):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return err
+}
+
+// actorFromContext is ID of user authenticated by access token, stock movements are attributed to them
+func actorFromContext(ctx context.Context) string {
+	userID, ok := middlewares.UserIDFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	return userID.String()
+}
+
+func parseOptionalUUID(s string) (uuid.UUID, error) {
+	if s == "" {
+		return uuid.Nil, nil
+	}
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", s)
+	}
+	return id, nil
+}
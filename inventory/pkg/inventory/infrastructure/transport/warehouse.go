@@ -0,0 +1,116 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"inventory/api/server/inventorypublicapi"
+	appmodel "inventory/pkg/inventory/app/model"
+	"inventory/pkg/inventory/domain/model"
+)
+
+func (u inventoryInternalAPI) TransferStock(ctx context.Context, request *inventorypublicapi.TransferStockRequest) (*inventorypublicapi.TransferStockResponse, error) {
+	productID, err := uuid.Parse(request.ProductID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.ProductID)
+	}
+	fromWarehouseID, err := uuid.Parse(request.FromWarehouseID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.FromWarehouseID)
+	}
+	toWarehouseID, err := uuid.Parse(request.ToWarehouseID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.ToWarehouseID)
+	}
+
+	transferID, err := u.inventoryService.TransferStock(ctx, appmodel.StockTransfer{
+		ProductID:       productID,
+		FromWarehouseID: fromWarehouseID,
+		ToWarehouseID:   toWarehouseID,
+		Quantity:        int(request.Quantity),
+		Actor:           actorFromContext(ctx),
+	})
+	if err != nil {
+		return nil, stockError(err, request.ProductID)
+	}
+	return &inventorypublicapi.TransferStockResponse{
+		TransferID: transferID.String(),
+	}, nil
+}
+
+func (u inventoryInternalAPI) AllocateStock(ctx context.Context, request *inventorypublicapi.AllocateStockRequest) (*inventorypublicapi.AllocateStockResponse, error) {
+	productID, err := uuid.Parse(request.ProductID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.ProductID)
+	}
+	referenceID, err := parseOptionalUUID(request.ReferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	allocations, err := u.inventoryService.AllocateStock(ctx, productID, int(request.Quantity), model.StockChangeOrigin{
+		Reason:      model.Sale,
+		ReferenceID: referenceID,
+		Actor:       actorFromContext(ctx),
+	})
+	if err != nil {
+		return nil, stockError(err, request.ProductID)
+	}
+
+	result := make([]*inventorypublicapi.Allocation, 0, len(allocations))
+	for _, allocation := range allocations {
+		result = append(result, &inventorypublicapi.Allocation{
+			WarehouseID: allocation.WarehouseID.String(),
+			Quantity:    int64(allocation.Quantity),
+			ProductID:   allocation.ProductID.String(),
+		})
+	}
+	return &inventorypublicapi.AllocateStockResponse{
+		Allocations: result,
+	}, nil
+}
+
+func (u inventoryInternalAPI) StoreWarehouse(ctx context.Context, request *inventorypublicapi.StoreWarehouseRequest) (*inventorypublicapi.StoreWarehouseResponse, error) {
+	warehouseID, err := parseOptionalUUID(request.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "warehouse name is required")
+	}
+
+	warehouseID, err = u.warehouseService.StoreWarehouse(ctx, appmodel.Warehouse{
+		ID:       warehouseID,
+		Name:     request.Name,
+		Priority: int(request.Priority),
+	})
+	if err != nil {
+		return nil, stockError(err, "")
+	}
+	return &inventorypublicapi.StoreWarehouseResponse{
+		WarehouseID: warehouseID.String(),
+	}, nil
+}
+
+func (u inventoryInternalAPI) ListWarehouses(ctx context.Context, _ *inventorypublicapi.ListWarehousesRequest) (*inventorypublicapi.ListWarehousesResponse, error) {
+	warehouses, err := u.warehouseQueryService.ListWarehouses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*inventorypublicapi.Warehouse, 0, len(warehouses))
+	for _, warehouse := range warehouses {
+		result = append(result, &inventorypublicapi.Warehouse{
+			WarehouseID: warehouse.ID.String(),
+			Name:        warehouse.Name,
+			Priority:    int32(warehouse.Priority), // nolint:gosec
+			IsDefault:   warehouse.IsDefault,
+		})
+	}
+	return &inventorypublicapi.ListWarehousesResponse{
+		Warehouses: result,
+	}, nil
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	appservice "notification/pkg/notification/app/service"
+	"notification/pkg/notification/infrastructure/channel"
+)
+
+func newChannels(smtp SMTP, telegram Telegram) []appservice.Channel {
+	var channels []appservice.Channel
+	if smtp.Host != "" {
+		channels = append(channels, channel.NewEmailChannel(channel.SMTPConfig(smtp)))
+	}
+	if telegram.BotToken != "" {
+		channels = append(channels, channel.NewTelegramChannel(channel.TelegramConfig(telegram)))
+	}
+	return channels
+}
@@ -37,6 +37,62 @@ type AMQP struct {
 	ConnectTimeout time.Duration `envconfig:"connect_timeout"`
 }
 
+type Purchasing struct {
+	// Contacts is comma separated list of emails notified about low stock
+	Contacts []string `envconfig:"contacts"`
+}
+
 type Temporal struct {
 	Host string `envconfig:"host" required:"true"`
 }
+
+// SMTP configures email channel, it is disabled if host is not set
+type SMTP struct {
+	Host     string        `envconfig:"host"`
+	Port     int           `envconfig:"port" default:"25"`
+	Username string        `envconfig:"username"`
+	Password string        `envconfig:"password"`
+	From     string        `envconfig:"from" default:"noreply@localhost"`
+	Timeout  time.Duration `envconfig:"timeout" default:"10s"`
+}
+
+// Telegram configures telegram channel, it is disabled if bot token is not set
+type Telegram struct {
+	APIURL   string        `envconfig:"api_url" default:"https://api.telegram.org"`
+	BotToken string        `envconfig:"bot_token"`
+	Timeout  time.Duration `envconfig:"timeout" default:"10s"`
+}
+
+// Unsubscribe configures tokens carried by user notifications, link is shown with token query parameter if URL is set
+type Unsubscribe struct {
+	Secret string `envconfig:"secret" required:"true"`
+	URL    string `envconfig:"url"`
+}
+
+// Delivery configures retries of failed deliveries, delay doubles after every attempt
+type Delivery struct {
+	MaxAttempts int           `envconfig:"max_attempts" default:"5"`
+	Backoff     time.Duration `envconfig:"backoff" default:"1m"`
+	MaxBackoff  time.Duration `envconfig:"max_backoff" default:"1h"`
+}
+
+type Dispatcher struct {
+	Interval  time.Duration `envconfig:"interval" default:"5s"`
+	BatchSize int           `envconfig:"batch_size" default:"50"`
+}
+
+type Scheduler struct {
+	Interval  time.Duration `envconfig:"interval" default:"30s"`
+	BatchSize int           `envconfig:"batch_size" default:"50"`
+}
+
+// Reminders are scheduled on events and cancelled once they are not needed, zero delay disables reminder
+type Reminders struct {
+	OrderPayment time.Duration `envconfig:"order_payment" default:"24h"`
+}
+
+type Auth struct {
+	// PublicKeys are base64 encoded ed25519 public keys of user service by key ID in "id1:key1,id2:key2" format,
+	// keys of rotated out signing keys are kept until tokens signed by them expire
+	PublicKeys map[string]string `envconfig:"public_keys" required:"true"`
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
+	libio "gitea.xscloud.ru/xscloud/golib/pkg/common/io"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/outbox"
+	"github.com/gorilla/mux"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/sync/errgroup"
+
+	appservice "notification/pkg/notification/app/service"
+	domainservice "notification/pkg/notification/domain/service"
+	"notification/pkg/notification/infrastructure/integrationevent"
+	inframysql "notification/pkg/notification/infrastructure/mysql"
+	"notification/pkg/notification/infrastructure/unsubscribe"
+)
+
+type dispatcherConfig struct {
+	Service    Service    `envconfig:"service"`
+	Database   Database   `envconfig:"database" required:"true"`
+	Delivery   Delivery   `envconfig:"delivery"`
+	Dispatcher Dispatcher `envconfig:"dispatcher"`
+	Scheduler  Scheduler  `envconfig:"scheduler"`
+
+	SMTP     SMTP     `envconfig:"smtp"`
+	Telegram Telegram `envconfig:"telegram"`
+
+	Unsubscribe Unsubscribe `envconfig:"unsubscribe" required:"true"`
+}
+
+// dispatcher retries failed deliveries, sends ones deferred by quiet hours, scheduled notifications and digests,
+// replicas share the work as deliveries and notifications are claimed with skipped locks
+func dispatcher(logger logging.Logger) *cli.Command {
+	return &cli.Command{
+		Name:   "dispatcher",
+		Before: migrateImpl(logger),
+		Action: func(c *cli.Context) error {
+			cnf, err := parseEnvs[dispatcherConfig]()
+			if err != nil {
+				return err
+			}
+
+			closer := libio.NewMultiCloser()
+			defer func() {
+				err = errors.Join(err, closer.Close())
+			}()
+
+			databaseConnector, err := newDatabaseConnector(cnf.Database)
+			if err != nil {
+				return err
+			}
+			closer.AddCloser(databaseConnector)
+			databaseConnectionPool := mysql.NewConnectionPool(databaseConnector.TransactionalClient())
+			libUoW := mysql.NewUnitOfWork(databaseConnectionPool, inframysql.NewRepositoryProvider)
+			uow := inframysql.NewUnitOfWork(libUoW)
+			eventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, integrationevent.NewEventSerializer(), libUoW)
+
+			channels := newChannels(cnf.SMTP, cnf.Telegram)
+			deliveryService := appservice.NewDeliveryService(uow, channels, eventDispatcher, newRetryPolicy(cnf.Delivery))
+			notificationService := appservice.NewNotificationService(
+				uow,
+				channels,
+				deliveryService,
+				unsubscribe.NewTokens([]byte(cnf.Unsubscribe.Secret), cnf.Unsubscribe.URL),
+			)
+
+			errGroup := errgroup.Group{}
+			errGroup.Go(func() error {
+				return dispatchDeliveries(c.Context, logger, deliveryService, cnf.Dispatcher)
+			})
+			errGroup.Go(func() error {
+				return runScheduler(c.Context, logger, notificationService, cnf.Scheduler)
+			})
+
+			errGroup.Go(func() error {
+				router := mux.NewRouter()
+				registerHealthcheck(router)
+				registerMetrics(router)
+				server := http.Server{
+					Addr:              cnf.Service.HTTPAddress,
+					Handler:           router,
+					ReadHeaderTimeout: 5 * time.Second,
+				}
+				graceCallback(c.Context, logger, cnf.Service.GracePeriod, server.Shutdown)
+				return server.ListenAndServe()
+			})
+
+			return errGroup.Wait()
+		},
+	}
+}
+
+// dispatchDeliveries claims due deliveries in batches until they run out and then waits for the next tick
+func dispatchDeliveries(ctx context.Context, logger logging.Logger, deliveryService appservice.DeliveryService, cnf Dispatcher) error {
+	ticker := time.NewTicker(cnf.Interval)
+	defer ticker.Stop()
+	for {
+		for {
+			claimed, err := deliveryService.DispatchDue(ctx, cnf.BatchSize)
+			if errors.Is(err, appservice.ErrDeliveryFailed) {
+				logger.Warning(err, "some deliveries failed")
+			} else if err != nil {
+				logger.Error(err, "failed to dispatch deliveries")
+				break
+			}
+			if claimed < cnf.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func newRetryPolicy(cnf Delivery) domainservice.RetryPolicy {
+	return domainservice.RetryPolicy{
+		MaxAttempts: cnf.MaxAttempts,
+		Backoff:     cnf.Backoff,
+		MaxBackoff:  cnf.MaxBackoff,
+	}
+}
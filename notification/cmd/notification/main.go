@@ -25,6 +25,7 @@ func main() {
 		Commands: cli.Commands{
 			migrate(logger),
 			messageHandler(logger),
+			dispatcher(logger),
 			service(logger),
 		},
 	}
@@ -9,17 +9,30 @@ import (
 	libio "gitea.xscloud.ru/xscloud/golib/pkg/common/io"
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/amqp"
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/outbox"
 	"github.com/gorilla/mux"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/sync/errgroup"
 
+	"notification/pkg/notification/domain/model"
 	"notification/pkg/notification/infrastructure/consumer"
+	"notification/pkg/notification/infrastructure/integrationevent"
+	inframysql "notification/pkg/notification/infrastructure/mysql"
+	"notification/pkg/notification/infrastructure/unsubscribe"
 )
 
 type messageHandlerConfig struct {
-	Service  Service  `envconfig:"service"`
-	Database Database `envconfig:"database" required:"true"`
-	AMQP     AMQP     `envconfig:"amqp" required:"true"`
+	Service    Service    `envconfig:"service"`
+	Database   Database   `envconfig:"database" required:"true"`
+	AMQP       AMQP       `envconfig:"amqp" required:"true"`
+	Purchasing Purchasing `envconfig:"purchasing"`
+	Delivery   Delivery   `envconfig:"delivery"`
+	Reminders  Reminders  `envconfig:"reminders"`
+
+	SMTP     SMTP     `envconfig:"smtp"`
+	Telegram Telegram `envconfig:"telegram"`
+
+	Unsubscribe Unsubscribe `envconfig:"unsubscribe" required:"true"`
 }
 
 func messageHandler(logger logging.Logger) *cli.Command {
@@ -46,7 +59,41 @@ func messageHandler(logger logging.Logger) *cli.Command {
 
 			amqpConnection := newAMQPConnection(cnf.AMQP, logger)
 
-			eventConsumer, err := consumer.NewEventConsumer(c.Context, amqpConnection, databaseConnectionPool, logger)
+			amqpEventProducer := amqpConnection.Producer(
+				&amqp.ExchangeConfig{
+					Name:    integrationevent.ExchangeName,
+					Kind:    integrationevent.ExchangeKind,
+					Durable: true,
+				},
+				nil,
+				nil,
+			)
+
+			libUoW := mysql.NewUnitOfWork(databaseConnectionPool, inframysql.NewRepositoryProvider)
+			eventDispatcher := outbox.NewEventDispatcher(
+				appID,
+				integrationevent.TransportName,
+				integrationevent.NewEventSerializer(),
+				libUoW,
+			)
+
+			purchasingContacts := make([]model.Recipient, 0, len(cnf.Purchasing.Contacts))
+			for _, email := range cnf.Purchasing.Contacts {
+				purchasingContacts = append(purchasingContacts, model.Recipient{Email: email})
+			}
+
+			eventConsumer, err := consumer.NewEventConsumer(
+				c.Context,
+				amqpConnection,
+				databaseConnectionPool,
+				purchasingContacts,
+				newChannels(cnf.SMTP, cnf.Telegram),
+				eventDispatcher,
+				newRetryPolicy(cnf.Delivery),
+				unsubscribe.NewTokens([]byte(cnf.Unsubscribe.Secret), cnf.Unsubscribe.URL),
+				cnf.Reminders.OrderPayment,
+				logger,
+			)
 			if err != nil {
 				return err
 			}
@@ -58,7 +105,7 @@ func messageHandler(logger logging.Logger) *cli.Command {
 			bindConfig := &amqp.BindConfig{
 				QueueName:    "notification_events",
 				ExchangeName: "domain_event_exchange",
-				RoutingKeys:  []string{"order.*", "user.*"},
+				RoutingKeys:  []string{"order.*", "user.*", "inventory.stock_low", "inventory.out_of_stock"},
 			}
 
 			amqpConnection.Consumer(
@@ -77,7 +124,17 @@ func messageHandler(logger logging.Logger) *cli.Command {
 				return amqpConnection.Stop()
 			}))
 
+			outboxEventHandler := outbox.NewEventHandler(outbox.EventHandlerConfig{
+				TransportName:  integrationevent.TransportName,
+				Transport:      integrationevent.NewTransport(logger, amqpEventProducer),
+				ConnectionPool: databaseConnectionPool,
+				Logger:         logger,
+			})
+
 			errGroup := errgroup.Group{}
+			errGroup.Go(func() error {
+				return outboxEventHandler.Start(c.Context)
+			})
 
 			errGroup.Go(func() error {
 				router := mux.NewRouter()
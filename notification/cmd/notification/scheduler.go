@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
+
+	appservice "notification/pkg/notification/app/service"
+)
+
+// runScheduler sends due scheduled notifications and digests in batches until they run out and then waits for the next tick
+func runScheduler(ctx context.Context, logger logging.Logger, notificationService appservice.NotificationService, cnf Scheduler) error {
+	ticker := time.NewTicker(cnf.Interval)
+	defer ticker.Stop()
+	for {
+		for {
+			claimed, err := notificationService.SendDue(ctx, cnf.BatchSize)
+			if errors.Is(err, appservice.ErrDeliveryFailed) {
+				logger.Warning(err, "some scheduled notifications failed to deliver")
+			} else if err != nil {
+				logger.Error(err, "failed to send scheduled notifications")
+				break
+			}
+			if claimed < cnf.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
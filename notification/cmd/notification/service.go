@@ -9,6 +9,8 @@ import (
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
 	libio "gitea.xscloud.ru/xscloud/golib/pkg/common/io"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/outbox"
 	"github.com/gorilla/mux"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/sync/errgroup"
@@ -16,14 +18,56 @@ import (
 	"google.golang.org/grpc/reflection"
 
 	"notification/api/server/notificationinternal"
+	appservice "notification/pkg/notification/app/service"
+	"notification/pkg/notification/infrastructure/integrationevent"
+	inframysql "notification/pkg/notification/infrastructure/mysql"
 	"notification/pkg/notification/infrastructure/mysql/query"
 	"notification/pkg/notification/infrastructure/transport"
 	"notification/pkg/notification/infrastructure/transport/middlewares"
+	"notification/pkg/notification/infrastructure/unsubscribe"
 )
 
+// methodPermissions are permissions required in access token, access to notifications of other users is checked by methods
+var methodPermissions = map[string]string{
+	"/Notification.NotificationInternalService/GetNotification":          "notification.read",
+	"/Notification.NotificationInternalService/ListDeliveries":           "notification.read",
+	"/Notification.NotificationInternalService/ListNotificationsForUser": "notification.read",
+	"/Notification.NotificationInternalService/CountUnreadNotifications": "notification.read",
+	"/Notification.NotificationInternalService/MarkRead":                 "notification.write",
+	"/Notification.NotificationInternalService/MarkAllRead":              "notification.write",
+	"/Notification.NotificationInternalService/ArchiveNotifications":     "notification.write",
+	"/Notification.NotificationInternalService/GetPreferences":           "notification.read",
+	"/Notification.NotificationInternalService/UpdatePreferences":        "notification.write",
+
+	"/Notification.NotificationInternalService/CreateTemplate":       "notification.template.write",
+	"/Notification.NotificationInternalService/UpdateTemplate":       "notification.template.write",
+	"/Notification.NotificationInternalService/DeleteTemplate":       "notification.template.write",
+	"/Notification.NotificationInternalService/GetTemplate":          "notification.template.read",
+	"/Notification.NotificationInternalService/ListTemplates":        "notification.template.read",
+	"/Notification.NotificationInternalService/ListTemplateVersions": "notification.template.read",
+	"/Notification.NotificationInternalService/PreviewTemplate":      "notification.template.read",
+
+	// data subject requests are served for user service workflow on behalf of administrator
+	"/Notification.NotificationInternalService/ExportRecipientData": "user.data.export",
+	"/Notification.NotificationInternalService/EraseRecipientData":  "user.data.erase",
+	// welcome message is sent by onboarding workflow of user service
+	"/Notification.NotificationInternalService/SendWelcomeMessage": "user.onboard",
+}
+
+// publicMethods are called without access token, unsubscribe is authorized by signed token of the link
+var publicMethods = []string{
+	"/Notification.NotificationInternalService/Unsubscribe",
+}
+
 type serviceConfig struct {
 	Service  Service  `envconfig:"service"`
 	Database Database `envconfig:"database" required:"true"`
+	Auth     Auth     `envconfig:"auth" required:"true"`
+	Delivery Delivery `envconfig:"delivery"`
+	SMTP     SMTP     `envconfig:"smtp"`
+	Telegram Telegram `envconfig:"telegram"`
+
+	Unsubscribe Unsubscribe `envconfig:"unsubscribe" required:"true"`
 }
 
 func service(logger logging.Logger) *cli.Command {
@@ -41,14 +85,29 @@ func service(logger logging.Logger) *cli.Command {
 				err = errors.Join(err, closer.Close())
 			}()
 
+			publicKeys, err := middlewares.ParsePublicKeys(cnf.Auth.PublicKeys)
+			if err != nil {
+				return err
+			}
+
 			databaseConnector, err := newDatabaseConnector(cnf.Database)
 			if err != nil {
 				return err
 			}
 			closer.AddCloser(databaseConnector)
+			databaseConnectionPool := mysql.NewConnectionPool(databaseConnector.TransactionalClient())
+			libUoW := mysql.NewUnitOfWork(databaseConnectionPool, inframysql.NewRepositoryProvider)
+			uow := inframysql.NewUnitOfWork(libUoW)
+			eventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, integrationevent.NewEventSerializer(), libUoW)
+			unsubscribeTokens := unsubscribe.NewTokens([]byte(cnf.Unsubscribe.Secret), cnf.Unsubscribe.URL)
+			channels := newChannels(cnf.SMTP, cnf.Telegram)
+			deliveryService := appservice.NewDeliveryService(uow, channels, eventDispatcher, newRetryPolicy(cnf.Delivery))
 
 			notificationAPI := transport.NewNotificationInternalAPI(
 				query.NewNotificationQueryService(databaseConnector.TransactionalClient()),
+				appservice.NewNotificationService(uow, channels, deliveryService, unsubscribeTokens),
+				appservice.NewTemplateService(uow),
+				appservice.NewPreferencesService(uow, unsubscribeTokens),
 			)
 
 			errGroup := errgroup.Group{}
@@ -59,6 +118,8 @@ func service(logger logging.Logger) *cli.Command {
 				}
 				grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
 					middlewares.NewGRPCLoggingMiddleware(logger),
+					middlewares.NewGRPCAuthMiddleware(publicKeys, publicMethods...),
+					middlewares.NewGRPCPermissionMiddleware(methodPermissions, publicMethods...),
 				))
 				notificationinternal.RegisterNotificationInternalServiceServer(grpcServer, notificationAPI)
 				reflection.Register(grpcServer)
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+type Delivery struct {
+	ID       uuid.UUID
+	Channel  string
+	Status   string
+	Attempts []DeliveryAttempt
+	// NextAttemptAt is set while delivery is pending
+	NextAttemptAt *int64
+	CreatedAt     int64
+	UpdatedAt     int64
+}
+
+type DeliveryAttempt struct {
+	Number      int
+	Status      string
+	Error       string
+	AttemptedAt int64
+}
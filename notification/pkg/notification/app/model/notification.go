@@ -10,4 +10,24 @@ type Notification struct {
 	Subject   string
 	Body      string
 	CreatedAt int64
+
+	// UserID is nil for notifications which are not in user inbox
+	UserID     *uuid.UUID
+	ReadAt     *int64
+	ArchivedAt *int64
+}
+
+type ListNotificationsSpec struct {
+	UserID     uuid.UUID
+	UnreadOnly bool
+	// IncludeArchived adds archived notifications, they are hidden from inbox by default
+	IncludeArchived bool
+	// Cursor is an opaque value from NotificationList.NextCursor of the previous page
+	Cursor string
+	Limit  int
+}
+
+type NotificationList struct {
+	Notifications []Notification
+	NextCursor    string
 }
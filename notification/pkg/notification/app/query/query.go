@@ -2,12 +2,24 @@ package query
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 
 	appmodel "notification/pkg/notification/app/model"
+	"notification/pkg/notification/domain/model"
 )
 
+var ErrInvalidCursor = errors.New("invalid cursor")
+
 type NotificationQueryService interface {
 	Find(ctx context.Context, id uuid.UUID) (*appmodel.Notification, error)
+	// ListForRecipient returns notifications sent to any of non-empty recipient contacts
+	ListForRecipient(ctx context.Context, recipient model.Recipient) ([]appmodel.Notification, error)
+	// ListForUser returns page of user inbox, newest first
+	ListForUser(ctx context.Context, spec appmodel.ListNotificationsSpec) (appmodel.NotificationList, error)
+	// CountUnread counts unread notifications in user inbox, archived ones are not counted
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+	// ListDeliveries returns delivery history of the notification through every channel
+	ListDeliveries(ctx context.Context, notificationID uuid.UUID) ([]appmodel.Delivery, error)
 }
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"notification/pkg/notification/domain/model"
+)
+
+// ErrRecipientRejected is wrapped by channels when recipient contact does not accept messages, such delivery is not retried
+var ErrRecipientRejected = errors.New("recipient rejected message")
+
+// Channel delivers notifications to one kind of recipient contacts
+type Channel interface {
+	Name() string
+	// Supports reports whether the recipient has contact this channel delivers to
+	Supports(recipient model.Recipient) bool
+	Send(ctx context.Context, recipient model.Recipient, subject, body string) error
+}
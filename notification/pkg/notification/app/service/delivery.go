@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/domain/service"
+)
+
+// deliveryClaim is time claimed delivery is hidden from other workers, it must exceed channel timeouts
+const deliveryClaim = time.Minute
+
+// DeliveryService sends stored deliveries and retries failed ones with backoff until retry policy gives up
+type DeliveryService interface {
+	// Deliver sends deliveries claimed on creation, ErrDeliveryFailed is returned for ones to be retried or given up
+	Deliver(ctx context.Context, deliveryIDs []uuid.UUID) error
+	// DispatchDue claims pending deliveries due to be sent and sends them, returns number of claimed deliveries
+	DispatchDue(ctx context.Context, limit int) (int, error)
+}
+
+func NewDeliveryService(
+	uow UnitOfWork,
+	channels []Channel,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+	retryPolicy service.RetryPolicy,
+) DeliveryService {
+	return &deliveryService{
+		uow:             uow,
+		channels:        channels,
+		eventDispatcher: eventDispatcher,
+		retryPolicy:     retryPolicy,
+	}
+}
+
+type deliveryService struct {
+	uow             UnitOfWork
+	channels        []Channel
+	eventDispatcher outbox.EventDispatcher[outbox.Event]
+	retryPolicy     service.RetryPolicy
+}
+
+func (s *deliveryService) Deliver(ctx context.Context, deliveryIDs []uuid.UUID) error {
+	var deliveryErrs []error
+	for _, id := range deliveryIDs {
+		var delivery *model.Delivery
+		err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+			var err error
+			delivery, err = provider.DeliveryRepository(ctx).Find(id)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		err = s.attempt(ctx, delivery)
+		if errors.Is(err, ErrDeliveryFailed) {
+			deliveryErrs = append(deliveryErrs, err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return errors.Join(deliveryErrs...)
+}
+
+func (s *deliveryService) DispatchDue(ctx context.Context, limit int) (int, error) {
+	var deliveries []model.Delivery
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		now := time.Now()
+		var err error
+		deliveries, err = provider.DeliveryRepository(ctx).ClaimDue(now, now.Add(deliveryClaim), limit)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var deliveryErrs []error
+	for i := range deliveries {
+		err = s.attempt(ctx, &deliveries[i])
+		if errors.Is(err, ErrDeliveryFailed) {
+			deliveryErrs = append(deliveryErrs, err)
+			continue
+		}
+		if err != nil {
+			return len(deliveries), err
+		}
+	}
+	return len(deliveries), errors.Join(deliveryErrs...)
+}
+
+// attempt sends delivery, records the attempt and emits NotificationSent or NotificationFailed if delivery is finished
+func (s *deliveryService) attempt(ctx context.Context, delivery *model.Delivery) error {
+	sendErr := errors.New("channel is not configured")
+	for _, channel := range s.channels {
+		if channel.Name() == delivery.Channel {
+			sendErr = channel.Send(ctx, delivery.Recipient, delivery.Message.Subject, delivery.Message.Body)
+			break
+		}
+	}
+
+	// events are stored in the same transaction as the attempt, so they are not lost or duplicated
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		notification, err := provider.NotificationRepository(ctx).Find(delivery.NotificationID)
+		if err != nil {
+			return err
+		}
+
+		domainService := service.NewDeliveryService(provider.DeliveryRepository(ctx))
+		if sendErr == nil {
+			err = domainService.MarkSent(delivery, time.Now())
+		} else {
+			err = domainService.MarkFailed(delivery, s.retryPolicy, sendErr.Error(), errors.Is(sendErr, ErrRecipientRejected), time.Now())
+		}
+		if err != nil {
+			return err
+		}
+		return s.dispatchDeliveryEvent(ctx, notification, delivery)
+	})
+	if err != nil {
+		return err
+	}
+
+	if sendErr != nil {
+		return fmt.Errorf("%w: %s: %w", ErrDeliveryFailed, delivery.Channel, sendErr)
+	}
+	return nil
+}
+
+// dispatchDeliveryEvent emits NotificationSent or NotificationFailed when delivery is finished
+func (s *deliveryService) dispatchDeliveryEvent(ctx context.Context, notification *model.Notification, delivery *model.Delivery) error {
+	attempt := delivery.Attempts[len(delivery.Attempts)-1]
+	switch delivery.Status {
+	case model.DeliverySent:
+		return s.eventDispatcher.Dispatch(ctx, &model.NotificationSent{
+			ID:                notification.ID,
+			Name:              notification.Name,
+			Channel:           delivery.Channel,
+			RecipientName:     delivery.Recipient.Name,
+			RecipientEmail:    delivery.Recipient.Email,
+			RecipientTelegram: delivery.Recipient.Telegram,
+			SentAt:            attempt.AttemptedAt,
+		})
+	case model.DeliveryFailed, model.DeliveryBounced:
+		return s.eventDispatcher.Dispatch(ctx, &model.NotificationFailed{
+			ID:                notification.ID,
+			Name:              notification.Name,
+			Channel:           delivery.Channel,
+			Status:            delivery.Status,
+			Error:             attempt.Error,
+			Attempts:          len(delivery.Attempts),
+			RecipientName:     delivery.Recipient.Name,
+			RecipientEmail:    delivery.Recipient.Email,
+			RecipientTelegram: delivery.Recipient.Telegram,
+			FailedAt:          attempt.AttemptedAt,
+		})
+	}
+	return nil
+}
@@ -2,29 +2,108 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 
+	"notification/pkg/notification/domain/model"
 	"notification/pkg/notification/domain/service"
 )
 
+// ErrDeliveryFailed is returned when notifications were stored but some of channels failed to send them,
+// failed deliveries are retried by dispatcher
+var ErrDeliveryFailed = errors.New("notification delivery failed")
+
+// scheduleClaim is time claimed scheduled notification or digest is hidden from other workers,
+// it is sent again by another worker if the claiming one stops before finishing it
+const scheduleClaim = 5 * time.Minute
+
+// Notifications are rendered from templates of the event type, payload is the event body with template variables
 type NotificationService interface {
-	CreateNotification(ctx context.Context, name, subject, body string) (uuid.UUID, error)
+	// CreateNotification stores notification without recipient rendered from default channel template
+	CreateNotification(ctx context.Context, eventType string, payload []byte) (uuid.UUID, error)
+	// NotifyRecipients stores notifications and sends them through every channel supporting recipient contacts,
+	// channels with own template get it rendered instead of default one. Delivery through every channel is tracked
+	// and retried until it is sent or given up
+	NotifyRecipients(ctx context.Context, recipients []model.Recipient, eventType string, payload []byte) ([]uuid.UUID, error)
+	// NotifyUser stores notification in user inbox and sends it to the recipient, contacts synced from user service are used if it is nil.
+	// Templates are rendered in the locale synced from user preferences, default locale is used until it is known.
+	// Notification is only stored in inbox if contacts of the user are unknown, channels disabled by user preferences are skipped,
+	// delivery is deferred until quiet hours end and messages carry unsubscribe link of the event category.
+	// Notification of the category grouped by user preferences is added to digest instead of being sent
+	NotifyUser(ctx context.Context, userID uuid.UUID, recipient *model.Recipient, eventType string, payload []byte) (uuid.UUID, error)
+	// ScheduleUserNotification notifies the user at sendAt, notification scheduled with the same key is replaced
+	ScheduleUserNotification(ctx context.Context, userID uuid.UUID, key, eventType string, payload []byte, sendAt time.Time) error
+	// CancelScheduledNotification returns number of cancelled notifications, it is zero if notification is already sent
+	CancelScheduledNotification(ctx context.Context, key string) (int, error)
+	// SendDue claims scheduled notifications and digests due to be sent and sends them to users,
+	// returns number of claimed ones. Claims are shared between replicas
+	SendDue(ctx context.Context, limit int) (int, error)
+	// EraseRecipientData deletes notifications sent to the recipient, they are not kept as they contain personal data
+	EraseRecipientData(ctx context.Context, recipient model.Recipient) (int, error)
+	EraseUserNotifications(ctx context.Context, userID uuid.UUID) (int, error)
+
+	// MarkRead marks notifications of the user as read and returns number of changed ones, other users notifications are skipped
+	MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error)
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error)
+	// Archive hides notifications of the user from inbox and returns number of changed ones
+	Archive(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error)
+
+	StoreUserRecipient(ctx context.Context, userID uuid.UUID, recipient model.Recipient) error
+	UpdateUserRecipientContacts(ctx context.Context, userID uuid.UUID, email, telegram *string) error
+	UpdateUserRecipientLocale(ctx context.Context, userID uuid.UUID, locale string) error
+	// DeleteUserRecipient removes contacts, notification preferences, scheduled notifications and digests of the user
+	DeleteUserRecipient(ctx context.Context, userID uuid.UUID) error
 }
 
-func NewNotificationService(uow UnitOfWork) NotificationService {
-	return &notificationService{uow: uow}
+func NewNotificationService(
+	uow UnitOfWork,
+	channels []Channel,
+	deliveryService DeliveryService,
+	unsubscribeTokens UnsubscribeTokens,
+) NotificationService {
+	return &notificationService{
+		uow:               uow,
+		channels:          channels,
+		deliveryService:   deliveryService,
+		unsubscribeTokens: unsubscribeTokens,
+	}
 }
 
 type notificationService struct {
-	uow UnitOfWork
+	uow               UnitOfWork
+	channels          []Channel
+	deliveryService   DeliveryService
+	unsubscribeTokens UnsubscribeTokens
 }
 
-func (n *notificationService) CreateNotification(ctx context.Context, name, subject, body string) (uuid.UUID, error) {
+// deliveryOptions are applied to notifications sent to user
+type deliveryOptions struct {
+	// locale of rendered templates, default locale is used if it is empty
+	locale string
+	// footer is appended to message body of every channel
+	footer string
+	// preferences of the user are applied to deliveries of category if set
+	preferences *model.Preferences
+	category    model.Category
+
+	// notifications are added to digest of the user instead of being sent if window is set
+	userID       uuid.UUID
+	digestWindow time.Duration
+}
+
+func (n *notificationService) CreateNotification(ctx context.Context, eventType string, payload []byte) (uuid.UUID, error) {
 	var notificationID uuid.UUID
 	err := n.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		message, err := service.NewTemplateService(provider.TemplateRepository(ctx)).Render(templateKey(eventType, model.ChannelDefault, ""), payload)
+		if err != nil {
+			return err
+		}
+
 		domainService := service.NewNotificationService(provider.NotificationRepository(ctx))
-		id, err := domainService.CreateNotification(name, subject, body)
+		id, err := domainService.CreateNotification(eventType, message.Subject, message.Body)
 		if err != nil {
 			return err
 		}
@@ -33,3 +112,403 @@ func (n *notificationService) CreateNotification(ctx context.Context, name, subj
 	})
 	return notificationID, err
 }
+
+func (n *notificationService) NotifyRecipients(ctx context.Context, recipients []model.Recipient, eventType string, payload []byte) ([]uuid.UUID, error) {
+	return n.notify(ctx, recipients, eventType, payload, deliveryOptions{}, func(domainService service.Notification, message model.Message) ([]uuid.UUID, error) {
+		return domainService.NotifyRecipients(recipients, eventType, message.Subject, message.Body)
+	})
+}
+
+func (n *notificationService) NotifyUser(ctx context.Context, userID uuid.UUID, recipient *model.Recipient, eventType string, payload []byte) (uuid.UUID, error) {
+	category := model.EventCategory(eventType)
+	recipient, options, err := n.userDeliveryOptions(ctx, userID, recipient, category)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	options.digestWindow, _ = options.preferences.DigestWindow(category)
+
+	ids, err := n.notify(ctx, []model.Recipient{*recipient}, eventType, payload, options, func(domainService service.Notification, message model.Message) ([]uuid.UUID, error) {
+		id, err := domainService.NotifyUser(userID, *recipient, eventType, message.Subject, message.Body)
+		return []uuid.UUID{id}, err
+	})
+	if len(ids) == 0 {
+		return uuid.Nil, err
+	}
+	return ids[0], err
+}
+
+func (n *notificationService) ScheduleUserNotification(
+	ctx context.Context,
+	userID uuid.UUID,
+	key, eventType string,
+	payload []byte,
+	sendAt time.Time,
+) error {
+	return n.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		scheduleService := service.NewScheduleService(provider.ScheduledNotificationRepository(ctx), provider.DigestRepository(ctx))
+		_, err := scheduleService.ScheduleNotification(userID, key, eventType, payload, sendAt)
+		return err
+	})
+}
+
+func (n *notificationService) CancelScheduledNotification(ctx context.Context, key string) (int, error) {
+	var cancelled int
+	err := n.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		scheduleService := service.NewScheduleService(provider.ScheduledNotificationRepository(ctx), provider.DigestRepository(ctx))
+		var err error
+		cancelled, err = scheduleService.CancelNotification(key)
+		return err
+	})
+	return cancelled, err
+}
+
+func (n *notificationService) SendDue(ctx context.Context, limit int) (int, error) {
+	var (
+		scheduled []model.ScheduledNotification
+		digests   []model.Digest
+	)
+	err := n.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		now := time.Now()
+		var err error
+		scheduled, err = provider.ScheduledNotificationRepository(ctx).ClaimDue(now, now.Add(scheduleClaim), limit)
+		if err != nil {
+			return err
+		}
+		digests, err = provider.DigestRepository(ctx).ClaimDue(now, now.Add(scheduleClaim), limit)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// notification is removed once it is stored, failed deliveries are retried by dispatcher
+	var deliveryErrs []error
+	for _, notification := range scheduled {
+		_, err = n.NotifyUser(ctx, notification.UserID, nil, notification.EventType, notification.Payload)
+		if errors.Is(err, ErrDeliveryFailed) {
+			deliveryErrs = append(deliveryErrs, err)
+		} else if err != nil {
+			return 0, err
+		}
+
+		err = n.uow.Execute(ctx, func(provider RepositoryProvider) error {
+			return provider.ScheduledNotificationRepository(ctx).Delete(notification.ID)
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	for _, digest := range digests {
+		err = n.sendDigest(ctx, digest)
+		if errors.Is(err, ErrDeliveryFailed) {
+			deliveryErrs = append(deliveryErrs, err)
+		} else if err != nil {
+			return 0, err
+		}
+
+		err = n.uow.Execute(ctx, func(provider RepositoryProvider) error {
+			return provider.DigestRepository(ctx).Delete(digest.ID)
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(scheduled) + len(digests), errors.Join(deliveryErrs...)
+}
+
+// sendDigest renders subjects of grouped notifications into one message, notifications erased since they were grouped are skipped
+func (n *notificationService) sendDigest(ctx context.Context, digest model.Digest) error {
+	variables := model.DigestVariables{Category: string(digest.Category)}
+	err := n.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		for _, id := range digest.NotificationIDs {
+			notification, err := provider.NotificationRepository(ctx).Find(id)
+			if errors.Is(err, model.ErrNotificationNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			variables.Notifications = append(variables.Notifications, model.DigestItem{
+				Subject:   notification.Subject,
+				CreatedAt: notification.CreatedAt.Unix(),
+			})
+		}
+		return nil
+	})
+	if err != nil || len(variables.Notifications) == 0 {
+		return err
+	}
+	payload, err := json.Marshal(variables)
+	if err != nil {
+		return err
+	}
+
+	recipient, options, err := n.userDeliveryOptions(ctx, digest.UserID, nil, digest.Category)
+	if err != nil {
+		return err
+	}
+	_, err = n.notify(ctx, []model.Recipient{*recipient}, model.DigestEvent, payload, options, func(domainService service.Notification, message model.Message) ([]uuid.UUID, error) {
+		id, err := domainService.CreateDigest(digest.UserID, *recipient, message.Subject, message.Body)
+		return []uuid.UUID{id}, err
+	})
+	return err
+}
+
+// userDeliveryOptions loads preferences and locale of the user and contacts synced from user service if recipient is nil,
+// recipient without contacts is returned if they are unknown
+func (n *notificationService) userDeliveryOptions(
+	ctx context.Context,
+	userID uuid.UUID,
+	recipient *model.Recipient,
+	category model.Category,
+) (*model.Recipient, deliveryOptions, error) {
+	var (
+		preferences   *model.Preferences
+		userRecipient *model.UserRecipient
+	)
+	err := n.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		preferences, err = provider.PreferencesRepository(ctx).Find(userID)
+		if err != nil {
+			return err
+		}
+
+		userRecipient, err = provider.UserRecipientRepository(ctx).Find(userID)
+		if errors.Is(err, model.ErrRecipientNotFound) {
+			userRecipient = &model.UserRecipient{UserID: userID}
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, deliveryOptions{}, err
+	}
+	if recipient == nil {
+		recipient = &userRecipient.Recipient
+	}
+
+	options := deliveryOptions{
+		locale:      userRecipient.TemplateLocale(),
+		preferences: preferences,
+		category:    category,
+		userID:      userID,
+	}
+	if category != model.CategorySecurity {
+		options.footer = "\n\n" + n.unsubscribeTokens.Link(n.unsubscribeTokens.Issue(userID, category))
+	}
+	return recipient, options, nil
+}
+
+// notify renders templates, stores notifications for recipients with store together with their deliveries
+// and then sends deliveries which are not deferred by quiet hours, notifications are added to digest instead if it is enabled
+func (n *notificationService) notify(
+	ctx context.Context,
+	recipients []model.Recipient,
+	eventType string,
+	payload []byte,
+	options deliveryOptions,
+	store func(domainService service.Notification, message model.Message) ([]uuid.UUID, error),
+) ([]uuid.UUID, error) {
+	var (
+		notificationIDs []uuid.UUID
+		deliveryIDs     []uuid.UUID
+	)
+	err := n.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		templateService := service.NewTemplateService(provider.TemplateRepository(ctx))
+		message, err := templateService.Render(templateKey(eventType, model.ChannelDefault, options.locale), payload)
+		if err != nil {
+			return err
+		}
+		channelMessages, err := n.renderChannelMessages(templateService, eventType, options.locale, payload)
+		if err != nil {
+			return err
+		}
+		message.Body += options.footer
+		for channel, channelMessage := range channelMessages {
+			channelMessage.Body += options.footer
+			channelMessages[channel] = channelMessage
+		}
+
+		ids, err := store(service.NewNotificationService(provider.NotificationRepository(ctx)), message)
+		if err != nil {
+			return err
+		}
+		notificationIDs = ids
+
+		if options.digestWindow > 0 {
+			scheduleService := service.NewScheduleService(provider.ScheduledNotificationRepository(ctx), provider.DigestRepository(ctx))
+			for _, id := range ids {
+				_, err = scheduleService.AddToDigest(options.userID, options.category, id, options.digestWindow)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		deliveryService := service.NewDeliveryService(provider.DeliveryRepository(ctx))
+		for i, id := range ids {
+			for _, channel := range n.channels {
+				if !channel.Supports(recipients[i]) {
+					continue
+				}
+				if options.preferences != nil && !options.preferences.Enabled(options.category, channel.Name()) {
+					continue
+				}
+
+				channelMessage, ok := channelMessages[channel.Name()]
+				if !ok {
+					channelMessage = message
+				}
+				now := time.Now()
+				nextAttemptAt := now
+				if options.preferences != nil {
+					nextAttemptAt = options.preferences.DeliveryTime(options.category, now)
+				}
+				deferred := nextAttemptAt.After(now)
+				if !deferred {
+					// delivery sent at once is claimed, so it is not picked up by dispatcher while it is being sent
+					nextAttemptAt = now.Add(deliveryClaim)
+				}
+
+				delivery, err := deliveryService.CreateDelivery(id, channel.Name(), recipients[i], channelMessage, nextAttemptAt)
+				if err != nil {
+					return err
+				}
+				if !deferred {
+					deliveryIDs = append(deliveryIDs, delivery.ID)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// deliveries are sent after commit, so they are not sent twice if storing them is retried
+	return notificationIDs, n.deliveryService.Deliver(ctx, deliveryIDs)
+}
+
+func (n *notificationService) EraseRecipientData(ctx context.Context, recipient model.Recipient) (int, error) {
+	var deleted int
+	err := n.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		deleted, err = provider.NotificationRepository(ctx).DeleteByRecipient(recipient)
+		return err
+	})
+	return deleted, err
+}
+
+func (n *notificationService) EraseUserNotifications(ctx context.Context, userID uuid.UUID) (int, error) {
+	var deleted int
+	err := n.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		deleted, err = provider.NotificationRepository(ctx).DeleteByUser(userID)
+		return err
+	})
+	return deleted, err
+}
+
+func (n *notificationService) MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error) {
+	if ids == nil {
+		ids = []uuid.UUID{}
+	}
+	var updated int
+	err := n.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		updated, err = service.NewNotificationService(provider.NotificationRepository(ctx)).MarkRead(userID, ids)
+		return err
+	})
+	return updated, err
+}
+
+func (n *notificationService) MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	var updated int
+	err := n.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		updated, err = service.NewNotificationService(provider.NotificationRepository(ctx)).MarkRead(userID, nil)
+		return err
+	})
+	return updated, err
+}
+
+func (n *notificationService) Archive(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error) {
+	var updated int
+	err := n.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		updated, err = service.NewNotificationService(provider.NotificationRepository(ctx)).Archive(userID, ids)
+		return err
+	})
+	return updated, err
+}
+
+func (n *notificationService) StoreUserRecipient(ctx context.Context, userID uuid.UUID, recipient model.Recipient) error {
+	return n.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		return service.NewUserRecipientService(provider.UserRecipientRepository(ctx)).StoreUserRecipient(userID, recipient)
+	})
+}
+
+func (n *notificationService) UpdateUserRecipientContacts(ctx context.Context, userID uuid.UUID, email, telegram *string) error {
+	return n.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		return service.NewUserRecipientService(provider.UserRecipientRepository(ctx)).UpdateUserRecipientContacts(userID, email, telegram)
+	})
+}
+
+func (n *notificationService) UpdateUserRecipientLocale(ctx context.Context, userID uuid.UUID, locale string) error {
+	return n.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		return service.NewUserRecipientService(provider.UserRecipientRepository(ctx)).UpdateUserRecipientLocale(userID, locale)
+	})
+}
+
+func (n *notificationService) DeleteUserRecipient(ctx context.Context, userID uuid.UUID) error {
+	return n.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		err := service.NewUserRecipientService(provider.UserRecipientRepository(ctx)).DeleteUserRecipient(userID)
+		if err != nil {
+			return err
+		}
+		err = provider.PreferencesRepository(ctx).Delete(userID)
+		if err != nil {
+			return err
+		}
+		err = provider.ScheduledNotificationRepository(ctx).DeleteByUser(userID)
+		if err != nil {
+			return err
+		}
+		return provider.DigestRepository(ctx).DeleteByUser(userID)
+	})
+}
+
+// renderChannelMessages renders own templates of channels, channels without them are missing in the result
+func (n *notificationService) renderChannelMessages(
+	templateService service.Template,
+	eventType string,
+	locale string,
+	payload []byte,
+) (map[string]model.Message, error) {
+	messages := make(map[string]model.Message)
+	for _, channel := range n.channels {
+		message, err := templateService.Render(templateKey(eventType, channel.Name(), locale), payload)
+		if errors.Is(err, model.ErrTemplateNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		messages[channel.Name()] = message
+	}
+	return messages, nil
+}
+
+// templateKey falls back to default locale if locale is empty, templates missing in locale are rendered in default one
+func templateKey(eventType, channel, locale string) model.TemplateKey {
+	if locale == "" {
+		locale = model.DefaultLocale
+	}
+	return model.TemplateKey{
+		EventType: eventType,
+		Channel:   channel,
+		Locale:    locale,
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/domain/service"
+)
+
+type PreferencesService interface {
+	// FindPreferences returns default preferences if user has not changed them
+	FindPreferences(ctx context.Context, userID uuid.UUID) (model.Preferences, error)
+	UpdatePreferences(
+		ctx context.Context,
+		userID uuid.UUID,
+		disabled []model.ChannelPreference,
+		quietHours *model.QuietHours,
+		digests map[model.Category]time.Duration,
+	) (model.Preferences, error)
+	// Unsubscribe turns off category of the token for every delivery channel
+	Unsubscribe(ctx context.Context, token string) (model.Preferences, model.Category, error)
+}
+
+func NewPreferencesService(uow UnitOfWork, unsubscribeTokens UnsubscribeTokens) PreferencesService {
+	return &preferencesService{
+		uow:               uow,
+		unsubscribeTokens: unsubscribeTokens,
+	}
+}
+
+type preferencesService struct {
+	uow               UnitOfWork
+	unsubscribeTokens UnsubscribeTokens
+}
+
+func (s *preferencesService) FindPreferences(ctx context.Context, userID uuid.UUID) (model.Preferences, error) {
+	var preferences model.Preferences
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		p, err := provider.PreferencesRepository(ctx).Find(userID)
+		if err != nil {
+			return err
+		}
+		preferences = *p
+		return nil
+	})
+	return preferences, err
+}
+
+func (s *preferencesService) UpdatePreferences(
+	ctx context.Context,
+	userID uuid.UUID,
+	disabled []model.ChannelPreference,
+	quietHours *model.QuietHours,
+	digests map[model.Category]time.Duration,
+) (model.Preferences, error) {
+	var preferences model.Preferences
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		p, err := service.NewPreferencesService(provider.PreferencesRepository(ctx)).UpdatePreferences(userID, disabled, quietHours, digests)
+		if err != nil {
+			return err
+		}
+		preferences = *p
+		return nil
+	})
+	return preferences, err
+}
+
+func (s *preferencesService) Unsubscribe(ctx context.Context, token string) (model.Preferences, model.Category, error) {
+	userID, category, err := s.unsubscribeTokens.Parse(token)
+	if err != nil {
+		return model.Preferences{}, "", err
+	}
+
+	var preferences model.Preferences
+	err = s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		p, err := service.NewPreferencesService(provider.PreferencesRepository(ctx)).DisableCategory(userID, category)
+		if err != nil {
+			return err
+		}
+		preferences = *p
+		return nil
+	})
+	return preferences, category, err
+}
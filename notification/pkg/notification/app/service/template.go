@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/domain/service"
+)
+
+type TemplateService interface {
+	CreateTemplate(ctx context.Context, key model.TemplateKey, subject, body string) (model.Template, error)
+	// UpdateTemplate stores new version of the template, previous versions are kept
+	UpdateTemplate(ctx context.Context, key model.TemplateKey, subject, body string) (model.Template, error)
+	// FindTemplate returns the latest version of the template if version is nil
+	FindTemplate(ctx context.Context, key model.TemplateKey, version *int) (model.Template, error)
+	ListTemplates(ctx context.Context, eventType string) ([]model.Template, error)
+	ListTemplateVersions(ctx context.Context, key model.TemplateKey) ([]model.Template, error)
+	DeleteTemplate(ctx context.Context, key model.TemplateKey) (int, error)
+	// PreviewTemplate renders stored template version against sample payload
+	PreviewTemplate(ctx context.Context, key model.TemplateKey, version *int, payload []byte) (model.Message, error)
+	// PreviewDraft renders not stored template against sample payload
+	PreviewDraft(eventType, subject, body string, payload []byte) (model.Message, error)
+}
+
+func NewTemplateService(uow UnitOfWork) TemplateService {
+	return &templateService{uow: uow}
+}
+
+type templateService struct {
+	uow UnitOfWork
+}
+
+func (s *templateService) CreateTemplate(ctx context.Context, key model.TemplateKey, subject, body string) (model.Template, error) {
+	var template model.Template
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		t, err := service.NewTemplateService(provider.TemplateRepository(ctx)).CreateTemplate(key, subject, body)
+		if err != nil {
+			return err
+		}
+		template = *t
+		return nil
+	})
+	return template, err
+}
+
+func (s *templateService) UpdateTemplate(ctx context.Context, key model.TemplateKey, subject, body string) (model.Template, error) {
+	var template model.Template
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		t, err := service.NewTemplateService(provider.TemplateRepository(ctx)).UpdateTemplate(key, subject, body)
+		if err != nil {
+			return err
+		}
+		template = *t
+		return nil
+	})
+	return template, err
+}
+
+func (s *templateService) FindTemplate(ctx context.Context, key model.TemplateKey, version *int) (model.Template, error) {
+	var template model.Template
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		t, err := provider.TemplateRepository(ctx).Find(key, version)
+		if err != nil {
+			return err
+		}
+		template = *t
+		return nil
+	})
+	return template, err
+}
+
+func (s *templateService) ListTemplates(ctx context.Context, eventType string) ([]model.Template, error) {
+	var templates []model.Template
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		templates, err = provider.TemplateRepository(ctx).ListLatest(eventType)
+		return err
+	})
+	return templates, err
+}
+
+func (s *templateService) ListTemplateVersions(ctx context.Context, key model.TemplateKey) ([]model.Template, error) {
+	var templates []model.Template
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		templates, err = provider.TemplateRepository(ctx).ListVersions(key)
+		return err
+	})
+	return templates, err
+}
+
+func (s *templateService) DeleteTemplate(ctx context.Context, key model.TemplateKey) (int, error) {
+	var deleted int
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		deleted, err = service.NewTemplateService(provider.TemplateRepository(ctx)).DeleteTemplate(key)
+		return err
+	})
+	return deleted, err
+}
+
+func (s *templateService) PreviewTemplate(ctx context.Context, key model.TemplateKey, version *int, payload []byte) (model.Message, error) {
+	var message model.Message
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		message, err = service.NewTemplateService(provider.TemplateRepository(ctx)).Preview(key, version, payload)
+		return err
+	})
+	return message, err
+}
+
+func (s *templateService) PreviewDraft(eventType, subject, body string, payload []byte) (model.Message, error) {
+	return service.NewTemplateService(nil).PreviewDraft(eventType, subject, body, payload)
+}
@@ -8,6 +8,12 @@ import (
 
 type RepositoryProvider interface {
 	NotificationRepository(ctx context.Context) model.NotificationRepository
+	UserRecipientRepository(ctx context.Context) model.UserRecipientRepository
+	TemplateRepository(ctx context.Context) model.TemplateRepository
+	DeliveryRepository(ctx context.Context) model.DeliveryRepository
+	PreferencesRepository(ctx context.Context) model.PreferencesRepository
+	ScheduledNotificationRepository(ctx context.Context) model.ScheduledNotificationRepository
+	DigestRepository(ctx context.Context) model.DigestRepository
 }
 
 type UnitOfWork interface {
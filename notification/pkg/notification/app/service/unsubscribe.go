@@ -0,0 +1,16 @@
+package service
+
+import (
+	"github.com/google/uuid"
+
+	"notification/pkg/notification/domain/model"
+)
+
+// UnsubscribeTokens are carried by user notifications and turn their category off without authentication
+type UnsubscribeTokens interface {
+	Issue(userID uuid.UUID, category model.Category) string
+	// Parse returns model.ErrInvalidUnsubscribeToken if token was not issued by Issue
+	Parse(token string) (uuid.UUID, model.Category, error)
+	// Link returns text shown to the recipient to unsubscribe with the token
+	Link(token string) string
+}
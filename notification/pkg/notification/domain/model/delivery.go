@@ -0,0 +1,56 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrDeliveryNotFound = errors.New("delivery not found")
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending DeliveryStatus = "pending"
+	DeliverySent    DeliveryStatus = "sent"
+	// DeliveryFailed is set when all attempts failed
+	DeliveryFailed DeliveryStatus = "failed"
+	// DeliveryBounced is set when channel rejected the recipient contact, such delivery is not retried
+	DeliveryBounced DeliveryStatus = "bounced"
+)
+
+type DeliveryAttempt struct {
+	Number      int
+	Status      DeliveryStatus
+	Error       string
+	AttemptedAt time.Time
+}
+
+// Delivery is sending of notification through one channel, message is kept to retry it
+type Delivery struct {
+	ID             uuid.UUID
+	NotificationID uuid.UUID
+	Channel        string
+	Recipient      Recipient
+	Message        Message
+	Status         DeliveryStatus
+	Attempts       []DeliveryAttempt
+	// NextAttemptAt is set while delivery is pending
+	NextAttemptAt *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type DeliveryRepository interface {
+	NextID() (uuid.UUID, error)
+	// Store inserts new attempts of the delivery, stored attempts are not changed
+	Store(delivery *Delivery) error
+	Find(id uuid.UUID) (*Delivery, error)
+	ListByNotification(notificationID uuid.UUID) ([]Delivery, error)
+	// ClaimDue locks pending deliveries with next attempt before now skipping ones locked by other workers
+	// and moves their next attempt to claimedUntil, so they are not claimed again while being sent
+	ClaimDue(now, claimedUntil time.Time, limit int) ([]Delivery, error)
+}
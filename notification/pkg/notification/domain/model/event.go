@@ -25,12 +25,33 @@ func (e NotificationDeleted) Type() string {
 }
 
 type NotificationSent struct {
-	ID             uuid.UUID
-	RecipientName  string
-	RecipientEmail string
-	SentAt         time.Time
+	ID                uuid.UUID
+	Name              string
+	Channel           string
+	RecipientName     string
+	RecipientEmail    string
+	RecipientTelegram string
+	SentAt            time.Time
 }
 
 func (e NotificationSent) Type() string {
-	return "NotificationSent"
+	return "notification_sent"
+}
+
+// NotificationFailed is emitted when delivery through the channel is given up, Status is failed or bounced
+type NotificationFailed struct {
+	ID                uuid.UUID
+	Name              string
+	Channel           string
+	Status            DeliveryStatus
+	Error             string
+	Attempts          int
+	RecipientName     string
+	RecipientEmail    string
+	RecipientTelegram string
+	FailedAt          time.Time
+}
+
+func (e NotificationFailed) Type() string {
+	return "notification_failed"
 }
@@ -2,6 +2,7 @@ package model
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -11,19 +12,33 @@ var (
 )
 
 type Recipient struct {
-	Name  string
-	Email string
+	Name     string
+	Email    string
+	Telegram string
 }
 
 type Notification struct {
-	ID      uuid.UUID
-	Name    string
-	Subject string
-	Body    string
+	ID uuid.UUID
+	// UserID is set for notifications in user inbox, notifications for configured contacts have no user
+	UserID     *uuid.UUID
+	Name       string
+	Subject    string
+	Body       string
+	Recipient  Recipient
+	CreatedAt  time.Time
+	ReadAt     *time.Time
+	ArchivedAt *time.Time
 }
 
 type NotificationRepository interface {
 	NextID() (uuid.UUID, error)
 	Store(notification *Notification) error
 	Find(id uuid.UUID) (*Notification, error)
+	// DeleteByRecipient deletes notifications sent to any of non-empty recipient contacts and returns their number
+	DeleteByRecipient(recipient Recipient) (int, error)
+	DeleteByUser(userID uuid.UUID) (int, error)
+	// MarkRead marks unread notifications of the user as read and returns their number, all of them if ids are nil
+	MarkRead(userID uuid.UUID, ids []uuid.UUID, readAt time.Time) (int, error)
+	// Archive archives notifications of the user and returns number of archived ones
+	Archive(userID uuid.UUID, ids []uuid.UUID, archivedAt time.Time) (int, error)
 }
@@ -0,0 +1,155 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCategory          = errors.New("invalid notification category")
+	ErrInvalidChannel           = errors.New("invalid notification channel")
+	ErrSecurityCategoryRequired = errors.New("security notifications can not be disabled")
+	ErrInvalidQuietHours        = errors.New("invalid quiet hours")
+	ErrInvalidUnsubscribeToken  = errors.New("invalid unsubscribe token")
+	ErrInvalidDigestWindow      = errors.New("invalid digest window")
+)
+
+const (
+	MinDigestWindow = 5 * time.Minute
+	MaxDigestWindow = 7 * 24 * time.Hour
+)
+
+type Category string
+
+const (
+	CategoryOrders    Category = "orders"
+	CategoryPayments  Category = "payments"
+	CategoryMarketing Category = "marketing"
+	CategorySecurity  Category = "security"
+)
+
+// DeliveryChannels are channels user can turn off, inbox always keeps notifications
+var DeliveryChannels = []string{ChannelEmail, ChannelTelegram}
+
+func (c Category) Valid() bool {
+	switch c {
+	case CategoryOrders, CategoryPayments, CategoryMarketing, CategorySecurity:
+		return true
+	default:
+		return false
+	}
+}
+
+// EventCategory returns category of notifications rendered for the event type, unknown events are marketing ones
+func EventCategory(eventType string) Category {
+	switch {
+	case eventType == "contact_verification_requested", eventType == "user_locked":
+		return CategorySecurity
+	case strings.HasPrefix(eventType, "order_"):
+		return CategoryOrders
+	case strings.HasPrefix(eventType, "payment_"):
+		return CategoryPayments
+	default:
+		return CategoryMarketing
+	}
+}
+
+type ChannelPreference struct {
+	Category Category
+	Channel  string
+}
+
+// QuietHours is time of day when notifications are deferred, Start and End are offsets from midnight
+// in TimeZone and hours span midnight if End is before Start
+type QuietHours struct {
+	Start    time.Duration
+	End      time.Duration
+	TimeZone string
+}
+
+func (q QuietHours) Validate() error {
+	if q.Start < 0 || q.Start >= 24*time.Hour || q.End < 0 || q.End >= 24*time.Hour || q.Start == q.End {
+		return ErrInvalidQuietHours
+	}
+	if _, err := time.LoadLocation(q.TimeZone); err != nil {
+		return ErrInvalidQuietHours
+	}
+	return nil
+}
+
+func (q QuietHours) Contains(t time.Time) bool {
+	location, err := time.LoadLocation(q.TimeZone)
+	if err != nil {
+		return false
+	}
+	local := t.In(location)
+	offset := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	if q.Start < q.End {
+		return offset >= q.Start && offset < q.End
+	}
+	return offset >= q.Start || offset < q.End
+}
+
+// EndAfter returns the nearest end of quiet hours after the moment
+func (q QuietHours) EndAfter(t time.Time) time.Time {
+	location, err := time.LoadLocation(q.TimeZone)
+	if err != nil {
+		return t
+	}
+	local := t.In(location)
+	hour, minute := int(q.End/time.Hour), int(q.End%time.Hour/time.Minute)
+	end := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, location)
+	if !end.After(local) {
+		end = time.Date(local.Year(), local.Month(), local.Day()+1, hour, minute, 0, 0, location)
+	}
+	return end
+}
+
+// Preferences of the user, every category is delivered through every channel unless it is disabled
+type Preferences struct {
+	UserID     uuid.UUID
+	Disabled   []ChannelPreference
+	QuietHours *QuietHours
+	// Digests are windows notifications of the category are grouped in, the first notification opens the window
+	Digests map[Category]time.Duration
+}
+
+// Enabled reports whether notification of the category can be sent through the channel, security notifications are always enabled
+func (p Preferences) Enabled(category Category, channel string) bool {
+	if category == CategorySecurity {
+		return true
+	}
+	for _, disabled := range p.Disabled {
+		if disabled.Category == category && disabled.Channel == channel {
+			return false
+		}
+	}
+	return true
+}
+
+// DeliveryTime returns end of quiet hours if they contain the moment, security notifications are not deferred
+func (p Preferences) DeliveryTime(category Category, at time.Time) time.Time {
+	if category == CategorySecurity || p.QuietHours == nil || !p.QuietHours.Contains(at) {
+		return at
+	}
+	return p.QuietHours.EndAfter(at)
+}
+
+// DigestWindow returns window notifications of the category are grouped in, security notifications are never grouped
+func (p Preferences) DigestWindow(category Category) (time.Duration, bool) {
+	if category == CategorySecurity {
+		return 0, false
+	}
+	window, ok := p.Digests[category]
+	return window, ok
+}
+
+// PreferencesRepository returns default preferences if user has not stored them
+type PreferencesRepository interface {
+	Store(preferences *Preferences) error
+	Find(userID uuid.UUID) (*Preferences, error)
+	Delete(userID uuid.UUID) error
+}
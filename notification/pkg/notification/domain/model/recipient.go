@@ -0,0 +1,33 @@
+package model
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrRecipientNotFound = errors.New("recipient not found")
+)
+
+// UserRecipient is user contacts and locale synced from user service events
+type UserRecipient struct {
+	UserID    uuid.UUID
+	Recipient Recipient
+	// Locale of templates notifications are rendered with, DefaultLocale is used if it is empty
+	Locale string
+}
+
+// TemplateLocale returns locale notifications of the user are rendered with
+func (r UserRecipient) TemplateLocale() string {
+	if r.Locale == "" {
+		return DefaultLocale
+	}
+	return r.Locale
+}
+
+type UserRecipientRepository interface {
+	Store(recipient *UserRecipient) error
+	Find(userID uuid.UUID) (*UserRecipient, error)
+	Delete(userID uuid.UUID) error
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrDigestNotFound = errors.New("digest not found")
+	ErrInvalidSendAt  = errors.New("invalid send time")
+)
+
+const (
+	// OrderPaymentReminderEvent is scheduled on order creation and cancelled once order is paid or cancelled
+	OrderPaymentReminderEvent = "order_payment_reminder"
+	// DigestEvent renders grouped notifications of the category
+	DigestEvent = "notification_digest"
+)
+
+// ScheduledNotification is user notification rendered and sent at SendAt
+type ScheduledNotification struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	// Key identifies notification to cancel it, scheduling notification with the same key replaces previous one
+	Key       string
+	EventType string
+	Payload   []byte
+	SendAt    time.Time
+	CreatedAt time.Time
+}
+
+type ScheduledNotificationRepository interface {
+	NextID() (uuid.UUID, error)
+	// Store replaces notification scheduled with the same key
+	Store(notification *ScheduledNotification) error
+	DeleteByKey(key string) (int, error)
+	DeleteByUser(userID uuid.UUID) error
+	Delete(id uuid.UUID) error
+	// ClaimDue locks notifications due before now skipping ones locked by other workers,
+	// they are not claimed again until claimedUntil
+	ClaimDue(now, claimedUntil time.Time, limit int) ([]ScheduledNotification, error)
+}
+
+// Digest groups notifications of the category which are sent to the user together at SendAt
+type Digest struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	Category        Category
+	NotificationIDs []uuid.UUID
+	SendAt          time.Time
+	CreatedAt       time.Time
+}
+
+type DigestRepository interface {
+	NextID() (uuid.UUID, error)
+	// Store inserts new notifications of the digest, stored ones are kept
+	Store(digest *Digest) error
+	// FindPending returns digest of the user category which is not claimed yet
+	FindPending(userID uuid.UUID, category Category) (*Digest, error)
+	DeleteByUser(userID uuid.UUID) error
+	Delete(id uuid.UUID) error
+	// ClaimDue locks digests due before now skipping ones locked by other workers,
+	// they are not claimed again until claimedUntil and are not extended with new notifications
+	ClaimDue(now, claimedUntil time.Time, limit int) ([]Digest, error)
+}
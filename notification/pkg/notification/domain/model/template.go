@@ -0,0 +1,63 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTemplateNotFound       = errors.New("template not found")
+	ErrTemplateAlreadyExists  = errors.New("template already exists")
+	ErrTemplateRequired       = errors.New("default template of the event can not be deleted")
+	ErrInvalidTemplateKey     = errors.New("invalid template key")
+	ErrInvalidTemplate        = errors.New("invalid template")
+	ErrInvalidTemplatePayload = errors.New("invalid template payload")
+	ErrUnknownTemplateEvent   = errors.New("unknown template event type")
+)
+
+const (
+	// ChannelDefault templates render stored notification and content of channels without own template
+	ChannelDefault  = "default"
+	ChannelEmail    = "email"
+	ChannelTelegram = "telegram"
+
+	// DefaultLocale templates are used when there is no template in recipient locale
+	DefaultLocale = "en"
+)
+
+type TemplateKey struct {
+	EventType string
+	Channel   string
+	Locale    string
+}
+
+// Template is a version of subject and body templates, every change is stored as new version and the latest one is used
+type Template struct {
+	TemplateID uuid.UUID
+	Key        TemplateKey
+	Version    int
+	Subject    string
+	Body       string
+	CreatedAt  time.Time
+}
+
+// Message is template rendered for the event
+type Message struct {
+	Subject string
+	Body    string
+}
+
+type TemplateRepository interface {
+	NextID() (uuid.UUID, error)
+	Store(template *Template) error
+	// Find returns the latest version of the template if version is nil
+	Find(key TemplateKey, version *int) (*Template, error)
+	// ListVersions returns all versions of the template starting from the latest
+	ListVersions(key TemplateKey) ([]Template, error)
+	// ListLatest returns the latest versions of templates of the event type, or of all events if it is empty
+	ListLatest(eventType string) ([]Template, error)
+	// Delete deletes all versions of the template and returns their number
+	Delete(key TemplateKey) (int, error)
+}
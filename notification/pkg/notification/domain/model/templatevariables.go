@@ -0,0 +1,92 @@
+package model
+
+// Template variables are typed payloads of events, templates are executed against them
+// and may use only fields declared here, e.g. {{.OrderID}}
+
+type OrderCreatedVariables struct {
+	OrderID string `json:"order_id"`
+	UserID  string `json:"user_id"`
+}
+
+type OrderPaidVariables struct {
+	OrderID string `json:"order_id"`
+	UserID  string `json:"user_id"`
+}
+
+type OrderCancelledVariables struct {
+	OrderID string `json:"order_id"`
+	UserID  string `json:"user_id"`
+	Reason  string `json:"reason"`
+}
+
+type StockLowVariables struct {
+	ProductID        string `json:"product_id"`
+	ProductName      string `json:"product_name"`
+	Quantity         int    `json:"quantity"`
+	ReorderThreshold int    `json:"reorder_threshold"`
+}
+
+type OutOfStockVariables struct {
+	ProductID   string `json:"product_id"`
+	ProductName string `json:"product_name"`
+}
+
+type ContactVerificationRequestedVariables struct {
+	Login       string `json:"login"`
+	ContactType string `json:"contact_type"`
+	Contact     string `json:"contact"`
+	Code        string `json:"code"`
+	ExpiresAt   int64  `json:"expires_at"`
+}
+
+type UserLockedVariables struct {
+	Login       string `json:"login"`
+	LockedUntil int64  `json:"locked_until"`
+}
+
+type UserWelcomeVariables struct {
+	Name string `json:"name"`
+}
+
+// OrderPaymentReminderVariables is payload of order_created event the reminder is scheduled on
+type OrderPaymentReminderVariables struct {
+	OrderID string `json:"order_id"`
+}
+
+type DigestVariables struct {
+	Category      string       `json:"category"`
+	Notifications []DigestItem `json:"notifications"`
+}
+
+type DigestItem struct {
+	Subject   string `json:"subject"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// NewTemplateVariables returns pointer to zero variables of the event type
+func NewTemplateVariables(eventType string) (any, error) {
+	switch eventType {
+	case "order_created":
+		return &OrderCreatedVariables{}, nil
+	case "order_paid":
+		return &OrderPaidVariables{}, nil
+	case "order_cancelled":
+		return &OrderCancelledVariables{}, nil
+	case "stock_low":
+		return &StockLowVariables{}, nil
+	case "out_of_stock":
+		return &OutOfStockVariables{}, nil
+	case "contact_verification_requested":
+		return &ContactVerificationRequestedVariables{}, nil
+	case "user_locked":
+		return &UserLockedVariables{}, nil
+	case "user_welcome":
+		return &UserWelcomeVariables{}, nil
+	case OrderPaymentReminderEvent:
+		return &OrderPaymentReminderVariables{}, nil
+	case DigestEvent:
+		return &DigestVariables{}, nil
+	default:
+		return nil, ErrUnknownTemplateEvent
+	}
+}
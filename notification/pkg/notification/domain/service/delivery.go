@@ -0,0 +1,110 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification/pkg/notification/domain/model"
+)
+
+// RetryPolicy doubles delay after every failed attempt starting from Backoff up to MaxBackoff
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+func (p RetryPolicy) delay(attempts int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempts && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxBackoff)
+}
+
+type Delivery interface {
+	CreateDelivery(
+		notificationID uuid.UUID,
+		channel string,
+		recipient model.Recipient,
+		message model.Message,
+		nextAttemptAt time.Time,
+	) (*model.Delivery, error)
+	MarkSent(delivery *model.Delivery, at time.Time) error
+	// MarkFailed schedules next attempt with backoff, delivery fails after max attempts
+	// or bounces at once if recipient was rejected
+	MarkFailed(delivery *model.Delivery, retryPolicy RetryPolicy, reason string, rejected bool, at time.Time) error
+}
+
+func NewDeliveryService(repo model.DeliveryRepository) Delivery {
+	return &deliveryService{repo: repo}
+}
+
+type deliveryService struct {
+	repo model.DeliveryRepository
+}
+
+func (s deliveryService) CreateDelivery(
+	notificationID uuid.UUID,
+	channel string,
+	recipient model.Recipient,
+	message model.Message,
+	nextAttemptAt time.Time,
+) (*model.Delivery, error) {
+	id, err := s.repo.NextID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	delivery := &model.Delivery{
+		ID:             id,
+		NotificationID: notificationID,
+		Channel:        channel,
+		Recipient:      recipient,
+		Message:        message,
+		Status:         model.DeliveryPending,
+		NextAttemptAt:  &nextAttemptAt,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	return delivery, s.repo.Store(delivery)
+}
+
+func (s deliveryService) MarkSent(delivery *model.Delivery, at time.Time) error {
+	delivery.Attempts = append(delivery.Attempts, model.DeliveryAttempt{
+		Number:      len(delivery.Attempts) + 1,
+		Status:      model.DeliverySent,
+		AttemptedAt: at,
+	})
+	delivery.Status = model.DeliverySent
+	delivery.NextAttemptAt = nil
+	delivery.UpdatedAt = at
+	return s.repo.Store(delivery)
+}
+
+func (s deliveryService) MarkFailed(delivery *model.Delivery, retryPolicy RetryPolicy, reason string, rejected bool, at time.Time) error {
+	attempt := model.DeliveryAttempt{
+		Number:      len(delivery.Attempts) + 1,
+		Status:      model.DeliveryFailed,
+		Error:       reason,
+		AttemptedAt: at,
+	}
+	switch {
+	case rejected:
+		attempt.Status = model.DeliveryBounced
+		delivery.Status = model.DeliveryBounced
+		delivery.NextAttemptAt = nil
+	case attempt.Number >= retryPolicy.MaxAttempts:
+		delivery.Status = model.DeliveryFailed
+		delivery.NextAttemptAt = nil
+	default:
+		nextAttemptAt := at.Add(retryPolicy.delay(attempt.Number))
+		delivery.Status = model.DeliveryPending
+		delivery.NextAttemptAt = &nextAttemptAt
+	}
+	delivery.Attempts = append(delivery.Attempts, attempt)
+	delivery.UpdatedAt = at
+	return s.repo.Store(delivery)
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 
 	"notification/pkg/notification/domain/model"
@@ -8,6 +10,16 @@ import (
 
 type Notification interface {
 	CreateNotification(name string, subject string, body string) (uuid.UUID, error)
+	// NotifyRecipients creates separate notification for every recipient
+	NotifyRecipients(recipients []model.Recipient, name, subject, body string) ([]uuid.UUID, error)
+	// NotifyUser creates notification in user inbox, it is also sent to non-empty recipient contacts
+	NotifyUser(userID uuid.UUID, recipient model.Recipient, name, subject, body string) (uuid.UUID, error)
+	// CreateDigest creates notification of the user sending grouped ones, it is read and archived at once
+	// as grouped notifications are already in inbox
+	CreateDigest(userID uuid.UUID, recipient model.Recipient, subject, body string) (uuid.UUID, error)
+	// MarkRead marks notifications of the user as read, all of them if ids are nil
+	MarkRead(userID uuid.UUID, ids []uuid.UUID) (int, error)
+	Archive(userID uuid.UUID, ids []uuid.UUID) (int, error)
 }
 
 func NewNotificationService(repo model.NotificationRepository) Notification {
@@ -25,10 +37,88 @@ func (n notificationService) CreateNotification(name, subject, body string) (uui
 	}
 
 	err = n.repo.Store(&model.Notification{
-		ID:      id,
-		Name:    name,
-		Subject: subject,
-		Body:    body,
+		ID:        id,
+		Name:      name,
+		Subject:   subject,
+		Body:      body,
+		CreatedAt: time.Now(),
+	})
+	return id, err
+}
+
+func (n notificationService) NotifyRecipients(recipients []model.Recipient, name, subject, body string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(recipients))
+	for _, recipient := range recipients {
+		id, err := n.repo.NextID()
+		if err != nil {
+			return nil, err
+		}
+
+		err = n.repo.Store(&model.Notification{
+			ID:        id,
+			Name:      name,
+			Subject:   subject,
+			Body:      body,
+			Recipient: recipient,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (n notificationService) NotifyUser(userID uuid.UUID, recipient model.Recipient, name, subject, body string) (uuid.UUID, error) {
+	id, err := n.repo.NextID()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	err = n.repo.Store(&model.Notification{
+		ID:        id,
+		UserID:    &userID,
+		Name:      name,
+		Subject:   subject,
+		Body:      body,
+		Recipient: recipient,
+		CreatedAt: time.Now(),
 	})
 	return id, err
 }
+
+func (n notificationService) CreateDigest(userID uuid.UUID, recipient model.Recipient, subject, body string) (uuid.UUID, error) {
+	id, err := n.repo.NextID()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	now := time.Now()
+	err = n.repo.Store(&model.Notification{
+		ID:         id,
+		UserID:     &userID,
+		Name:       model.DigestEvent,
+		Subject:    subject,
+		Body:       body,
+		Recipient:  recipient,
+		CreatedAt:  now,
+		ReadAt:     &now,
+		ArchivedAt: &now,
+	})
+	return id, err
+}
+
+func (n notificationService) MarkRead(userID uuid.UUID, ids []uuid.UUID) (int, error) {
+	if ids != nil && len(ids) == 0 {
+		return 0, nil
+	}
+	return n.repo.MarkRead(userID, ids, time.Now())
+}
+
+func (n notificationService) Archive(userID uuid.UUID, ids []uuid.UUID) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return n.repo.Archive(userID, ids, time.Now())
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification/pkg/notification/domain/model"
+)
+
+type Preferences interface {
+	// UpdatePreferences replaces disabled channels, quiet hours and digests of the user, nil quiet hours are removed
+	UpdatePreferences(
+		userID uuid.UUID,
+		disabled []model.ChannelPreference,
+		quietHours *model.QuietHours,
+		digests map[model.Category]time.Duration,
+	) (*model.Preferences, error)
+	// DisableCategory turns off every delivery channel of the category
+	DisableCategory(userID uuid.UUID, category model.Category) (*model.Preferences, error)
+}
+
+func NewPreferencesService(repo model.PreferencesRepository) Preferences {
+	return &preferencesService{repo: repo}
+}
+
+type preferencesService struct {
+	repo model.PreferencesRepository
+}
+
+func (s preferencesService) UpdatePreferences(
+	userID uuid.UUID,
+	disabled []model.ChannelPreference,
+	quietHours *model.QuietHours,
+	digests map[model.Category]time.Duration,
+) (*model.Preferences, error) {
+	var unique []model.ChannelPreference
+	for _, preference := range disabled {
+		err := validateChannelPreference(preference)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(unique, preference) {
+			unique = append(unique, preference)
+		}
+	}
+	if quietHours != nil {
+		err := quietHours.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for category, window := range digests {
+		if !category.Valid() {
+			return nil, model.ErrInvalidCategory
+		}
+		if category == model.CategorySecurity {
+			return nil, model.ErrSecurityCategoryRequired
+		}
+		if window < model.MinDigestWindow || window > model.MaxDigestWindow {
+			return nil, model.ErrInvalidDigestWindow
+		}
+	}
+
+	preferences := &model.Preferences{
+		UserID:     userID,
+		Disabled:   unique,
+		QuietHours: quietHours,
+		Digests:    digests,
+	}
+	return preferences, s.repo.Store(preferences)
+}
+
+func (s preferencesService) DisableCategory(userID uuid.UUID, category model.Category) (*model.Preferences, error) {
+	if !category.Valid() {
+		return nil, model.ErrInvalidCategory
+	}
+	if category == model.CategorySecurity {
+		return nil, model.ErrSecurityCategoryRequired
+	}
+
+	preferences, err := s.repo.Find(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, channel := range model.DeliveryChannels {
+		preference := model.ChannelPreference{Category: category, Channel: channel}
+		if !slices.Contains(preferences.Disabled, preference) {
+			preferences.Disabled = append(preferences.Disabled, preference)
+		}
+	}
+	return preferences, s.repo.Store(preferences)
+}
+
+func validateChannelPreference(preference model.ChannelPreference) error {
+	if !preference.Category.Valid() {
+		return model.ErrInvalidCategory
+	}
+	if !slices.Contains(model.DeliveryChannels, preference.Channel) {
+		return model.ErrInvalidChannel
+	}
+	if preference.Category == model.CategorySecurity {
+		return model.ErrSecurityCategoryRequired
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+
+	"notification/pkg/notification/domain/model"
+)
+
+type UserRecipient interface {
+	// StoreUserRecipient replaces contacts of the user, synced locale is kept
+	StoreUserRecipient(userID uuid.UUID, recipient model.Recipient) error
+	// UpdateUserRecipientContacts changes non-nil contacts, empty contact is removed
+	UpdateUserRecipientContacts(userID uuid.UUID, email, telegram *string) error
+	UpdateUserRecipientLocale(userID uuid.UUID, locale string) error
+	DeleteUserRecipient(userID uuid.UUID) error
+}
+
+func NewUserRecipientService(repo model.UserRecipientRepository) UserRecipient {
+	return &userRecipientService{repo: repo}
+}
+
+type userRecipientService struct {
+	repo model.UserRecipientRepository
+}
+
+func (s userRecipientService) StoreUserRecipient(userID uuid.UUID, recipient model.Recipient) error {
+	userRecipient, err := s.findUserRecipient(userID)
+	if err != nil {
+		return err
+	}
+	userRecipient.Recipient = recipient
+	return s.repo.Store(userRecipient)
+}
+
+func (s userRecipientService) UpdateUserRecipientContacts(userID uuid.UUID, email, telegram *string) error {
+	if email == nil && telegram == nil {
+		return nil
+	}
+
+	userRecipient, err := s.findUserRecipient(userID)
+	if err != nil {
+		return err
+	}
+
+	if email != nil {
+		userRecipient.Recipient.Email = *email
+	}
+	if telegram != nil {
+		userRecipient.Recipient.Telegram = *telegram
+	}
+	return s.repo.Store(userRecipient)
+}
+
+func (s userRecipientService) UpdateUserRecipientLocale(userID uuid.UUID, locale string) error {
+	userRecipient, err := s.findUserRecipient(userID)
+	if err != nil {
+		return err
+	}
+	if userRecipient.Locale == locale {
+		return nil
+	}
+	userRecipient.Locale = locale
+	return s.repo.Store(userRecipient)
+}
+
+func (s userRecipientService) DeleteUserRecipient(userID uuid.UUID) error {
+	return s.repo.Delete(userID)
+}
+
+// findUserRecipient returns recipient without contacts if it is not stored yet,
+// events of the user may be handled out of order and missing fields are filled in by later events
+func (s userRecipientService) findUserRecipient(userID uuid.UUID) (*model.UserRecipient, error) {
+	userRecipient, err := s.repo.Find(userID)
+	if errors.Is(err, model.ErrRecipientNotFound) {
+		return &model.UserRecipient{UserID: userID}, nil
+	}
+	return userRecipient, err
+}
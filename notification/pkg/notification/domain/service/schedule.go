@@ -0,0 +1,82 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification/pkg/notification/domain/model"
+)
+
+type Schedule interface {
+	// ScheduleNotification stores user notification to be sent at sendAt replacing one with the same key
+	ScheduleNotification(userID uuid.UUID, key, eventType string, payload []byte, sendAt time.Time) (*model.ScheduledNotification, error)
+	CancelNotification(key string) (int, error)
+	// AddToDigest appends notification to pending digest of the user category,
+	// new digest is opened to be sent when window passes
+	AddToDigest(userID uuid.UUID, category model.Category, notificationID uuid.UUID, window time.Duration) (*model.Digest, error)
+}
+
+func NewScheduleService(scheduledRepo model.ScheduledNotificationRepository, digestRepo model.DigestRepository) Schedule {
+	return &scheduleService{
+		scheduledRepo: scheduledRepo,
+		digestRepo:    digestRepo,
+	}
+}
+
+type scheduleService struct {
+	scheduledRepo model.ScheduledNotificationRepository
+	digestRepo    model.DigestRepository
+}
+
+func (s scheduleService) ScheduleNotification(userID uuid.UUID, key, eventType string, payload []byte, sendAt time.Time) (*model.ScheduledNotification, error) {
+	if sendAt.IsZero() {
+		return nil, model.ErrInvalidSendAt
+	}
+	if _, err := model.NewTemplateVariables(eventType); err != nil {
+		return nil, err
+	}
+
+	id, err := s.scheduledRepo.NextID()
+	if err != nil {
+		return nil, err
+	}
+	notification := &model.ScheduledNotification{
+		ID:        id,
+		UserID:    userID,
+		Key:       key,
+		EventType: eventType,
+		Payload:   payload,
+		SendAt:    sendAt,
+		CreatedAt: time.Now(),
+	}
+	return notification, s.scheduledRepo.Store(notification)
+}
+
+func (s scheduleService) CancelNotification(key string) (int, error) {
+	return s.scheduledRepo.DeleteByKey(key)
+}
+
+func (s scheduleService) AddToDigest(userID uuid.UUID, category model.Category, notificationID uuid.UUID, window time.Duration) (*model.Digest, error) {
+	digest, err := s.digestRepo.FindPending(userID, category)
+	if errors.Is(err, model.ErrDigestNotFound) {
+		id, err := s.digestRepo.NextID()
+		if err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		digest = &model.Digest{
+			ID:        id,
+			UserID:    userID,
+			Category:  category,
+			SendAt:    now.Add(window),
+			CreatedAt: now,
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	digest.NotificationIDs = append(digest.NotificationIDs, notificationID)
+	return digest, s.digestRepo.Store(digest)
+}
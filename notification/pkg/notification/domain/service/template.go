@@ -0,0 +1,177 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"text/template"
+	"time"
+
+	"notification/pkg/notification/domain/model"
+)
+
+type Template interface {
+	// CreateTemplate stores the first version of the template
+	CreateTemplate(key model.TemplateKey, subject, body string) (*model.Template, error)
+	// UpdateTemplate stores new version of existing template
+	UpdateTemplate(key model.TemplateKey, subject, body string) (*model.Template, error)
+	DeleteTemplate(key model.TemplateKey) (int, error)
+	// Render renders the latest template of the key against event payload, falling back to default locale
+	Render(key model.TemplateKey, payload []byte) (model.Message, error)
+	// Preview renders template version against sample payload, the latest version is used if version is nil
+	Preview(key model.TemplateKey, version *int, payload []byte) (model.Message, error)
+	// PreviewDraft renders not stored template against sample payload
+	PreviewDraft(eventType, subject, body string, payload []byte) (model.Message, error)
+}
+
+func NewTemplateService(repo model.TemplateRepository) Template {
+	return &templateService{repo: repo}
+}
+
+type templateService struct {
+	repo model.TemplateRepository
+}
+
+func (s templateService) CreateTemplate(key model.TemplateKey, subject, body string) (*model.Template, error) {
+	err := validateTemplate(key, subject, body)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.repo.Find(key, nil)
+	if err == nil {
+		return nil, model.ErrTemplateAlreadyExists
+	} else if !errors.Is(err, model.ErrTemplateNotFound) {
+		return nil, err
+	}
+	return s.storeVersion(key, 1, subject, body)
+}
+
+func (s templateService) UpdateTemplate(key model.TemplateKey, subject, body string) (*model.Template, error) {
+	err := validateTemplate(key, subject, body)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := s.repo.Find(key, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.storeVersion(key, latest.Version+1, subject, body)
+}
+
+func (s templateService) DeleteTemplate(key model.TemplateKey) (int, error) {
+	if key.Channel == model.ChannelDefault && key.Locale == model.DefaultLocale {
+		return 0, model.ErrTemplateRequired
+	}
+	deleted, err := s.repo.Delete(key)
+	if err != nil {
+		return 0, err
+	}
+	if deleted == 0 {
+		return 0, model.ErrTemplateNotFound
+	}
+	return deleted, nil
+}
+
+func (s templateService) Render(key model.TemplateKey, payload []byte) (model.Message, error) {
+	t, err := s.repo.Find(key, nil)
+	if errors.Is(err, model.ErrTemplateNotFound) && key.Locale != model.DefaultLocale {
+		key.Locale = model.DefaultLocale
+		t, err = s.repo.Find(key, nil)
+	}
+	if err != nil {
+		return model.Message{}, err
+	}
+	return render(t.Key.EventType, t.Subject, t.Body, payload)
+}
+
+func (s templateService) Preview(key model.TemplateKey, version *int, payload []byte) (model.Message, error) {
+	t, err := s.repo.Find(key, version)
+	if err != nil {
+		return model.Message{}, err
+	}
+	return render(t.Key.EventType, t.Subject, t.Body, payload)
+}
+
+func (s templateService) PreviewDraft(eventType, subject, body string, payload []byte) (model.Message, error) {
+	return render(eventType, subject, body, payload)
+}
+
+func (s templateService) storeVersion(key model.TemplateKey, version int, subject, body string) (*model.Template, error) {
+	id, err := s.repo.NextID()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &model.Template{
+		TemplateID: id,
+		Key:        key,
+		Version:    version,
+		Subject:    subject,
+		Body:       body,
+		CreatedAt:  time.Now(),
+	}
+	return t, s.repo.Store(t)
+}
+
+var templateFuncs = template.FuncMap{
+	"formatTime": func(unix int64) string {
+		return time.Unix(unix, 0).UTC().Format(time.RFC1123)
+	},
+}
+
+// validateTemplate checks the key and executes templates against zero variables of the event,
+// so templates referring to fields missing in the event payload are rejected before they are stored
+func validateTemplate(key model.TemplateKey, subject, body string) error {
+	switch {
+	case key.EventType == "", key.Locale == "":
+		return model.ErrInvalidTemplateKey
+	case key.Channel != model.ChannelDefault && key.Channel != model.ChannelEmail && key.Channel != model.ChannelTelegram:
+		return fmt.Errorf("%w: unknown channel %q", model.ErrInvalidTemplateKey, key.Channel)
+	case subject == "" || body == "":
+		return fmt.Errorf("%w: subject and body are required", model.ErrInvalidTemplate)
+	}
+	_, err := render(key.EventType, subject, body, nil)
+	return err
+}
+
+func render(eventType, subject, body string, payload []byte) (model.Message, error) {
+	variables, err := model.NewTemplateVariables(eventType)
+	if err != nil {
+		return model.Message{}, err
+	}
+	if payload != nil {
+		err = json.Unmarshal(payload, variables)
+		if err != nil {
+			return model.Message{}, fmt.Errorf("%w: %w", model.ErrInvalidTemplatePayload, err)
+		}
+	}
+
+	renderedSubject, err := execute("subject", subject, variables)
+	if err != nil {
+		return model.Message{}, err
+	}
+	renderedBody, err := execute("body", body, variables)
+	if err != nil {
+		return model.Message{}, err
+	}
+	return model.Message{
+		Subject: renderedSubject,
+		Body:    renderedBody,
+	}, nil
+}
+
+func execute(name, text string, variables any) (string, error) {
+	t, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", model.ErrInvalidTemplate, err)
+	}
+	var b bytes.Buffer
+	err = t.Execute(&b, variables)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", model.ErrInvalidTemplate, err)
+	}
+	return b.String(), nil
+}
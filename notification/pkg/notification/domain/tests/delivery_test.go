@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/domain/service"
+)
+
+func TestDeliveryService(t *testing.T) {
+	repo := &mockDeliveryRepository{
+		store: make(map[uuid.UUID]*model.Delivery),
+	}
+	deliveryService := service.NewDeliveryService(repo)
+	retryPolicy := service.RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     time.Minute,
+		MaxBackoff:  90 * time.Second,
+	}
+	recipient := model.Recipient{Email: "john@example.com"}
+	message := model.Message{Subject: "Subject", Body: "Body"}
+	now := time.Now()
+
+	t.Run("Create delivery", func(t *testing.T) {
+		delivery, err := deliveryService.CreateDelivery(uuid.New(), model.ChannelEmail, recipient, message, now)
+		require.NoError(t, err)
+		require.Equal(t, model.DeliveryPending, repo.store[delivery.ID].Status)
+		require.Equal(t, now, *repo.store[delivery.ID].NextAttemptAt)
+		require.Empty(t, delivery.Attempts)
+	})
+
+	t.Run("Retry with backoff and fail", func(t *testing.T) {
+		delivery, err := deliveryService.CreateDelivery(uuid.New(), model.ChannelEmail, recipient, message, now)
+		require.NoError(t, err)
+
+		err = deliveryService.MarkFailed(delivery, retryPolicy, "connection refused", false, now)
+		require.NoError(t, err)
+		require.Equal(t, model.DeliveryPending, delivery.Status)
+		require.Equal(t, now.Add(time.Minute), *delivery.NextAttemptAt)
+
+		err = deliveryService.MarkFailed(delivery, retryPolicy, "connection refused", false, now)
+		require.NoError(t, err)
+		require.Equal(t, model.DeliveryPending, delivery.Status)
+		require.Equal(t, now.Add(90*time.Second), *delivery.NextAttemptAt)
+
+		err = deliveryService.MarkFailed(delivery, retryPolicy, "connection refused", false, now)
+		require.NoError(t, err)
+		require.Equal(t, model.DeliveryFailed, delivery.Status)
+		require.Nil(t, delivery.NextAttemptAt)
+		require.Len(t, repo.store[delivery.ID].Attempts, 3)
+		require.Equal(t, "connection refused", repo.store[delivery.ID].Attempts[2].Error)
+	})
+
+	t.Run("Bounce rejected recipient", func(t *testing.T) {
+		delivery, err := deliveryService.CreateDelivery(uuid.New(), model.ChannelEmail, recipient, message, now)
+		require.NoError(t, err)
+
+		err = deliveryService.MarkFailed(delivery, retryPolicy, "no such user", true, now)
+		require.NoError(t, err)
+		require.Equal(t, model.DeliveryBounced, delivery.Status)
+		require.Nil(t, delivery.NextAttemptAt)
+		require.Equal(t, model.DeliveryBounced, delivery.Attempts[0].Status)
+	})
+
+	t.Run("Sent after failure", func(t *testing.T) {
+		delivery, err := deliveryService.CreateDelivery(uuid.New(), model.ChannelEmail, recipient, message, now)
+		require.NoError(t, err)
+
+		err = deliveryService.MarkFailed(delivery, retryPolicy, "timeout", false, now)
+		require.NoError(t, err)
+		err = deliveryService.MarkSent(delivery, now.Add(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, model.DeliverySent, repo.store[delivery.ID].Status)
+		require.Nil(t, repo.store[delivery.ID].NextAttemptAt)
+		require.Equal(t, []model.DeliveryStatus{model.DeliveryFailed, model.DeliverySent}, []model.DeliveryStatus{
+			delivery.Attempts[0].Status,
+			delivery.Attempts[1].Status,
+		})
+	})
+}
+
+var _ model.DeliveryRepository = &mockDeliveryRepository{}
+
+type mockDeliveryRepository struct {
+	store map[uuid.UUID]*model.Delivery
+}
+
+func (m *mockDeliveryRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (m *mockDeliveryRepository) Store(delivery *model.Delivery) error {
+	stored := *delivery
+	stored.Attempts = append([]model.DeliveryAttempt(nil), delivery.Attempts...)
+	m.store[delivery.ID] = &stored
+	return nil
+}
+
+func (m *mockDeliveryRepository) Find(id uuid.UUID) (*model.Delivery, error) {
+	delivery, ok := m.store[id]
+	if !ok {
+		return nil, model.ErrDeliveryNotFound
+	}
+	found := *delivery
+	return &found, nil
+}
+
+func (m *mockDeliveryRepository) ListByNotification(notificationID uuid.UUID) ([]model.Delivery, error) {
+	var deliveries []model.Delivery
+	for _, delivery := range m.store {
+		if delivery.NotificationID == notificationID {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *mockDeliveryRepository) ClaimDue(now, claimedUntil time.Time, limit int) ([]model.Delivery, error) {
+	var deliveries []model.Delivery
+	for _, delivery := range m.store {
+		if len(deliveries) == limit {
+			break
+		}
+		if delivery.Status == model.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			delivery.NextAttemptAt = &claimedUntil
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	return deliveries, nil
+}
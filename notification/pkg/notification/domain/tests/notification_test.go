@@ -1,7 +1,9 @@
 package tests
 
 import (
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -30,6 +32,71 @@ func TestNotificationService(t *testing.T) {
 		require.Equal(t, "Something went wrong", repo.store[notificationID].Subject)
 		require.Equal(t, "Something went wrong. Please contact support.", repo.store[notificationID].Body)
 	})
+
+	t.Run("Notify recipients", func(t *testing.T) {
+		recipients := []model.Recipient{
+			{Email: "purchasing@example.com"},
+			{Name: "Supply", Email: "supply@example.com"},
+		}
+
+		ids, err := notificationService.NotifyRecipients(recipients, "stock_low", "Low stock", "Product is running low")
+		require.NoError(t, err)
+		require.Len(t, ids, 2)
+		for i, id := range ids {
+			require.Equal(t, "stock_low", repo.store[id].Name)
+			require.Equal(t, recipients[i], repo.store[id].Recipient)
+		}
+	})
+}
+
+func TestNotificationService_Inbox(t *testing.T) {
+	repo := &mockNotificationRepository{
+		store: make(map[uuid.UUID]*model.Notification),
+	}
+	notificationService := service.NewNotificationService(repo)
+
+	userID := uuid.New()
+	otherUserID := uuid.New()
+	recipient := model.Recipient{Name: "john", Email: "john@example.com"}
+
+	var ids []uuid.UUID
+	for range 3 {
+		id, err := notificationService.NotifyUser(userID, recipient, "order_created", "Order was created", "Order #1 has been created")
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	otherID, err := notificationService.NotifyUser(otherUserID, model.Recipient{}, "order_created", "Order was created", "Order #2 has been created")
+	require.NoError(t, err)
+
+	require.Equal(t, userID, *repo.store[ids[0]].UserID)
+	require.False(t, repo.store[ids[0]].CreatedAt.IsZero())
+
+	t.Run("Mark read", func(t *testing.T) {
+		updated, err := notificationService.MarkRead(userID, []uuid.UUID{ids[0], otherID})
+		require.NoError(t, err)
+		require.Equal(t, 1, updated)
+		require.NotNil(t, repo.store[ids[0]].ReadAt)
+		require.Nil(t, repo.store[otherID].ReadAt)
+
+		updated, err = notificationService.MarkRead(userID, []uuid.UUID{})
+		require.NoError(t, err)
+		require.Equal(t, 0, updated)
+	})
+
+	t.Run("Mark all read", func(t *testing.T) {
+		updated, err := notificationService.MarkRead(userID, nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, updated)
+		require.Nil(t, repo.store[otherID].ReadAt)
+	})
+
+	t.Run("Archive", func(t *testing.T) {
+		updated, err := notificationService.Archive(userID, []uuid.UUID{ids[1], otherID})
+		require.NoError(t, err)
+		require.Equal(t, 1, updated)
+		require.NotNil(t, repo.store[ids[1]].ArchivedAt)
+		require.Nil(t, repo.store[otherID].ArchivedAt)
+	})
 }
 
 var _ model.NotificationRepository = &mockNotificationRepository{}
@@ -54,3 +121,64 @@ func (m *mockNotificationRepository) Find(id uuid.UUID) (*model.Notification, er
 	}
 	return notification, nil
 }
+
+func (m *mockNotificationRepository) DeleteByRecipient(recipient model.Recipient) (int, error) {
+	deleted := 0
+	for id, notification := range m.store {
+		if (recipient.Name != "" && notification.Recipient.Name == recipient.Name) ||
+			(recipient.Email != "" && notification.Recipient.Email == recipient.Email) ||
+			(recipient.Telegram != "" && notification.Recipient.Telegram == recipient.Telegram) {
+			delete(m.store, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (m *mockNotificationRepository) DeleteByUser(userID uuid.UUID) (int, error) {
+	deleted := 0
+	for id, notification := range m.store {
+		if notification.UserID != nil && *notification.UserID == userID {
+			delete(m.store, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (m *mockNotificationRepository) MarkRead(userID uuid.UUID, ids []uuid.UUID, readAt time.Time) (int, error) {
+	updated := 0
+	for _, notification := range m.userNotifications(userID, ids) {
+		if notification.ReadAt == nil {
+			notification.ReadAt = &readAt
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+func (m *mockNotificationRepository) Archive(userID uuid.UUID, ids []uuid.UUID, archivedAt time.Time) (int, error) {
+	updated := 0
+	for _, notification := range m.userNotifications(userID, ids) {
+		if notification.ArchivedAt == nil {
+			notification.ArchivedAt = &archivedAt
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+// userNotifications returns notifications of the user with given ids, all of them if ids are nil
+func (m *mockNotificationRepository) userNotifications(userID uuid.UUID, ids []uuid.UUID) []*model.Notification {
+	var result []*model.Notification
+	for id, notification := range m.store {
+		if notification.UserID == nil || *notification.UserID != userID {
+			continue
+		}
+		if ids != nil && !slices.Contains(ids, id) {
+			continue
+		}
+		result = append(result, notification)
+	}
+	return result
+}
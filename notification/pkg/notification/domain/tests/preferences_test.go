@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/domain/service"
+)
+
+func TestPreferencesService(t *testing.T) {
+	repo := &mockPreferencesRepository{
+		store: make(map[uuid.UUID]*model.Preferences),
+	}
+	preferencesService := service.NewPreferencesService(repo)
+	userID := uuid.New()
+
+	t.Run("Update preferences", func(t *testing.T) {
+		preferences, err := preferencesService.UpdatePreferences(userID, []model.ChannelPreference{
+			{Category: model.CategoryMarketing, Channel: model.ChannelTelegram},
+			{Category: model.CategoryMarketing, Channel: model.ChannelTelegram},
+		}, &model.QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour, TimeZone: "Europe/Moscow"}, map[model.Category]time.Duration{
+			model.CategoryOrders: 24 * time.Hour,
+		})
+		require.NoError(t, err)
+		require.Len(t, preferences.Disabled, 1)
+		require.Equal(t, preferences, repo.store[userID])
+	})
+
+	t.Run("Security can not be disabled", func(t *testing.T) {
+		_, err := preferencesService.UpdatePreferences(userID, []model.ChannelPreference{
+			{Category: model.CategorySecurity, Channel: model.ChannelEmail},
+		}, nil, nil)
+		require.ErrorIs(t, err, model.ErrSecurityCategoryRequired)
+
+		_, err = preferencesService.DisableCategory(userID, model.CategorySecurity)
+		require.ErrorIs(t, err, model.ErrSecurityCategoryRequired)
+	})
+
+	t.Run("Invalid preferences", func(t *testing.T) {
+		_, err := preferencesService.UpdatePreferences(userID, []model.ChannelPreference{
+			{Category: "news", Channel: model.ChannelEmail},
+		}, nil, nil)
+		require.ErrorIs(t, err, model.ErrInvalidCategory)
+
+		_, err = preferencesService.UpdatePreferences(userID, []model.ChannelPreference{
+			{Category: model.CategoryOrders, Channel: model.ChannelDefault},
+		}, nil, nil)
+		require.ErrorIs(t, err, model.ErrInvalidChannel)
+
+		_, err = preferencesService.UpdatePreferences(userID, nil, &model.QuietHours{Start: time.Hour, End: time.Hour, TimeZone: "UTC"}, nil)
+		require.ErrorIs(t, err, model.ErrInvalidQuietHours)
+
+		_, err = preferencesService.UpdatePreferences(userID, nil, &model.QuietHours{Start: time.Hour, End: 2 * time.Hour, TimeZone: "Mars/Olympus"}, nil)
+		require.ErrorIs(t, err, model.ErrInvalidQuietHours)
+
+		_, err = preferencesService.UpdatePreferences(userID, nil, nil, map[model.Category]time.Duration{model.CategoryOrders: time.Minute})
+		require.ErrorIs(t, err, model.ErrInvalidDigestWindow)
+
+		_, err = preferencesService.UpdatePreferences(userID, nil, nil, map[model.Category]time.Duration{model.CategorySecurity: time.Hour})
+		require.ErrorIs(t, err, model.ErrSecurityCategoryRequired)
+	})
+
+	t.Run("Disable category", func(t *testing.T) {
+		preferences, err := preferencesService.DisableCategory(userID, model.CategoryMarketing)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []model.ChannelPreference{
+			{Category: model.CategoryMarketing, Channel: model.ChannelEmail},
+			{Category: model.CategoryMarketing, Channel: model.ChannelTelegram},
+		}, preferences.Disabled)
+		require.NotNil(t, preferences.QuietHours)
+		require.Equal(t, 24*time.Hour, preferences.Digests[model.CategoryOrders])
+	})
+}
+
+func TestPreferences(t *testing.T) {
+	preferences := model.Preferences{
+		Disabled: []model.ChannelPreference{
+			{Category: model.CategoryOrders, Channel: model.ChannelEmail},
+		},
+		QuietHours: &model.QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour, TimeZone: "Europe/Moscow"},
+		Digests: map[model.Category]time.Duration{
+			model.CategoryOrders:   time.Hour,
+			model.CategorySecurity: time.Hour,
+		},
+	}
+	// 12:00 and 23:30 in Moscow
+	day := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	night := time.Date(2025, 1, 10, 20, 30, 0, 0, time.UTC)
+
+	require.False(t, preferences.Enabled(model.CategoryOrders, model.ChannelEmail))
+	require.True(t, preferences.Enabled(model.CategoryOrders, model.ChannelTelegram))
+	require.True(t, preferences.Enabled(model.CategorySecurity, model.ChannelEmail))
+
+	require.Equal(t, day, preferences.DeliveryTime(model.CategoryOrders, day))
+	// 07:00 in Moscow of the next day
+	require.Equal(t, time.Date(2025, 1, 11, 4, 0, 0, 0, time.UTC), preferences.DeliveryTime(model.CategoryOrders, night).UTC())
+	require.Equal(t, night, preferences.DeliveryTime(model.CategorySecurity, night))
+
+	window, ok := preferences.DigestWindow(model.CategoryOrders)
+	require.True(t, ok)
+	require.Equal(t, time.Hour, window)
+	_, ok = preferences.DigestWindow(model.CategorySecurity)
+	require.False(t, ok)
+	_, ok = preferences.DigestWindow(model.CategoryPayments)
+	require.False(t, ok)
+}
+
+func TestEventCategory(t *testing.T) {
+	require.Equal(t, model.CategoryOrders, model.EventCategory("order_paid"))
+	require.Equal(t, model.CategoryPayments, model.EventCategory("payment_failed"))
+	require.Equal(t, model.CategorySecurity, model.EventCategory("user_locked"))
+	require.Equal(t, model.CategorySecurity, model.EventCategory("contact_verification_requested"))
+	require.Equal(t, model.CategoryMarketing, model.EventCategory("user_welcome"))
+}
+
+var _ model.PreferencesRepository = &mockPreferencesRepository{}
+
+type mockPreferencesRepository struct {
+	store map[uuid.UUID]*model.Preferences
+}
+
+func (m *mockPreferencesRepository) Store(preferences *model.Preferences) error {
+	m.store[preferences.UserID] = preferences
+	return nil
+}
+
+func (m *mockPreferencesRepository) Find(userID uuid.UUID) (*model.Preferences, error) {
+	preferences, ok := m.store[userID]
+	if !ok {
+		return &model.Preferences{UserID: userID}, nil
+	}
+	found := *preferences
+	found.Disabled = append([]model.ChannelPreference(nil), preferences.Disabled...)
+	return &found, nil
+}
+
+func (m *mockPreferencesRepository) Delete(userID uuid.UUID) error {
+	delete(m.store, userID)
+	return nil
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/domain/service"
+)
+
+func TestUserRecipientService(t *testing.T) {
+	repo := &mockUserRecipientRepository{
+		store: make(map[uuid.UUID]*model.UserRecipient),
+	}
+	recipientService := service.NewUserRecipientService(repo)
+	userID := uuid.New()
+
+	t.Run("Store recipient", func(t *testing.T) {
+		err := recipientService.StoreUserRecipient(userID, model.Recipient{Name: "john", Email: "john@example.com"})
+		require.NoError(t, err)
+		require.Equal(t, model.Recipient{Name: "john", Email: "john@example.com"}, repo.store[userID].Recipient)
+	})
+
+	t.Run("Update contacts", func(t *testing.T) {
+		telegram := "123456"
+		err := recipientService.UpdateUserRecipientContacts(userID, nil, &telegram)
+		require.NoError(t, err)
+		require.Equal(t, model.Recipient{Name: "john", Email: "john@example.com", Telegram: "123456"}, repo.store[userID].Recipient)
+
+		removed := ""
+		err = recipientService.UpdateUserRecipientContacts(userID, &removed, nil)
+		require.NoError(t, err)
+		require.Equal(t, model.Recipient{Name: "john", Telegram: "123456"}, repo.store[userID].Recipient)
+	})
+
+	t.Run("Update unknown recipient", func(t *testing.T) {
+		otherUserID := uuid.New()
+		email := "jane@example.com"
+		err := recipientService.UpdateUserRecipientContacts(otherUserID, &email, nil)
+		require.NoError(t, err)
+		require.Equal(t, model.Recipient{Email: "jane@example.com"}, repo.store[otherUserID].Recipient)
+	})
+
+	t.Run("Update locale", func(t *testing.T) {
+		require.Equal(t, model.DefaultLocale, repo.store[userID].TemplateLocale())
+
+		err := recipientService.UpdateUserRecipientLocale(userID, "ru")
+		require.NoError(t, err)
+		require.Equal(t, "ru", repo.store[userID].TemplateLocale())
+
+		// contacts stored again keep synced locale
+		err = recipientService.StoreUserRecipient(userID, model.Recipient{Name: "john", Telegram: "123456"})
+		require.NoError(t, err)
+		require.Equal(t, "ru", repo.store[userID].Locale)
+		require.Equal(t, model.Recipient{Name: "john", Telegram: "123456"}, repo.store[userID].Recipient)
+	})
+
+	t.Run("Delete recipient", func(t *testing.T) {
+		err := recipientService.DeleteUserRecipient(userID)
+		require.NoError(t, err)
+		_, err = repo.Find(userID)
+		require.ErrorIs(t, err, model.ErrRecipientNotFound)
+	})
+}
+
+var _ model.UserRecipientRepository = &mockUserRecipientRepository{}
+
+type mockUserRecipientRepository struct {
+	store map[uuid.UUID]*model.UserRecipient
+}
+
+func (m *mockUserRecipientRepository) Store(recipient *model.UserRecipient) error {
+	stored := *recipient
+	m.store[recipient.UserID] = &stored
+	return nil
+}
+
+func (m *mockUserRecipientRepository) Find(userID uuid.UUID) (*model.UserRecipient, error) {
+	recipient, ok := m.store[userID]
+	if !ok {
+		return nil, model.ErrRecipientNotFound
+	}
+	found := *recipient
+	return &found, nil
+}
+
+func (m *mockUserRecipientRepository) Delete(userID uuid.UUID) error {
+	delete(m.store, userID)
+	return nil
+}
@@ -0,0 +1,201 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/domain/service"
+)
+
+func TestScheduleService(t *testing.T) {
+	scheduledRepo := &mockScheduledNotificationRepository{
+		store: make(map[string]*model.ScheduledNotification),
+	}
+	digestRepo := &mockDigestRepository{
+		store: make(map[uuid.UUID]*model.Digest),
+	}
+	scheduleService := service.NewScheduleService(scheduledRepo, digestRepo)
+	userID := uuid.New()
+	sendAt := time.Now().Add(24 * time.Hour)
+
+	t.Run("Schedule notification", func(t *testing.T) {
+		_, err := scheduleService.ScheduleNotification(userID, "reminder:1", model.OrderPaymentReminderEvent, []byte(`{"order_id":"1"}`), sendAt)
+		require.NoError(t, err)
+
+		// scheduling with the same key replaces notification
+		later := sendAt.Add(time.Hour)
+		notification, err := scheduleService.ScheduleNotification(userID, "reminder:1", model.OrderPaymentReminderEvent, []byte(`{"order_id":"1"}`), later)
+		require.NoError(t, err)
+		require.Len(t, scheduledRepo.store, 1)
+		require.Equal(t, notification.ID, scheduledRepo.store["reminder:1"].ID)
+		require.Equal(t, later, scheduledRepo.store["reminder:1"].SendAt)
+	})
+
+	t.Run("Invalid scheduled notification", func(t *testing.T) {
+		_, err := scheduleService.ScheduleNotification(userID, "reminder:2", "unknown_event", nil, sendAt)
+		require.ErrorIs(t, err, model.ErrUnknownTemplateEvent)
+
+		_, err = scheduleService.ScheduleNotification(userID, "reminder:2", model.OrderPaymentReminderEvent, nil, time.Time{})
+		require.ErrorIs(t, err, model.ErrInvalidSendAt)
+	})
+
+	t.Run("Cancel notification", func(t *testing.T) {
+		cancelled, err := scheduleService.CancelNotification("reminder:1")
+		require.NoError(t, err)
+		require.Equal(t, 1, cancelled)
+
+		cancelled, err = scheduleService.CancelNotification("reminder:1")
+		require.NoError(t, err)
+		require.Zero(t, cancelled)
+	})
+
+	t.Run("Add to digest", func(t *testing.T) {
+		first, second := uuid.New(), uuid.New()
+		digest, err := scheduleService.AddToDigest(userID, model.CategoryOrders, first, time.Hour)
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now().Add(time.Hour), digest.SendAt, time.Second)
+
+		// window of pending digest is kept
+		next, err := scheduleService.AddToDigest(userID, model.CategoryOrders, second, 24*time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, digest.ID, next.ID)
+		require.Equal(t, digest.SendAt, next.SendAt)
+		require.Equal(t, []uuid.UUID{first, second}, digestRepo.store[digest.ID].NotificationIDs)
+
+		other, err := scheduleService.AddToDigest(userID, model.CategoryPayments, first, time.Hour)
+		require.NoError(t, err)
+		require.NotEqual(t, digest.ID, other.ID)
+	})
+
+	t.Run("Claimed digest is not extended", func(t *testing.T) {
+		now := time.Now().Add(2 * time.Hour)
+		claimed, err := digestRepo.ClaimDue(now, now.Add(time.Minute), 10)
+		require.NoError(t, err)
+		require.Len(t, claimed, 2)
+
+		digest, err := scheduleService.AddToDigest(userID, model.CategoryOrders, uuid.New(), time.Hour)
+		require.NoError(t, err)
+		require.NotEqual(t, claimed[0].ID, digest.ID)
+		require.NotEqual(t, claimed[1].ID, digest.ID)
+		require.Len(t, digest.NotificationIDs, 1)
+	})
+}
+
+var _ model.ScheduledNotificationRepository = &mockScheduledNotificationRepository{}
+
+type mockScheduledNotificationRepository struct {
+	store map[string]*model.ScheduledNotification
+}
+
+func (m *mockScheduledNotificationRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (m *mockScheduledNotificationRepository) Store(notification *model.ScheduledNotification) error {
+	stored := *notification
+	m.store[notification.Key] = &stored
+	return nil
+}
+
+func (m *mockScheduledNotificationRepository) DeleteByKey(key string) (int, error) {
+	if _, ok := m.store[key]; !ok {
+		return 0, nil
+	}
+	delete(m.store, key)
+	return 1, nil
+}
+
+func (m *mockScheduledNotificationRepository) DeleteByUser(userID uuid.UUID) error {
+	for key, notification := range m.store {
+		if notification.UserID == userID {
+			delete(m.store, key)
+		}
+	}
+	return nil
+}
+
+func (m *mockScheduledNotificationRepository) Delete(id uuid.UUID) error {
+	for key, notification := range m.store {
+		if notification.ID == id {
+			delete(m.store, key)
+		}
+	}
+	return nil
+}
+
+func (m *mockScheduledNotificationRepository) ClaimDue(now, _ time.Time, limit int) ([]model.ScheduledNotification, error) {
+	var notifications []model.ScheduledNotification
+	for _, notification := range m.store {
+		if len(notifications) == limit {
+			break
+		}
+		if !notification.SendAt.After(now) {
+			notifications = append(notifications, *notification)
+		}
+	}
+	return notifications, nil
+}
+
+var _ model.DigestRepository = &mockDigestRepository{}
+
+type mockDigestRepository struct {
+	store   map[uuid.UUID]*model.Digest
+	claimed map[uuid.UUID]bool
+}
+
+func (m *mockDigestRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (m *mockDigestRepository) Store(digest *model.Digest) error {
+	stored := *digest
+	stored.NotificationIDs = append([]uuid.UUID(nil), digest.NotificationIDs...)
+	m.store[digest.ID] = &stored
+	return nil
+}
+
+func (m *mockDigestRepository) FindPending(userID uuid.UUID, category model.Category) (*model.Digest, error) {
+	for _, digest := range m.store {
+		if digest.UserID == userID && digest.Category == category && !m.claimed[digest.ID] {
+			found := *digest
+			found.NotificationIDs = append([]uuid.UUID(nil), digest.NotificationIDs...)
+			return &found, nil
+		}
+	}
+	return nil, model.ErrDigestNotFound
+}
+
+func (m *mockDigestRepository) DeleteByUser(userID uuid.UUID) error {
+	for id, digest := range m.store {
+		if digest.UserID == userID {
+			delete(m.store, id)
+		}
+	}
+	return nil
+}
+
+func (m *mockDigestRepository) Delete(id uuid.UUID) error {
+	delete(m.store, id)
+	return nil
+}
+
+func (m *mockDigestRepository) ClaimDue(now, _ time.Time, limit int) ([]model.Digest, error) {
+	if m.claimed == nil {
+		m.claimed = make(map[uuid.UUID]bool)
+	}
+	var digests []model.Digest
+	for _, digest := range m.store {
+		if len(digests) == limit {
+			break
+		}
+		if !m.claimed[digest.ID] && !digest.SendAt.After(now) {
+			m.claimed[digest.ID] = true
+			digests = append(digests, *digest)
+		}
+	}
+	return digests, nil
+}
@@ -0,0 +1,163 @@
+package tests
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/domain/service"
+)
+
+func TestTemplateService(t *testing.T) {
+	repo := &mockTemplateRepository{}
+	templateService := service.NewTemplateService(repo)
+
+	key := model.TemplateKey{EventType: "order_cancelled", Channel: model.ChannelDefault, Locale: model.DefaultLocale}
+	payload := []byte(`{"order_id":"42","reason":"out of stock"}`)
+
+	t.Run("Create template", func(t *testing.T) {
+		template, err := templateService.CreateTemplate(key, "Order was cancelled", "Order #{{.OrderID}} has been cancelled. Reason: {{.Reason}}")
+		require.NoError(t, err)
+		require.Equal(t, 1, template.Version)
+
+		_, err = templateService.CreateTemplate(key, "Order was cancelled", "Order #{{.OrderID}}")
+		require.ErrorIs(t, err, model.ErrTemplateAlreadyExists)
+	})
+
+	t.Run("Reject invalid templates", func(t *testing.T) {
+		_, err := templateService.UpdateTemplate(key, "Order was cancelled", "Order #{{.OrderNumber}}")
+		require.ErrorIs(t, err, model.ErrInvalidTemplate)
+
+		_, err = templateService.UpdateTemplate(key, "Order was cancelled", "Order #{{.OrderID")
+		require.ErrorIs(t, err, model.ErrInvalidTemplate)
+
+		_, err = templateService.CreateTemplate(model.TemplateKey{EventType: "order_cancelled", Channel: "sms", Locale: "en"}, "Subject", "Body")
+		require.ErrorIs(t, err, model.ErrInvalidTemplateKey)
+
+		_, err = templateService.CreateTemplate(model.TemplateKey{EventType: "order_refunded", Channel: model.ChannelDefault, Locale: "en"}, "Subject", "Body")
+		require.ErrorIs(t, err, model.ErrUnknownTemplateEvent)
+	})
+
+	t.Run("Update creates new version", func(t *testing.T) {
+		template, err := templateService.UpdateTemplate(key, "Order #{{.OrderID}} was cancelled", "Reason: {{.Reason}}")
+		require.NoError(t, err)
+		require.Equal(t, 2, template.Version)
+
+		message, err := templateService.Render(key, payload)
+		require.NoError(t, err)
+		require.Equal(t, model.Message{Subject: "Order #42 was cancelled", Body: "Reason: out of stock"}, message)
+
+		version := 1
+		message, err = templateService.Preview(key, &version, payload)
+		require.NoError(t, err)
+		require.Equal(t, "Order #42 has been cancelled. Reason: out of stock", message.Body)
+	})
+
+	t.Run("Render falls back to default locale", func(t *testing.T) {
+		ruKey := key
+		ruKey.Locale = "ru"
+		message, err := templateService.Render(ruKey, payload)
+		require.NoError(t, err)
+		require.Equal(t, "Reason: out of stock", message.Body)
+
+		_, err = templateService.CreateTemplate(ruKey, "Заказ отменён", "Заказ #{{.OrderID}} отменён")
+		require.NoError(t, err)
+		message, err = templateService.Render(ruKey, payload)
+		require.NoError(t, err)
+		require.Equal(t, "Заказ #42 отменён", message.Body)
+	})
+
+	t.Run("Preview draft", func(t *testing.T) {
+		message, err := templateService.PreviewDraft("contact_verification_requested", "Code", "Code {{.Code}} expires at {{formatTime .ExpiresAt}}",
+			[]byte(`{"code":"123456","expires_at":0}`))
+		require.NoError(t, err)
+		require.Equal(t, "Code 123456 expires at Thu, 01 Jan 1970 00:00:00 UTC", message.Body)
+
+		_, err = templateService.PreviewDraft("order_paid", "Paid", "Order #{{.OrderID}}", []byte(`{"order_id":`))
+		require.ErrorIs(t, err, model.ErrInvalidTemplatePayload)
+	})
+
+	t.Run("Delete template", func(t *testing.T) {
+		_, err := templateService.DeleteTemplate(key)
+		require.ErrorIs(t, err, model.ErrTemplateRequired)
+
+		ruKey := key
+		ruKey.Locale = "ru"
+		deleted, err := templateService.DeleteTemplate(ruKey)
+		require.NoError(t, err)
+		require.Equal(t, 1, deleted)
+
+		_, err = templateService.DeleteTemplate(ruKey)
+		require.ErrorIs(t, err, model.ErrTemplateNotFound)
+	})
+}
+
+var _ model.TemplateRepository = &mockTemplateRepository{}
+
+type mockTemplateRepository struct {
+	templates []model.Template
+}
+
+func (m *mockTemplateRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (m *mockTemplateRepository) Store(template *model.Template) error {
+	m.templates = append(m.templates, *template)
+	return nil
+}
+
+func (m *mockTemplateRepository) Find(key model.TemplateKey, version *int) (*model.Template, error) {
+	versions, _ := m.ListVersions(key)
+	for _, template := range versions {
+		if version == nil || template.Version == *version {
+			return &template, nil
+		}
+	}
+	return nil, model.ErrTemplateNotFound
+}
+
+func (m *mockTemplateRepository) ListVersions(key model.TemplateKey) ([]model.Template, error) {
+	var versions []model.Template
+	for _, template := range m.templates {
+		if template.Key == key {
+			versions = append(versions, template)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version > versions[j].Version
+	})
+	return versions, nil
+}
+
+func (m *mockTemplateRepository) ListLatest(eventType string) ([]model.Template, error) {
+	latest := make(map[model.TemplateKey]model.Template)
+	for _, template := range m.templates {
+		if eventType != "" && template.Key.EventType != eventType {
+			continue
+		}
+		if current, ok := latest[template.Key]; !ok || current.Version < template.Version {
+			latest[template.Key] = template
+		}
+	}
+	result := make([]model.Template, 0, len(latest))
+	for _, template := range latest {
+		result = append(result, template)
+	}
+	return result, nil
+}
+
+func (m *mockTemplateRepository) Delete(key model.TemplateKey) (int, error) {
+	kept := m.templates[:0]
+	for _, template := range m.templates {
+		if template.Key != key {
+			kept = append(kept, template)
+		}
+	}
+	deleted := len(m.templates) - len(kept)
+	m.templates = kept
+	return deleted, nil
+}
@@ -0,0 +1,143 @@
+package channel
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	appservice "notification/pkg/notification/app/service"
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/infrastructure/metrics"
+)
+
+type SMTPConfig struct {
+	Host string
+	Port int
+	// Username and Password are optional, authentication is skipped if server is open relay
+	Username string
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+func NewEmailChannel(config SMTPConfig) appservice.Channel {
+	return &emailChannel{config: config}
+}
+
+type emailChannel struct {
+	config SMTPConfig
+}
+
+func (c *emailChannel) Name() string {
+	return model.ChannelEmail
+}
+
+func (c *emailChannel) Supports(recipient model.Recipient) bool {
+	return recipient.Email != ""
+}
+
+func (c *emailChannel) Send(ctx context.Context, recipient model.Recipient, subject, body string) (err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DeliveryDuration.WithLabelValues(model.ChannelEmail, status).Observe(time.Since(start).Seconds())
+	}()
+
+	to := mail.Address{Name: recipient.Name, Address: recipient.Email}
+	from, err := mail.ParseAddress(c.config.From)
+	if err != nil {
+		return errors.Wrap(err, "invalid sender address")
+	}
+
+	if c.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
+	}
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(c.config.Host, strconv.Itoa(c.config.Port)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		err = conn.SetDeadline(deadline)
+		if err != nil {
+			_ = conn.Close()
+			return errors.WithStack(err)
+		}
+	}
+
+	client, err := smtp.NewClient(conn, c.config.Host)
+	if err != nil {
+		_ = conn.Close()
+		return errors.WithStack(err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: c.config.Host, MinVersion: tls.VersionTLS12})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if c.config.Username != "" {
+		err = client.Auth(smtp.PlainAuth("", c.config.Username, c.config.Password, c.config.Host))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	if err = client.Mail(from.Address); err != nil {
+		return errors.WithStack(err)
+	}
+	if err = client.Rcpt(to.Address); err != nil {
+		// permanent failure for recipient means mailbox does not exist or does not accept mail
+		var smtpErr *textproto.Error
+		if errors.As(err, &smtpErr) && smtpErr.Code >= 500 {
+			return fmt.Errorf("%w: %w", appservice.ErrRecipientRejected, err)
+		}
+		return errors.WithStack(err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = w.Write(emailMessage(from, &to, subject, body))
+	if err != nil {
+		_ = w.Close()
+		return errors.WithStack(err)
+	}
+	if err = w.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(client.Quit())
+}
+
+func emailMessage(from, to *mail.Address, subject, body string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from.String())
+	fmt.Fprintf(&b, "To: %s\r\n", to.String())
+	// encoding also prevents header injection through line breaks in subject
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(body)
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,102 @@
+package channel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+
+	appservice "notification/pkg/notification/app/service"
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/infrastructure/metrics"
+)
+
+type TelegramConfig struct {
+	// APIURL is Bot API base url, it is replaced in tests
+	APIURL   string
+	BotToken string
+	Timeout  time.Duration
+}
+
+func NewTelegramChannel(config TelegramConfig) appservice.Channel {
+	return &telegramChannel{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+type telegramChannel struct {
+	config TelegramConfig
+	client *http.Client
+}
+
+func (c *telegramChannel) Name() string {
+	return model.ChannelTelegram
+}
+
+// Supports expects telegram contact to be chat id or @username the bot can write to
+func (c *telegramChannel) Supports(recipient model.Recipient) bool {
+	return recipient.Telegram != ""
+}
+
+func (c *telegramChannel) Send(ctx context.Context, recipient model.Recipient, subject, body string) (err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DeliveryDuration.WithLabelValues(model.ChannelTelegram, status).Observe(time.Since(start).Seconds())
+	}()
+
+	payload, err := json.Marshal(struct {
+		ChatID string `json:"chat_id"`
+		Text   string `json:"text"`
+	}{
+		ChatID: recipient.Telegram,
+		Text:   subject + "\n\n" + body,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.APIURL+"/bot"+c.config.BotToken+"/sendMessage", bytes.NewReader(payload))
+	if err != nil {
+		return errors.New("failed to build telegram request")
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		// url error contains bot token, only underlying error is returned
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return errors.Wrap(err, "failed to call telegram bot api")
+	}
+	defer response.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&result)
+	if err != nil {
+		return errors.Wrapf(err, "invalid telegram bot api response, status %d", response.StatusCode)
+	}
+	if !result.OK {
+		err = errors.Errorf("telegram bot api error, status %d: %s", response.StatusCode, result.Description)
+		// chat is not found or bot was blocked by the user, other errors are caused by bot setup or rate limits
+		if response.StatusCode == http.StatusBadRequest || response.StatusCode == http.StatusForbidden {
+			return fmt.Errorf("%w: %w", appservice.ErrRecipientRejected, err)
+		}
+		return err
+	}
+	return nil
+}
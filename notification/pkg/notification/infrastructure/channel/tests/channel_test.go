@@ -0,0 +1,252 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	appservice "notification/pkg/notification/app/service"
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/infrastructure/channel"
+)
+
+func TestEmailChannel(t *testing.T) {
+	server := newSMTPServer(t)
+	host, port, err := net.SplitHostPort(server.listener.Addr().String())
+	require.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	emailChannel := channel.NewEmailChannel(channel.SMTPConfig{
+		Host:    host,
+		Port:    portNumber,
+		From:    "Shop <noreply@shop.example>",
+		Timeout: 5 * time.Second,
+	})
+
+	recipient := model.Recipient{Name: "John", Email: "john@example.com"}
+	require.True(t, emailChannel.Supports(recipient))
+	require.False(t, emailChannel.Supports(model.Recipient{Name: "John", Telegram: "123456"}))
+
+	err = emailChannel.Send(context.Background(), recipient, "Order was created", "Order #1 has been created")
+	require.NoError(t, err)
+
+	message := <-server.messages
+	require.Equal(t, "<noreply@shop.example>", message.from)
+	require.Equal(t, []string{"<john@example.com>"}, message.recipients)
+	require.Contains(t, message.data, "To: \"John\" <john@example.com>\r\n")
+	require.Contains(t, message.data, "Subject: Order was created\r\n")
+	require.True(t, strings.HasSuffix(message.data, "\r\n\r\nOrder #1 has been created\r\n"))
+}
+
+func TestEmailChannel_EncodesSubject(t *testing.T) {
+	server := newSMTPServer(t)
+	host, port, err := net.SplitHostPort(server.listener.Addr().String())
+	require.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	emailChannel := channel.NewEmailChannel(channel.SMTPConfig{
+		Host: host,
+		Port: portNumber,
+		From: "noreply@shop.example",
+	})
+
+	err = emailChannel.Send(context.Background(), model.Recipient{Email: "john@example.com"}, "Hi\r\nBcc: evil@example.com", "Body")
+	require.NoError(t, err)
+
+	message := <-server.messages
+	require.NotContains(t, message.data, "\r\nBcc:")
+}
+
+func TestEmailChannel_RejectedRecipient(t *testing.T) {
+	server := newSMTPServer(t)
+	host, port, err := net.SplitHostPort(server.listener.Addr().String())
+	require.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	emailChannel := channel.NewEmailChannel(channel.SMTPConfig{
+		Host: host,
+		Port: portNumber,
+		From: "noreply@shop.example",
+	})
+
+	err = emailChannel.Send(context.Background(), model.Recipient{Email: "unknown@example.com"}, "Subject", "Body")
+	require.ErrorIs(t, err, appservice.ErrRecipientRejected)
+}
+
+func TestTelegramChannel(t *testing.T) {
+	type sendMessageRequest struct {
+		ChatID string `json:"chat_id"`
+		Text   string `json:"text"`
+	}
+	requests := make(chan sendMessageRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/bottest-token/sendMessage" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"ok":false,"error_code":404,"description":"Not Found"}`))
+			return
+		}
+		var request sendMessageRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if request.ChatID == "blocked" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`))
+			return
+		}
+		requests <- request
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	defer server.Close()
+
+	telegramChannel := channel.NewTelegramChannel(channel.TelegramConfig{
+		APIURL:   server.URL,
+		BotToken: "test-token",
+		Timeout:  5 * time.Second,
+	})
+
+	require.True(t, telegramChannel.Supports(model.Recipient{Telegram: "123456"}))
+	require.False(t, telegramChannel.Supports(model.Recipient{Email: "john@example.com"}))
+
+	t.Run("Send message", func(t *testing.T) {
+		err := telegramChannel.Send(context.Background(), model.Recipient{Name: "John", Telegram: "123456"}, "Order was created", "Order #1 has been created")
+		require.NoError(t, err)
+
+		request := <-requests
+		require.Equal(t, "123456", request.ChatID)
+		require.Equal(t, "Order was created\n\nOrder #1 has been created", request.Text)
+	})
+
+	t.Run("Bot API error", func(t *testing.T) {
+		err := telegramChannel.Send(context.Background(), model.Recipient{Telegram: "blocked"}, "Subject", "Body")
+		require.ErrorContains(t, err, "bot was blocked by the user")
+		require.ErrorIs(t, err, appservice.ErrRecipientRejected)
+	})
+
+	t.Run("Token is not leaked", func(t *testing.T) {
+		unreachableChannel := channel.NewTelegramChannel(channel.TelegramConfig{
+			APIURL:   "http://127.0.0.1:1",
+			BotToken: "secret-token",
+			Timeout:  time.Second,
+		})
+		err := unreachableChannel.Send(context.Background(), model.Recipient{Telegram: "123456"}, "Subject", "Body")
+		require.Error(t, err)
+		require.NotErrorIs(t, err, appservice.ErrRecipientRejected)
+		require.NotContains(t, err.Error(), "secret-token")
+	})
+}
+
+type smtpMessage struct {
+	from       string
+	recipients []string
+	data       string
+}
+
+// smtpServer is minimal SMTP stand-in accepting every message without authentication
+type smtpServer struct {
+	listener net.Listener
+	messages chan smtpMessage
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &smtpServer{
+		listener: listener,
+		messages: make(chan smtpMessage, 1),
+	}
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	go server.serve()
+	return server
+}
+
+func (s *smtpServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpServer) handle(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	reply := func(code int, message string) {
+		_ = tp.PrintfLine("%d %s", code, message)
+	}
+
+	reply(220, "localhost ESMTP")
+	var message smtpMessage
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch command {
+		case "EHLO", "HELO":
+			reply(250, "localhost")
+		case "MAIL":
+			message.from = strings.TrimPrefix(line[len("MAIL FROM:"):], " ")
+			if i := strings.Index(message.from, " "); i >= 0 {
+				message.from = message.from[:i]
+			}
+			reply(250, "OK")
+		case "RCPT":
+			if strings.Contains(line, "unknown@") {
+				reply(550, "No such user")
+				continue
+			}
+			message.recipients = append(message.recipients, strings.TrimPrefix(line[len("RCPT TO:"):], " "))
+			reply(250, "OK")
+		case "DATA":
+			reply(354, "End data with <CR><LF>.<CR><LF>")
+			data, err := readData(tp.R)
+			if err != nil {
+				return
+			}
+			message.data = data
+			s.messages <- message
+			reply(250, "OK")
+		case "QUIT":
+			reply(221, "Bye")
+			return
+		default:
+			reply(502, fmt.Sprintf("command %s not implemented", command))
+		}
+	}
+}
+
+func readData(r *bufio.Reader) (string, error) {
+	var b strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		if line == ".\r\n" {
+			return b.String(), nil
+		}
+		b.WriteString(strings.TrimPrefix(line, "."))
+	}
+}
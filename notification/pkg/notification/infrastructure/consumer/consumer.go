@@ -3,39 +3,55 @@ package consumer
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"time"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/amqp"
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
 	appservice "notification/pkg/notification/app/service"
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/domain/service"
 	"notification/pkg/notification/infrastructure/metrics"
 )
 
 type EventConsumer struct {
 	conn                amqp.Connection
 	notificationService appservice.NotificationService
+	purchasingContacts  []model.Recipient
 	logger              logging.Logger
 	ctx                 context.Context
+
+	// paymentReminderDelay is time after order creation the owner is reminded of unpaid order, zero disables reminders
+	paymentReminderDelay time.Duration
 }
 
 func NewEventConsumer(
 	ctx context.Context,
 	conn amqp.Connection,
 	pool mysql.ConnectionPool,
+	purchasingContacts []model.Recipient,
+	channels []appservice.Channel,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+	retryPolicy service.RetryPolicy,
+	unsubscribeTokens appservice.UnsubscribeTokens,
+	paymentReminderDelay time.Duration,
 	logger logging.Logger,
 ) (*EventConsumer, error) {
 	uow := &unitOfWorkForSync{pool: pool}
+	deliveryService := appservice.NewDeliveryService(uow, channels, eventDispatcher, retryPolicy)
 
 	return &EventConsumer{
 		conn:                conn,
-		notificationService: appservice.NewNotificationService(uow),
+		notificationService: appservice.NewNotificationService(uow, channels, deliveryService, unsubscribeTokens),
+		purchasingContacts:  purchasingContacts,
 		logger:              logger,
 		ctx:                 ctx,
+
+		paymentReminderDelay: paymentReminderDelay,
 	}, nil
 }
 
@@ -56,78 +72,284 @@ func (c *EventConsumer) handle(ctx context.Context, delivery amqp.Delivery) (err
 	l := c.logger.WithField("event_type", delivery.Type)
 	l.Info("processing event")
 
-	var name, subject, body string
-
 	switch delivery.Type {
 	case "user_created":
 		var event struct {
-			UserID string `json:"user_id"`
-			Login  string `json:"login"`
+			UserID   string  `json:"user_id"`
+			Login    string  `json:"login"`
+			Email    *string `json:"email"`
+			Telegram *string `json:"telegram"`
 		}
 		if err = json.Unmarshal(delivery.Body, &event); err != nil {
 			err = errors.Wrap(err, "failed to unmarshal user_created")
 			return err
 		}
+		userID, parseErr := uuid.Parse(event.UserID)
+		if parseErr != nil {
+			err = errors.Wrap(parseErr, "invalid user_created user id")
+			return err
+		}
 
-		l.Info(fmt.Sprintf("Sending email to new user %s (%s)", event.Login, event.UserID))
-		return nil
+		recipient := model.Recipient{Name: event.Login}
+		if event.Email != nil {
+			recipient.Email = *event.Email
+		}
+		if event.Telegram != nil {
+			recipient.Telegram = *event.Telegram
+		}
+		err = c.notificationService.StoreUserRecipient(ctx, userID, recipient)
+		if err != nil {
+			l.Error(err, "failed to store user recipient")
+		}
+		return err
 
-	case "order_created":
+	case "user_updated":
 		var event struct {
-			OrderID string `json:"order_id"`
-			UserID  string `json:"user_id"`
+			UserID        string `json:"user_id"`
+			UpdatedFields *struct {
+				Email    *string `json:"email"`
+				Telegram *string `json:"telegram"`
+			} `json:"updated_fields"`
+			RemovedFields *struct {
+				Email    *bool `json:"email"`
+				Telegram *bool `json:"telegram"`
+			} `json:"removed_fields"`
 		}
 		if err = json.Unmarshal(delivery.Body, &event); err != nil {
-			err = errors.Wrap(err, "failed to unmarshal order_created")
+			err = errors.Wrap(err, "failed to unmarshal user_updated")
+			return err
+		}
+		userID, parseErr := uuid.Parse(event.UserID)
+		if parseErr != nil {
+			err = errors.Wrap(parseErr, "invalid user_updated user id")
+			return err
+		}
+
+		var email, telegram *string
+		if event.UpdatedFields != nil {
+			email, telegram = event.UpdatedFields.Email, event.UpdatedFields.Telegram
+		}
+		removed := ""
+		if event.RemovedFields != nil {
+			if event.RemovedFields.Email != nil && *event.RemovedFields.Email {
+				email = &removed
+			}
+			if event.RemovedFields.Telegram != nil && *event.RemovedFields.Telegram {
+				telegram = &removed
+			}
+		}
+		err = c.notificationService.UpdateUserRecipientContacts(ctx, userID, email, telegram)
+		if err != nil {
+			l.Error(err, "failed to update user recipient")
+		}
+		return err
+
+	case "user_preferences_changed":
+		var event struct {
+			UserID string `json:"user_id"`
+			Locale string `json:"locale"`
+		}
+		if err = json.Unmarshal(delivery.Body, &event); err != nil {
+			err = errors.Wrap(err, "failed to unmarshal user_preferences_changed")
+			return err
+		}
+		userID, parseErr := uuid.Parse(event.UserID)
+		if parseErr != nil {
+			err = errors.Wrap(parseErr, "invalid user_preferences_changed user id")
 			return err
 		}
 
-		orderID, _ := uuid.Parse(event.OrderID)
-		name = "order_created"
-		subject = "Order was created"
-		body = fmt.Sprintf("Order #%s has been created", orderID.String())
+		err = c.notificationService.UpdateUserRecipientLocale(ctx, userID, event.Locale)
+		if err != nil {
+			l.Error(err, "failed to update user recipient locale")
+		}
+		return err
 
-	case "order_paid":
+	case "user_deleted":
+		var event struct {
+			UserID string `json:"user_id"`
+			Hard   bool   `json:"hard"`
+		}
+		if err = json.Unmarshal(delivery.Body, &event); err != nil {
+			err = errors.Wrap(err, "failed to unmarshal user_deleted")
+			return err
+		}
+		userID, parseErr := uuid.Parse(event.UserID)
+		if parseErr != nil {
+			err = errors.Wrap(parseErr, "invalid user_deleted user id")
+			return err
+		}
+
+		// deleted users are not notified anymore, so their contacts are not kept
+		err = c.notificationService.DeleteUserRecipient(ctx, userID)
+		if err != nil {
+			l.Error(err, "failed to delete user recipient")
+			return err
+		}
+		if event.Hard {
+			_, err = c.notificationService.EraseUserNotifications(ctx, userID)
+			if err != nil {
+				l.Error(err, "failed to erase user notifications")
+			}
+		}
+		return err
+
+	case "contact_verification_requested":
+		var event struct {
+			Login       string `json:"login"`
+			ContactType string `json:"contact_type"`
+			Contact     string `json:"contact"`
+		}
+		if err = json.Unmarshal(delivery.Body, &event); err != nil {
+			err = errors.Wrap(err, "failed to unmarshal contact_verification_requested")
+			return err
+		}
+
+		recipient := model.Recipient{Name: event.Login}
+		if event.ContactType == "telegram" {
+			recipient.Telegram = event.Contact
+		} else {
+			recipient.Email = event.Contact
+		}
+		_, err = c.notificationService.NotifyRecipients(ctx, []model.Recipient{recipient}, delivery.Type, delivery.Body)
+		err = c.skipDeliveryError(l, err)
+		if err != nil {
+			l.Error(err, "failed to notify contact")
+		}
+		return err
+
+	case "user_locked":
+		var event struct {
+			UserID   string  `json:"user_id"`
+			Login    string  `json:"login"`
+			Email    *string `json:"email"`
+			Telegram *string `json:"telegram"`
+		}
+		if err = json.Unmarshal(delivery.Body, &event); err != nil {
+			err = errors.Wrap(err, "failed to unmarshal user_locked")
+			return err
+		}
+		userID, parseErr := uuid.Parse(event.UserID)
+		if parseErr != nil {
+			err = errors.Wrap(parseErr, "invalid user_locked user id")
+			return err
+		}
+
+		// alert is kept in inbox even if the user has no contacts to send it to
+		recipient := model.Recipient{Name: event.Login}
+		if event.Email != nil {
+			recipient.Email = *event.Email
+		}
+		if event.Telegram != nil {
+			recipient.Telegram = *event.Telegram
+		}
+		_, err = c.notificationService.NotifyUser(ctx, userID, &recipient, delivery.Type, delivery.Body)
+		err = c.skipDeliveryError(l, err)
+		if err != nil {
+			l.Error(err, "failed to alert locked user")
+		}
+		return err
+
+	case "order_created":
 		var event struct {
 			OrderID string `json:"order_id"`
+			UserID  string `json:"user_id"`
 		}
 		if err = json.Unmarshal(delivery.Body, &event); err != nil {
-			err = errors.Wrap(err, "failed to unmarshal order_paid")
+			err = errors.Wrap(err, "failed to unmarshal order_created")
 			return err
 		}
-		orderID, _ := uuid.Parse(event.OrderID)
-		name = "order_paid"
-		subject = "Order was paid"
-		body = fmt.Sprintf("Order #%s has been paid successfully.", orderID.String())
 
-	case "order_cancelled":
+		userID, parseErr := uuid.Parse(event.UserID)
+		if parseErr == nil {
+			// reminder replaces one scheduled by redelivered event, so it is scheduled before owner is notified
+			if c.paymentReminderDelay > 0 && event.OrderID != "" {
+				err = c.notificationService.ScheduleUserNotification(
+					ctx,
+					userID,
+					paymentReminderKey(event.OrderID),
+					model.OrderPaymentReminderEvent,
+					delivery.Body,
+					time.Now().Add(c.paymentReminderDelay),
+				)
+				if err != nil {
+					l.Error(err, "failed to schedule payment reminder")
+					return err
+				}
+			}
+
+			_, err = c.notificationService.NotifyUser(ctx, userID, nil, delivery.Type, delivery.Body)
+			err = c.skipDeliveryError(l, err)
+			if err != nil {
+				l.Error(err, "failed to notify order owner")
+			}
+			return err
+		}
+
+	case "order_paid", "order_cancelled":
 		var event struct {
 			OrderID string `json:"order_id"`
-			Reason  string `json:"reason"`
+			UserID  string `json:"user_id"`
 		}
 		if err = json.Unmarshal(delivery.Body, &event); err != nil {
-			err = errors.Wrap(err, "failed to unmarshal order_cancelled")
+			err = errors.Wrapf(err, "failed to unmarshal %s", delivery.Type)
+			return err
+		}
+		_, err = c.notificationService.CancelScheduledNotification(ctx, paymentReminderKey(event.OrderID))
+		if err != nil {
+			l.Error(err, "failed to cancel payment reminder")
 			return err
 		}
-		orderID, _ := uuid.Parse(event.OrderID)
-		name = "order_cancelled"
-		subject = "Order was cancelled"
-		body = fmt.Sprintf("Order #%s has been cancelled. Reason: %s", orderID.String(), event.Reason)
+
+		// events published before order owner was added to them are stored without recipient
+		userID, parseErr := uuid.Parse(event.UserID)
+		if parseErr == nil {
+			_, err = c.notificationService.NotifyUser(ctx, userID, nil, delivery.Type, delivery.Body)
+			err = c.skipDeliveryError(l, err)
+			if err != nil {
+				l.Error(err, "failed to notify order owner")
+			}
+			return err
+		}
+
+	case "stock_low", "out_of_stock":
+		err = c.notifyPurchasing(ctx, l, delivery.Type, delivery.Body)
+		return err
 
 	default:
 		l.WithField("type", delivery.Type).Info("unhandled event type")
 		return nil
 	}
 
+	_, err = c.notificationService.CreateNotification(ctx, delivery.Type, delivery.Body)
 	if err != nil {
-		l.Error(err, "failed to process event payload")
-		return err
+		l.Error(err, "failed to create notification")
 	}
+	return err
+}
 
-	_, createErr := c.notificationService.CreateNotification(ctx, name, subject, body)
-	if createErr != nil {
-		err = createErr
-		l.Error(err, "failed to create notification")
+func (c *EventConsumer) notifyPurchasing(ctx context.Context, l logging.Logger, eventType string, payload []byte) error {
+	if len(c.purchasingContacts) == 0 {
+		l.Info("no purchasing contacts configured, skipping")
+		return nil
+	}
+	_, err := c.notificationService.NotifyRecipients(ctx, c.purchasingContacts, eventType, payload)
+	err = c.skipDeliveryError(l, err)
+	if err != nil {
+		l.Error(err, "failed to notify purchasing contacts")
+	}
+	return err
+}
+
+func paymentReminderKey(orderID string) string {
+	return model.OrderPaymentReminderEvent + ":" + orderID
+}
+
+// skipDeliveryError only logs failed delivery, notifications are already stored and redelivery of event would duplicate them
+func (c *EventConsumer) skipDeliveryError(l logging.Logger, err error) error {
+	if errors.Is(err, appservice.ErrDeliveryFailed) {
+		l.Warning(err, "failed to deliver notification")
+		return nil
 	}
 	return err
 }
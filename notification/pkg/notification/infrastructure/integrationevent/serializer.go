@@ -0,0 +1,71 @@
+package integrationevent
+
+import (
+	"encoding/json"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/pkg/errors"
+
+	"notification/pkg/notification/domain/model"
+)
+
+func NewEventSerializer() outbox.EventSerializer[outbox.Event] {
+	return &eventSerializer{}
+}
+
+type eventSerializer struct{}
+
+func (s eventSerializer) Serialize(event outbox.Event) (string, error) {
+	switch e := event.(type) {
+	case *model.NotificationSent:
+		b, err := json.Marshal(NotificationSent{
+			NotificationID:    e.ID.String(),
+			Name:              e.Name,
+			Channel:           e.Channel,
+			RecipientName:     e.RecipientName,
+			RecipientEmail:    e.RecipientEmail,
+			RecipientTelegram: e.RecipientTelegram,
+			SentAt:            e.SentAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.NotificationFailed:
+		b, err := json.Marshal(NotificationFailed{
+			NotificationID:    e.ID.String(),
+			Name:              e.Name,
+			Channel:           e.Channel,
+			Status:            string(e.Status),
+			Error:             e.Error,
+			Attempts:          e.Attempts,
+			RecipientName:     e.RecipientName,
+			RecipientEmail:    e.RecipientEmail,
+			RecipientTelegram: e.RecipientTelegram,
+			FailedAt:          e.FailedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	default:
+		return "", errors.Errorf("unknown event %q", event.Type())
+	}
+}
+
+type NotificationSent struct {
+	NotificationID    string `json:"notification_id"`
+	Name              string `json:"name"`
+	Channel           string `json:"channel"`
+	RecipientName     string `json:"recipient_name,omitempty"`
+	RecipientEmail    string `json:"recipient_email,omitempty"`
+	RecipientTelegram string `json:"recipient_telegram,omitempty"`
+	SentAt            int64  `json:"sent_at"`
+}
+
+type NotificationFailed struct {
+	NotificationID    string `json:"notification_id"`
+	Name              string `json:"name"`
+	Channel           string `json:"channel"`
+	Status            string `json:"status"`
+	Error             string `json:"error"`
+	Attempts          int    `json:"attempts"`
+	RecipientName     string `json:"recipient_name,omitempty"`
+	RecipientEmail    string `json:"recipient_email,omitempty"`
+	RecipientTelegram string `json:"recipient_telegram,omitempty"`
+	FailedAt          int64  `json:"failed_at"`
+}
@@ -20,6 +20,13 @@ var (
 		Help:      "Duration of event processing",
 	}, []string{"event_type", "status"})
 
+	DeliveryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "notification",
+		Subsystem: "channel",
+		Name:      "delivery_duration_seconds",
+		Help:      "Duration of notification delivery through channel",
+	}, []string{"channel", "status"})
+
 	StatusSuccess = "success"
 	StatusError   = "error"
 )
@@ -44,4 +44,13 @@ func NewDatabaseMigrator(
 
 var builderFunctions = []MigrationBuilderFunc{
 	NewVersion1,
+	NewVersion2,
+	NewVersion3,
+	NewVersion4,
+	NewVersion5,
+	NewVersion6,
+	NewVersion7,
+	NewVersion8,
+	NewVersion9,
+	NewVersion10,
 }
@@ -0,0 +1,33 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion10(client mysql.ClientContext) migrator.Migration {
+	return &version10{
+		client: client,
+	}
+}
+
+type version10 struct {
+	client mysql.ClientContext
+}
+
+func (v version10) Version() int64 {
+	return 10
+}
+
+func (v version10) Description() string {
+	return "Add locale to 'user_recipient' table"
+}
+
+func (v version10) Up(ctx context.Context) error {
+	// empty locale is rendered with default templates until preferences of the user are synced
+	_, err := v.client.ExecContext(ctx, `ALTER TABLE user_recipient ADD COLUMN locale VARCHAR(20) NOT NULL DEFAULT '' AFTER telegram`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion2(client mysql.ClientContext) migrator.Migration {
+	return &version2{
+		client: client,
+	}
+}
+
+type version2 struct {
+	client mysql.ClientContext
+}
+
+func (v version2) Version() int64 {
+	return 2
+}
+
+func (v version2) Description() string {
+	return "Add recipient columns to 'notification' table"
+}
+
+func (v version2) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE notification
+			ADD COLUMN recipient_name  VARCHAR(255) NOT NULL DEFAULT '' AFTER body,
+			ADD COLUMN recipient_email VARCHAR(255) NOT NULL DEFAULT '' AFTER recipient_name
+	`)
+	return errors.WithStack(err)
+}
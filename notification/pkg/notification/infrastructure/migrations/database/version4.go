@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion4(client mysql.ClientContext) migrator.Migration {
+	return &version4{
+		client: client,
+	}
+}
+
+type version4 struct {
+	client mysql.ClientContext
+}
+
+func (v version4) Version() int64 {
+	return 4
+}
+
+func (v version4) Description() string {
+	return "Create 'user_recipient' table"
+}
+
+func (v version4) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE user_recipient
+		(
+			user_id    BINARY(16)   NOT NULL PRIMARY KEY,
+			name       VARCHAR(255) NOT NULL DEFAULT '',
+			email      VARCHAR(255) NOT NULL DEFAULT '',
+			telegram   VARCHAR(255) NOT NULL DEFAULT '',
+			created_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+		) ENGINE = InnoDB
+		  DEFAULT CHARSET = utf8mb4
+		  COLLATE = utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+func NewVersion5(client mysql.ClientContext) migrator.Migration {
+	return &version5{
+		client: client,
+	}
+}
+
+type version5 struct {
+	client mysql.ClientContext
+}
+
+func (v version5) Version() int64 {
+	return 5
+}
+
+func (v version5) Description() string {
+	return "Create 'notification_template' table with default templates"
+}
+
+// defaultTemplates replace messages previously built in code, they are the first versions of default channel templates
+var defaultTemplates = []struct {
+	eventType string
+	subject   string
+	body      string
+}{
+	{"order_created", "Order was created", "Order #{{.OrderID}} has been created"},
+	{"order_paid", "Order was paid", "Order #{{.OrderID}} has been paid successfully."},
+	{"order_cancelled", "Order was cancelled", "Order #{{.OrderID}} has been cancelled. Reason: {{.Reason}}"},
+	{"stock_low", "Low stock: {{.ProductName}}", `Product {{printf "%q" .ProductName}} (#{{.ProductID}}) has {{.Quantity}} items left, reorder threshold is {{.ReorderThreshold}}.`},
+	{"out_of_stock", "Out of stock: {{.ProductName}}", `Product {{printf "%q" .ProductName}} (#{{.ProductID}}) is out of stock.`},
+	{"contact_verification_requested", "Confirm your contact", "Your verification code is {{.Code}}. It expires at {{formatTime .ExpiresAt}}."},
+	{"user_locked", "Your account was locked", "Your account was locked after too many failed login attempts. " +
+		"It will be unlocked at {{formatTime .LockedUntil}}. If it was not you, change your password after unlock."},
+	{"user_welcome", "Welcome", "Welcome, {{.Name}}! Your account is ready."},
+}
+
+func (v version5) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE notification_template
+		(
+			template_id BINARY(16)   NOT NULL PRIMARY KEY,
+			event_type  VARCHAR(100) NOT NULL,
+			channel     VARCHAR(50)  NOT NULL,
+			locale      VARCHAR(20)  NOT NULL,
+			version     INT          NOT NULL,
+			subject     VARCHAR(500) NOT NULL,
+			body        TEXT         NOT NULL,
+			created_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE KEY notification_template_version (event_type, channel, locale, version)
+		) ENGINE = InnoDB
+		  DEFAULT CHARSET = utf8mb4
+		  COLLATE = utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, template := range defaultTemplates {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		_, err = v.client.ExecContext(ctx,
+			`INSERT INTO notification_template (template_id, event_type, channel, locale, version, subject, body) VALUES (?, ?, 'default', 'en', 1, ?, ?)`,
+			id[:], template.eventType, template.subject, template.body,
+		)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
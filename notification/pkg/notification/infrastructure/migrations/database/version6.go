@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion6(client mysql.ClientContext) migrator.Migration {
+	return &version6{
+		client: client,
+	}
+}
+
+type version6 struct {
+	client mysql.ClientContext
+}
+
+func (v version6) Version() int64 {
+	return 6
+}
+
+func (v version6) Description() string {
+	return "Add user inbox columns to 'notification' table"
+}
+
+func (v version6) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE notification
+			ADD COLUMN user_id     BINARY(16) NULL AFTER id,
+			ADD COLUMN read_at     DATETIME   NULL AFTER created_at,
+			ADD COLUMN archived_at DATETIME   NULL AFTER read_at,
+			ADD INDEX notification_user_id (user_id, id)
+	`)
+	return errors.WithStack(err)
+}
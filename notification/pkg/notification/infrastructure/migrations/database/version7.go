@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion7(client mysql.ClientContext) migrator.Migration {
+	return &version7{
+		client: client,
+	}
+}
+
+type version7 struct {
+	client mysql.ClientContext
+}
+
+func (v version7) Version() int64 {
+	return 7
+}
+
+func (v version7) Description() string {
+	return "Create 'notification_preferences' table"
+}
+
+func (v version7) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE notification_preferences
+		(
+			user_id     BINARY(16)  NOT NULL PRIMARY KEY,
+			disabled    JSON        NOT NULL,
+			quiet_start SMALLINT    NULL,
+			quiet_end   SMALLINT    NULL,
+			time_zone   VARCHAR(64) NULL,
+			created_at  DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at  DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+		) ENGINE = InnoDB
+		  DEFAULT CHARSET = utf8mb4
+		  COLLATE = utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion8(client mysql.ClientContext) migrator.Migration {
+	return &version8{
+		client: client,
+	}
+}
+
+type version8 struct {
+	client mysql.ClientContext
+}
+
+func (v version8) Version() int64 {
+	return 8
+}
+
+func (v version8) Description() string {
+	return "Create 'notification_delivery' and 'notification_delivery_attempt' tables"
+}
+
+func (v version8) Up(ctx context.Context) error {
+	// deliveries keep recipient contacts, so they are deleted with erased notifications
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE notification_delivery
+		(
+			delivery_id        BINARY(16)   NOT NULL PRIMARY KEY,
+			notification_id    BINARY(16)   NOT NULL,
+			channel            VARCHAR(50)  NOT NULL,
+			recipient_name     VARCHAR(255) NOT NULL DEFAULT '',
+			recipient_email    VARCHAR(255) NOT NULL DEFAULT '',
+			recipient_telegram VARCHAR(255) NOT NULL DEFAULT '',
+			subject            VARCHAR(500) NOT NULL,
+			body               TEXT         NOT NULL,
+			status             VARCHAR(20)  NOT NULL,
+			next_attempt_at    DATETIME     NULL,
+			created_at         DATETIME     NOT NULL,
+			updated_at         DATETIME     NOT NULL,
+			INDEX notification_delivery_notification_id (notification_id),
+			INDEX notification_delivery_due (status, next_attempt_at),
+			CONSTRAINT notification_delivery_notification_fk FOREIGN KEY (notification_id)
+				REFERENCES notification (id) ON DELETE CASCADE
+		) ENGINE = InnoDB
+		  DEFAULT CHARSET = utf8mb4
+		  COLLATE = utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE notification_delivery_attempt
+		(
+			delivery_id  BINARY(16)  NOT NULL,
+			number       INT         NOT NULL,
+			status       VARCHAR(20) NOT NULL,
+			error        TEXT        NOT NULL,
+			attempted_at DATETIME    NOT NULL,
+			PRIMARY KEY (delivery_id, number),
+			CONSTRAINT notification_delivery_attempt_delivery_fk FOREIGN KEY (delivery_id)
+				REFERENCES notification_delivery (delivery_id) ON DELETE CASCADE
+		) ENGINE = InnoDB
+		  DEFAULT CHARSET = utf8mb4
+		  COLLATE = utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+func NewVersion9(client mysql.ClientContext) migrator.Migration {
+	return &version9{
+		client: client,
+	}
+}
+
+type version9 struct {
+	client mysql.ClientContext
+}
+
+func (v version9) Version() int64 {
+	return 9
+}
+
+func (v version9) Description() string {
+	return "Create scheduled notification and digest tables"
+}
+
+var scheduleTemplates = []struct {
+	eventType string
+	subject   string
+	body      string
+}{
+	{"order_payment_reminder", "Order is waiting for payment", "Order #{{.OrderID}} has not been paid yet. Pay it to complete the purchase."},
+	{"notification_digest", "Your {{.Category}} updates", "{{range .Notifications}}{{formatTime .CreatedAt}}: {{.Subject}}\n{{end}}"},
+}
+
+func (v version9) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `ALTER TABLE notification_preferences ADD COLUMN digests JSON NULL AFTER time_zone`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE scheduled_notification
+		(
+			id            BINARY(16)   NOT NULL PRIMARY KEY,
+			user_id       BINARY(16)   NOT NULL,
+			schedule_key  VARCHAR(255) NOT NULL,
+			event_type    VARCHAR(100) NOT NULL,
+			payload       JSON         NOT NULL,
+			send_at       DATETIME     NOT NULL,
+			claimed_until DATETIME     NULL,
+			created_at    DATETIME     NOT NULL,
+			UNIQUE KEY scheduled_notification_key (schedule_key),
+			INDEX scheduled_notification_send_at (send_at)
+		) ENGINE = InnoDB
+		  DEFAULT CHARSET = utf8mb4
+		  COLLATE = utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE notification_digest
+		(
+			id            BINARY(16)  NOT NULL PRIMARY KEY,
+			user_id       BINARY(16)  NOT NULL,
+			category      VARCHAR(50) NOT NULL,
+			send_at       DATETIME    NOT NULL,
+			claimed_until DATETIME    NULL,
+			created_at    DATETIME    NOT NULL,
+			INDEX notification_digest_user_id (user_id, category),
+			INDEX notification_digest_send_at (send_at)
+		) ENGINE = InnoDB
+		  DEFAULT CHARSET = utf8mb4
+		  COLLATE = utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	// grouped notifications are dropped from digest when they are erased
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE notification_digest_item
+		(
+			digest_id       BINARY(16) NOT NULL,
+			notification_id BINARY(16) NOT NULL,
+			PRIMARY KEY (digest_id, notification_id),
+			CONSTRAINT notification_digest_item_digest_fk FOREIGN KEY (digest_id)
+				REFERENCES notification_digest (id) ON DELETE CASCADE,
+			CONSTRAINT notification_digest_item_notification_fk FOREIGN KEY (notification_id)
+				REFERENCES notification (id) ON DELETE CASCADE
+		) ENGINE = InnoDB
+		  DEFAULT CHARSET = utf8mb4
+		  COLLATE = utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, template := range scheduleTemplates {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		_, err = v.client.ExecContext(ctx,
+			`INSERT INTO notification_template (template_id, event_type, channel, locale, version, subject, body) VALUES (?, ?, 'default', 'en', 1, ?, ?)`,
+			id[:], template.eventType, template.subject, template.body,
+		)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
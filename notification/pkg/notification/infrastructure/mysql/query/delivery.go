@@ -0,0 +1,92 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appmodel "notification/pkg/notification/app/model"
+	"notification/pkg/notification/infrastructure/metrics"
+)
+
+type sqlxDelivery struct {
+	DeliveryID    uuid.UUID           `db:"delivery_id"`
+	Channel       string              `db:"channel"`
+	Status        string              `db:"status"`
+	NextAttemptAt sql.Null[time.Time] `db:"next_attempt_at"`
+	CreatedAt     time.Time           `db:"created_at"`
+	UpdatedAt     time.Time           `db:"updated_at"`
+}
+
+type sqlxDeliveryAttempt struct {
+	DeliveryID  uuid.UUID `db:"delivery_id"`
+	Number      int       `db:"number"`
+	Status      string    `db:"status"`
+	Error       string    `db:"error"`
+	AttemptedAt time.Time `db:"attempted_at"`
+}
+
+func (s *notificationQueryService) ListDeliveries(ctx context.Context, notificationID uuid.UUID) (_ []appmodel.Delivery, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("list_query", "notification_delivery", status).Observe(time.Since(start).Seconds())
+	}()
+
+	var deliveries []sqlxDelivery
+	err = s.client.SelectContext(ctx, &deliveries, `
+		SELECT delivery_id, channel, status, next_attempt_at, created_at, updated_at FROM notification_delivery
+		WHERE notification_id = ?
+		ORDER BY delivery_id`,
+		notificationID[:],
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var attempts []sqlxDeliveryAttempt
+	err = s.client.SelectContext(ctx, &attempts, `
+		SELECT a.delivery_id, a.number, a.status, a.error, a.attempted_at FROM notification_delivery_attempt a
+		INNER JOIN notification_delivery d ON d.delivery_id = a.delivery_id
+		WHERE d.notification_id = ?
+		ORDER BY a.number`,
+		notificationID[:],
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	attemptsByDelivery := make(map[uuid.UUID][]appmodel.DeliveryAttempt)
+	for _, attempt := range attempts {
+		attemptsByDelivery[attempt.DeliveryID] = append(attemptsByDelivery[attempt.DeliveryID], appmodel.DeliveryAttempt{
+			Number:      attempt.Number,
+			Status:      attempt.Status,
+			Error:       attempt.Error,
+			AttemptedAt: attempt.AttemptedAt.Unix(),
+		})
+	}
+
+	result := make([]appmodel.Delivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		var nextAttemptAt *int64
+		if delivery.NextAttemptAt.Valid {
+			unix := delivery.NextAttemptAt.V.Unix()
+			nextAttemptAt = &unix
+		}
+		result = append(result, appmodel.Delivery{
+			ID:            delivery.DeliveryID,
+			Channel:       delivery.Channel,
+			Status:        delivery.Status,
+			Attempts:      attemptsByDelivery[delivery.DeliveryID],
+			NextAttemptAt: nextAttemptAt,
+			CreatedAt:     delivery.CreatedAt.Unix(),
+			UpdatedAt:     delivery.UpdatedAt.Unix(),
+		})
+	}
+	return result, nil
+}
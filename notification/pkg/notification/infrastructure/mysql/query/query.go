@@ -3,6 +3,8 @@ package query
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"strings"
 	"time"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
@@ -11,6 +13,7 @@ import (
 
 	appmodel "notification/pkg/notification/app/model"
 	"notification/pkg/notification/app/query"
+	"notification/pkg/notification/domain/model"
 	"notification/pkg/notification/infrastructure/metrics"
 )
 
@@ -24,36 +27,208 @@ type notificationQueryService struct {
 	client mysql.ClientContext
 }
 
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+
+	notificationColumns = `id, user_id, name, subject, body, created_at, read_at, archived_at`
+)
+
+type sqlxNotification struct {
+	ID         uuid.UUID           `db:"id"`
+	UserID     uuid.NullUUID       `db:"user_id"`
+	Name       string              `db:"name"`
+	Subject    string              `db:"subject"`
+	Body       sql.Null[string]    `db:"body"`
+	CreatedAt  time.Time           `db:"created_at"`
+	ReadAt     sql.Null[time.Time] `db:"read_at"`
+	ArchivedAt sql.Null[time.Time] `db:"archived_at"`
+}
+
 func (s *notificationQueryService) Find(ctx context.Context, id uuid.UUID) (_ *appmodel.Notification, err error) {
 	start := time.Now()
 	defer func() {
 		status := metrics.StatusSuccess
-		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		if err != nil && !errors.Is(err, model.ErrNotificationNotFound) {
 			status = metrics.StatusError
 		}
 		metrics.DatabaseDuration.WithLabelValues("find_query", "notification", status).Observe(time.Since(start).Seconds())
 	}()
 
-	var data struct {
-		ID        uuid.UUID `db:"notification_id"`
-		Name      string    `db:"user_id"`
-		Subject   string    `db:"order_id"`
-		Body      string    `db:"message"`
-		CreatedAt time.Time `db:"created_at"`
+	var row sqlxNotification
+	err = s.client.GetContext(ctx, &row, `SELECT `+notificationColumns+` FROM notification WHERE id = ?`, id[:])
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrNotificationNotFound)
+		}
+		return nil, errors.WithStack(err)
 	}
 
-	err = s.client.SelectContext(ctx, &data, "SELECT * FROM notification WHERE id = ? ORDER BY created_at DESC", id)
+	notification := toAppNotification(row)
+	return &notification, nil
+}
+
+func (s *notificationQueryService) ListForRecipient(ctx context.Context, recipient model.Recipient) (_ []appmodel.Notification, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("list_query", "notification", status).Observe(time.Since(start).Seconds())
+	}()
+
+	conditions, args := recipientConditions(recipient)
+	if conditions == "" {
+		return nil, nil
+	}
+
+	var rows []sqlxNotification
+	err = s.client.SelectContext(ctx, &rows,
+		`SELECT `+notificationColumns+` FROM notification WHERE `+conditions+` ORDER BY created_at`,
+		args...,
+	)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
+	notifications := make([]appmodel.Notification, 0, len(rows))
+	for _, row := range rows {
+		notifications = append(notifications, toAppNotification(row))
+	}
+	return notifications, nil
+}
+
+func (s *notificationQueryService) ListForUser(ctx context.Context, spec appmodel.ListNotificationsSpec) (_ appmodel.NotificationList, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil && !errors.Is(err, query.ErrInvalidCursor) {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("list_for_user_query", "notification", status).Observe(time.Since(start).Seconds())
+	}()
+
+	limit := spec.Limit
+	if limit <= 0 || limit > maxListLimit {
+		limit = defaultListLimit
+	}
+
+	conditions := []string{"user_id = ?"}
+	args := []interface{}{spec.UserID[:]}
+	if spec.UnreadOnly {
+		conditions = append(conditions, "read_at IS NULL")
+	}
+	if !spec.IncludeArchived {
+		conditions = append(conditions, "archived_at IS NULL")
+	}
+	if spec.Cursor != "" {
+		beforeID, err2 := decodeCursor(spec.Cursor)
+		if err2 != nil {
+			return appmodel.NotificationList{}, err2
+		}
+		// notification IDs are UUIDv7, so reverse order of IDs is newest first
+		conditions = append(conditions, "id < ?")
+		args = append(args, beforeID[:])
+	}
+	// fetch one extra row to know whether next page exists
+	args = append(args, limit+1)
+
+	var rows []sqlxNotification
+	err = s.client.SelectContext(ctx, &rows,
+		`SELECT `+notificationColumns+` FROM notification WHERE `+strings.Join(conditions, " AND ")+` ORDER BY id DESC LIMIT ?`,
+		args...,
+	)
+	if err != nil {
+		return appmodel.NotificationList{}, errors.WithStack(err)
+	}
+
+	var result appmodel.NotificationList
+	if len(rows) > limit {
+		rows = rows[:limit]
+		result.NextCursor = encodeCursor(rows[limit-1].ID)
+	}
+	result.Notifications = make([]appmodel.Notification, 0, len(rows))
+	for _, row := range rows {
+		result.Notifications = append(result.Notifications, toAppNotification(row))
+	}
+	return result, nil
+}
+
+func (s *notificationQueryService) CountUnread(ctx context.Context, userID uuid.UUID) (_ int, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("count_unread_query", "notification", status).Observe(time.Since(start).Seconds())
+	}()
+
+	var count int
+	err = s.client.GetContext(ctx, &count,
+		`SELECT COUNT(*) FROM notification WHERE user_id = ? AND read_at IS NULL AND archived_at IS NULL`,
+		userID[:],
+	)
+	return count, errors.WithStack(err)
+}
+
+func toAppNotification(row sqlxNotification) appmodel.Notification {
 	notification := appmodel.Notification{
-		ID:        data.ID,
-		Name:      data.Name,
-		Subject:   data.Subject,
-		Body:      data.Body,
-		CreatedAt: data.CreatedAt.Unix(),
+		ID:        row.ID,
+		Name:      row.Name,
+		Subject:   row.Subject,
+		Body:      row.Body.V,
+		CreatedAt: row.CreatedAt.Unix(),
 	}
+	if row.UserID.Valid {
+		notification.UserID = &row.UserID.UUID
+	}
+	if row.ReadAt.Valid {
+		readAt := row.ReadAt.V.Unix()
+		notification.ReadAt = &readAt
+	}
+	if row.ArchivedAt.Valid {
+		archivedAt := row.ArchivedAt.V.Unix()
+		notification.ArchivedAt = &archivedAt
+	}
+	return notification
+}
 
-	return &notification, nil
+func encodeCursor(id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString(id[:])
+}
+
+func decodeCursor(cursor string) (uuid.UUID, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return uuid.Nil, errors.WithStack(query.ErrInvalidCursor)
+	}
+	id, err := uuid.FromBytes(b)
+	if err != nil {
+		return uuid.Nil, errors.WithStack(query.ErrInvalidCursor)
+	}
+	return id, nil
+}
+
+// recipientConditions matches notifications sent to any of non-empty recipient contacts
+func recipientConditions(recipient model.Recipient) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if recipient.Name != "" {
+		conditions = append(conditions, "recipient_name = ?")
+		args = append(args, recipient.Name)
+	}
+	if recipient.Email != "" {
+		conditions = append(conditions, "recipient_email = ?")
+		args = append(args, recipient.Email)
+	}
+	if recipient.Telegram != "" {
+		conditions = append(conditions, "recipient_telegram = ?")
+		args = append(args, recipient.Telegram)
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
 }
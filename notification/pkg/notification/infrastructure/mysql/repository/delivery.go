@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/infrastructure/metrics"
+)
+
+func NewDeliveryRepository(ctx context.Context, client mysql.ClientContext) model.DeliveryRepository {
+	return &deliveryRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type deliveryRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+type sqlxDelivery struct {
+	DeliveryID        uuid.UUID           `db:"delivery_id"`
+	NotificationID    uuid.UUID           `db:"notification_id"`
+	Channel           string              `db:"channel"`
+	RecipientName     string              `db:"recipient_name"`
+	RecipientEmail    string              `db:"recipient_email"`
+	RecipientTelegram string              `db:"recipient_telegram"`
+	Subject           string              `db:"subject"`
+	Body              string              `db:"body"`
+	Status            string              `db:"status"`
+	NextAttemptAt     sql.Null[time.Time] `db:"next_attempt_at"`
+	CreatedAt         time.Time           `db:"created_at"`
+	UpdatedAt         time.Time           `db:"updated_at"`
+}
+
+type sqlxDeliveryAttempt struct {
+	DeliveryID  uuid.UUID `db:"delivery_id"`
+	Number      int       `db:"number"`
+	Status      string    `db:"status"`
+	Error       string    `db:"error"`
+	AttemptedAt time.Time `db:"attempted_at"`
+}
+
+const deliveryColumns = `delivery_id, notification_id, channel, recipient_name, recipient_email, recipient_telegram,
+	subject, body, status, next_attempt_at, created_at, updated_at`
+
+func (r deliveryRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (r deliveryRepository) Store(delivery *model.Delivery) (err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("store", "notification_delivery", status).Observe(time.Since(start).Seconds())
+	}()
+
+	_, err = r.client.ExecContext(r.ctx, `
+		INSERT INTO notification_delivery (`+deliveryColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			status = VALUES(status),
+			next_attempt_at = VALUES(next_attempt_at),
+			updated_at = VALUES(updated_at)`,
+		delivery.ID[:], delivery.NotificationID[:], delivery.Channel,
+		delivery.Recipient.Name, delivery.Recipient.Email, delivery.Recipient.Telegram,
+		delivery.Message.Subject, delivery.Message.Body, string(delivery.Status),
+		toSQLNull(delivery.NextAttemptAt), delivery.CreatedAt, delivery.UpdatedAt,
+	)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(delivery.Attempts) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(delivery.Attempts))
+	args := make([]interface{}, 0, len(delivery.Attempts)*5)
+	for _, attempt := range delivery.Attempts {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?)")
+		args = append(args, delivery.ID[:], attempt.Number, string(attempt.Status), attempt.Error, attempt.AttemptedAt)
+	}
+	_, err = r.client.ExecContext(r.ctx, `
+		INSERT IGNORE INTO notification_delivery_attempt (delivery_id, number, status, error, attempted_at)
+		VALUES `+strings.Join(placeholders, ", "),
+		args...,
+	)
+	return errors.WithStack(err)
+}
+
+func (r deliveryRepository) Find(id uuid.UUID) (_ *model.Delivery, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil && !errors.Is(err, model.ErrDeliveryNotFound) {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("find", "notification_delivery", status).Observe(time.Since(start).Seconds())
+	}()
+
+	var delivery sqlxDelivery
+	err = r.client.GetContext(r.ctx, &delivery, `SELECT `+deliveryColumns+` FROM notification_delivery WHERE delivery_id = ?`, id[:])
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrDeliveryNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	deliveries, err := r.withAttempts([]sqlxDelivery{delivery})
+	if err != nil {
+		return nil, err
+	}
+	return &deliveries[0], nil
+}
+
+func (r deliveryRepository) ListByNotification(notificationID uuid.UUID) (_ []model.Delivery, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("list", "notification_delivery", status).Observe(time.Since(start).Seconds())
+	}()
+
+	var deliveries []sqlxDelivery
+	err = r.client.SelectContext(r.ctx, &deliveries,
+		`SELECT `+deliveryColumns+` FROM notification_delivery WHERE notification_id = ? ORDER BY delivery_id`,
+		notificationID[:],
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return r.withAttempts(deliveries)
+}
+
+func (r deliveryRepository) ClaimDue(now, claimedUntil time.Time, limit int) (_ []model.Delivery, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("claim", "notification_delivery", status).Observe(time.Since(start).Seconds())
+	}()
+
+	// rows locked by other workers are skipped instead of waiting for their transactions
+	var deliveries []sqlxDelivery
+	err = r.client.SelectContext(r.ctx, &deliveries, `
+		SELECT `+deliveryColumns+` FROM notification_delivery
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED`,
+		string(model.DeliveryPending), now, limit,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(deliveries) == 0 {
+		return nil, nil
+	}
+
+	condition, args := deliveryIDsCondition(deliveries)
+	_, err = r.client.ExecContext(r.ctx,
+		`UPDATE notification_delivery SET next_attempt_at = ? WHERE `+condition,
+		append([]interface{}{claimedUntil}, args...)...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for i := range deliveries {
+		deliveries[i].NextAttemptAt = sql.Null[time.Time]{V: claimedUntil, Valid: true}
+	}
+	return r.withAttempts(deliveries)
+}
+
+func (r deliveryRepository) withAttempts(deliveries []sqlxDelivery) ([]model.Delivery, error) {
+	if len(deliveries) == 0 {
+		return nil, nil
+	}
+
+	condition, args := deliveryIDsCondition(deliveries)
+	var attempts []sqlxDeliveryAttempt
+	err := r.client.SelectContext(r.ctx, &attempts, `
+		SELECT delivery_id, number, status, error, attempted_at FROM notification_delivery_attempt
+		WHERE `+condition+`
+		ORDER BY number`,
+		args...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	attemptsByDelivery := make(map[uuid.UUID][]model.DeliveryAttempt)
+	for _, attempt := range attempts {
+		attemptsByDelivery[attempt.DeliveryID] = append(attemptsByDelivery[attempt.DeliveryID], model.DeliveryAttempt{
+			Number:      attempt.Number,
+			Status:      model.DeliveryStatus(attempt.Status),
+			Error:       attempt.Error,
+			AttemptedAt: attempt.AttemptedAt,
+		})
+	}
+
+	result := make([]model.Delivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		result = append(result, model.Delivery{
+			ID:             delivery.DeliveryID,
+			NotificationID: delivery.NotificationID,
+			Channel:        delivery.Channel,
+			Recipient: model.Recipient{
+				Name:     delivery.RecipientName,
+				Email:    delivery.RecipientEmail,
+				Telegram: delivery.RecipientTelegram,
+			},
+			Message: model.Message{
+				Subject: delivery.Subject,
+				Body:    delivery.Body,
+			},
+			Status:        model.DeliveryStatus(delivery.Status),
+			Attempts:      attemptsByDelivery[delivery.DeliveryID],
+			NextAttemptAt: fromSQLNull(delivery.NextAttemptAt),
+			CreatedAt:     delivery.CreatedAt,
+			UpdatedAt:     delivery.UpdatedAt,
+		})
+	}
+	return result, nil
+}
+
+func deliveryIDsCondition(deliveries []sqlxDelivery) (string, []interface{}) {
+	placeholders := make([]string, 0, len(deliveries))
+	args := make([]interface{}, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		placeholders = append(placeholders, "?")
+		args = append(args, delivery.DeliveryID[:])
+	}
+	return "delivery_id IN (" + strings.Join(placeholders, ", ") + ")", args
+}
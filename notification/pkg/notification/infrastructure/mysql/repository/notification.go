@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"time"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
@@ -39,9 +40,19 @@ func (n notificationRepository) Store(notification *model.Notification) (err err
 		metrics.DatabaseDuration.WithLabelValues("store", "notification", status).Observe(time.Since(start).Seconds())
 	}()
 
-	_, err = n.client.ExecContext(n.ctx,
-		`INSERT INTO notification (id, name, subject, body) VALUES (?, ?, ?)`,
-		notification.ID, notification.Name, notification.Subject, notification.Body,
+	var userID []byte
+	if notification.UserID != nil {
+		userID = notification.UserID[:]
+	}
+	_, err = n.client.ExecContext(n.ctx, `
+		INSERT INTO notification (id, user_id, name, subject, body, recipient_name, recipient_email, recipient_telegram, created_at, read_at, archived_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			read_at = VALUES(read_at),
+			archived_at = VALUES(archived_at)`,
+		notification.ID[:], userID, notification.Name, notification.Subject, notification.Body,
+		notification.Recipient.Name, notification.Recipient.Email, notification.Recipient.Telegram,
+		notification.CreatedAt, toSQLNull(notification.ReadAt), toSQLNull(notification.ArchivedAt),
 	)
 	return errors.WithStack(err)
 }
@@ -50,16 +61,186 @@ func (n notificationRepository) Find(id uuid.UUID) (_ *model.Notification, err e
 	start := time.Now()
 	defer func() {
 		status := metrics.StatusSuccess
-		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		if err != nil && !errors.Is(err, model.ErrNotificationNotFound) {
 			status = metrics.StatusError
 		}
 		metrics.DatabaseDuration.WithLabelValues("find", "notification", status).Observe(time.Since(start).Seconds())
 	}()
 
-	var notification *model.Notification
-	err = n.client.SelectContext(n.ctx, &notification, "SELECT * FROM notification WHERE id = ? ORDER BY created_at DESC", id)
+	var notification struct {
+		ID                uuid.UUID           `db:"id"`
+		UserID            uuid.NullUUID       `db:"user_id"`
+		Name              string              `db:"name"`
+		Subject           string              `db:"subject"`
+		Body              sql.Null[string]    `db:"body"`
+		RecipientName     string              `db:"recipient_name"`
+		RecipientEmail    string              `db:"recipient_email"`
+		RecipientTelegram string              `db:"recipient_telegram"`
+		CreatedAt         time.Time           `db:"created_at"`
+		ReadAt            sql.Null[time.Time] `db:"read_at"`
+		ArchivedAt        sql.Null[time.Time] `db:"archived_at"`
+	}
+	err = n.client.GetContext(n.ctx, &notification, `
+		SELECT id, user_id, name, subject, body, recipient_name, recipient_email, recipient_telegram, created_at, read_at, archived_at
+		FROM notification WHERE id = ?`,
+		id[:],
+	)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrNotificationNotFound)
+		}
 		return nil, errors.WithStack(err)
 	}
-	return notification, nil
+
+	var userID *uuid.UUID
+	if notification.UserID.Valid {
+		userID = &notification.UserID.UUID
+	}
+	return &model.Notification{
+		ID:      notification.ID,
+		UserID:  userID,
+		Name:    notification.Name,
+		Subject: notification.Subject,
+		Body:    notification.Body.V,
+		Recipient: model.Recipient{
+			Name:     notification.RecipientName,
+			Email:    notification.RecipientEmail,
+			Telegram: notification.RecipientTelegram,
+		},
+		CreatedAt:  notification.CreatedAt,
+		ReadAt:     fromSQLNull(notification.ReadAt),
+		ArchivedAt: fromSQLNull(notification.ArchivedAt),
+	}, nil
+}
+
+func (n notificationRepository) DeleteByRecipient(recipient model.Recipient) (_ int, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("delete", "notification", status).Observe(time.Since(start).Seconds())
+	}()
+
+	conditions, args := recipientConditions(recipient)
+	if conditions == "" {
+		return 0, nil
+	}
+	result, err := n.client.ExecContext(n.ctx, `DELETE FROM notification WHERE `+conditions, args...)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), errors.WithStack(err)
+}
+
+func (n notificationRepository) DeleteByUser(userID uuid.UUID) (_ int, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("delete_by_user", "notification", status).Observe(time.Since(start).Seconds())
+	}()
+
+	result, err := n.client.ExecContext(n.ctx, `DELETE FROM notification WHERE user_id = ?`, userID[:])
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), errors.WithStack(err)
+}
+
+func (n notificationRepository) MarkRead(userID uuid.UUID, ids []uuid.UUID, readAt time.Time) (_ int, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("mark_read", "notification", status).Observe(time.Since(start).Seconds())
+	}()
+
+	query := `UPDATE notification SET read_at = ? WHERE user_id = ? AND read_at IS NULL`
+	args := []interface{}{readAt, userID[:]}
+	if ids != nil {
+		condition, idArgs := idsCondition(ids)
+		query += ` AND ` + condition
+		args = append(args, idArgs...)
+	}
+	result, err := n.client.ExecContext(n.ctx, query, args...)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	updated, err := result.RowsAffected()
+	return int(updated), errors.WithStack(err)
+}
+
+func (n notificationRepository) Archive(userID uuid.UUID, ids []uuid.UUID, archivedAt time.Time) (_ int, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("archive", "notification", status).Observe(time.Since(start).Seconds())
+	}()
+
+	condition, idArgs := idsCondition(ids)
+	result, err := n.client.ExecContext(n.ctx,
+		`UPDATE notification SET archived_at = ? WHERE user_id = ? AND archived_at IS NULL AND `+condition,
+		append([]interface{}{archivedAt, userID[:]}, idArgs...)...,
+	)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	updated, err := result.RowsAffected()
+	return int(updated), errors.WithStack(err)
+}
+
+func idsCondition(ids []uuid.UUID) (string, []interface{}) {
+	placeholders := make([]string, 0, len(ids))
+	args := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		placeholders = append(placeholders, "?")
+		args = append(args, id[:])
+	}
+	return "id IN (" + strings.Join(placeholders, ", ") + ")", args
+}
+
+func recipientConditions(recipient model.Recipient) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if recipient.Name != "" {
+		conditions = append(conditions, "recipient_name = ?")
+		args = append(args, recipient.Name)
+	}
+	if recipient.Email != "" {
+		conditions = append(conditions, "recipient_email = ?")
+		args = append(args, recipient.Email)
+	}
+	if recipient.Telegram != "" {
+		conditions = append(conditions, "recipient_telegram = ?")
+		args = append(args, recipient.Telegram)
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+func toSQLNull[T any](v *T) sql.Null[T] {
+	if v == nil {
+		return sql.Null[T]{}
+	}
+	return sql.Null[T]{V: *v, Valid: true}
+}
+
+func fromSQLNull[T any](v sql.Null[T]) *T {
+	if !v.Valid {
+		return nil
+	}
+	return &v.V
 }
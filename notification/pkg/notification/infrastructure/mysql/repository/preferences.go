@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/infrastructure/metrics"
+)
+
+func NewPreferencesRepository(ctx context.Context, client mysql.ClientContext) model.PreferencesRepository {
+	return &preferencesRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type preferencesRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+type sqlxChannelPreference struct {
+	Category string `json:"category"`
+	Channel  string `json:"channel"`
+}
+
+func (r preferencesRepository) Store(preferences *model.Preferences) (err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("store", "notification_preferences", status).Observe(time.Since(start).Seconds())
+	}()
+
+	disabled := make([]sqlxChannelPreference, 0, len(preferences.Disabled))
+	for _, preference := range preferences.Disabled {
+		disabled = append(disabled, sqlxChannelPreference{
+			Category: string(preference.Category),
+			Channel:  preference.Channel,
+		})
+	}
+	disabledJSON, err := json.Marshal(disabled)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	// digest windows are stored in minutes by category
+	digests := make(map[string]int, len(preferences.Digests))
+	for category, window := range preferences.Digests {
+		digests[string(category)] = int(window / time.Minute)
+	}
+	digestsJSON, err := json.Marshal(digests)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var quietStart, quietEnd sql.Null[int]
+	var timeZone sql.Null[string]
+	if preferences.QuietHours != nil {
+		quietStart = sql.Null[int]{V: int(preferences.QuietHours.Start / time.Minute), Valid: true}
+		quietEnd = sql.Null[int]{V: int(preferences.QuietHours.End / time.Minute), Valid: true}
+		timeZone = sql.Null[string]{V: preferences.QuietHours.TimeZone, Valid: true}
+	}
+
+	_, err = r.client.ExecContext(r.ctx, `
+		INSERT INTO notification_preferences (user_id, disabled, quiet_start, quiet_end, time_zone, digests) VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			disabled = VALUES(disabled),
+			quiet_start = VALUES(quiet_start),
+			quiet_end = VALUES(quiet_end),
+			time_zone = VALUES(time_zone),
+			digests = VALUES(digests)`,
+		preferences.UserID[:], string(disabledJSON), quietStart, quietEnd, timeZone, string(digestsJSON),
+	)
+	return errors.WithStack(err)
+}
+
+func (r preferencesRepository) Find(userID uuid.UUID) (_ *model.Preferences, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("find", "notification_preferences", status).Observe(time.Since(start).Seconds())
+	}()
+
+	var preferences struct {
+		Disabled   []byte           `db:"disabled"`
+		QuietStart sql.Null[int]    `db:"quiet_start"`
+		QuietEnd   sql.Null[int]    `db:"quiet_end"`
+		TimeZone   sql.Null[string] `db:"time_zone"`
+		Digests    []byte           `db:"digests"`
+	}
+	err = r.client.GetContext(r.ctx, &preferences, `
+		SELECT disabled, quiet_start, quiet_end, time_zone, digests FROM notification_preferences WHERE user_id = ?`,
+		userID[:],
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &model.Preferences{UserID: userID}, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	var disabled []sqlxChannelPreference
+	err = json.Unmarshal(preferences.Disabled, &disabled)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := &model.Preferences{UserID: userID}
+	for _, preference := range disabled {
+		result.Disabled = append(result.Disabled, model.ChannelPreference{
+			Category: model.Category(preference.Category),
+			Channel:  preference.Channel,
+		})
+	}
+	if preferences.Digests != nil {
+		var digests map[string]int
+		err = json.Unmarshal(preferences.Digests, &digests)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		for category, window := range digests {
+			if result.Digests == nil {
+				result.Digests = make(map[model.Category]time.Duration, len(digests))
+			}
+			result.Digests[model.Category(category)] = time.Duration(window) * time.Minute
+		}
+	}
+	if preferences.QuietStart.Valid && preferences.QuietEnd.Valid && preferences.TimeZone.Valid {
+		result.QuietHours = &model.QuietHours{
+			Start:    time.Duration(preferences.QuietStart.V) * time.Minute,
+			End:      time.Duration(preferences.QuietEnd.V) * time.Minute,
+			TimeZone: preferences.TimeZone.V,
+		}
+	}
+	return result, nil
+}
+
+func (r preferencesRepository) Delete(userID uuid.UUID) (err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("delete", "notification_preferences", status).Observe(time.Since(start).Seconds())
+	}()
+
+	_, err = r.client.ExecContext(r.ctx, `DELETE FROM notification_preferences WHERE user_id = ?`, userID[:])
+	return errors.WithStack(err)
+}
@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/infrastructure/metrics"
+)
+
+func NewScheduledNotificationRepository(ctx context.Context, client mysql.ClientContext) model.ScheduledNotificationRepository {
+	return &scheduledNotificationRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type scheduledNotificationRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+type sqlxScheduledNotification struct {
+	ID        uuid.UUID `db:"id"`
+	UserID    uuid.UUID `db:"user_id"`
+	Key       string    `db:"schedule_key"`
+	EventType string    `db:"event_type"`
+	Payload   []byte    `db:"payload"`
+	SendAt    time.Time `db:"send_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (r scheduledNotificationRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (r scheduledNotificationRepository) Store(notification *model.ScheduledNotification) (err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("store", "scheduled_notification", status).Observe(time.Since(start).Seconds())
+	}()
+
+	_, err = r.client.ExecContext(r.ctx, `
+		INSERT INTO scheduled_notification (id, user_id, schedule_key, event_type, payload, send_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			id = VALUES(id),
+			user_id = VALUES(user_id),
+			event_type = VALUES(event_type),
+			payload = VALUES(payload),
+			send_at = VALUES(send_at),
+			claimed_until = NULL,
+			created_at = VALUES(created_at)`,
+		notification.ID[:], notification.UserID[:], notification.Key, notification.EventType,
+		string(notification.Payload), notification.SendAt, notification.CreatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (r scheduledNotificationRepository) DeleteByKey(key string) (_ int, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("delete", "scheduled_notification", status).Observe(time.Since(start).Seconds())
+	}()
+
+	result, err := r.client.ExecContext(r.ctx, `DELETE FROM scheduled_notification WHERE schedule_key = ?`, key)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), errors.WithStack(err)
+}
+
+func (r scheduledNotificationRepository) DeleteByUser(userID uuid.UUID) (err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("delete", "scheduled_notification", status).Observe(time.Since(start).Seconds())
+	}()
+
+	_, err = r.client.ExecContext(r.ctx, `DELETE FROM scheduled_notification WHERE user_id = ?`, userID[:])
+	return errors.WithStack(err)
+}
+
+func (r scheduledNotificationRepository) Delete(id uuid.UUID) (err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("delete", "scheduled_notification", status).Observe(time.Since(start).Seconds())
+	}()
+
+	_, err = r.client.ExecContext(r.ctx, `DELETE FROM scheduled_notification WHERE id = ?`, id[:])
+	return errors.WithStack(err)
+}
+
+func (r scheduledNotificationRepository) ClaimDue(now, claimedUntil time.Time, limit int) (_ []model.ScheduledNotification, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("claim", "scheduled_notification", status).Observe(time.Since(start).Seconds())
+	}()
+
+	// rows locked by other workers are skipped instead of waiting for their transactions
+	var rows []sqlxScheduledNotification
+	err = r.client.SelectContext(r.ctx, &rows, `
+		SELECT id, user_id, schedule_key, event_type, payload, send_at, created_at FROM scheduled_notification
+		WHERE send_at <= ? AND (claimed_until IS NULL OR claimed_until <= ?)
+		ORDER BY send_at
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED`,
+		now, now, limit,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(rows))
+	notifications := make([]model.ScheduledNotification, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+		notifications = append(notifications, model.ScheduledNotification{
+			ID:        row.ID,
+			UserID:    row.UserID,
+			Key:       row.Key,
+			EventType: row.EventType,
+			Payload:   row.Payload,
+			SendAt:    row.SendAt,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	condition, args := idsCondition(ids)
+	_, err = r.client.ExecContext(r.ctx,
+		`UPDATE scheduled_notification SET claimed_until = ? WHERE `+condition,
+		append([]interface{}{claimedUntil}, args...)...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return notifications, nil
+}
+
+func NewDigestRepository(ctx context.Context, client mysql.ClientContext) model.DigestRepository {
+	return &digestRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type digestRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+type sqlxDigest struct {
+	ID        uuid.UUID `db:"id"`
+	UserID    uuid.UUID `db:"user_id"`
+	Category  string    `db:"category"`
+	SendAt    time.Time `db:"send_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (r digestRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (r digestRepository) Store(digest *model.Digest) (err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("store", "notification_digest", status).Observe(time.Since(start).Seconds())
+	}()
+
+	_, err = r.client.ExecContext(r.ctx, `
+		INSERT INTO notification_digest (id, user_id, category, send_at, created_at) VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE send_at = VALUES(send_at)`,
+		digest.ID[:], digest.UserID[:], string(digest.Category), digest.SendAt, digest.CreatedAt,
+	)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, notificationID := range digest.NotificationIDs {
+		_, err = r.client.ExecContext(r.ctx,
+			`INSERT IGNORE INTO notification_digest_item (digest_id, notification_id) VALUES (?, ?)`,
+			digest.ID[:], notificationID[:],
+		)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func (r digestRepository) FindPending(userID uuid.UUID, category model.Category) (_ *model.Digest, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil && !errors.Is(err, model.ErrDigestNotFound) {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("find", "notification_digest", status).Observe(time.Since(start).Seconds())
+	}()
+
+	var rows []sqlxDigest
+	err = r.client.SelectContext(r.ctx, &rows, `
+		SELECT id, user_id, category, send_at, created_at FROM notification_digest
+		WHERE user_id = ? AND category = ? AND claimed_until IS NULL
+		ORDER BY id
+		LIMIT 1
+		FOR UPDATE`,
+		userID[:], string(category),
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(rows) == 0 {
+		return nil, errors.WithStack(model.ErrDigestNotFound)
+	}
+
+	digests, err := r.withItems(rows)
+	if err != nil {
+		return nil, err
+	}
+	return &digests[0], nil
+}
+
+func (r digestRepository) DeleteByUser(userID uuid.UUID) (err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("delete", "notification_digest", status).Observe(time.Since(start).Seconds())
+	}()
+
+	_, err = r.client.ExecContext(r.ctx, `DELETE FROM notification_digest WHERE user_id = ?`, userID[:])
+	return errors.WithStack(err)
+}
+
+func (r digestRepository) Delete(id uuid.UUID) (err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("delete", "notification_digest", status).Observe(time.Since(start).Seconds())
+	}()
+
+	_, err = r.client.ExecContext(r.ctx, `DELETE FROM notification_digest WHERE id = ?`, id[:])
+	return errors.WithStack(err)
+}
+
+func (r digestRepository) ClaimDue(now, claimedUntil time.Time, limit int) (_ []model.Digest, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("claim", "notification_digest", status).Observe(time.Since(start).Seconds())
+	}()
+
+	// rows locked by other workers are skipped instead of waiting for their transactions
+	var rows []sqlxDigest
+	err = r.client.SelectContext(r.ctx, &rows, `
+		SELECT id, user_id, category, send_at, created_at FROM notification_digest
+		WHERE send_at <= ? AND (claimed_until IS NULL OR claimed_until <= ?)
+		ORDER BY send_at
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED`,
+		now, now, limit,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	condition, args := idsCondition(ids)
+	_, err = r.client.ExecContext(r.ctx,
+		`UPDATE notification_digest SET claimed_until = ? WHERE `+condition,
+		append([]interface{}{claimedUntil}, args...)...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return r.withItems(rows)
+}
+
+func (r digestRepository) withItems(rows []sqlxDigest) ([]model.Digest, error) {
+	digests := make([]model.Digest, 0, len(rows))
+	for _, row := range rows {
+		var notificationIDs []uuid.UUID
+		err := r.client.SelectContext(r.ctx, &notificationIDs,
+			`SELECT notification_id FROM notification_digest_item WHERE digest_id = ? ORDER BY notification_id`,
+			row.ID[:],
+		)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		digests = append(digests, model.Digest{
+			ID:              row.ID,
+			UserID:          row.UserID,
+			Category:        model.Category(row.Category),
+			NotificationIDs: notificationIDs,
+			SendAt:          row.SendAt,
+			CreatedAt:       row.CreatedAt,
+		})
+	}
+	return digests, nil
+}
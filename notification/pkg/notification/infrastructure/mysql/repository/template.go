@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/infrastructure/metrics"
+)
+
+func NewTemplateRepository(ctx context.Context, client mysql.ClientContext) model.TemplateRepository {
+	return &templateRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type templateRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+type sqlxTemplate struct {
+	TemplateID uuid.UUID `db:"template_id"`
+	EventType  string    `db:"event_type"`
+	Channel    string    `db:"channel"`
+	Locale     string    `db:"locale"`
+	Version    int       `db:"version"`
+	Subject    string    `db:"subject"`
+	Body       string    `db:"body"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func (r templateRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (r templateRepository) Store(template *model.Template) (err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("store", "notification_template", status).Observe(time.Since(start).Seconds())
+	}()
+
+	_, err = r.client.ExecContext(r.ctx,
+		`INSERT INTO notification_template (template_id, event_type, channel, locale, version, subject, body, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		template.TemplateID[:], template.Key.EventType, template.Key.Channel, template.Key.Locale,
+		template.Version, template.Subject, template.Body, template.CreatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (r templateRepository) Find(key model.TemplateKey, version *int) (_ *model.Template, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil && !errors.Is(err, model.ErrTemplateNotFound) {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("find", "notification_template", status).Observe(time.Since(start).Seconds())
+	}()
+
+	query := `SELECT template_id, event_type, channel, locale, version, subject, body, created_at FROM notification_template
+		WHERE event_type = ? AND channel = ? AND locale = ?`
+	args := []interface{}{key.EventType, key.Channel, key.Locale}
+	if version != nil {
+		query += ` AND version = ?`
+		args = append(args, *version)
+	}
+	query += ` ORDER BY version DESC LIMIT 1`
+
+	var template sqlxTemplate
+	err = r.client.GetContext(r.ctx, &template, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrTemplateNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+	t := toTemplate(template)
+	return &t, nil
+}
+
+func (r templateRepository) ListVersions(key model.TemplateKey) (_ []model.Template, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("list", "notification_template", status).Observe(time.Since(start).Seconds())
+	}()
+
+	var templates []sqlxTemplate
+	err = r.client.SelectContext(r.ctx, &templates,
+		`SELECT template_id, event_type, channel, locale, version, subject, body, created_at FROM notification_template
+		WHERE event_type = ? AND channel = ? AND locale = ? ORDER BY version DESC`,
+		key.EventType, key.Channel, key.Locale,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return toTemplates(templates), nil
+}
+
+func (r templateRepository) ListLatest(eventType string) (_ []model.Template, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("list_latest", "notification_template", status).Observe(time.Since(start).Seconds())
+	}()
+
+	condition := ""
+	var args []interface{}
+	if eventType != "" {
+		condition = `WHERE event_type = ?`
+		args = append(args, eventType)
+	}
+
+	var templates []sqlxTemplate
+	err = r.client.SelectContext(r.ctx, &templates,
+		`SELECT t.template_id, t.event_type, t.channel, t.locale, t.version, t.subject, t.body, t.created_at
+		FROM notification_template t
+		INNER JOIN (
+			SELECT event_type, channel, locale, MAX(version) AS version FROM notification_template `+condition+`
+			GROUP BY event_type, channel, locale
+		) latest USING (event_type, channel, locale, version)
+		ORDER BY t.event_type, t.channel, t.locale`,
+		args...,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return toTemplates(templates), nil
+}
+
+func (r templateRepository) Delete(key model.TemplateKey) (_ int, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("delete", "notification_template", status).Observe(time.Since(start).Seconds())
+	}()
+
+	result, err := r.client.ExecContext(r.ctx,
+		`DELETE FROM notification_template WHERE event_type = ? AND channel = ? AND locale = ?`,
+		key.EventType, key.Channel, key.Locale,
+	)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), errors.WithStack(err)
+}
+
+func toTemplates(templates []sqlxTemplate) []model.Template {
+	result := make([]model.Template, 0, len(templates))
+	for _, template := range templates {
+		result = append(result, toTemplate(template))
+	}
+	return result
+}
+
+func toTemplate(template sqlxTemplate) model.Template {
+	return model.Template{
+		TemplateID: template.TemplateID,
+		Key: model.TemplateKey{
+			EventType: template.EventType,
+			Channel:   template.Channel,
+			Locale:    template.Locale,
+		},
+		Version:   template.Version,
+		Subject:   template.Subject,
+		Body:      template.Body,
+		CreatedAt: template.CreatedAt,
+	}
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/infrastructure/metrics"
+)
+
+func NewUserRecipientRepository(ctx context.Context, client mysql.ClientContext) model.UserRecipientRepository {
+	return &userRecipientRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type userRecipientRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (r userRecipientRepository) Store(recipient *model.UserRecipient) (err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("store", "user_recipient", status).Observe(time.Since(start).Seconds())
+	}()
+
+	_, err = r.client.ExecContext(r.ctx, `
+		INSERT INTO user_recipient (user_id, name, email, telegram, locale) VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			name = VALUES(name),
+			email = VALUES(email),
+			telegram = VALUES(telegram),
+			locale = VALUES(locale)`,
+		recipient.UserID[:], recipient.Recipient.Name, recipient.Recipient.Email, recipient.Recipient.Telegram, recipient.Locale,
+	)
+	return errors.WithStack(err)
+}
+
+func (r userRecipientRepository) Find(userID uuid.UUID) (_ *model.UserRecipient, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil && !errors.Is(err, model.ErrRecipientNotFound) {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("find", "user_recipient", status).Observe(time.Since(start).Seconds())
+	}()
+
+	var recipient struct {
+		UserID   uuid.UUID `db:"user_id"`
+		Name     string    `db:"name"`
+		Email    string    `db:"email"`
+		Telegram string    `db:"telegram"`
+		Locale   string    `db:"locale"`
+	}
+	err = r.client.GetContext(r.ctx, &recipient, `SELECT user_id, name, email, telegram, locale FROM user_recipient WHERE user_id = ?`, userID[:])
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrRecipientNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	return &model.UserRecipient{
+		UserID: recipient.UserID,
+		Recipient: model.Recipient{
+			Name:     recipient.Name,
+			Email:    recipient.Email,
+			Telegram: recipient.Telegram,
+		},
+		Locale: recipient.Locale,
+	}, nil
+}
+
+func (r userRecipientRepository) Delete(userID uuid.UUID) (err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("delete", "user_recipient", status).Observe(time.Since(start).Seconds())
+	}()
+
+	_, err = r.client.ExecContext(r.ctx, `DELETE FROM user_recipient WHERE user_id = ?`, userID[:])
+	return errors.WithStack(err)
+}
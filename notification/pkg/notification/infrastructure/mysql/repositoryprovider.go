@@ -21,3 +21,27 @@ type repositoryProvider struct {
 func (r *repositoryProvider) NotificationRepository(ctx context.Context) model.NotificationRepository {
 	return repository.NewNotificationRepository(ctx, r.client)
 }
+
+func (r *repositoryProvider) UserRecipientRepository(ctx context.Context) model.UserRecipientRepository {
+	return repository.NewUserRecipientRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) TemplateRepository(ctx context.Context) model.TemplateRepository {
+	return repository.NewTemplateRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) PreferencesRepository(ctx context.Context) model.PreferencesRepository {
+	return repository.NewPreferencesRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) DeliveryRepository(ctx context.Context) model.DeliveryRepository {
+	return repository.NewDeliveryRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) ScheduledNotificationRepository(ctx context.Context) model.ScheduledNotificationRepository {
+	return repository.NewScheduledNotificationRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) DigestRepository(ctx context.Context) model.DigestRepository {
+	return repository.NewDigestRepository(ctx, r.client)
+}
@@ -0,0 +1,57 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"notification/api/server/notificationinternal"
+	appmodel "notification/pkg/notification/app/model"
+)
+
+func (a *notificationInternalAPI) ListDeliveries(ctx context.Context, request *notificationinternal.GetNotificationRequest) (*notificationinternal.ListDeliveriesResponse, error) {
+	notificationID, err := uuid.Parse(request.NotificationID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid notification id")
+	}
+
+	_, err = a.findNotification(ctx, notificationID)
+	if err != nil {
+		return nil, err
+	}
+	deliveries, err := a.queryService.ListDeliveries(ctx, notificationID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*notificationinternal.Delivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		result = append(result, toAPIDelivery(delivery))
+	}
+	return &notificationinternal.ListDeliveriesResponse{
+		Deliveries: result,
+	}, nil
+}
+
+func toAPIDelivery(delivery appmodel.Delivery) *notificationinternal.Delivery {
+	attempts := make([]*notificationinternal.DeliveryAttempt, 0, len(delivery.Attempts))
+	for _, attempt := range delivery.Attempts {
+		attempts = append(attempts, &notificationinternal.DeliveryAttempt{
+			Number:      int32(attempt.Number), // nolint:gosec
+			Status:      attempt.Status,
+			Error:       attempt.Error,
+			AttemptedAt: attempt.AttemptedAt,
+		})
+	}
+	return &notificationinternal.Delivery{
+		DeliveryID:    delivery.ID.String(),
+		Channel:       delivery.Channel,
+		Status:        delivery.Status,
+		Attempts:      attempts,
+		NextAttemptAt: delivery.NextAttemptAt,
+		CreatedAt:     delivery.CreatedAt,
+		UpdatedAt:     delivery.UpdatedAt,
+	}
+}
@@ -0,0 +1,131 @@
+package transport
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"notification/api/server/notificationinternal"
+	appmodel "notification/pkg/notification/app/model"
+	"notification/pkg/notification/app/query"
+)
+
+func (a *notificationInternalAPI) ListNotificationsForUser(ctx context.Context, request *notificationinternal.ListNotificationsForUserRequest) (*notificationinternal.ListNotificationsForUserResponse, error) {
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+	if err = authorizeUser(ctx, userID, permissionNotificationReadAny); err != nil {
+		return nil, err
+	}
+
+	list, err := a.queryService.ListForUser(ctx, appmodel.ListNotificationsSpec{
+		UserID:          userID,
+		UnreadOnly:      request.UnreadOnly,
+		IncludeArchived: request.IncludeArchived,
+		Cursor:          request.Cursor,
+		Limit:           int(request.Limit),
+	})
+	if err != nil {
+		if errors.Is(err, query.ErrInvalidCursor) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, err
+	}
+
+	notifications := make([]*notificationinternal.Notification, 0, len(list.Notifications))
+	for _, notification := range list.Notifications {
+		notifications = append(notifications, toAPINotification(notification))
+	}
+	return &notificationinternal.ListNotificationsForUserResponse{
+		Notifications: notifications,
+		NextCursor:    list.NextCursor,
+	}, nil
+}
+
+func (a *notificationInternalAPI) MarkRead(ctx context.Context, request *notificationinternal.UserNotificationsRequest) (*notificationinternal.UpdateNotificationsResponse, error) {
+	userID, ids, err := parseUserNotifications(request)
+	if err != nil {
+		return nil, err
+	}
+	if err = authorizeUser(ctx, userID, permissionNotificationWriteAny); err != nil {
+		return nil, err
+	}
+	updated, err := a.notificationService.MarkRead(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	return &notificationinternal.UpdateNotificationsResponse{
+		Updated: int32(updated), // nolint:gosec
+	}, nil
+}
+
+func (a *notificationInternalAPI) MarkAllRead(ctx context.Context, request *notificationinternal.UserRequest) (*notificationinternal.UpdateNotificationsResponse, error) {
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+	if err = authorizeUser(ctx, userID, permissionNotificationWriteAny); err != nil {
+		return nil, err
+	}
+	updated, err := a.notificationService.MarkAllRead(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &notificationinternal.UpdateNotificationsResponse{
+		Updated: int32(updated), // nolint:gosec
+	}, nil
+}
+
+func (a *notificationInternalAPI) ArchiveNotifications(ctx context.Context, request *notificationinternal.UserNotificationsRequest) (*notificationinternal.UpdateNotificationsResponse, error) {
+	userID, ids, err := parseUserNotifications(request)
+	if err != nil {
+		return nil, err
+	}
+	if err = authorizeUser(ctx, userID, permissionNotificationWriteAny); err != nil {
+		return nil, err
+	}
+	updated, err := a.notificationService.Archive(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	return &notificationinternal.UpdateNotificationsResponse{
+		Updated: int32(updated), // nolint:gosec
+	}, nil
+}
+
+func (a *notificationInternalAPI) CountUnreadNotifications(ctx context.Context, request *notificationinternal.UserRequest) (*notificationinternal.CountUnreadNotificationsResponse, error) {
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+	if err = authorizeUser(ctx, userID, permissionNotificationReadAny); err != nil {
+		return nil, err
+	}
+	count, err := a.queryService.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &notificationinternal.CountUnreadNotificationsResponse{
+		Count: int32(count), // nolint:gosec
+	}, nil
+}
+
+func parseUserNotifications(request *notificationinternal.UserNotificationsRequest) (uuid.UUID, []uuid.UUID, error) {
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return uuid.Nil, nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+	ids := make([]uuid.UUID, 0, len(request.NotificationIDs))
+	for _, notificationID := range request.NotificationIDs {
+		id, err := uuid.Parse(notificationID)
+		if err != nil {
+			return uuid.Nil, nil, status.Errorf(codes.InvalidArgument, "invalid notification id %q", notificationID)
+		}
+		ids = append(ids, id)
+	}
+	return userID, ids, nil
+}
@@ -2,47 +2,160 @@ package transport
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"notification/api/server/notificationinternal"
+	appmodel "notification/pkg/notification/app/model"
 	"notification/pkg/notification/app/query"
+	"notification/pkg/notification/app/service"
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/infrastructure/transport/middlewares"
+)
+
+const (
+	permissionNotificationReadAny  = "notification.read.any"
+	permissionNotificationWriteAny = "notification.write.any"
 )
 
 func NewNotificationInternalAPI(
 	queryService query.NotificationQueryService,
+	notificationService service.NotificationService,
+	templateService service.TemplateService,
+	preferencesService service.PreferencesService,
 ) notificationinternal.NotificationInternalServiceServer {
 	return &notificationInternalAPI{
-		queryService: queryService,
+		queryService:        queryService,
+		notificationService: notificationService,
+		templateService:     templateService,
+		preferencesService:  preferencesService,
 	}
 }
 
 type notificationInternalAPI struct {
-	queryService query.NotificationQueryService
+	queryService        query.NotificationQueryService
+	notificationService service.NotificationService
+	templateService     service.TemplateService
+	preferencesService  service.PreferencesService
 	notificationinternal.UnimplementedNotificationInternalServiceServer
 }
 
-func (a *notificationInternalAPI) FindNotificationsForUser(ctx context.Context, request *notificationinternal.FindNotificationsForUserRequest) (*notificationinternal.FindNotificationsForUserResponse, error) {
-	userID, err := uuid.Parse(request.NotificationID)
+func (a *notificationInternalAPI) GetNotification(ctx context.Context, request *notificationinternal.GetNotificationRequest) (*notificationinternal.GetNotificationResponse, error) {
+	notificationID, err := uuid.Parse(request.NotificationID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid notification id")
+	}
+
+	notification, err := a.findNotification(ctx, notificationID)
 	if err != nil {
-		return nil, errors.Wrap(err, "invalid user id")
+		return nil, err
 	}
 
-	notification, err := a.queryService.Find(ctx, userID)
+	return &notificationinternal.GetNotificationResponse{
+		Notification: toAPINotification(*notification),
+	}, nil
+}
+
+func (a *notificationInternalAPI) ExportRecipientData(ctx context.Context, request *notificationinternal.RecipientDataRequest) (*notificationinternal.ExportRecipientDataResponse, error) {
+	notifications, err := a.queryService.ListForRecipient(ctx, toRecipient(request))
 	if err != nil {
 		return nil, err
 	}
 
-	result := notificationinternal.Notification{
+	result := make([]*notificationinternal.Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		result = append(result, toAPINotification(notification))
+	}
+	return &notificationinternal.ExportRecipientDataResponse{
+		Notifications: result,
+	}, nil
+}
+
+func (a *notificationInternalAPI) EraseRecipientData(ctx context.Context, request *notificationinternal.RecipientDataRequest) (*notificationinternal.EraseRecipientDataResponse, error) {
+	deleted, err := a.notificationService.EraseRecipientData(ctx, toRecipient(request))
+	if err != nil {
+		return nil, err
+	}
+	return &notificationinternal.EraseRecipientDataResponse{
+		DeletedRecords: int32(deleted), // nolint:gosec
+	}, nil
+}
+
+func (a *notificationInternalAPI) SendWelcomeMessage(ctx context.Context, request *notificationinternal.RecipientDataRequest) (*notificationinternal.SendWelcomeMessageResponse, error) {
+	recipient := toRecipient(request)
+	if recipient.Email == "" && recipient.Telegram == "" {
+		return nil, status.Error(codes.InvalidArgument, "email or telegram is required")
+	}
+
+	payload, err := json.Marshal(model.UserWelcomeVariables{Name: recipient.Name})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	ids, err := a.notificationService.NotifyRecipients(ctx, []model.Recipient{recipient}, "user_welcome", payload)
+	// failed delivery is not reported to the caller, retrying the call would store the message again
+	if err != nil && !errors.Is(err, service.ErrDeliveryFailed) {
+		return nil, err
+	}
+	return &notificationinternal.SendWelcomeMessageResponse{
+		NotificationID: ids[0].String(),
+	}, nil
+}
+
+// findNotification returns notification of the caller, notifications of other users and ones without user are not found
+// unless caller may read any notification
+func (a *notificationInternalAPI) findNotification(ctx context.Context, notificationID uuid.UUID) (*appmodel.Notification, error) {
+	notification, err := a.queryService.Find(ctx, notificationID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotificationNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, err
+	}
+	owned := notification.UserID != nil && isOwner(ctx, *notification.UserID)
+	if !owned && !middlewares.HasPermission(ctx, permissionNotificationReadAny) {
+		return nil, status.Error(codes.NotFound, model.ErrNotificationNotFound.Error())
+	}
+	return notification, nil
+}
+
+// authorizeUser allows access to notifications of the caller or of any user with the permission
+func authorizeUser(ctx context.Context, userID uuid.UUID, permissionAny string) error {
+	if !isOwner(ctx, userID) && !middlewares.HasPermission(ctx, permissionAny) {
+		return status.Error(codes.PermissionDenied, "notifications of other users are not accessible")
+	}
+	return nil
+}
+
+func isOwner(ctx context.Context, userID uuid.UUID) bool {
+	callerID, ok := middlewares.UserIDFromContext(ctx)
+	return ok && callerID == userID
+}
+
+func toRecipient(request *notificationinternal.RecipientDataRequest) model.Recipient {
+	return model.Recipient{
+		Name:     request.Name,
+		Email:    request.GetEmail(),
+		Telegram: request.GetTelegram(),
+	}
+}
+
+func toAPINotification(notification appmodel.Notification) *notificationinternal.Notification {
+	result := &notificationinternal.Notification{
 		NotificationID: notification.ID.String(),
 		Name:           notification.Name,
 		Subject:        notification.Subject,
 		Body:           notification.Body,
 		CreatedAt:      notification.CreatedAt,
+		ReadAt:         notification.ReadAt,
+		ArchivedAt:     notification.ArchivedAt,
 	}
-
-	return &notificationinternal.FindNotificationsForUserResponse{
-		Notification: &result,
-	}, nil
+	if notification.UserID != nil {
+		userID := notification.UserID.String()
+		result.UserID = &userID
+	}
+	return result
 }
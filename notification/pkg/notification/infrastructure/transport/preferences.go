@@ -0,0 +1,162 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"notification/api/server/notificationinternal"
+	"notification/pkg/notification/domain/model"
+)
+
+const quietHoursLayout = "15:04"
+
+func (a *notificationInternalAPI) GetPreferences(ctx context.Context, request *notificationinternal.UserRequest) (*notificationinternal.PreferencesResponse, error) {
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+	if err = authorizeUser(ctx, userID, permissionNotificationReadAny); err != nil {
+		return nil, err
+	}
+	preferences, err := a.preferencesService.FindPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &notificationinternal.PreferencesResponse{
+		Preferences: toAPIPreferences(preferences),
+	}, nil
+}
+
+func (a *notificationInternalAPI) UpdatePreferences(ctx context.Context, request *notificationinternal.UpdatePreferencesRequest) (*notificationinternal.PreferencesResponse, error) {
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+	if err = authorizeUser(ctx, userID, permissionNotificationWriteAny); err != nil {
+		return nil, err
+	}
+	quietHours, err := toQuietHours(request.QuietHours)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	disabled := make([]model.ChannelPreference, 0, len(request.Disabled))
+	for _, preference := range request.Disabled {
+		disabled = append(disabled, model.ChannelPreference{
+			Category: model.Category(preference.GetCategory()),
+			Channel:  preference.GetChannel(),
+		})
+	}
+
+	digests, err := toDigests(request.Digests)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	preferences, err := a.preferencesService.UpdatePreferences(ctx, userID, disabled, quietHours, digests)
+	if err != nil {
+		return nil, preferencesError(err)
+	}
+	return &notificationinternal.PreferencesResponse{
+		Preferences: toAPIPreferences(preferences),
+	}, nil
+}
+
+func (a *notificationInternalAPI) Unsubscribe(ctx context.Context, request *notificationinternal.UnsubscribeRequest) (*notificationinternal.UnsubscribeResponse, error) {
+	preferences, category, err := a.preferencesService.Unsubscribe(ctx, request.Token)
+	if err != nil {
+		return nil, preferencesError(err)
+	}
+	return &notificationinternal.UnsubscribeResponse{
+		UserID:   preferences.UserID.String(),
+		Category: string(category),
+	}, nil
+}
+
+func preferencesError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrSecurityCategoryRequired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, model.ErrInvalidCategory),
+		errors.Is(err, model.ErrInvalidChannel),
+		errors.Is(err, model.ErrInvalidQuietHours),
+		errors.Is(err, model.ErrInvalidDigestWindow),
+		errors.Is(err, model.ErrInvalidUnsubscribeToken):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return err
+}
+
+func toQuietHours(quietHours *notificationinternal.QuietHours) (*model.QuietHours, error) {
+	if quietHours == nil {
+		return nil, nil
+	}
+	start, err := time.Parse(quietHoursLayout, quietHours.GetStart())
+	if err != nil {
+		return nil, fmt.Errorf("%w: start must be in HH:MM format", model.ErrInvalidQuietHours)
+	}
+	end, err := time.Parse(quietHoursLayout, quietHours.GetEnd())
+	if err != nil {
+		return nil, fmt.Errorf("%w: end must be in HH:MM format", model.ErrInvalidQuietHours)
+	}
+	return &model.QuietHours{
+		Start:    time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute,
+		End:      time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute,
+		TimeZone: quietHours.GetTimeZone(),
+	}, nil
+}
+
+func toDigests(digests []*notificationinternal.DigestPreference) (map[model.Category]time.Duration, error) {
+	if len(digests) == 0 {
+		return nil, nil
+	}
+	result := make(map[model.Category]time.Duration, len(digests))
+	for _, digest := range digests {
+		window, err := time.ParseDuration(digest.GetWindow())
+		if err != nil {
+			return nil, fmt.Errorf("%w: window must be a duration, e.g. 24h", model.ErrInvalidDigestWindow)
+		}
+		result[model.Category(digest.GetCategory())] = window
+	}
+	return result, nil
+}
+
+func toAPIPreferences(preferences model.Preferences) *notificationinternal.Preferences {
+	disabled := make([]*notificationinternal.ChannelPreference, 0, len(preferences.Disabled))
+	for _, preference := range preferences.Disabled {
+		disabled = append(disabled, &notificationinternal.ChannelPreference{
+			Category: string(preference.Category),
+			Channel:  preference.Channel,
+		})
+	}
+	result := &notificationinternal.Preferences{
+		UserID:   preferences.UserID.String(),
+		Disabled: disabled,
+	}
+	categories := make([]model.Category, 0, len(preferences.Digests))
+	for category := range preferences.Digests {
+		categories = append(categories, category)
+	}
+	slices.Sort(categories)
+	for _, category := range categories {
+		result.Digests = append(result.Digests, &notificationinternal.DigestPreference{
+			Category: string(category),
+			Window:   preferences.Digests[category].String(),
+		})
+	}
+	if preferences.QuietHours != nil {
+		midnight := time.Time{}
+		result.QuietHours = &notificationinternal.QuietHours{
+			Start:    midnight.Add(preferences.QuietHours.Start).Format(quietHoursLayout),
+			End:      midnight.Add(preferences.QuietHours.End).Format(quietHoursLayout),
+			TimeZone: preferences.QuietHours.TimeZone,
+		}
+	}
+	return result
+}
@@ -0,0 +1,138 @@
+package transport
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"notification/api/server/notificationinternal"
+	"notification/pkg/notification/domain/model"
+)
+
+func (a *notificationInternalAPI) CreateTemplate(ctx context.Context, request *notificationinternal.StoreTemplateRequest) (*notificationinternal.TemplateResponse, error) {
+	template, err := a.templateService.CreateTemplate(ctx, toTemplateKey(request.Key), request.Subject, request.Body)
+	if err != nil {
+		return nil, templateError(err)
+	}
+	return &notificationinternal.TemplateResponse{Template: toAPITemplate(template)}, nil
+}
+
+func (a *notificationInternalAPI) UpdateTemplate(ctx context.Context, request *notificationinternal.StoreTemplateRequest) (*notificationinternal.TemplateResponse, error) {
+	template, err := a.templateService.UpdateTemplate(ctx, toTemplateKey(request.Key), request.Subject, request.Body)
+	if err != nil {
+		return nil, templateError(err)
+	}
+	return &notificationinternal.TemplateResponse{Template: toAPITemplate(template)}, nil
+}
+
+func (a *notificationInternalAPI) GetTemplate(ctx context.Context, request *notificationinternal.GetTemplateRequest) (*notificationinternal.TemplateResponse, error) {
+	template, err := a.templateService.FindTemplate(ctx, toTemplateKey(request.Key), toVersion(request.Version))
+	if err != nil {
+		return nil, templateError(err)
+	}
+	return &notificationinternal.TemplateResponse{Template: toAPITemplate(template)}, nil
+}
+
+func (a *notificationInternalAPI) ListTemplates(ctx context.Context, request *notificationinternal.ListTemplatesRequest) (*notificationinternal.ListTemplatesResponse, error) {
+	templates, err := a.templateService.ListTemplates(ctx, request.GetEventType())
+	if err != nil {
+		return nil, templateError(err)
+	}
+	return toAPITemplates(templates), nil
+}
+
+func (a *notificationInternalAPI) ListTemplateVersions(ctx context.Context, request *notificationinternal.TemplateKey) (*notificationinternal.ListTemplatesResponse, error) {
+	templates, err := a.templateService.ListTemplateVersions(ctx, toTemplateKey(request))
+	if err != nil {
+		return nil, templateError(err)
+	}
+	return toAPITemplates(templates), nil
+}
+
+func (a *notificationInternalAPI) DeleteTemplate(ctx context.Context, request *notificationinternal.TemplateKey) (*notificationinternal.DeleteTemplateResponse, error) {
+	deleted, err := a.templateService.DeleteTemplate(ctx, toTemplateKey(request))
+	if err != nil {
+		return nil, templateError(err)
+	}
+	return &notificationinternal.DeleteTemplateResponse{
+		DeletedVersions: int32(deleted), // nolint:gosec
+	}, nil
+}
+
+func (a *notificationInternalAPI) PreviewTemplate(ctx context.Context, request *notificationinternal.PreviewTemplateRequest) (*notificationinternal.PreviewTemplateResponse, error) {
+	key := toTemplateKey(request.Key)
+	var (
+		message model.Message
+		err     error
+	)
+	if request.Draft != nil {
+		message, err = a.templateService.PreviewDraft(key.EventType, request.Draft.Subject, request.Draft.Body, []byte(request.Payload))
+	} else {
+		message, err = a.templateService.PreviewTemplate(ctx, key, toVersion(request.Version), []byte(request.Payload))
+	}
+	if err != nil {
+		return nil, templateError(err)
+	}
+	return &notificationinternal.PreviewTemplateResponse{
+		Subject: message.Subject,
+		Body:    message.Body,
+	}, nil
+}
+
+func templateError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrTemplateNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, model.ErrTemplateAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, model.ErrTemplateRequired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, model.ErrInvalidTemplateKey),
+		errors.Is(err, model.ErrInvalidTemplate),
+		errors.Is(err, model.ErrInvalidTemplatePayload),
+		errors.Is(err, model.ErrUnknownTemplateEvent):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return err
+}
+
+func toTemplateKey(key *notificationinternal.TemplateKey) model.TemplateKey {
+	return model.TemplateKey{
+		EventType: key.GetEventType(),
+		Channel:   key.GetChannel(),
+		Locale:    key.GetLocale(),
+	}
+}
+
+func toVersion(version *int32) *int {
+	if version == nil {
+		return nil
+	}
+	v := int(*version)
+	return &v
+}
+
+func toAPITemplates(templates []model.Template) *notificationinternal.ListTemplatesResponse {
+	result := make([]*notificationinternal.Template, 0, len(templates))
+	for _, template := range templates {
+		result = append(result, toAPITemplate(template))
+	}
+	return &notificationinternal.ListTemplatesResponse{Templates: result}
+}
+
+func toAPITemplate(template model.Template) *notificationinternal.Template {
+	return &notificationinternal.Template{
+		TemplateID: template.TemplateID.String(),
+		Key: &notificationinternal.TemplateKey{
+			EventType: template.Key.EventType,
+			Channel:   template.Key.Channel,
+			Locale:    template.Key.Locale,
+		},
+		Version:   int32(template.Version), // nolint:gosec
+		Subject:   template.Subject,
+		Body:      template.Body,
+		CreatedAt: template.CreatedAt.Unix(),
+	}
+}
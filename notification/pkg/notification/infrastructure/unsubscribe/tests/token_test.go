@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"notification/pkg/notification/domain/model"
+	"notification/pkg/notification/infrastructure/unsubscribe"
+)
+
+func TestTokens(t *testing.T) {
+	tokens := unsubscribe.NewTokens([]byte("secret"), "https://shop.example/unsubscribe")
+	userID := uuid.Must(uuid.NewV7())
+
+	t.Run("Parse issued token", func(t *testing.T) {
+		token := tokens.Issue(userID, model.CategoryOrders)
+
+		parsedUserID, category, err := tokens.Parse(token)
+		require.NoError(t, err)
+		require.Equal(t, userID, parsedUserID)
+		require.Equal(t, model.CategoryOrders, category)
+	})
+
+	t.Run("Token signed with other secret", func(t *testing.T) {
+		token := unsubscribe.NewTokens([]byte("other"), "").Issue(userID, model.CategoryOrders)
+
+		_, _, err := tokens.Parse(token)
+		require.ErrorIs(t, err, model.ErrInvalidUnsubscribeToken)
+	})
+
+	t.Run("Tampered category", func(t *testing.T) {
+		token := tokens.Issue(userID, model.CategoryOrders)
+		_, signature, _ := strings.Cut(token, ".")
+		forged := unsubscribe.NewTokens(nil, "").Issue(userID, model.CategoryMarketing)
+		payload, _, _ := strings.Cut(forged, ".")
+
+		_, _, err := tokens.Parse(payload + "." + signature)
+		require.ErrorIs(t, err, model.ErrInvalidUnsubscribeToken)
+	})
+
+	t.Run("Malformed token", func(t *testing.T) {
+		_, _, err := tokens.Parse("not-a-token")
+		require.ErrorIs(t, err, model.ErrInvalidUnsubscribeToken)
+	})
+
+	t.Run("Link", func(t *testing.T) {
+		token := tokens.Issue(userID, model.CategoryOrders)
+		require.Equal(t, "Unsubscribe: https://shop.example/unsubscribe?token="+token, tokens.Link(token))
+		require.Equal(t, "Unsubscribe token: "+token, unsubscribe.NewTokens(nil, "").Link(token))
+	})
+}
@@ -0,0 +1,78 @@
+package unsubscribe
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"notification/pkg/notification/app/service"
+	"notification/pkg/notification/domain/model"
+)
+
+// NewTokens returns tokens signed with the secret, link is URL with token query parameter or bare token if URL is empty
+func NewTokens(secret []byte, linkURL string) service.UnsubscribeTokens {
+	return &tokens{
+		secret:  secret,
+		linkURL: linkURL,
+	}
+}
+
+// tokens are user id and category signed with HMAC-SHA256, they do not expire as category can be turned on again
+type tokens struct {
+	secret  []byte
+	linkURL string
+}
+
+func (t *tokens) Issue(userID uuid.UUID, category model.Category) string {
+	payload := append(userID[:], category...)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(t.sign(payload))
+}
+
+func (t *tokens) Parse(token string) (uuid.UUID, model.Category, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.Nil, "", model.ErrInvalidUnsubscribeToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) <= len(uuid.UUID{}) {
+		return uuid.Nil, "", model.ErrInvalidUnsubscribeToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, t.sign(payload)) {
+		return uuid.Nil, "", model.ErrInvalidUnsubscribeToken
+	}
+
+	userID, err := uuid.FromBytes(payload[:len(uuid.UUID{})])
+	if err != nil {
+		return uuid.Nil, "", model.ErrInvalidUnsubscribeToken
+	}
+	category := model.Category(payload[len(uuid.UUID{}):])
+	if !category.Valid() {
+		return uuid.Nil, "", model.ErrInvalidUnsubscribeToken
+	}
+	return userID, category, nil
+}
+
+func (t *tokens) Link(token string) string {
+	if t.linkURL == "" {
+		return "Unsubscribe token: " + token
+	}
+	link, err := url.Parse(t.linkURL)
+	if err != nil {
+		return "Unsubscribe token: " + token
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return "Unsubscribe: " + link.String()
+}
+
+func (t *tokens) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
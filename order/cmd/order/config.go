@@ -40,3 +40,9 @@ type AMQP struct {
 type Temporal struct {
 	Host string `envconfig:"host" required:"true"`
 }
+
+type Auth struct {
+	// PublicKeys are base64 encoded ed25519 public keys of user service by key ID in "id1:key1,id2:key2" format,
+	// keys of rotated out signing keys are kept until tokens signed by them expire
+	PublicKeys map[string]string `envconfig:"public_keys" required:"true"`
+}
@@ -26,9 +26,19 @@ import (
 	"order/pkg/infrastructure/transport/middlewares"
 )
 
+// methodPermissions are permissions required in access token, access to orders of other users is checked by methods
+var methodPermissions = map[string]string{
+	"/Order.OrderInternalService/CreateOrder": "order.create",
+	"/Order.OrderInternalService/FindOrder":   "order.read",
+	// data subject requests are served for user service workflow on behalf of administrator
+	"/Order.OrderInternalService/ExportUserData": "user.data.export",
+	"/Order.OrderInternalService/EraseUserData":  "user.data.erase",
+}
+
 type serviceConfig struct {
 	Service  Service  `envconfig:"service"`
 	Database Database `envconfig:"database" required:"true"`
+	Auth     Auth     `envconfig:"auth" required:"true"`
 }
 
 func service(logger logging.Logger) *cli.Command {
@@ -46,6 +56,11 @@ func service(logger logging.Logger) *cli.Command {
 				err = errors.Join(err, closer.Close())
 			}()
 
+			publicKeys, err := middlewares.ParsePublicKeys(cnf.Auth.PublicKeys)
+			if err != nil {
+				return err
+			}
+
 			databaseConnector, err := newDatabaseConnector(cnf.Database)
 			if err != nil {
 				return err
@@ -62,6 +77,7 @@ func service(logger logging.Logger) *cli.Command {
 			orderInternalAPI := transport.NewOrderInternalAPI(
 				query.NewOrderQueryService(databaseConnector.TransactionalClient()),
 				appservice.NewOrderService(uow, luow, eventDispatcher),
+				appservice.NewUserDataService(uow),
 			)
 
 			errGroup := errgroup.Group{}
@@ -72,6 +88,8 @@ func service(logger logging.Logger) *cli.Command {
 				}
 				grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
 					middlewares.NewGRPCLoggingMiddleware(logger),
+					middlewares.NewGRPCAuthMiddleware(publicKeys),
+					middlewares.NewGRPCPermissionMiddleware(methodPermissions),
 				))
 				orderinternal.RegisterOrderInternalServiceServer(grpcServer, orderInternalAPI)
 				reflection.Register(grpcServer)
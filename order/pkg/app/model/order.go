@@ -20,3 +20,14 @@ type Order struct {
 	Status     int
 	CreatedAt  int64
 }
+
+// UserData is everything order service stores about the user
+type UserData struct {
+	Login  *string
+	Orders []Order
+}
+
+type UserDataErasure struct {
+	DeletedRecords       int
+	PseudonymizedRecords int
+}
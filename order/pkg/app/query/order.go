@@ -10,4 +10,5 @@ import (
 
 type OrderQueryService interface {
 	FindOrder(ctx context.Context, orderID uuid.UUID) (*appmodel.Order, error)
+	ExportUserData(ctx context.Context, userID uuid.UUID) (appmodel.UserData, error)
 }
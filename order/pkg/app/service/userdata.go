@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	appmodel "order/pkg/app/model"
+)
+
+// UserDataService serves data subject requests, orders are financial records,
+// so they are kept with user replaced by pseudonym
+type UserDataService interface {
+	EraseUserData(ctx context.Context, userID, pseudonymID uuid.UUID) (appmodel.UserDataErasure, error)
+}
+
+func NewUserDataService(uow UnitOfWork) UserDataService {
+	return &userDataService{uow: uow}
+}
+
+type userDataService struct {
+	uow UnitOfWork
+}
+
+func (s *userDataService) EraseUserData(ctx context.Context, userID, pseudonymID uuid.UUID) (appmodel.UserDataErasure, error) {
+	var result appmodel.UserDataErasure
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		deleted, err := provider.LocalUserRepository(ctx).Delete(userID)
+		if err != nil {
+			return err
+		}
+		pseudonymized, err := provider.OrderRepository(ctx).Pseudonymize(userID, pseudonymID)
+		if err != nil {
+			return err
+		}
+		result = appmodel.UserDataErasure{
+			DeletedRecords:       deleted,
+			PseudonymizedRecords: pseudonymized,
+		}
+		return nil
+	})
+	return result, err
+}
@@ -20,6 +20,8 @@ func (e OrderCreated) Type() string {
 
 type OrderPaid struct {
 	OrderID uuid.UUID
+	UserID  uuid.UUID
+	Items   []OrderItem
 	PaidAt  time.Time
 }
 
@@ -29,6 +31,8 @@ func (e OrderPaid) Type() string {
 
 type OrderCancelled struct {
 	OrderID     uuid.UUID
+	UserID      uuid.UUID
+	Items       []OrderItem
 	Reason      string
 	CancelledAt time.Time
 }
@@ -12,6 +12,8 @@ type LocalUser struct {
 type LocalUserRepository interface {
 	Store(user LocalUser) error
 	Find(userID uuid.UUID) (*LocalUser, error)
+	// Delete returns number of deleted users, it is 0 for not synced user
+	Delete(userID uuid.UUID) (int, error)
 }
 
 type LocalProduct struct {
@@ -43,4 +43,6 @@ type OrderRepository interface {
 	NextID() (uuid.UUID, error)
 	Store(order Order) error
 	Find(orderID uuid.UUID) (*Order, error)
+	// Pseudonymize replaces user of all user orders with pseudonym and returns number of changed orders
+	Pseudonymize(userID, pseudonymID uuid.UUID) (int, error)
 }
@@ -88,6 +88,8 @@ func (s *orderService) MarkAsPaid(orderID uuid.UUID) error {
 
 	return s.eventDispatcher.Dispatch(&model.OrderPaid{
 		OrderID: orderID,
+		UserID:  order.UserID,
+		Items:   order.Items,
 		PaidAt:  order.UpdatedAt,
 	})
 }
@@ -111,6 +113,8 @@ func (s *orderService) CancelOrder(orderID uuid.UUID, reason string) error {
 
 	return s.eventDispatcher.Dispatch(&model.OrderCancelled{
 		OrderID:     orderID,
+		UserID:      order.UserID,
+		Items:       order.Items,
 		Reason:      reason,
 		CancelledAt: order.UpdatedAt,
 	})
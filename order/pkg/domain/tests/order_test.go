@@ -34,6 +34,11 @@ func (m *MockOrderRepository) Find(orderID uuid.UUID) (*model.Order, error) {
 	return args.Get(0).(*model.Order), args.Error(1)
 }
 
+func (m *MockOrderRepository) Pseudonymize(userID, pseudonymID uuid.UUID) (int, error) {
+	args := m.Called(userID, pseudonymID)
+	return args.Int(0), args.Error(1)
+}
+
 type MockEventDispatcher struct {
 	mock.Mock
 }
@@ -84,10 +89,12 @@ func TestOrderService_MarkAsPaid(t *testing.T) {
 	service := service.NewOrderService(repo, dispatcher)
 
 	orderID := uuid.New()
+	userID := uuid.New()
 
 	t.Run("success", func(t *testing.T) {
 		existingOrder := &model.Order{
 			OrderID: orderID,
+			UserID:  userID,
 			Status:  model.StatusCreated,
 		}
 
@@ -96,7 +103,7 @@ func TestOrderService_MarkAsPaid(t *testing.T) {
 			return o.OrderID == orderID && o.Status == model.StatusPaid
 		})).Return(nil).Once()
 		dispatcher.On("Dispatch", mock.MatchedBy(func(e *model.OrderPaid) bool {
-			return e.OrderID == orderID
+			return e.OrderID == orderID && e.UserID == userID
 		})).Return(nil).Once()
 
 		err := service.MarkAsPaid(orderID)
@@ -124,10 +131,12 @@ func TestOrderService_CancelOrder(t *testing.T) {
 	service := service.NewOrderService(repo, dispatcher)
 
 	orderID := uuid.New()
+	userID := uuid.New()
 
 	t.Run("success", func(t *testing.T) {
 		existingOrder := &model.Order{
 			OrderID: orderID,
+			UserID:  userID,
 			Status:  model.StatusCreated,
 		}
 
@@ -136,7 +145,7 @@ func TestOrderService_CancelOrder(t *testing.T) {
 			return o.OrderID == orderID && o.Status == model.StatusCancelled
 		})).Return(nil).Once()
 		dispatcher.On("Dispatch", mock.MatchedBy(func(e *model.OrderCancelled) bool {
-			return e.OrderID == orderID && e.Reason == "test"
+			return e.OrderID == orderID && e.UserID == userID && e.Reason == "test"
 		})).Return(nil).Once()
 
 		err := service.CancelOrder(orderID, "test")
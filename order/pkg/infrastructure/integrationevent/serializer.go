@@ -19,19 +19,11 @@ type eventSerializer struct{}
 func (s eventSerializer) Serialize(event outbox.Event) (string, error) {
 	switch e := event.(type) {
 	case *model.OrderCreated:
-		items := make([]OrderItem, len(e.Items))
-		for i, item := range e.Items {
-			items[i] = OrderItem{
-				ProductID: item.ProductID.String(),
-				Quantity:  item.Quantity,
-				Price:     item.Price,
-			}
-		}
 		b, err := json.Marshal(OrderCreated{
 			OrderID:    e.OrderID.String(),
 			UserID:     e.UserID.String(),
 			TotalPrice: e.TotalPrice,
-			Items:      items,
+			Items:      toOrderItems(e.Items),
 			CreatedAt:  e.CreatedAt.Unix(),
 		})
 		return string(b), errors.WithStack(err)
@@ -39,6 +31,8 @@ func (s eventSerializer) Serialize(event outbox.Event) (string, error) {
 	case *model.OrderPaid:
 		b, err := json.Marshal(OrderPaid{
 			OrderID: e.OrderID.String(),
+			UserID:  e.UserID.String(),
+			Items:   toOrderItems(e.Items),
 			PaidAt:  e.PaidAt.Unix(),
 		})
 		return string(b), errors.WithStack(err)
@@ -46,6 +40,8 @@ func (s eventSerializer) Serialize(event outbox.Event) (string, error) {
 	case *model.OrderCancelled:
 		b, err := json.Marshal(OrderCancelled{
 			OrderID:     e.OrderID.String(),
+			UserID:      e.UserID.String(),
+			Items:       toOrderItems(e.Items),
 			Reason:      e.Reason,
 			CancelledAt: e.CancelledAt.Unix(),
 		})
@@ -71,12 +67,28 @@ type OrderCreated struct {
 }
 
 type OrderPaid struct {
-	OrderID string `json:"order_id"`
-	PaidAt  int64  `json:"paid_at"`
+	OrderID string      `json:"order_id"`
+	UserID  string      `json:"user_id"`
+	Items   []OrderItem `json:"items"`
+	PaidAt  int64       `json:"paid_at"`
 }
 
 type OrderCancelled struct {
-	OrderID     string `json:"order_id"`
-	Reason      string `json:"reason"`
-	CancelledAt int64  `json:"cancelled_at"`
+	OrderID     string      `json:"order_id"`
+	UserID      string      `json:"user_id"`
+	Items       []OrderItem `json:"items"`
+	Reason      string      `json:"reason"`
+	CancelledAt int64       `json:"cancelled_at"`
+}
+
+func toOrderItems(items []model.OrderItem) []OrderItem {
+	result := make([]OrderItem, len(items))
+	for i, item := range items {
+		result[i] = OrderItem{
+			ProductID: item.ProductID.String(),
+			Quantity:  item.Quantity,
+			Price:     item.Price,
+		}
+	}
+	return result
 }
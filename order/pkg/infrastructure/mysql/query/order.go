@@ -77,3 +77,67 @@ func (s *orderQueryService) FindOrder(ctx context.Context, orderID uuid.UUID) (_
 		CreatedAt:  orderData.CreatedAt.Unix(),
 	}, nil
 }
+
+func (s *orderQueryService) ExportUserData(ctx context.Context, userID uuid.UUID) (_ appmodel.UserData, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("export_query", "order", status).Observe(time.Since(start).Seconds())
+	}()
+
+	var result appmodel.UserData
+	var login string
+	err = s.client.GetContext(ctx, &login, `SELECT login FROM local_user WHERE user_id = ?`, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return appmodel.UserData{}, errors.WithStack(err)
+	}
+	if err == nil {
+		result.Login = &login
+	}
+
+	var ordersData []struct {
+		OrderID    uuid.UUID `db:"order_id"`
+		TotalPrice int64     `db:"total_price"`
+		Status     int       `db:"status"`
+		CreatedAt  time.Time `db:"created_at"`
+	}
+	err = s.client.SelectContext(ctx, &ordersData,
+		"SELECT order_id, total_price, status, created_at FROM `order` WHERE user_id = ? ORDER BY order_id", userID)
+	if err != nil {
+		return appmodel.UserData{}, errors.WithStack(err)
+	}
+
+	var itemsData []struct {
+		OrderID   uuid.UUID `db:"order_id"`
+		ProductID uuid.UUID `db:"product_id"`
+		Quantity  int       `db:"quantity"`
+	}
+	err = s.client.SelectContext(ctx, &itemsData,
+		"SELECT oi.order_id, oi.product_id, oi.quantity FROM order_item oi INNER JOIN `order` o ON o.order_id = oi.order_id WHERE o.user_id = ?", userID)
+	if err != nil {
+		return appmodel.UserData{}, errors.WithStack(err)
+	}
+	items := make(map[uuid.UUID][]appmodel.OrderItem)
+	for _, itemData := range itemsData {
+		items[itemData.OrderID] = append(items[itemData.OrderID], appmodel.OrderItem{
+			ProductID: itemData.ProductID,
+			Quantity:  itemData.Quantity,
+		})
+	}
+
+	result.Orders = make([]appmodel.Order, 0, len(ordersData))
+	for _, orderData := range ordersData {
+		result.Orders = append(result.Orders, appmodel.Order{
+			OrderID:    orderData.OrderID,
+			UserID:     userID,
+			Items:      items[orderData.OrderID],
+			TotalPrice: orderData.TotalPrice,
+			Status:     orderData.Status,
+			CreatedAt:  orderData.CreatedAt.Unix(),
+		})
+	}
+	return result, nil
+}
@@ -46,6 +46,15 @@ func (r *localUserRepository) Find(userID uuid.UUID) (*model.LocalUser, error) {
 	}, nil
 }
 
+func (r *localUserRepository) Delete(userID uuid.UUID) (int, error) {
+	result, err := r.client.ExecContext(r.ctx, `DELETE FROM local_user WHERE user_id = ?`, userID)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), errors.WithStack(err)
+}
+
 type sqlxLocalUser struct {
 	UserID uuid.UUID `db:"user_id"`
 	Login  string    `db:"login"`
@@ -122,3 +122,21 @@ func (r *orderRepository) Find(orderID uuid.UUID) (_ *model.Order, err error) {
 		UpdatedAt:  orderData.UpdatedAt,
 	}, nil
 }
+
+func (r *orderRepository) Pseudonymize(userID, pseudonymID uuid.UUID) (_ int, err error) {
+	start := time.Now()
+	defer func() {
+		status := metrics.StatusSuccess
+		if err != nil {
+			status = metrics.StatusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("pseudonymize", "order", status).Observe(time.Since(start).Seconds())
+	}()
+
+	result, err := r.client.ExecContext(r.ctx, "UPDATE `order` SET user_id = ? WHERE user_id = ?", pseudonymID, userID)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	changed, err := result.RowsAffected()
+	return int(changed), errors.WithStack(err)
+}
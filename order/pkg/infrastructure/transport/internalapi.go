@@ -5,26 +5,39 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"order/api/server/orderinternal"
 	appmodel "order/pkg/app/model"
 	"order/pkg/app/query"
 	"order/pkg/app/service"
+	"order/pkg/infrastructure/transport/middlewares"
+)
+
+const (
+	// permissionOrderCreateAny allows to create orders on behalf of other users
+	permissionOrderCreateAny = "order.create.any"
+	// permissionOrderReadAny allows to read orders of other users
+	permissionOrderReadAny = "order.read.any"
 )
 
 func NewOrderInternalAPI(
 	orderQueryService query.OrderQueryService,
 	orderService service.OrderService,
+	userDataService service.UserDataService,
 ) orderinternal.OrderInternalServiceServer {
 	return &orderInternalAPI{
 		orderQueryService: orderQueryService,
 		orderService:      orderService,
+		userDataService:   userDataService,
 	}
 }
 
 type orderInternalAPI struct {
 	orderQueryService query.OrderQueryService
 	orderService      service.OrderService
+	userDataService   service.UserDataService
 	orderinternal.UnimplementedOrderInternalServiceServer
 }
 
@@ -33,6 +46,9 @@ func (a *orderInternalAPI) CreateOrder(ctx context.Context, request *orderintern
 	if err != nil {
 		return nil, errors.Wrap(err, "invalid user id")
 	}
+	if !isOwner(ctx, userID) && !middlewares.HasPermission(ctx, permissionOrderCreateAny) {
+		return nil, status.Error(codes.PermissionDenied, "order can be created only for own user")
+	}
 
 	items := make([]appmodel.OrderItem, len(request.Items))
 	for i, item := range request.Items {
@@ -68,10 +84,58 @@ func (a *orderInternalAPI) FindOrder(ctx context.Context, request *orderinternal
 		return nil, err
 	}
 
-	if order == nil {
+	// orders of other users are not found for customers
+	if order == nil || (!isOwner(ctx, order.UserID) && !middlewares.HasPermission(ctx, permissionOrderReadAny)) {
 		return &orderinternal.FindOrderResponse{}, nil
 	}
 
+	return &orderinternal.FindOrderResponse{
+		Order: toAPIOrder(*order),
+	}, nil
+}
+
+func (a *orderInternalAPI) ExportUserData(ctx context.Context, request *orderinternal.ExportUserDataRequest) (*orderinternal.ExportUserDataResponse, error) {
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user id %q", request.UserID)
+	}
+
+	userData, err := a.orderQueryService.ExportUserData(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]*orderinternal.Order, len(userData.Orders))
+	for i, order := range userData.Orders {
+		orders[i] = toAPIOrder(order)
+	}
+	return &orderinternal.ExportUserDataResponse{
+		Login:  userData.Login,
+		Orders: orders,
+	}, nil
+}
+
+func (a *orderInternalAPI) EraseUserData(ctx context.Context, request *orderinternal.EraseUserDataRequest) (*orderinternal.EraseUserDataResponse, error) {
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user id %q", request.UserID)
+	}
+	pseudonymID, err := uuid.Parse(request.PseudonymID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid pseudonym id %q", request.PseudonymID)
+	}
+
+	erasure, err := a.userDataService.EraseUserData(ctx, userID, pseudonymID)
+	if err != nil {
+		return nil, err
+	}
+	return &orderinternal.EraseUserDataResponse{
+		DeletedRecords:       int32(erasure.DeletedRecords),       // nolint:gosec
+		PseudonymizedRecords: int32(erasure.PseudonymizedRecords), // nolint:gosec
+	}, nil
+}
+
+func toAPIOrder(order appmodel.Order) *orderinternal.Order {
 	items := make([]*orderinternal.OrderItem, len(order.Items))
 	for i, item := range order.Items {
 		items[i] = &orderinternal.OrderItem{
@@ -79,15 +143,17 @@ func (a *orderInternalAPI) FindOrder(ctx context.Context, request *orderinternal
 			Quantity:  int32(item.Quantity), // nolint:gosec
 		}
 	}
+	return &orderinternal.Order{
+		OrderID:    order.OrderID.String(),
+		UserID:     order.UserID.String(),
+		Items:      items,
+		TotalPrice: order.TotalPrice,
+		Status:     orderinternal.OrderStatus(order.Status), // nolint:gosec
+		CreatedAt:  order.CreatedAt,
+	}
+}
 
-	return &orderinternal.FindOrderResponse{
-		Order: &orderinternal.Order{
-			OrderID:    order.OrderID.String(),
-			UserID:     order.UserID.String(),
-			Items:      items,
-			TotalPrice: order.TotalPrice,
-			Status:     orderinternal.OrderStatus(order.Status), // nolint:gosec
-			CreatedAt:  order.CreatedAt,
-		},
-	}, nil
+func isOwner(ctx context.Context, userID uuid.UUID) bool {
+	callerID, ok := middlewares.UserIDFromContext(ctx)
+	return ok && callerID == userID
 }
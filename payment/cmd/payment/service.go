@@ -24,9 +24,23 @@ import (
 	"payment/pkg/payment/infrastructure/transport/middlewares"
 )
 
+// methodPermissions are permissions required in access token, access to balances of other customers is checked by methods
+var methodPermissions = map[string]string{
+	"/Payment.PaymentPublicAPI/StoreCustomerBalance": "payment.balance.write",
+	"/Payment.PaymentPublicAPI/FindCustomerBalance":  "payment.read",
+	"/Payment.PaymentPublicAPI/GetStatement":         "payment.read",
+	// data subject requests are served for user service workflow on behalf of administrator
+	"/Payment.PaymentPublicAPI/ExportCustomerData": "user.data.export",
+	"/Payment.PaymentPublicAPI/EraseCustomerData":  "user.data.erase",
+	// accounts are opened and closed by onboarding workflow of user service
+	"/Payment.PaymentPublicAPI/OpenCustomerAccount":  "user.onboard",
+	"/Payment.PaymentPublicAPI/CloseCustomerAccount": "user.onboard",
+}
+
 type serviceConfig struct {
 	Service  Service  `envconfig:"service"`
 	Database Database `envconfig:"database" required:"true"`
+	Auth     Auth     `envconfig:"auth" required:"true"`
 }
 
 func service(logger logging.Logger) *cli.Command {
@@ -44,6 +58,11 @@ func service(logger logging.Logger) *cli.Command {
 				err = errors.Join(err, closer.Close())
 			}()
 
+			publicKeys, err := middlewares.ParsePublicKeys(cnf.Auth.PublicKeys)
+			if err != nil {
+				return err
+			}
+
 			databaseConnector, err := newDatabaseConnector(cnf.Database)
 			if err != nil {
 				return err
@@ -56,9 +75,13 @@ func service(logger logging.Logger) *cli.Command {
 			luow := inframysql.NewLockableUnitOfWork(libLUow)
 			eventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, integrationevent.NewEventSerializer(), libUoW)
 
+			statementQueryService := query.NewStatementQueryService(databaseConnector.TransactionalClient())
 			paymentPublicAPIServer := transport.NewPaymentInternalAPI(
 				query.NewAccountBalanceQueryService(databaseConnector.TransactionalClient()),
+				statementQueryService,
+				query.NewCustomerDataQueryService(databaseConnector.TransactionalClient()),
 				appservice.NewPaymentService(luow, eventDispatcher),
+				appservice.NewCustomerDataService(luow),
 			)
 
 			errGroup := errgroup.Group{}
@@ -69,6 +92,8 @@ func service(logger logging.Logger) *cli.Command {
 				}
 				grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
 					middlewares.NewGRPCLoggingMiddleware(logger),
+					middlewares.NewGRPCAuthMiddleware(publicKeys),
+					middlewares.NewGRPCPermissionMiddleware(methodPermissions),
 				))
 				internalapi.RegisterPaymentPublicAPIServer(grpcServer, paymentPublicAPIServer)
 				graceCallback(c.Context, logger, cnf.Service.GracePeriod, func(_ context.Context) error {
@@ -80,6 +105,9 @@ func service(logger logging.Logger) *cli.Command {
 			errGroup.Go(func() error {
 				router := mux.NewRouter()
 				registerHealthcheck(router)
+				statementDownloadHandler := middlewares.NewHTTPAuthMiddleware(publicKeys)(transport.NewStatementDownloadHandler(statementQueryService))
+				router.Handle("/statements/{customerID}/{period}", statementDownloadHandler).
+					Methods(http.MethodGet)
 				// nolint:gosec
 				server := http.Server{
 					Addr:    cnf.Service.HTTPAddress,
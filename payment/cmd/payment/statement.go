@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
+	libio "gitea.xscloud.ru/xscloud/golib/pkg/common/io"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/outbox"
+	"github.com/google/uuid"
+	"github.com/urfave/cli/v2"
+
+	appservice "payment/pkg/payment/app/service"
+	"payment/pkg/payment/infrastructure/integrationevent"
+	inframysql "payment/pkg/payment/infrastructure/mysql"
+	"payment/pkg/payment/infrastructure/mysql/query"
+)
+
+type statementConfig struct {
+	Database Database `envconfig:"database" required:"true"`
+}
+
+func statement(logger logging.Logger) *cli.Command {
+	return &cli.Command{
+		Name: "statement",
+		Subcommands: cli.Commands{
+			&cli.Command{
+				Name:   "generate",
+				Usage:  "generate (or regenerate as a new version) customer statements for a closed month",
+				Before: migrateImpl(logger),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "period",
+						Usage:    "statement month in YYYY-MM format",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "customer-id",
+						Usage: "generate statement only for the customer, all customers by default",
+					},
+				},
+				Action: generateStatementsImpl(logger),
+			},
+		},
+	}
+}
+
+func generateStatementsImpl(logger logging.Logger) func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		cnf, err := parseEnvs[statementConfig]()
+		if err != nil {
+			return err
+		}
+
+		closer := libio.NewMultiCloser()
+		defer func() {
+			err = errors.Join(err, closer.Close())
+		}()
+
+		databaseConnector, err := newDatabaseConnector(cnf.Database)
+		if err != nil {
+			return err
+		}
+		closer.AddCloser(databaseConnector)
+		databaseConnectionPool := mysql.NewConnectionPool(databaseConnector.TransactionalClient())
+
+		libUoW := mysql.NewUnitOfWork(databaseConnectionPool, inframysql.NewRepositoryProvider)
+		libLUow := mysql.NewLockableUnitOfWork(libUoW, mysql.NewLocker(databaseConnectionPool))
+		luow := inframysql.NewLockableUnitOfWork(libLUow)
+		eventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, integrationevent.NewEventSerializer(), libUoW)
+		statementService := appservice.NewStatementService(luow, eventDispatcher)
+
+		var customerIDs []uuid.UUID
+		if rawCustomerID := c.String("customer-id"); rawCustomerID != "" {
+			customerID, parseErr := uuid.Parse(rawCustomerID)
+			if parseErr != nil {
+				return fmt.Errorf("invalid uuid %q", rawCustomerID)
+			}
+			customerIDs = append(customerIDs, customerID)
+		} else {
+			customerIDs, err = query.NewAccountBalanceQueryService(databaseConnector.TransactionalClient()).ListCustomerIDs(c.Context)
+			if err != nil {
+				return err
+			}
+		}
+
+		period := c.String("period")
+		for _, customerID := range customerIDs {
+			statementID, generateErr := statementService.GenerateStatement(c.Context, customerID, period)
+			if generateErr != nil {
+				return generateErr
+			}
+			logger.Info(fmt.Sprintf("Statement %s for %s was generated for customer %s", statementID, period, customerID))
+		}
+		return nil
+	}
+}
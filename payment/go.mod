@@ -6,6 +6,7 @@ replace gitea.xscloud.ru/xscloud/golib v1.2.4 => github.com/veresnikov/rp-golib
 
 require (
 	gitea.xscloud.ru/xscloud/golib v1.2.4
+	github.com/golang-jwt/jwt/v5 v5.2.2
 	github.com/google/uuid v1.6.0
 	github.com/gorilla/mux v1.7.4
 	github.com/kelseyhightower/envconfig v1.4.0
@@ -0,0 +1,13 @@
+package model
+
+// CustomerData is everything payment service stores about the customer
+type CustomerData struct {
+	Balance      *CustomerBalance
+	Transactions []StatementLine
+	// Statements are without lines, lines are the same transactions
+	Statements []Statement
+}
+
+type CustomerDataErasure struct {
+	PseudonymizedRecords int
+}
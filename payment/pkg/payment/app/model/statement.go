@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Statement struct {
+	StatementID    uuid.UUID
+	CustomerID     uuid.UUID
+	Period         string
+	Version        int
+	OpeningBalance float64
+	ClosingBalance float64
+	TotalCharges   float64
+	TotalRefunds   float64
+	TotalBonuses   float64
+	Lines          []StatementLine
+	CreatedAt      time.Time
+}
+
+type StatementLine struct {
+	TransactionID uuid.UUID
+	OrderID       uuid.UUID
+	Type          string
+	Amount        float64
+	PaymentDate   time.Time
+}
@@ -10,4 +10,5 @@ import (
 
 type AccountBalanceQueryService interface {
 	FindBalance(ctx context.Context, id uuid.UUID) (*appmodel.CustomerBalance, error)
+	ListCustomerIDs(ctx context.Context) ([]uuid.UUID, error)
 }
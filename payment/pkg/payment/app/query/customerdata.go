@@ -0,0 +1,13 @@
+package query
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	appmodel "payment/pkg/payment/app/model"
+)
+
+type CustomerDataQueryService interface {
+	ExportCustomerData(ctx context.Context, customerID uuid.UUID) (appmodel.CustomerData, error)
+}
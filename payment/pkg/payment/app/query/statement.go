@@ -0,0 +1,14 @@
+package query
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	appmodel "payment/pkg/payment/app/model"
+)
+
+type StatementQueryService interface {
+	// FindStatement returns the latest version of customer statement for period in "YYYY-MM" format
+	FindStatement(ctx context.Context, customerID uuid.UUID, period string) (*appmodel.Statement, error)
+}
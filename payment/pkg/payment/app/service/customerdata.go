@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	appmodel "payment/pkg/payment/app/model"
+)
+
+// CustomerDataService serves data subject requests, payment records are kept for accounting,
+// so customer is only replaced by pseudonym
+type CustomerDataService interface {
+	EraseCustomerData(ctx context.Context, customerID, pseudonymID uuid.UUID) (appmodel.CustomerDataErasure, error)
+}
+
+func NewCustomerDataService(luow LockableUnitOfWork) CustomerDataService {
+	return &customerDataService{luow: luow}
+}
+
+type customerDataService struct {
+	luow LockableUnitOfWork
+}
+
+func (s *customerDataService) EraseCustomerData(ctx context.Context, customerID, pseudonymID uuid.UUID) (appmodel.CustomerDataErasure, error) {
+	var result appmodel.CustomerDataErasure
+	err := s.luow.Execute(ctx, []string{"balance_" + customerID.String()}, func(provider RepositoryProvider) error {
+		balances, err := provider.AccountBalanceRepository(ctx).Pseudonymize(customerID, pseudonymID)
+		if err != nil {
+			return err
+		}
+		transactions, err := provider.PaymentRepository(ctx).Pseudonymize(customerID, pseudonymID)
+		if err != nil {
+			return err
+		}
+		statements, err := provider.StatementRepository(ctx).Pseudonymize(customerID, pseudonymID)
+		if err != nil {
+			return err
+		}
+		result.PseudonymizedRecords = balances + transactions + statements
+		return nil
+	})
+	return result, err
+}
@@ -15,6 +15,10 @@ import (
 
 type PaymentService interface {
 	StoreUserBalance(ctx context.Context, balance appmodel.CustomerBalance) (uuid.UUID, error)
+	// CreateCustomerBalance opens customer account and credits welcome bonus once
+	CreateCustomerBalance(ctx context.Context, customerID uuid.UUID, bonus float64) (uuid.UUID, error)
+	// CloseCustomerBalance deletes customer account unless customer already paid with it
+	CloseCustomerBalance(ctx context.Context, customerID uuid.UUID) error
 }
 
 func NewPaymentService(
@@ -48,6 +52,32 @@ func (p *paymentService) StoreUserBalance(ctx context.Context, balance appmodel.
 	return balanceID, err
 }
 
+func (p *paymentService) CreateCustomerBalance(ctx context.Context, customerID uuid.UUID, bonus float64) (uuid.UUID, error) {
+	var balanceID uuid.UUID
+	err := p.luow.Execute(ctx, []string{"balance_" + customerID.String()}, func(provider RepositoryProvider) error {
+		domainService := p.domainService(ctx, provider.PaymentRepository(ctx), provider.AccountBalanceRepository(ctx))
+
+		domainBalanceID, createErr := domainService.CreateCustomerBalance(customerID)
+		balanceID = domainBalanceID
+		if errors.Is(createErr, service.ErrBalanceExisted) {
+			return nil
+		}
+		if createErr != nil || bonus == 0 {
+			return createErr
+		}
+
+		_, createErr = domainService.CreateBonus(customerID, bonus)
+		return createErr
+	})
+	return balanceID, err
+}
+
+func (p *paymentService) CloseCustomerBalance(ctx context.Context, customerID uuid.UUID) error {
+	return p.luow.Execute(ctx, []string{"balance_" + customerID.String()}, func(provider RepositoryProvider) error {
+		return p.domainService(ctx, provider.PaymentRepository(ctx), provider.AccountBalanceRepository(ctx)).CloseCustomerBalance(customerID)
+	})
+}
+
 func (p *paymentService) domainService(
 	ctx context.Context,
 	paymentRepo model.PaymentRepository,
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+
+	"payment/pkg/common/domain"
+	"payment/pkg/payment/domain/model"
+	"payment/pkg/payment/domain/service"
+)
+
+type StatementService interface {
+	GenerateStatement(ctx context.Context, customerID uuid.UUID, period string) (uuid.UUID, error)
+}
+
+func NewStatementService(
+	luow LockableUnitOfWork,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+) StatementService {
+	return &statementService{
+		luow:            luow,
+		eventDispatcher: eventDispatcher,
+	}
+}
+
+type statementService struct {
+	luow            LockableUnitOfWork
+	eventDispatcher outbox.EventDispatcher[outbox.Event]
+}
+
+func (s *statementService) GenerateStatement(ctx context.Context, customerID uuid.UUID, period string) (uuid.UUID, error) {
+	statementPeriod, err := model.ParseStatementPeriod(period)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	var statementID uuid.UUID
+	err = s.luow.Execute(ctx, []string{"statement_" + customerID.String()}, func(provider RepositoryProvider) error {
+		domainService := service.NewStatementService(
+			provider.PaymentRepository(ctx),
+			provider.AccountBalanceRepository(ctx),
+			provider.StatementRepository(ctx),
+			s.domainEventDispatcher(ctx),
+		)
+
+		statement, generateErr := domainService.GenerateStatement(customerID, statementPeriod)
+		if generateErr != nil {
+			return generateErr
+		}
+		statementID = statement.ID
+		return nil
+	})
+	return statementID, err
+}
+
+func (s *statementService) domainEventDispatcher(ctx context.Context) domain.EventDispatcher {
+	return &domainEventDispatcher{
+		ctx:             ctx,
+		eventDispatcher: s.eventDispatcher,
+	}
+}
@@ -9,6 +9,7 @@ import (
 type RepositoryProvider interface {
 	PaymentRepository(ctx context.Context) model.PaymentRepository
 	AccountBalanceRepository(ctx context.Context) model.CustomerBalanceRepository
+	StatementRepository(ctx context.Context) model.StatementRepository
 }
 
 type LockableUnitOfWork interface {
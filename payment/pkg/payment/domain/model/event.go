@@ -45,3 +45,37 @@ type CustomerAccountCreated struct {
 func (e CustomerAccountCreated) Type() string {
 	return "customer_account_created"
 }
+
+type CustomerAccountClosed struct {
+	CustomerID uuid.UUID
+	ClosedAt   time.Time
+}
+
+func (e CustomerAccountClosed) Type() string {
+	return "customer_account_closed"
+}
+
+type CustomerStatementGenerated struct {
+	StatementID    uuid.UUID
+	CustomerID     uuid.UUID
+	Period         string
+	Version        int
+	OpeningBalance float64
+	ClosingBalance float64
+	GeneratedAt    time.Time
+}
+
+func (e CustomerStatementGenerated) Type() string {
+	return "customer_statement_generated"
+}
+
+type BonusCreated struct {
+	TransactionID uuid.UUID
+	CustomerID    uuid.UUID
+	Amount        float64
+	PaymentDate   time.Time
+}
+
+func (e BonusCreated) Type() string {
+	return "bonus_created"
+}
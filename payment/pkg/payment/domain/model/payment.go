@@ -17,6 +17,7 @@ type TransactionType int
 const (
 	New TransactionType = iota
 	Refund
+	Bonus
 )
 
 type CustomerAccountBalance struct {
@@ -40,9 +41,18 @@ type PaymentRepository interface {
 	NextID() (uuid.UUID, error)
 	Store(transaction *Transaction) error
 	Find(id uuid.UUID) (*Transaction, error)
+	// ListByCustomer returns customer transactions with payment date in [from, to) ordered by payment date
+	ListByCustomer(customerID uuid.UUID, from, to time.Time) ([]Transaction, error)
+	// Pseudonymize replaces customer of all customer transactions with pseudonym and returns number of changed transactions
+	Pseudonymize(customerID, pseudonymID uuid.UUID) (int, error)
+	// DeleteByCustomer returns number of deleted transactions
+	DeleteByCustomer(customerID uuid.UUID) (int, error)
 }
 
 type CustomerBalanceRepository interface {
 	Store(balance CustomerAccountBalance) (uuid.UUID, error)
 	Find(customerID uuid.UUID) (*CustomerAccountBalance, error)
+	Delete(customerID uuid.UUID) error
+	// Pseudonymize replaces customer of the balance with pseudonym and returns number of changed balances
+	Pseudonymize(customerID, pseudonymID uuid.UUID) (int, error)
 }
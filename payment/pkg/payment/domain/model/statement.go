@@ -0,0 +1,92 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrStatementNotFound      = errors.New("statement not found")
+	ErrInvalidStatementPeriod = errors.New("invalid statement period")
+)
+
+const statementPeriodLayout = "2006-01"
+
+// StatementPeriod is a calendar month in UTC
+type StatementPeriod struct {
+	Year  int
+	Month time.Month
+}
+
+func ParseStatementPeriod(s string) (StatementPeriod, error) {
+	t, err := time.Parse(statementPeriodLayout, s)
+	if err != nil {
+		return StatementPeriod{}, fmt.Errorf("%w: %q", ErrInvalidStatementPeriod, s)
+	}
+	return StatementPeriod{Year: t.Year(), Month: t.Month()}, nil
+}
+
+func StatementPeriodOf(t time.Time) StatementPeriod {
+	t = t.UTC()
+	return StatementPeriod{Year: t.Year(), Month: t.Month()}
+}
+
+func (p StatementPeriod) Start() time.Time {
+	return time.Date(p.Year, p.Month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func (p StatementPeriod) End() time.Time {
+	return p.Start().AddDate(0, 1, 0)
+}
+
+func (p StatementPeriod) Previous() StatementPeriod {
+	return StatementPeriodOf(p.Start().AddDate(0, -1, 0))
+}
+
+func (p StatementPeriod) String() string {
+	return p.Start().Format(statementPeriodLayout)
+}
+
+// SignedAmount returns transaction amount as it affects customer balance
+func (t Transaction) SignedAmount() float64 {
+	if t.Type == New {
+		return -t.Amount
+	}
+	return t.Amount
+}
+
+// Statement is an immutable snapshot of customer account activity for a period.
+// Regenerating a period stores a new version instead of changing the existing one.
+type Statement struct {
+	ID             uuid.UUID
+	CustomerID     uuid.UUID
+	Period         StatementPeriod
+	Version        int
+	OpeningBalance float64
+	ClosingBalance float64
+	TotalCharges   float64
+	TotalRefunds   float64
+	TotalBonuses   float64
+	Lines          []StatementLine
+	CreatedAt      time.Time
+}
+
+type StatementLine struct {
+	TransactionID uuid.UUID
+	OrderID       uuid.UUID
+	Type          TransactionType
+	Amount        float64
+	PaymentDate   time.Time
+}
+
+type StatementRepository interface {
+	NextID() (uuid.UUID, error)
+	Store(statement *Statement) error
+	// FindLatest returns statement with the highest version for customer and period
+	FindLatest(customerID uuid.UUID, period StatementPeriod) (*Statement, error)
+	// Pseudonymize replaces customer of all customer statements with pseudonym and returns number of changed statements
+	Pseudonymize(customerID, pseudonymID uuid.UUID) (int, error)
+}
@@ -14,13 +14,17 @@ var (
 	ErrAddingNegativeAmount = errors.New("adding negative amount")
 	ErrNotEnoughAmount      = errors.New("not enough amount")
 	ErrBalanceExisted       = errors.New("balance existed")
+	ErrBalanceInUse         = errors.New("balance in use")
 )
 
 type PaymentService interface {
 	CreateTransaction(orderID uuid.UUID, customerID uuid.UUID, amount float64) (uuid.UUID, error)
 	CreateRefund(orderID uuid.UUID, customerID uuid.UUID, amount float64) (uuid.UUID, error)
+	CreateBonus(customerID uuid.UUID, amount float64) (uuid.UUID, error)
 
 	CreateCustomerBalance(customerID uuid.UUID) (uuid.UUID, error)
+	// CloseCustomerBalance deletes balance with bonuses, balance with payments or refunds is kept and ErrBalanceInUse returned
+	CloseCustomerBalance(customerID uuid.UUID) error
 	UpdateBalance(customerID uuid.UUID, amount float64) error
 }
 
@@ -134,6 +138,52 @@ func (p paymentService) CreateRefund(orderID, customerID uuid.UUID, amount float
 	})
 }
 
+func (p paymentService) CreateBonus(customerID uuid.UUID, amount float64) (uuid.UUID, error) {
+	if amount < 0 {
+		return uuid.Nil, ErrAddingNegativeAmount
+	}
+
+	balance, err := p.balanceRepo.Find(customerID)
+	if err != nil {
+		return uuid.Nil, model.ErrBalanceNotFound
+	}
+
+	currentTime := time.Now()
+	_, err = p.balanceRepo.Store(model.CustomerAccountBalance{
+		ID:         balance.ID,
+		CustomerID: customerID,
+		Amount:     balance.Amount + amount,
+		CreatedAt:  balance.CreatedAt,
+		UpdatedAt:  &currentTime,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	transactionID, err := p.paymentRepo.NextID()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	err = p.paymentRepo.Store(&model.Transaction{
+		ID:          transactionID,
+		CustomerID:  customerID,
+		Type:        model.Bonus,
+		Amount:      amount,
+		PaymentDate: currentTime,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return transactionID, p.dispatcher.Dispatch(&model.BonusCreated{
+		TransactionID: transactionID,
+		CustomerID:    customerID,
+		Amount:        amount,
+		PaymentDate:   currentTime,
+	})
+}
+
 func (p paymentService) CreateCustomerBalance(customerID uuid.UUID) (uuid.UUID, error) {
 	balance, err := p.balanceRepo.Find(customerID)
 	if err == nil {
@@ -157,6 +207,42 @@ func (p paymentService) CreateCustomerBalance(customerID uuid.UUID) (uuid.UUID,
 	})
 }
 
+func (p paymentService) CloseCustomerBalance(customerID uuid.UUID) error {
+	balance, err := p.balanceRepo.Find(customerID)
+	if errors.Is(err, model.ErrBalanceNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	currentTime := time.Now()
+	// transactions are created after balance and are not dated in future
+	transactions, err := p.paymentRepo.ListByCustomer(customerID, balance.CreatedAt, currentTime.Add(time.Hour))
+	if err != nil {
+		return err
+	}
+	for _, transaction := range transactions {
+		if transaction.Type != model.Bonus {
+			return ErrBalanceInUse
+		}
+	}
+
+	_, err = p.paymentRepo.DeleteByCustomer(customerID)
+	if err != nil {
+		return err
+	}
+	err = p.balanceRepo.Delete(customerID)
+	if err != nil {
+		return err
+	}
+
+	return p.dispatcher.Dispatch(&model.CustomerAccountClosed{
+		CustomerID: customerID,
+		ClosedAt:   currentTime,
+	})
+}
+
 func (p paymentService) UpdateBalance(customerID uuid.UUID, amount float64) error {
 	if amount < 0 {
 		return ErrAddingNegativeAmount
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"payment/pkg/common/domain"
+	"payment/pkg/payment/domain/model"
+)
+
+var ErrStatementPeriodNotClosed = errors.New("statement period is not closed yet")
+
+type StatementService interface {
+	GenerateStatement(customerID uuid.UUID, period model.StatementPeriod) (*model.Statement, error)
+}
+
+func NewStatementService(
+	paymentRepo model.PaymentRepository,
+	balanceRepo model.CustomerBalanceRepository,
+	statementRepo model.StatementRepository,
+	dispatcher domain.EventDispatcher,
+) StatementService {
+	return &statementService{
+		paymentRepo:   paymentRepo,
+		balanceRepo:   balanceRepo,
+		statementRepo: statementRepo,
+		dispatcher:    dispatcher,
+	}
+}
+
+type statementService struct {
+	paymentRepo   model.PaymentRepository
+	balanceRepo   model.CustomerBalanceRepository
+	statementRepo model.StatementRepository
+	dispatcher    domain.EventDispatcher
+}
+
+func (s statementService) GenerateStatement(customerID uuid.UUID, period model.StatementPeriod) (*model.Statement, error) {
+	currentTime := time.Now()
+	if period.End().After(currentTime) {
+		return nil, ErrStatementPeriodNotClosed
+	}
+
+	openingBalance, err := s.openingBalance(customerID, period, currentTime)
+	if err != nil {
+		return nil, err
+	}
+
+	version := 1
+	existing, err := s.statementRepo.FindLatest(customerID, period)
+	switch {
+	case err == nil:
+		version = existing.Version + 1
+	case !errors.Is(err, model.ErrStatementNotFound):
+		return nil, err
+	}
+
+	transactions, err := s.paymentRepo.ListByCustomer(customerID, period.Start(), period.End())
+	if err != nil {
+		return nil, err
+	}
+
+	statementID, err := s.statementRepo.NextID()
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &model.Statement{
+		ID:             statementID,
+		CustomerID:     customerID,
+		Period:         period,
+		Version:        version,
+		OpeningBalance: openingBalance,
+		ClosingBalance: openingBalance,
+		Lines:          make([]model.StatementLine, 0, len(transactions)),
+		CreatedAt:      currentTime,
+	}
+	for _, transaction := range transactions {
+		switch transaction.Type {
+		case model.New:
+			statement.TotalCharges += transaction.Amount
+		case model.Refund:
+			statement.TotalRefunds += transaction.Amount
+		case model.Bonus:
+			statement.TotalBonuses += transaction.Amount
+		}
+		statement.ClosingBalance += transaction.SignedAmount()
+		statement.Lines = append(statement.Lines, model.StatementLine{
+			TransactionID: transaction.ID,
+			OrderID:       transaction.OrderID,
+			Type:          transaction.Type,
+			Amount:        transaction.Amount,
+			PaymentDate:   transaction.PaymentDate,
+		})
+	}
+
+	err = s.statementRepo.Store(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	return statement, s.dispatcher.Dispatch(&model.CustomerStatementGenerated{
+		StatementID:    statement.ID,
+		CustomerID:     customerID,
+		Period:         period.String(),
+		Version:        version,
+		OpeningBalance: statement.OpeningBalance,
+		ClosingBalance: statement.ClosingBalance,
+		GeneratedAt:    currentTime,
+	})
+}
+
+// openingBalance takes closing balance of the previous statement. First statement is seeded from stored balance,
+// as it is also set directly without transactions, transactions since the period start are rolled back from it.
+// Whole history before the period is summed up if balance is already closed
+func (s statementService) openingBalance(customerID uuid.UUID, period model.StatementPeriod, currentTime time.Time) (float64, error) {
+	previous, err := s.statementRepo.FindLatest(customerID, period.Previous())
+	if err == nil {
+		return previous.ClosingBalance, nil
+	}
+	if !errors.Is(err, model.ErrStatementNotFound) {
+		return 0, err
+	}
+
+	balance, err := s.balanceRepo.Find(customerID)
+	if err == nil {
+		// transactions are not dated in future
+		transactions, listErr := s.paymentRepo.ListByCustomer(customerID, period.Start(), currentTime.Add(time.Hour))
+		if listErr != nil {
+			return 0, listErr
+		}
+		amount := balance.Amount
+		for _, transaction := range transactions {
+			amount -= transaction.SignedAmount()
+		}
+		return amount, nil
+	}
+	if !errors.Is(err, model.ErrBalanceNotFound) {
+		return 0, err
+	}
+
+	transactions, err := s.paymentRepo.ListByCustomer(customerID, time.Time{}, period.Start())
+	if err != nil {
+		return 0, err
+	}
+	var amount float64
+	for _, transaction := range transactions {
+		amount += transaction.SignedAmount()
+	}
+	return amount, nil
+}
@@ -1,7 +1,9 @@
 package tests
 
 import (
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -60,6 +62,53 @@ func TestPaymentService(t *testing.T) {
 		require.Equal(t, model.CustomerAccountCreated{}.Type(), eventDispatcher.events[0].Type())
 	})
 
+	t.Run("Close customer balance with bonus", func(t *testing.T) {
+		t.Cleanup(func() {
+			eventDispatcher.Reset()
+			paymentRepo.Reset()
+			balanceRepo.Reset()
+		})
+		_, err := paymentService.CreateCustomerBalance(customerID)
+		require.NoError(t, err)
+		_, err = paymentService.CreateBonus(customerID, 100.0)
+		require.NoError(t, err)
+		eventDispatcher.Reset()
+
+		err = paymentService.CloseCustomerBalance(customerID)
+		require.NoError(t, err)
+
+		_, err = balanceRepo.Find(customerID)
+		require.ErrorIs(t, err, model.ErrBalanceNotFound)
+		require.Empty(t, paymentRepo.store)
+		require.Len(t, eventDispatcher.events, 1)
+		require.Equal(t, model.CustomerAccountClosed{}.Type(), eventDispatcher.events[0].Type())
+
+		// closing is repeated by retries
+		require.NoError(t, paymentService.CloseCustomerBalance(customerID))
+	})
+
+	t.Run("Close customer balance in use", func(t *testing.T) {
+		t.Cleanup(func() {
+			eventDispatcher.Reset()
+			paymentRepo.Reset()
+			balanceRepo.Reset()
+		})
+		_, err := paymentService.CreateCustomerBalance(customerID)
+		require.NoError(t, err)
+		_, err = paymentService.CreateBonus(customerID, 100.0)
+		require.NoError(t, err)
+		_, err = paymentService.CreateTransaction(orderID, customerID, 10.0)
+		require.NoError(t, err)
+		eventDispatcher.Reset()
+
+		err = paymentService.CloseCustomerBalance(customerID)
+		require.ErrorIs(t, err, service.ErrBalanceInUse)
+
+		_, err = balanceRepo.Find(customerID)
+		require.NoError(t, err)
+		require.Len(t, eventDispatcher.events, 0)
+	})
+
 	t.Run("Add amount to balance", func(t *testing.T) {
 		t.Cleanup(func() {
 			eventDispatcher.Reset()
@@ -192,6 +241,32 @@ func TestPaymentService(t *testing.T) {
 		require.Equal(t, customerID, e.CustomerID)
 	})
 
+	t.Run("Create bonus", func(t *testing.T) {
+		t.Cleanup(func() {
+			eventDispatcher.Reset()
+			paymentRepo.Reset()
+			balanceRepo.Reset()
+		})
+		_, err := paymentService.CreateCustomerBalance(customerID)
+		require.NoError(t, err)
+		eventDispatcher.Reset()
+
+		transactionID, err := paymentService.CreateBonus(customerID, 100.0)
+		require.NoError(t, err)
+
+		transaction, err := paymentRepo.Find(transactionID)
+		require.NoError(t, err)
+		require.Equal(t, model.Bonus, transaction.Type)
+		require.Equal(t, uuid.Nil, transaction.OrderID)
+
+		balance, err := balanceRepo.Find(customerID)
+		require.NoError(t, err)
+		require.Equal(t, 100.0, balance.Amount)
+
+		require.Len(t, eventDispatcher.events, 1)
+		require.Equal(t, model.BonusCreated{}.Type(), eventDispatcher.events[0].Type())
+	})
+
 	t.Run("Create transaction when customer balance not found", func(t *testing.T) {
 		t.Cleanup(func() {
 			eventDispatcher.Reset()
@@ -235,6 +310,134 @@ func TestPaymentService(t *testing.T) {
 	})
 }
 
+func TestStatementService(t *testing.T) {
+	paymentRepo := &mockPaymentRepository{
+		store: make(map[uuid.UUID]*model.Transaction),
+	}
+	balanceRepo := &mockCustomerBalanceRepository{
+		store: make(map[uuid.UUID]*model.CustomerAccountBalance),
+	}
+	statementRepo := &mockStatementRepository{
+		store: make([]*model.Statement, 0),
+	}
+	eventDispatcher := &mockEventDispatcher{
+		events: make([]domain.Event, 0),
+	}
+
+	statementService := service.NewStatementService(paymentRepo, balanceRepo, statementRepo, eventDispatcher)
+
+	customerID := uuid.Must(uuid.NewV7())
+	period := model.StatementPeriod{Year: 2025, Month: time.March}
+	storeTransaction := func(transactionType model.TransactionType, amount float64, date time.Time) {
+		err := paymentRepo.Store(&model.Transaction{
+			ID:          uuid.Must(uuid.NewV7()),
+			OrderID:     uuid.Must(uuid.NewV7()),
+			CustomerID:  customerID,
+			Type:        transactionType,
+			Amount:      amount,
+			PaymentDate: date,
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("Generate statement from transaction history", func(t *testing.T) {
+		t.Cleanup(func() {
+			eventDispatcher.Reset()
+			paymentRepo.Reset()
+			statementRepo.Reset()
+		})
+		storeTransaction(model.Bonus, 100, time.Date(2025, time.January, 10, 0, 0, 0, 0, time.UTC))
+		storeTransaction(model.New, 30, time.Date(2025, time.February, 28, 23, 59, 0, 0, time.UTC))
+		storeTransaction(model.New, 50, time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC))
+		storeTransaction(model.Refund, 20, time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC))
+		storeTransaction(model.Bonus, 5, time.Date(2025, time.March, 31, 23, 59, 0, 0, time.UTC))
+		storeTransaction(model.New, 10, time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC))
+
+		statement, err := statementService.GenerateStatement(customerID, period)
+		require.NoError(t, err)
+		require.Equal(t, 1, statement.Version)
+		require.Equal(t, 70.0, statement.OpeningBalance)
+		require.Equal(t, 45.0, statement.ClosingBalance)
+		require.Equal(t, 50.0, statement.TotalCharges)
+		require.Equal(t, 20.0, statement.TotalRefunds)
+		require.Equal(t, 5.0, statement.TotalBonuses)
+		require.Len(t, statement.Lines, 3)
+		require.Equal(t, model.New, statement.Lines[0].Type)
+
+		require.Len(t, eventDispatcher.events, 1)
+		require.Equal(t, model.CustomerStatementGenerated{}.Type(), eventDispatcher.events[0].Type())
+	})
+
+	t.Run("Opening balance of first statement is seeded from stored balance", func(t *testing.T) {
+		t.Cleanup(func() {
+			eventDispatcher.Reset()
+			paymentRepo.Reset()
+			balanceRepo.Reset()
+			statementRepo.Reset()
+		})
+		// balance set directly has no transactions, so only charge and refund since the period start are rolled back
+		_, err := balanceRepo.Store(model.CustomerAccountBalance{ID: uuid.Must(uuid.NewV7()), CustomerID: customerID, Amount: 500})
+		require.NoError(t, err)
+		storeTransaction(model.Bonus, 100, time.Date(2025, time.January, 10, 0, 0, 0, 0, time.UTC))
+		storeTransaction(model.New, 50, time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC))
+		storeTransaction(model.Refund, 20, time.Date(2025, time.April, 15, 0, 0, 0, 0, time.UTC))
+
+		statement, err := statementService.GenerateStatement(customerID, period)
+		require.NoError(t, err)
+		require.Equal(t, 530.0, statement.OpeningBalance)
+		require.Equal(t, 480.0, statement.ClosingBalance)
+	})
+
+	t.Run("Opening balance is taken from previous statement", func(t *testing.T) {
+		t.Cleanup(func() {
+			eventDispatcher.Reset()
+			paymentRepo.Reset()
+			statementRepo.Reset()
+		})
+		require.NoError(t, statementRepo.Store(&model.Statement{
+			ID:             uuid.Must(uuid.NewV7()),
+			CustomerID:     customerID,
+			Period:         period.Previous(),
+			Version:        1,
+			ClosingBalance: 42,
+		}))
+		storeTransaction(model.New, 2, time.Date(2025, time.March, 2, 0, 0, 0, 0, time.UTC))
+
+		statement, err := statementService.GenerateStatement(customerID, period)
+		require.NoError(t, err)
+		require.Equal(t, 42.0, statement.OpeningBalance)
+		require.Equal(t, 40.0, statement.ClosingBalance)
+	})
+
+	t.Run("Regenerate statement stores new version", func(t *testing.T) {
+		t.Cleanup(func() {
+			eventDispatcher.Reset()
+			paymentRepo.Reset()
+			statementRepo.Reset()
+		})
+		first, err := statementService.GenerateStatement(customerID, period)
+		require.NoError(t, err)
+		second, err := statementService.GenerateStatement(customerID, period)
+		require.NoError(t, err)
+
+		require.NotEqual(t, first.ID, second.ID)
+		require.Equal(t, 2, second.Version)
+		require.Len(t, statementRepo.store, 2)
+	})
+
+	t.Run("Generate statement for open period", func(t *testing.T) {
+		t.Cleanup(func() {
+			eventDispatcher.Reset()
+			paymentRepo.Reset()
+			statementRepo.Reset()
+		})
+		_, err := statementService.GenerateStatement(customerID, model.StatementPeriodOf(time.Now()))
+		require.ErrorIs(t, err, service.ErrStatementPeriodNotClosed)
+
+		require.Len(t, eventDispatcher.events, 0)
+	})
+}
+
 var _ model.PaymentRepository = &mockPaymentRepository{}
 
 type mockPaymentRepository struct {
@@ -258,6 +461,42 @@ func (m *mockPaymentRepository) Find(id uuid.UUID) (*model.Transaction, error) {
 	return transaction, nil
 }
 
+func (m *mockPaymentRepository) ListByCustomer(customerID uuid.UUID, from, to time.Time) ([]model.Transaction, error) {
+	transactions := make([]model.Transaction, 0)
+	for _, transaction := range m.store {
+		if transaction.CustomerID != customerID || transaction.PaymentDate.Before(from) || !transaction.PaymentDate.Before(to) {
+			continue
+		}
+		transactions = append(transactions, *transaction)
+	}
+	sort.Slice(transactions, func(i, j int) bool {
+		return transactions[i].PaymentDate.Before(transactions[j].PaymentDate)
+	})
+	return transactions, nil
+}
+
+func (m *mockPaymentRepository) Pseudonymize(customerID, pseudonymID uuid.UUID) (int, error) {
+	changed := 0
+	for _, transaction := range m.store {
+		if transaction.CustomerID == customerID {
+			transaction.CustomerID = pseudonymID
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+func (m *mockPaymentRepository) DeleteByCustomer(customerID uuid.UUID) (int, error) {
+	deleted := 0
+	for id, transaction := range m.store {
+		if transaction.CustomerID == customerID {
+			delete(m.store, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
 func (m *mockPaymentRepository) Delete(id uuid.UUID) error {
 	delete(m.store, id)
 	return nil
@@ -281,15 +520,77 @@ func (m *mockCustomerBalanceRepository) Store(balance model.CustomerAccountBalan
 func (m *mockCustomerBalanceRepository) Find(customerID uuid.UUID) (*model.CustomerAccountBalance, error) {
 	balance, ok := m.store[customerID]
 	if !ok {
-		return nil, model.ErrPaymentNotFound
+		return nil, model.ErrBalanceNotFound
 	}
 	return balance, nil
 }
 
+func (m *mockCustomerBalanceRepository) Delete(customerID uuid.UUID) error {
+	delete(m.store, customerID)
+	return nil
+}
+
+func (m *mockCustomerBalanceRepository) Pseudonymize(customerID, pseudonymID uuid.UUID) (int, error) {
+	balance, ok := m.store[customerID]
+	if !ok {
+		return 0, nil
+	}
+	delete(m.store, customerID)
+	balance.CustomerID = pseudonymID
+	m.store[pseudonymID] = balance
+	return 1, nil
+}
+
 func (m *mockCustomerBalanceRepository) Reset() {
 	m.store = make(map[uuid.UUID]*model.CustomerAccountBalance)
 }
 
+var _ model.StatementRepository = &mockStatementRepository{}
+
+type mockStatementRepository struct {
+	store []*model.Statement
+}
+
+func (m *mockStatementRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (m *mockStatementRepository) Store(statement *model.Statement) error {
+	m.store = append(m.store, statement)
+	return nil
+}
+
+func (m *mockStatementRepository) FindLatest(customerID uuid.UUID, period model.StatementPeriod) (*model.Statement, error) {
+	var latest *model.Statement
+	for _, statement := range m.store {
+		if statement.CustomerID != customerID || statement.Period != period {
+			continue
+		}
+		if latest == nil || statement.Version > latest.Version {
+			latest = statement
+		}
+	}
+	if latest == nil {
+		return nil, model.ErrStatementNotFound
+	}
+	return latest, nil
+}
+
+func (m *mockStatementRepository) Pseudonymize(customerID, pseudonymID uuid.UUID) (int, error) {
+	changed := 0
+	for _, statement := range m.store {
+		if statement.CustomerID == customerID {
+			statement.CustomerID = pseudonymID
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+func (m *mockStatementRepository) Reset() {
+	m.store = make([]*model.Statement, 0)
+}
+
 type mockEventDispatcher struct {
 	events []domain.Event
 }
@@ -23,12 +23,37 @@ func (s eventSerializer) Serialize(event outbox.Event) (string, error) {
 			CreatedAt:  e.CreatedAt.Unix(),
 		})
 		return string(b), errors.WithStack(err)
+	case *model.CustomerAccountClosed:
+		b, err := json.Marshal(AccountClosed{
+			CustomerID: e.CustomerID.String(),
+			ClosedAt:   e.ClosedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
 	case *model.CustomerAmountUpdated:
 		b, err := json.Marshal(AccountUpdated{
 			CustomerID: e.CustomerID.String(),
 			NewAmount:  e.NewAmount,
 		})
 		return string(b), errors.WithStack(err)
+	case *model.BonusCreated:
+		b, err := json.Marshal(BonusCreated{
+			TransactionID: e.TransactionID.String(),
+			CustomerID:    e.CustomerID.String(),
+			Amount:        e.Amount,
+			PaymentDate:   e.PaymentDate.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.CustomerStatementGenerated:
+		b, err := json.Marshal(StatementGenerated{
+			StatementID:    e.StatementID.String(),
+			CustomerID:     e.CustomerID.String(),
+			Period:         e.Period,
+			Version:        e.Version,
+			OpeningBalance: e.OpeningBalance,
+			ClosingBalance: e.ClosingBalance,
+			GeneratedAt:    e.GeneratedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
 	default:
 		return "", errors.Errorf("unknown event %q", event.Type())
 	}
@@ -39,7 +64,29 @@ type AccountCreated struct {
 	CreatedAt  int64  `json:"created_at"`
 }
 
+type AccountClosed struct {
+	CustomerID string `json:"customer_id"`
+	ClosedAt   int64  `json:"closed_at"`
+}
+
 type AccountUpdated struct {
 	CustomerID string  `json:"customer_id"`
 	NewAmount  float64 `json:"new_amount"`
 }
+
+type BonusCreated struct {
+	TransactionID string  `json:"transaction_id"`
+	CustomerID    string  `json:"customer_id"`
+	Amount        float64 `json:"amount"`
+	PaymentDate   int64   `json:"payment_date"`
+}
+
+type StatementGenerated struct {
+	StatementID    string  `json:"statement_id"`
+	CustomerID     string  `json:"customer_id"`
+	Period         string  `json:"period"`
+	Version        int     `json:"version"`
+	OpeningBalance float64 `json:"opening_balance"`
+	ClosingBalance float64 `json:"closing_balance"`
+	GeneratedAt    int64   `json:"generated_at"`
+}
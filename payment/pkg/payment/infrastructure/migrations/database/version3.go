@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion3(client mysql.ClientContext) migrator.Migration {
+	return &version3{
+		client: client,
+	}
+}
+
+type version3 struct {
+	client mysql.ClientContext
+}
+
+func (v version3) Version() int64 {
+	return 3
+}
+
+func (v version3) Description() string {
+	return "Create 'customer_statement' and 'customer_statement_line' tables"
+}
+
+func (v version3) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE customer_statement
+		(
+			id              BINARY(16)     NOT NULL PRIMARY KEY,
+			customer_id     BINARY(16)     NOT NULL,
+			period          CHAR(7)        NOT NULL COMMENT 'YYYY-MM',
+			version         INT            NOT NULL,
+			opening_balance DECIMAL(10, 2) NOT NULL,
+			closing_balance DECIMAL(10, 2) NOT NULL,
+			total_charges   DECIMAL(10, 2) NOT NULL,
+			total_refunds   DECIMAL(10, 2) NOT NULL,
+			total_bonuses   DECIMAL(10, 2) NOT NULL,
+			created_at      DATETIME       NOT NULL,
+			UNIQUE KEY customer_period_version (customer_id, period, version)
+		) ENGINE = InnoDB
+		  DEFAULT CHARSET = utf8mb4
+		  COLLATE = utf8mb4_unicode_ci;
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE customer_statement_line
+		(
+			statement_id   BINARY(16)     NOT NULL,
+			line_no        INT            NOT NULL,
+			transaction_id BINARY(16)     NOT NULL,
+			order_id       BINARY(16)     NOT NULL,
+			type           TINYINT        NOT NULL COMMENT '0: New, 1: Refund, 2: Bonus',
+			amount         DECIMAL(10, 2) NOT NULL,
+			payment_date   DATETIME       NOT NULL,
+			PRIMARY KEY (statement_id, line_no)
+		) ENGINE = InnoDB
+		  DEFAULT CHARSET = utf8mb4
+		  COLLATE = utf8mb4_unicode_ci;
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE INDEX transaction_customer_id_payment_date_index ON transaction (customer_id, payment_date)
+	`)
+	return errors.WithStack(err)
+}
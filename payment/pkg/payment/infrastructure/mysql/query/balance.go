@@ -47,3 +47,13 @@ func (a accountQueryService) FindBalance(ctx context.Context, id uuid.UUID) (*ap
 		Amount:     account.Amount,
 	}, nil
 }
+
+func (a accountQueryService) ListCustomerIDs(ctx context.Context) ([]uuid.UUID, error) {
+	var customerIDs []uuid.UUID
+	err := a.client.SelectContext(
+		ctx,
+		&customerIDs,
+		`SELECT customer_id FROM customer_account_balance ORDER BY customer_id`,
+	)
+	return customerIDs, errors.WithStack(err)
+}
@@ -0,0 +1,115 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appmodel "payment/pkg/payment/app/model"
+	"payment/pkg/payment/app/query"
+	"payment/pkg/payment/domain/model"
+)
+
+func NewCustomerDataQueryService(client mysql.ClientContext) query.CustomerDataQueryService {
+	return &customerDataQueryService{
+		client: client,
+	}
+}
+
+type customerDataQueryService struct {
+	client mysql.ClientContext
+}
+
+func (s customerDataQueryService) ExportCustomerData(ctx context.Context, customerID uuid.UUID) (appmodel.CustomerData, error) {
+	var result appmodel.CustomerData
+
+	var amount float64
+	err := s.client.GetContext(
+		ctx,
+		&amount,
+		`SELECT amount FROM customer_account_balance WHERE customer_id = ?`,
+		customerID[:],
+	)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return appmodel.CustomerData{}, errors.WithStack(err)
+	}
+	if err == nil {
+		result.Balance = &appmodel.CustomerBalance{
+			CustomerID: customerID,
+			Amount:     amount,
+		}
+	}
+
+	var transactions []struct {
+		ID          uuid.UUID `db:"id"`
+		OrderID     uuid.UUID `db:"order_id"`
+		Type        int       `db:"type"`
+		Amount      float64   `db:"amount"`
+		PaymentDate time.Time `db:"payment_date"`
+	}
+	err = s.client.SelectContext(
+		ctx,
+		&transactions,
+		`SELECT id, order_id, type, amount, payment_date FROM transaction WHERE customer_id = ? ORDER BY payment_date, id`,
+		customerID[:],
+	)
+	if err != nil {
+		return appmodel.CustomerData{}, errors.WithStack(err)
+	}
+	result.Transactions = make([]appmodel.StatementLine, 0, len(transactions))
+	for _, transaction := range transactions {
+		result.Transactions = append(result.Transactions, appmodel.StatementLine{
+			TransactionID: transaction.ID,
+			OrderID:       transaction.OrderID,
+			Type:          transactionTypeName(model.TransactionType(transaction.Type)),
+			Amount:        transaction.Amount,
+			PaymentDate:   transaction.PaymentDate,
+		})
+	}
+
+	var statements []struct {
+		ID             uuid.UUID `db:"id"`
+		Period         string    `db:"period"`
+		Version        int       `db:"version"`
+		OpeningBalance float64   `db:"opening_balance"`
+		ClosingBalance float64   `db:"closing_balance"`
+		TotalCharges   float64   `db:"total_charges"`
+		TotalRefunds   float64   `db:"total_refunds"`
+		TotalBonuses   float64   `db:"total_bonuses"`
+		CreatedAt      time.Time `db:"created_at"`
+	}
+	err = s.client.SelectContext(
+		ctx,
+		&statements,
+		`
+		SELECT id, period, version, opening_balance, closing_balance, total_charges, total_refunds, total_bonuses, created_at
+		FROM customer_statement
+		WHERE customer_id = ?
+		ORDER BY period, version
+		`,
+		customerID[:],
+	)
+	if err != nil {
+		return appmodel.CustomerData{}, errors.WithStack(err)
+	}
+	result.Statements = make([]appmodel.Statement, 0, len(statements))
+	for _, statement := range statements {
+		result.Statements = append(result.Statements, appmodel.Statement{
+			StatementID:    statement.ID,
+			CustomerID:     customerID,
+			Period:         statement.Period,
+			Version:        statement.Version,
+			OpeningBalance: statement.OpeningBalance,
+			ClosingBalance: statement.ClosingBalance,
+			TotalCharges:   statement.TotalCharges,
+			TotalRefunds:   statement.TotalRefunds,
+			TotalBonuses:   statement.TotalBonuses,
+			CreatedAt:      statement.CreatedAt,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appmodel "payment/pkg/payment/app/model"
+	"payment/pkg/payment/app/query"
+	"payment/pkg/payment/domain/model"
+)
+
+func NewStatementQueryService(client mysql.ClientContext) query.StatementQueryService {
+	return &statementQueryService{
+		client: client,
+	}
+}
+
+type statementQueryService struct {
+	client mysql.ClientContext
+}
+
+func (s statementQueryService) FindStatement(ctx context.Context, customerID uuid.UUID, period string) (*appmodel.Statement, error) {
+	statement := struct {
+		ID             uuid.UUID `db:"id"`
+		CustomerID     uuid.UUID `db:"customer_id"`
+		Period         string    `db:"period"`
+		Version        int       `db:"version"`
+		OpeningBalance float64   `db:"opening_balance"`
+		ClosingBalance float64   `db:"closing_balance"`
+		TotalCharges   float64   `db:"total_charges"`
+		TotalRefunds   float64   `db:"total_refunds"`
+		TotalBonuses   float64   `db:"total_bonuses"`
+		CreatedAt      time.Time `db:"created_at"`
+	}{}
+
+	err := s.client.GetContext(
+		ctx,
+		&statement,
+		`
+		SELECT id, customer_id, period, version, opening_balance, closing_balance, total_charges, total_refunds, total_bonuses, created_at
+		FROM customer_statement
+		WHERE customer_id = ? AND period = ?
+		ORDER BY version DESC
+		LIMIT 1
+		`,
+		customerID[:],
+		period,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrStatementNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	var lines []struct {
+		TransactionID uuid.UUID `db:"transaction_id"`
+		OrderID       uuid.UUID `db:"order_id"`
+		Type          int       `db:"type"`
+		Amount        float64   `db:"amount"`
+		PaymentDate   time.Time `db:"payment_date"`
+	}
+	err = s.client.SelectContext(
+		ctx,
+		&lines,
+		`
+		SELECT transaction_id, order_id, type, amount, payment_date FROM customer_statement_line
+		WHERE statement_id = ?
+		ORDER BY line_no
+		`,
+		statement.ID[:],
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := &appmodel.Statement{
+		StatementID:    statement.ID,
+		CustomerID:     statement.CustomerID,
+		Period:         statement.Period,
+		Version:        statement.Version,
+		OpeningBalance: statement.OpeningBalance,
+		ClosingBalance: statement.ClosingBalance,
+		TotalCharges:   statement.TotalCharges,
+		TotalRefunds:   statement.TotalRefunds,
+		TotalBonuses:   statement.TotalBonuses,
+		Lines:          make([]appmodel.StatementLine, 0, len(lines)),
+		CreatedAt:      statement.CreatedAt,
+	}
+	for _, line := range lines {
+		result.Lines = append(result.Lines, appmodel.StatementLine{
+			TransactionID: line.TransactionID,
+			OrderID:       line.OrderID,
+			Type:          transactionTypeName(model.TransactionType(line.Type)),
+			Amount:        line.Amount,
+			PaymentDate:   line.PaymentDate,
+		})
+	}
+	return result, nil
+}
+
+func transactionTypeName(t model.TransactionType) string {
+	switch t {
+	case model.New:
+		return "charge"
+	case model.Refund:
+		return "refund"
+	case model.Bonus:
+		return "bonus"
+	default:
+		return "unknown"
+	}
+}
@@ -75,3 +75,24 @@ func (b balanceRepository) Find(customerID uuid.UUID) (*model.CustomerAccountBal
 		UpdatedAt:  &balance.UpdatedAt,
 	}, nil
 }
+
+func (b balanceRepository) Pseudonymize(customerID, pseudonymID uuid.UUID) (int, error) {
+	result, err := b.client.ExecContext(b.ctx,
+		`UPDATE customer_account_balance SET customer_id = ? WHERE customer_id = ?`,
+		pseudonymID[:],
+		customerID[:],
+	)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	changed, err := result.RowsAffected()
+	return int(changed), errors.WithStack(err)
+}
+
+func (b balanceRepository) Delete(customerID uuid.UUID) error {
+	_, err := b.client.ExecContext(b.ctx,
+		`DELETE FROM customer_account_balance WHERE customer_id = ?`,
+		customerID[:],
+	)
+	return errors.WithStack(err)
+}
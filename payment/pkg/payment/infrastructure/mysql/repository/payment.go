@@ -31,12 +31,13 @@ func (p paymentRepository) NextID() (uuid.UUID, error) {
 func (p paymentRepository) Store(transaction *model.Transaction) error {
 	_, err := p.client.ExecContext(p.ctx,
 		`
-	INSERT INTO transaction (order_id, customer_id, type, transaction.amount, payment_date) VALUES (?, ?, ?, ?, ?, ?)
+	INSERT INTO transaction (id, order_id, customer_id, type, amount, payment_date) VALUES (?, ?, ?, ?, ?, ?)
 	ON DUPLICATE KEY UPDATE
 		amount=VALUES(amount)
 	`,
-		transaction.OrderID,
-		transaction.CustomerID,
+		transaction.ID[:],
+		transaction.OrderID[:],
+		transaction.CustomerID[:],
 		transaction.Type,
 		transaction.Amount,
 		transaction.PaymentDate,
@@ -76,3 +77,68 @@ func (p paymentRepository) Find(id uuid.UUID) (*model.Transaction, error) {
 		PaymentDate: transaction.PaymentDate,
 	}, nil
 }
+
+func (p paymentRepository) ListByCustomer(customerID uuid.UUID, from, to time.Time) ([]model.Transaction, error) {
+	var transactions []struct {
+		ID          uuid.UUID `db:"id"`
+		OrderID     uuid.UUID `db:"order_id"`
+		CustomerID  uuid.UUID `db:"customer_id"`
+		Type        int       `db:"type"`
+		Amount      float64   `db:"amount"`
+		PaymentDate time.Time `db:"payment_date"`
+	}
+
+	err := p.client.SelectContext(
+		p.ctx,
+		&transactions,
+		`
+		SELECT id, order_id, customer_id, type, amount, payment_date FROM transaction
+		WHERE customer_id = ? AND payment_date >= ? AND payment_date < ?
+		ORDER BY payment_date, id
+		`,
+		customerID[:],
+		from,
+		to,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]model.Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		result = append(result, model.Transaction{
+			ID:          transaction.ID,
+			OrderID:     transaction.OrderID,
+			CustomerID:  transaction.CustomerID,
+			Type:        model.TransactionType(transaction.Type),
+			Amount:      transaction.Amount,
+			PaymentDate: transaction.PaymentDate,
+		})
+	}
+	return result, nil
+}
+
+func (p paymentRepository) Pseudonymize(customerID, pseudonymID uuid.UUID) (int, error) {
+	result, err := p.client.ExecContext(p.ctx,
+		`UPDATE transaction SET customer_id = ? WHERE customer_id = ?`,
+		pseudonymID[:],
+		customerID[:],
+	)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	changed, err := result.RowsAffected()
+	return int(changed), errors.WithStack(err)
+}
+
+func (p paymentRepository) DeleteByCustomer(customerID uuid.UUID) (int, error) {
+	result, err := p.client.ExecContext(p.ctx,
+		`DELETE FROM transaction WHERE customer_id = ?`,
+		customerID[:],
+	)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), errors.WithStack(err)
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"payment/pkg/payment/domain/model"
+)
+
+func NewStatementRepository(ctx context.Context, client mysql.ClientContext) model.StatementRepository {
+	return &statementRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type statementRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (s statementRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+// Store only inserts, statements are never updated
+func (s statementRepository) Store(statement *model.Statement) error {
+	_, err := s.client.ExecContext(s.ctx,
+		`
+	INSERT INTO customer_statement (
+		id, customer_id, period, version, opening_balance, closing_balance,
+		total_charges, total_refunds, total_bonuses, created_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		statement.ID[:],
+		statement.CustomerID[:],
+		statement.Period.String(),
+		statement.Version,
+		statement.OpeningBalance,
+		statement.ClosingBalance,
+		statement.TotalCharges,
+		statement.TotalRefunds,
+		statement.TotalBonuses,
+		statement.CreatedAt,
+	)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for i, line := range statement.Lines {
+		_, err = s.client.ExecContext(s.ctx,
+			`
+		INSERT INTO customer_statement_line (statement_id, line_no, transaction_id, order_id, type, amount, payment_date)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		`,
+			statement.ID[:],
+			i,
+			line.TransactionID[:],
+			line.OrderID[:],
+			line.Type,
+			line.Amount,
+			line.PaymentDate,
+		)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func (s statementRepository) FindLatest(customerID uuid.UUID, period model.StatementPeriod) (*model.Statement, error) {
+	statement := struct {
+		ID             uuid.UUID `db:"id"`
+		CustomerID     uuid.UUID `db:"customer_id"`
+		Version        int       `db:"version"`
+		OpeningBalance float64   `db:"opening_balance"`
+		ClosingBalance float64   `db:"closing_balance"`
+		TotalCharges   float64   `db:"total_charges"`
+		TotalRefunds   float64   `db:"total_refunds"`
+		TotalBonuses   float64   `db:"total_bonuses"`
+		CreatedAt      time.Time `db:"created_at"`
+	}{}
+
+	err := s.client.GetContext(
+		s.ctx,
+		&statement,
+		`
+		SELECT id, customer_id, version, opening_balance, closing_balance, total_charges, total_refunds, total_bonuses, created_at
+		FROM customer_statement
+		WHERE customer_id = ? AND period = ?
+		ORDER BY version DESC
+		LIMIT 1
+		`,
+		customerID[:],
+		period.String(),
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrStatementNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	var lines []struct {
+		TransactionID uuid.UUID `db:"transaction_id"`
+		OrderID       uuid.UUID `db:"order_id"`
+		Type          int       `db:"type"`
+		Amount        float64   `db:"amount"`
+		PaymentDate   time.Time `db:"payment_date"`
+	}
+	err = s.client.SelectContext(
+		s.ctx,
+		&lines,
+		`
+		SELECT transaction_id, order_id, type, amount, payment_date FROM customer_statement_line
+		WHERE statement_id = ?
+		ORDER BY line_no
+		`,
+		statement.ID[:],
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := &model.Statement{
+		ID:             statement.ID,
+		CustomerID:     statement.CustomerID,
+		Period:         period,
+		Version:        statement.Version,
+		OpeningBalance: statement.OpeningBalance,
+		ClosingBalance: statement.ClosingBalance,
+		TotalCharges:   statement.TotalCharges,
+		TotalRefunds:   statement.TotalRefunds,
+		TotalBonuses:   statement.TotalBonuses,
+		Lines:          make([]model.StatementLine, 0, len(lines)),
+		CreatedAt:      statement.CreatedAt,
+	}
+	for _, line := range lines {
+		result.Lines = append(result.Lines, model.StatementLine{
+			TransactionID: line.TransactionID,
+			OrderID:       line.OrderID,
+			Type:          model.TransactionType(line.Type),
+			Amount:        line.Amount,
+			PaymentDate:   line.PaymentDate,
+		})
+	}
+	return result, nil
+}
+
+func (s statementRepository) Pseudonymize(customerID, pseudonymID uuid.UUID) (int, error) {
+	result, err := s.client.ExecContext(s.ctx,
+		`UPDATE customer_statement SET customer_id = ? WHERE customer_id = ?`,
+		pseudonymID[:],
+		customerID[:],
+	)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	changed, err := result.RowsAffected()
+	return int(changed), errors.WithStack(err)
+}
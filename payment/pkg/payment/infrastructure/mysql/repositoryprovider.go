@@ -25,3 +25,7 @@ func (r *repositoryProvider) PaymentRepository(ctx context.Context) model.Paymen
 func (r *repositoryProvider) AccountBalanceRepository(ctx context.Context) model.CustomerBalanceRepository {
 	return repository.NewBalanceRepository(ctx, r.client)
 }
+
+func (r *repositoryProvider) StatementRepository(ctx context.Context) model.StatementRepository {
+	return repository.NewStatementRepository(ctx, r.client)
+}
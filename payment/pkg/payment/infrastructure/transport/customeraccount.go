@@ -0,0 +1,47 @@
+package transport
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"payment/api/server/paymentpublicapi"
+	"payment/pkg/payment/domain/service"
+)
+
+// welcomeBonus is credited once to account of new customer
+const welcomeBonus = 100.00
+
+func (u paymentInternalAPI) OpenCustomerAccount(ctx context.Context, request *paymentpublicapi.CustomerAccountRequest) (*paymentpublicapi.OpenCustomerAccountResponse, error) {
+	customerID, err := uuid.Parse(request.CustomerID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.CustomerID)
+	}
+
+	balanceID, err := u.paymentService.CreateCustomerBalance(ctx, customerID, welcomeBonus)
+	if err != nil {
+		return nil, err
+	}
+	return &paymentpublicapi.OpenCustomerAccountResponse{
+		BalanceID: balanceID.String(),
+	}, nil
+}
+
+func (u paymentInternalAPI) CloseCustomerAccount(ctx context.Context, request *paymentpublicapi.CustomerAccountRequest) (*paymentpublicapi.CloseCustomerAccountResponse, error) {
+	customerID, err := uuid.Parse(request.CustomerID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.CustomerID)
+	}
+
+	err = u.paymentService.CloseCustomerBalance(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, service.ErrBalanceInUse) {
+			return nil, status.Errorf(codes.FailedPrecondition, "account of %q is in use", request.CustomerID)
+		}
+		return nil, err
+	}
+	return &paymentpublicapi.CloseCustomerAccountResponse{}, nil
+}
@@ -0,0 +1,74 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"payment/api/server/paymentpublicapi"
+)
+
+func (u paymentInternalAPI) ExportCustomerData(ctx context.Context, request *paymentpublicapi.ExportCustomerDataRequest) (*paymentpublicapi.ExportCustomerDataResponse, error) {
+	customerID, err := uuid.Parse(request.CustomerID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.CustomerID)
+	}
+
+	data, err := u.customerDataQueryService.ExportCustomerData(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &paymentpublicapi.ExportCustomerDataResponse{
+		Transactions: make([]*paymentpublicapi.StatementLine, 0, len(data.Transactions)),
+		Statements:   make([]*paymentpublicapi.GetStatementResponse, 0, len(data.Statements)),
+	}
+	if data.Balance != nil {
+		response.Balance = &data.Balance.Amount
+	}
+	for _, transaction := range data.Transactions {
+		response.Transactions = append(response.Transactions, &paymentpublicapi.StatementLine{
+			TransactionID: transaction.TransactionID.String(),
+			OrderID:       transaction.OrderID.String(),
+			Type:          transaction.Type,
+			Amount:        transaction.Amount,
+			PaymentDate:   transaction.PaymentDate.Unix(),
+		})
+	}
+	for _, statement := range data.Statements {
+		response.Statements = append(response.Statements, &paymentpublicapi.GetStatementResponse{
+			StatementID:    statement.StatementID.String(),
+			CustomerID:     statement.CustomerID.String(),
+			Period:         statement.Period,
+			Version:        int32(statement.Version), // nolint:gosec
+			OpeningBalance: statement.OpeningBalance,
+			ClosingBalance: statement.ClosingBalance,
+			TotalCharges:   statement.TotalCharges,
+			TotalRefunds:   statement.TotalRefunds,
+			TotalBonuses:   statement.TotalBonuses,
+			CreatedAt:      statement.CreatedAt.Unix(),
+		})
+	}
+	return response, nil
+}
+
+func (u paymentInternalAPI) EraseCustomerData(ctx context.Context, request *paymentpublicapi.EraseCustomerDataRequest) (*paymentpublicapi.EraseCustomerDataResponse, error) {
+	customerID, err := uuid.Parse(request.CustomerID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.CustomerID)
+	}
+	pseudonymID, err := uuid.Parse(request.PseudonymID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.PseudonymID)
+	}
+
+	erasure, err := u.customerDataService.EraseCustomerData(ctx, customerID, pseudonymID)
+	if err != nil {
+		return nil, err
+	}
+	return &paymentpublicapi.EraseCustomerDataResponse{
+		PseudonymizedRecords: int32(erasure.PseudonymizedRecords), // nolint:gosec
+	}, nil
+}
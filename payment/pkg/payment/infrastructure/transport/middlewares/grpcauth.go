@@ -0,0 +1,128 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// tokenIssuer is "iss" claim of access tokens signed by user service
+const tokenIssuer = "user"
+
+type principalKey struct{}
+
+// Principal is user authenticated by access token with role and permissions carried in the token
+type Principal struct {
+	UserID      uuid.UUID
+	Role        string
+	Permissions []string
+}
+
+// PrincipalFromContext returns user authenticated by access token
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// UserIDFromContext returns ID of user authenticated by access token
+func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	principal, ok := PrincipalFromContext(ctx)
+	return principal.UserID, ok
+}
+
+// HasPermission reports whether user authenticated by access token has the permission
+func HasPermission(ctx context.Context, permission string) bool {
+	principal, ok := PrincipalFromContext(ctx)
+	return ok && slices.Contains(principal.Permissions, permission)
+}
+
+// ParsePublicKeys decodes base64 encoded ed25519 public keys of user service by key ID
+func ParsePublicKeys(keys map[string]string) (map[string]ed25519.PublicKey, error) {
+	publicKeys := make(map[string]ed25519.PublicKey, len(keys))
+	for keyID, key := range keys {
+		rawKey, err := base64.StdEncoding.DecodeString(key)
+		if err != nil || len(rawKey) != ed25519.PublicKeySize {
+			return nil, errors.Errorf("invalid public key %q", keyID)
+		}
+		publicKeys[keyID] = rawKey
+	}
+	return publicKeys, nil
+}
+
+// NewGRPCAuthMiddleware requires access token in "authorization: Bearer <token>" metadata for all methods except public ones
+func NewGRPCAuthMiddleware(publicKeys map[string]ed25519.PublicKey, publicMethods ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		if slices.Contains(publicMethods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, err = authenticate(ctx, publicKeys)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func authenticate(ctx context.Context, publicKeys map[string]ed25519.PublicKey) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "access token is required")
+	}
+	accessToken, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authorization must be bearer token")
+	}
+	principal, err := parseAccessToken(accessToken, publicKeys)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid access token")
+	}
+	return context.WithValue(ctx, principalKey{}, principal), nil
+}
+
+// parseAccessToken verifies signature, issuer and expiration of access token
+func parseAccessToken(accessToken string, publicKeys map[string]ed25519.PublicKey) (Principal, error) {
+	var claims struct {
+		jwt.RegisteredClaims
+		TokenType   string   `json:"token_type"`
+		Role        string   `json:"role"`
+		Permissions []string `json:"permissions"`
+	}
+	_, err := jwt.ParseWithClaims(accessToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		keyID, _ := token.Header["kid"].(string)
+		key, found := publicKeys[keyID]
+		if !found {
+			return nil, errors.Errorf("unknown key %q", keyID)
+		}
+		return key, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return Principal{}, errors.WithStack(err)
+	}
+	if claims.TokenType != "access" {
+		return Principal{}, errors.Errorf("unexpected token type %q", claims.TokenType)
+	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return Principal{}, errors.WithStack(err)
+	}
+	return Principal{
+		UserID:      userID,
+		Role:        claims.Role,
+		Permissions: claims.Permissions,
+	}, nil
+}
@@ -0,0 +1,28 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/ed25519"
+	"net/http"
+	"strings"
+)
+
+// NewHTTPAuthMiddleware requires access token in "Authorization: Bearer <token>" header,
+// user is available in request context the same way as for gRPC methods
+func NewHTTPAuthMiddleware(publicKeys map[string]ed25519.PublicKey) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			accessToken, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok {
+				http.Error(w, "access token is required", http.StatusUnauthorized)
+				return
+			}
+			principal, err := parseAccessToken(accessToken, publicKeys)
+			if err != nil {
+				http.Error(w, "invalid access token", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, principal)))
+		})
+	}
+}
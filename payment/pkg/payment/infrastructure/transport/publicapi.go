@@ -2,6 +2,7 @@ package transport
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
@@ -11,26 +12,40 @@ import (
 	appmodel "payment/pkg/payment/app/model"
 	"payment/pkg/payment/app/query"
 	"payment/pkg/payment/app/service"
+	"payment/pkg/payment/domain/model"
+	"payment/pkg/payment/infrastructure/transport/middlewares"
 )
 
+// permissionPaymentReadAny allows to read balances and statements of other customers
+const permissionPaymentReadAny = "payment.read.any"
+
 func NewPaymentInternalAPI(
 	balanceQueryService query.AccountBalanceQueryService,
+	statementQueryService query.StatementQueryService,
+	customerDataQueryService query.CustomerDataQueryService,
 	paymentService service.PaymentService,
+	customerDataService service.CustomerDataService,
 ) paymentpublicapi.PaymentPublicAPIServer {
 	return &paymentInternalAPI{
-		balanceQueryService: balanceQueryService,
-		paymentService:      paymentService,
+		balanceQueryService:      balanceQueryService,
+		statementQueryService:    statementQueryService,
+		customerDataQueryService: customerDataQueryService,
+		paymentService:           paymentService,
+		customerDataService:      customerDataService,
 	}
 }
 
 type paymentInternalAPI struct {
-	balanceQueryService query.AccountBalanceQueryService
-	paymentService      service.PaymentService
+	balanceQueryService      query.AccountBalanceQueryService
+	statementQueryService    query.StatementQueryService
+	customerDataQueryService query.CustomerDataQueryService
+	paymentService           service.PaymentService
+	customerDataService      service.CustomerDataService
 
 	paymentpublicapi.UnimplementedPaymentPublicAPIServer
 }
 
-func (u paymentInternalAPI) StoreUser(ctx context.Context, request *paymentpublicapi.StoreUserBalanceRequest) (*paymentpublicapi.StoreCustomerBalanceResponse, error) {
+func (u paymentInternalAPI) StoreCustomerBalance(ctx context.Context, request *paymentpublicapi.StoreUserBalanceRequest) (*paymentpublicapi.StoreCustomerBalanceResponse, error) {
 	var (
 		customerID uuid.UUID
 		err        error
@@ -55,11 +70,14 @@ func (u paymentInternalAPI) StoreUser(ctx context.Context, request *paymentpubli
 	}, nil
 }
 
-func (u paymentInternalAPI) FindUser(ctx context.Context, request *paymentpublicapi.FindCustomerBalanceRequest) (*paymentpublicapi.FindCustomerBalanceResponse, error) {
+func (u paymentInternalAPI) FindCustomerBalance(ctx context.Context, request *paymentpublicapi.FindCustomerBalanceRequest) (*paymentpublicapi.FindCustomerBalanceResponse, error) {
 	balanceID, err := uuid.Parse(request.CustomerID)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.CustomerID)
 	}
+	if !isOwner(ctx, balanceID) && !middlewares.HasPermission(ctx, permissionPaymentReadAny) {
+		return nil, status.Errorf(codes.NotFound, "balance %q not found", request.CustomerID)
+	}
 	balance, err := u.balanceQueryService.FindBalance(ctx, balanceID)
 	if err != nil {
 		return nil, err
@@ -72,3 +90,53 @@ func (u paymentInternalAPI) FindUser(ctx context.Context, request *paymentpublic
 		Balance:    balance.Amount,
 	}, nil
 }
+
+func (u paymentInternalAPI) GetStatement(ctx context.Context, request *paymentpublicapi.GetStatementRequest) (*paymentpublicapi.GetStatementResponse, error) {
+	customerID, err := uuid.Parse(request.CustomerID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.CustomerID)
+	}
+	if _, err = model.ParseStatementPeriod(request.Period); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid period %q", request.Period)
+	}
+	if !isOwner(ctx, customerID) && !middlewares.HasPermission(ctx, permissionPaymentReadAny) {
+		return nil, status.Errorf(codes.NotFound, "statement %q for %q not found", request.Period, request.CustomerID)
+	}
+
+	statement, err := u.statementQueryService.FindStatement(ctx, customerID, request.Period)
+	if err != nil {
+		if errors.Is(err, model.ErrStatementNotFound) {
+			return nil, status.Errorf(codes.NotFound, "statement %q for %q not found", request.Period, request.CustomerID)
+		}
+		return nil, err
+	}
+
+	lines := make([]*paymentpublicapi.StatementLine, 0, len(statement.Lines))
+	for _, line := range statement.Lines {
+		lines = append(lines, &paymentpublicapi.StatementLine{
+			TransactionID: line.TransactionID.String(),
+			OrderID:       line.OrderID.String(),
+			Type:          line.Type,
+			Amount:        line.Amount,
+			PaymentDate:   line.PaymentDate.Unix(),
+		})
+	}
+	return &paymentpublicapi.GetStatementResponse{
+		StatementID:    statement.StatementID.String(),
+		CustomerID:     statement.CustomerID.String(),
+		Period:         statement.Period,
+		Version:        int32(statement.Version), // nolint:gosec
+		OpeningBalance: statement.OpeningBalance,
+		ClosingBalance: statement.ClosingBalance,
+		TotalCharges:   statement.TotalCharges,
+		TotalRefunds:   statement.TotalRefunds,
+		TotalBonuses:   statement.TotalBonuses,
+		CreatedAt:      statement.CreatedAt.Unix(),
+		Lines:          lines,
+	}, nil
+}
+
+func isOwner(ctx context.Context, customerID uuid.UUID) bool {
+	callerID, ok := middlewares.UserIDFromContext(ctx)
+	return ok && callerID == customerID
+}
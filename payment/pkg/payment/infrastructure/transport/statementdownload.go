@@ -0,0 +1,177 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	appmodel "payment/pkg/payment/app/model"
+	"payment/pkg/payment/app/query"
+	"payment/pkg/payment/domain/model"
+	"payment/pkg/payment/infrastructure/transport/middlewares"
+)
+
+const (
+	statementFormatCSV  = "csv"
+	statementFormatJSON = "json"
+)
+
+// permissionPaymentRead is required to download statements, the same as for GetStatement method
+const permissionPaymentRead = "payment.read"
+
+// NewStatementDownloadHandler serves GET /statements/{customerID}/{period}?format=csv|json,
+// handler must be wrapped with middlewares.NewHTTPAuthMiddleware
+func NewStatementDownloadHandler(statementQueryService query.StatementQueryService) http.Handler {
+	return &statementDownloadHandler{
+		statementQueryService: statementQueryService,
+	}
+}
+
+type statementDownloadHandler struct {
+	statementQueryService query.StatementQueryService
+}
+
+func (h *statementDownloadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !middlewares.HasPermission(r.Context(), permissionPaymentRead) {
+		http.Error(w, fmt.Sprintf("permission %q is required", permissionPaymentRead), http.StatusForbidden)
+		return
+	}
+	vars := mux.Vars(r)
+	customerID, err := uuid.Parse(vars["customerID"])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid uuid %q", vars["customerID"]), http.StatusBadRequest)
+		return
+	}
+	period := vars["period"]
+	if _, err = model.ParseStatementPeriod(period); err != nil {
+		http.Error(w, fmt.Sprintf("invalid period %q", period), http.StatusBadRequest)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = statementFormatJSON
+	}
+	if format != statementFormatCSV && format != statementFormatJSON {
+		http.Error(w, fmt.Sprintf("unsupported format %q", format), http.StatusBadRequest)
+		return
+	}
+	// statements of other customers are reported as missing like in GetStatement method
+	if !isOwner(r.Context(), customerID) && !middlewares.HasPermission(r.Context(), permissionPaymentReadAny) {
+		http.Error(w, fmt.Sprintf("statement %q for %q not found", period, customerID), http.StatusNotFound)
+		return
+	}
+
+	statement, err := h.statementQueryService.FindStatement(r.Context(), customerID, period)
+	if err != nil {
+		if errors.Is(err, model.ErrStatementNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// statement is rendered before writing response, so rendering error is reported with its own status
+	var (
+		body        bytes.Buffer
+		contentType string
+	)
+	if format == statementFormatCSV {
+		contentType = "text/csv"
+		err = writeStatementCSV(&body, statement)
+	} else {
+		contentType = "application/json"
+		err = json.NewEncoder(&body).Encode(newStatementDocument(statement))
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fileName := fmt.Sprintf("statement-%s-%s.%s", customerID, period, format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(body.Len()))
+	_, _ = body.WriteTo(w)
+}
+
+type statementDocument struct {
+	StatementID    string                  `json:"statement_id"`
+	CustomerID     string                  `json:"customer_id"`
+	Period         string                  `json:"period"`
+	Version        int                     `json:"version"`
+	OpeningBalance float64                 `json:"opening_balance"`
+	ClosingBalance float64                 `json:"closing_balance"`
+	TotalCharges   float64                 `json:"total_charges"`
+	TotalRefunds   float64                 `json:"total_refunds"`
+	TotalBonuses   float64                 `json:"total_bonuses"`
+	CreatedAt      string                  `json:"created_at"`
+	Lines          []statementDocumentLine `json:"lines"`
+}
+
+type statementDocumentLine struct {
+	TransactionID string  `json:"transaction_id"`
+	OrderID       string  `json:"order_id"`
+	Type          string  `json:"type"`
+	Amount        float64 `json:"amount"`
+	PaymentDate   string  `json:"payment_date"`
+}
+
+func newStatementDocument(statement *appmodel.Statement) statementDocument {
+	lines := make([]statementDocumentLine, 0, len(statement.Lines))
+	for _, line := range statement.Lines {
+		lines = append(lines, statementDocumentLine{
+			TransactionID: line.TransactionID.String(),
+			OrderID:       line.OrderID.String(),
+			Type:          line.Type,
+			Amount:        line.Amount,
+			PaymentDate:   line.PaymentDate.Format(time.RFC3339),
+		})
+	}
+	return statementDocument{
+		StatementID:    statement.StatementID.String(),
+		CustomerID:     statement.CustomerID.String(),
+		Period:         statement.Period,
+		Version:        statement.Version,
+		OpeningBalance: statement.OpeningBalance,
+		ClosingBalance: statement.ClosingBalance,
+		TotalCharges:   statement.TotalCharges,
+		TotalRefunds:   statement.TotalRefunds,
+		TotalBonuses:   statement.TotalBonuses,
+		CreatedAt:      statement.CreatedAt.Format(time.RFC3339),
+		Lines:          lines,
+	}
+}
+
+// writeStatementCSV writes opening balance, one row per transaction and closing balance
+func writeStatementCSV(w io.Writer, statement *appmodel.Statement) error {
+	writer := csv.NewWriter(w)
+	records := [][]string{
+		{"date", "type", "transaction_id", "order_id", "amount"},
+		{statement.Period, "opening_balance", "", "", formatAmount(statement.OpeningBalance)},
+	}
+	for _, line := range statement.Lines {
+		records = append(records, []string{
+			line.PaymentDate.Format(time.RFC3339),
+			line.Type,
+			line.TransactionID.String(),
+			line.OrderID.String(),
+			formatAmount(line.Amount),
+		})
+	}
+	records = append(records, []string{statement.Period, "closing_balance", "", "", formatAmount(statement.ClosingBalance)})
+	return writer.WriteAll(records)
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
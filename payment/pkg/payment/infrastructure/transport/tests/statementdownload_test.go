@@ -0,0 +1,174 @@
+package tests
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	appmodel "payment/pkg/payment/app/model"
+	"payment/pkg/payment/domain/model"
+	"payment/pkg/payment/infrastructure/transport"
+	"payment/pkg/payment/infrastructure/transport/middlewares"
+)
+
+const keyID = "test"
+
+func TestStatementDownloadHandler(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	customerID := uuid.Must(uuid.NewV7())
+	statement := &appmodel.Statement{
+		StatementID:    uuid.Must(uuid.NewV7()),
+		CustomerID:     customerID,
+		Period:         "2025-03",
+		Version:        2,
+		OpeningBalance: 70,
+		ClosingBalance: 20,
+		TotalCharges:   50,
+		Lines: []appmodel.StatementLine{{
+			TransactionID: uuid.Must(uuid.NewV7()),
+			OrderID:       uuid.Must(uuid.NewV7()),
+			Type:          "new",
+			Amount:        50,
+			PaymentDate:   time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+		}},
+		CreatedAt: time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC),
+	}
+	queryService := &mockStatementQueryService{
+		statements: map[uuid.UUID]*appmodel.Statement{customerID: statement},
+	}
+	router := mux.NewRouter()
+	router.Handle("/statements/{customerID}/{period}",
+		middlewares.NewHTTPAuthMiddleware(map[string]ed25519.PublicKey{keyID: publicKey})(transport.NewStatementDownloadHandler(queryService)),
+	)
+	download := func(t *testing.T, userID uuid.UUID, path string, permissions ...string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		request.Header.Set("Authorization", "Bearer "+signAccessToken(t, privateKey, userID, permissions))
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+	statementPath := "/statements/" + customerID.String() + "/2025-03"
+
+	t.Run("Download statement as JSON", func(t *testing.T) {
+		response := download(t, customerID, statementPath, "payment.read")
+		require.Equal(t, http.StatusOK, response.Code)
+		require.Equal(t, "application/json", response.Header().Get("Content-Type"))
+		require.Contains(t, response.Header().Get("Content-Disposition"), "statement-"+customerID.String()+"-2025-03.json")
+
+		var document struct {
+			StatementID    string  `json:"statement_id"`
+			Version        int     `json:"version"`
+			OpeningBalance float64 `json:"opening_balance"`
+			ClosingBalance float64 `json:"closing_balance"`
+			Lines          []struct {
+				Type   string  `json:"type"`
+				Amount float64 `json:"amount"`
+			} `json:"lines"`
+		}
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &document))
+		require.Equal(t, statement.StatementID.String(), document.StatementID)
+		require.Equal(t, 2, document.Version)
+		require.Equal(t, 70.0, document.OpeningBalance)
+		require.Equal(t, 20.0, document.ClosingBalance)
+		require.Len(t, document.Lines, 1)
+		require.Equal(t, 50.0, document.Lines[0].Amount)
+	})
+
+	t.Run("Download statement as CSV", func(t *testing.T) {
+		response := download(t, customerID, statementPath+"?format=csv", "payment.read")
+		require.Equal(t, http.StatusOK, response.Code)
+		require.Equal(t, "text/csv", response.Header().Get("Content-Type"))
+
+		records, err := csv.NewReader(response.Body).ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, [][]string{
+			{"date", "type", "transaction_id", "order_id", "amount"},
+			{"2025-03", "opening_balance", "", "", "70.00"},
+			{"2025-03-01T00:00:00Z", "new", statement.Lines[0].TransactionID.String(), statement.Lines[0].OrderID.String(), "50.00"},
+			{"2025-03", "closing_balance", "", "", "20.00"},
+		}, records)
+	})
+
+	t.Run("Statement of other customer", func(t *testing.T) {
+		response := download(t, uuid.Must(uuid.NewV7()), statementPath, "payment.read")
+		require.Equal(t, http.StatusNotFound, response.Code)
+
+		response = download(t, uuid.Must(uuid.NewV7()), statementPath, "payment.read", "payment.read.any")
+		require.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		require.Equal(t, http.StatusForbidden, download(t, customerID, statementPath).Code)
+		require.Equal(t, http.StatusBadRequest, download(t, customerID, statementPath+"?format=xml", "payment.read").Code)
+		require.Equal(t, http.StatusBadRequest, download(t, customerID, "/statements/"+customerID.String()+"/2025-13", "payment.read").Code)
+		require.Equal(t, http.StatusBadRequest, download(t, customerID, "/statements/not-uuid/2025-03", "payment.read").Code)
+
+		request := httptest.NewRequest(http.MethodGet, statementPath, nil)
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		require.Equal(t, http.StatusUnauthorized, response.Code)
+	})
+
+	t.Run("Statement is not generated", func(t *testing.T) {
+		otherCustomerID := uuid.Must(uuid.NewV7())
+		response := download(t, otherCustomerID, "/statements/"+otherCustomerID.String()+"/2025-03", "payment.read")
+		require.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Query error is reported without partial body", func(t *testing.T) {
+		queryService.err = errors.New("connection lost")
+		t.Cleanup(func() {
+			queryService.err = nil
+		})
+		response := download(t, customerID, statementPath, "payment.read")
+		require.Equal(t, http.StatusInternalServerError, response.Code)
+		require.Empty(t, response.Header().Get("Content-Disposition"))
+		require.True(t, strings.HasPrefix(response.Body.String(), "connection lost"))
+	})
+}
+
+func signAccessToken(t *testing.T, privateKey ed25519.PrivateKey, userID uuid.UUID, permissions []string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{
+		"iss":         "user",
+		"sub":         userID.String(),
+		"exp":         time.Now().Add(time.Hour).Unix(),
+		"token_type":  "access",
+		"role":        "customer",
+		"permissions": permissions,
+	})
+	token.Header["kid"] = keyID
+	signed, err := token.SignedString(privateKey)
+	require.NoError(t, err)
+	return signed
+}
+
+type mockStatementQueryService struct {
+	statements map[uuid.UUID]*appmodel.Statement
+	err        error
+}
+
+func (m *mockStatementQueryService) FindStatement(_ context.Context, customerID uuid.UUID, period string) (*appmodel.Statement, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	statement, ok := m.statements[customerID]
+	if !ok || statement.Period != period {
+		return nil, model.ErrStatementNotFound
+	}
+	return statement, nil
+}
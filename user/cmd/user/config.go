@@ -40,3 +40,40 @@ type AMQP struct {
 type Temporal struct {
 	Host string `envconfig:"host" required:"true"`
 }
+
+type Auth struct {
+	// SigningKeys are base64 encoded ed25519 seeds by key ID in "id1:seed1,id2:seed2" format,
+	// key is rotated by adding new key and switching SigningKeyID to it
+	SigningKeys      map[string]string `envconfig:"signing_keys" required:"true"`
+	SigningKeyID     string            `envconfig:"signing_key_id" required:"true"`
+	AccessTokenTTL   time.Duration     `envconfig:"access_token_ttl" default:"15m"`
+	RefreshTokenTTL  time.Duration     `envconfig:"refresh_token_ttl" default:"720h"`
+	PasswordHashCost int               `envconfig:"password_hash_cost" default:"10"`
+}
+
+// LoginThrottling limits failed logins in sliding windows per account and per IP
+type LoginThrottling struct {
+	AccountMaxFailures int           `envconfig:"account_max_failures" default:"5"`
+	AccountWindow      time.Duration `envconfig:"account_window" default:"15m"`
+	IPMaxFailures      int           `envconfig:"ip_max_failures" default:"50"`
+	IPWindow           time.Duration `envconfig:"ip_window" default:"15m"`
+	LockoutDuration    time.Duration `envconfig:"lockout_duration" default:"30m"`
+}
+
+// Services are gRPC addresses of other services called by workflows
+type Services struct {
+	OrderAddress        string `envconfig:"order_address" required:"true"`
+	PaymentAddress      string `envconfig:"payment_address" required:"true"`
+	NotificationAddress string `envconfig:"notification_address" required:"true"`
+}
+
+// Erasure configures data subject erasure requests
+type Erasure struct {
+	// PseudonymSecret keys pseudonyms of erased users, changing it gives new pseudonyms to users erased again
+	PseudonymSecret string `envconfig:"pseudonym_secret" required:"true"`
+}
+
+type Verification struct {
+	// CodeHashCost is bcrypt cost of stored one-time codes, codes live for minutes so it is kept low
+	CodeHashCost int `envconfig:"code_hash_cost" default:"6"`
+}
@@ -18,16 +18,55 @@ import (
 
 	"user/api/server/userpublicapi"
 	appservice "user/pkg/user/application/service"
+	"user/pkg/user/domain/model"
+	"user/pkg/user/infrastructure/auth"
 	"user/pkg/user/infrastructure/integrationevent"
 	inframysql "user/pkg/user/infrastructure/mysql"
 	"user/pkg/user/infrastructure/mysql/query"
+	"user/pkg/user/infrastructure/temporal"
 	"user/pkg/user/infrastructure/transport"
 	"user/pkg/user/infrastructure/transport/middlewares"
 )
 
+// publicMethods are called without access token
+var publicMethods = []string{
+	"/User.UserPublicAPI/Register",
+	"/User.UserPublicAPI/Login",
+	"/User.UserPublicAPI/RefreshToken",
+	"/User.UserPublicAPI/Logout",
+	"/User.UserPublicAPI/ConfirmContact",
+	"/User.UserPublicAPI/SendVerificationCode",
+}
+
+// methodPermissions are permissions required in access token, access to other users is checked by methods
+var methodPermissions = map[string]string{
+	"/User.UserPublicAPI/StoreUser":   string(model.PermissionUserWrite),
+	"/User.UserPublicAPI/FindUser":    string(model.PermissionUserRead),
+	"/User.UserPublicAPI/FindUserBy":  string(model.PermissionUserRead),
+	"/User.UserPublicAPI/ListUsers":   string(model.PermissionUserReadAny),
+	"/User.UserPublicAPI/DeleteUser":  string(model.PermissionUserWrite),
+	"/User.UserPublicAPI/SetUserRole": string(model.PermissionUserRoleWrite),
+
+	"/User.UserPublicAPI/SetUserPreferences": string(model.PermissionUserWrite),
+
+	"/User.UserPublicAPI/RequestUserDataExport":  string(model.PermissionUserDataExport),
+	"/User.UserPublicAPI/RequestUserDataErasure": string(model.PermissionUserDataErase),
+	"/User.UserPublicAPI/GetUserDataRequest":     string(model.PermissionUserDataExport),
+
+	"/User.UserPublicAPI/GetUserOnboarding": string(model.PermissionUserRead),
+
+	"/User.UserPublicAPI/GetUserHistory": string(model.PermissionUserReadAny),
+}
+
 type serviceConfig struct {
 	Service  Service  `envconfig:"service"`
 	Database Database `envconfig:"database" required:"true"`
+	Auth     Auth     `envconfig:"auth" required:"true"`
+	Temporal Temporal `envconfig:"temporal" required:"true"`
+
+	Verification Verification `envconfig:"verification"`
+
+	LoginThrottling LoginThrottling `envconfig:"login_throttling"`
 }
 
 func service(logger logging.Logger) *cli.Command {
@@ -45,6 +84,11 @@ func service(logger logging.Logger) *cli.Command {
 				err = errors.Join(err, closer.Close())
 			}()
 
+			signingKeys, err := auth.ParseSigningKeys(cnf.Auth.SigningKeys, cnf.Auth.SigningKeyID)
+			if err != nil {
+				return err
+			}
+
 			databaseConnector, err := newDatabaseConnector(cnf.Database)
 			if err != nil {
 				return err
@@ -52,6 +96,15 @@ func service(logger logging.Logger) *cli.Command {
 			closer.AddCloser(databaseConnector)
 			databaseConnectionPool := mysql.NewConnectionPool(databaseConnector.TransactionalClient())
 
+			temporalClient, err := temporal.NewClient(logger, cnf.Temporal.Host)
+			if err != nil {
+				return err
+			}
+			closer.AddCloser(libio.CloserFunc(func() error {
+				temporalClient.Close()
+				return nil
+			}))
+
 			libUoW := mysql.NewUnitOfWork(databaseConnectionPool, inframysql.NewRepositoryProvider)
 			libLUow := mysql.NewLockableUnitOfWork(libUoW, mysql.NewLocker(databaseConnectionPool))
 			uow := inframysql.NewUnitOfWork(libUoW)
@@ -61,6 +114,18 @@ func service(logger logging.Logger) *cli.Command {
 			userPublicAPIServer := transport.NewUserInternalAPI(
 				query.NewUserQueryService(databaseConnector.TransactionalClient()),
 				appservice.NewUserService(uow, luow, eventDispatcher),
+				appservice.NewAuthService(
+					uow,
+					luow,
+					eventDispatcher,
+					auth.NewBcryptPasswordHasher(cnf.Auth.PasswordHashCost),
+					auth.NewTokenIssuer(signingKeys, cnf.Auth.AccessTokenTTL),
+					cnf.Auth.RefreshTokenTTL,
+					model.LoginThrottling(cnf.LoginThrottling),
+				),
+				appservice.NewContactVerificationService(uow, luow, eventDispatcher, auth.NewBcryptPasswordHasher(cnf.Verification.CodeHashCost)),
+				appservice.NewPreferencesService(luow, eventDispatcher),
+				temporal.NewWorkflowService(temporalClient),
 			)
 
 			errGroup := errgroup.Group{}
@@ -72,6 +137,9 @@ func service(logger logging.Logger) *cli.Command {
 				grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
 					middlewares.NewGRPCLoggingMiddleware(logger),
 					middlewares.NewGRPCMetricsMiddleware(),
+					middlewares.NewGRPCAuthMiddleware(signingKeys.PublicKeys(), publicMethods...),
+					middlewares.NewGRPCPermissionMiddleware(methodPermissions, publicMethods...),
+					middlewares.NewGRPCActorMiddleware(appservice.WithActor),
 				))
 				userpublicapi.RegisterUserPublicAPIServer(grpcServer, userPublicAPIServer)
 				graceCallback(c.Context, logger, cnf.Service.GracePeriod, func(_ context.Context) error {
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/logging"
+	libio "gitea.xscloud.ru/xscloud/golib/pkg/common/io"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/outbox"
+	"github.com/google/uuid"
+	"github.com/urfave/cli/v2"
+
+	appservice "user/pkg/user/application/service"
+	"user/pkg/user/infrastructure/integrationevent"
+	inframysql "user/pkg/user/infrastructure/mysql"
+)
+
+type setUserRoleConfig struct {
+	Database Database `envconfig:"database" required:"true"`
+}
+
+// setUserRole grants role to the user without access token, it is used to create first admin
+func setUserRole(logger logging.Logger) *cli.Command {
+	return &cli.Command{
+		Name:   "set-user-role",
+		Before: migrateImpl(logger),
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "user-id", Required: true},
+			&cli.StringFlag{Name: "role", Required: true},
+		},
+		Action: func(c *cli.Context) error {
+			cnf, err := parseEnvs[setUserRoleConfig]()
+			if err != nil {
+				return err
+			}
+			userID, err := uuid.Parse(c.String("user-id"))
+			if err != nil {
+				return err
+			}
+
+			closer := libio.NewMultiCloser()
+			defer func() {
+				err = errors.Join(err, closer.Close())
+			}()
+
+			databaseConnector, err := newDatabaseConnector(cnf.Database)
+			if err != nil {
+				return err
+			}
+			closer.AddCloser(databaseConnector)
+			databaseConnectionPool := mysql.NewConnectionPool(databaseConnector.TransactionalClient())
+
+			libUoW := mysql.NewUnitOfWork(databaseConnectionPool, inframysql.NewRepositoryProvider)
+			libLUow := mysql.NewLockableUnitOfWork(libUoW, mysql.NewLocker(databaseConnectionPool))
+			eventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, integrationevent.NewEventSerializer(), libUoW)
+			userService := appservice.NewUserService(inframysql.NewUnitOfWork(libUoW), inframysql.NewLockableUnitOfWork(libLUow), eventDispatcher)
+
+			err = userService.SetUserRole(c.Context, userID, c.String("role"))
+			if err != nil {
+				return err
+			}
+			logger.WithFields(logging.Fields{
+				"user_id": userID,
+				"role":    c.String("role"),
+			}).Info("user role changed")
+			return nil
+		},
+	}
+}
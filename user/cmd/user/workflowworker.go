@@ -12,11 +12,18 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 
+	"user/api/client/notificationinternal"
+	"user/api/client/orderinternal"
+	"user/api/client/paymentpublicapi"
 	appservice "user/pkg/user/application/service"
+	"user/pkg/user/infrastructure/auth"
 	"user/pkg/user/infrastructure/integrationevent"
 	inframysql "user/pkg/user/infrastructure/mysql"
 	"user/pkg/user/infrastructure/temporal"
+	"user/pkg/user/infrastructure/temporal/activity"
 	"user/pkg/user/infrastructure/temporal/worker"
 )
 
@@ -24,6 +31,12 @@ type workflowWorkerConfig struct {
 	Service  Service  `envconfig:"service"`
 	Database Database `envconfig:"database" required:"true"`
 	Temporal Temporal `envconfig:"temporal" required:"true"`
+	// Auth issues access tokens of requesters for calls to other services
+	Auth     Auth     `envconfig:"auth" required:"true"`
+	Services Services `envconfig:"services" required:"true"`
+	Erasure  Erasure  `envconfig:"erasure" required:"true"`
+
+	Verification Verification `envconfig:"verification"`
 }
 
 func workflowWorker(logger logging.Logger) *cli.Command {
@@ -41,6 +54,11 @@ func workflowWorker(logger logging.Logger) *cli.Command {
 				err = errors.Join(err, closer.Close())
 			}()
 
+			signingKeys, err := auth.ParseSigningKeys(cnf.Auth.SigningKeys, cnf.Auth.SigningKeyID)
+			if err != nil {
+				return err
+			}
+
 			databaseConnector, err := newDatabaseConnector(cnf.Database)
 			if err != nil {
 				return err
@@ -57,15 +75,55 @@ func workflowWorker(logger logging.Logger) *cli.Command {
 				return nil
 			}))
 
+			orderConn, err := newGRPCClient(cnf.Services.OrderAddress)
+			if err != nil {
+				return err
+			}
+			closer.AddCloser(orderConn)
+			paymentConn, err := newGRPCClient(cnf.Services.PaymentAddress)
+			if err != nil {
+				return err
+			}
+			closer.AddCloser(paymentConn)
+			notificationConn, err := newGRPCClient(cnf.Services.NotificationAddress)
+			if err != nil {
+				return err
+			}
+			closer.AddCloser(notificationConn)
+
 			libUoW := mysql.NewUnitOfWork(databaseConnectionPool, inframysql.NewRepositoryProvider)
 			libLUow := mysql.NewLockableUnitOfWork(libUoW, mysql.NewLocker(databaseConnectionPool))
 			uow := inframysql.NewUnitOfWork(libUoW)
 			luow := inframysql.NewLockableUnitOfWork(libLUow)
 			eventDispatcher := outbox.NewEventDispatcher(appID, integrationevent.TransportName, integrationevent.NewEventSerializer(), libUoW)
 
+			userService := appservice.NewUserService(uow, luow, eventDispatcher)
+			tokenIssuer := auth.NewTokenIssuer(signingKeys, cnf.Auth.AccessTokenTTL)
+			paymentClient := paymentpublicapi.NewPaymentPublicAPIClient(paymentConn)
+			notificationClient := notificationinternal.NewNotificationInternalServiceClient(notificationConn)
+
 			errGroup := errgroup.Group{}
 			errGroup.Go(func() error {
-				w := worker.NewWorker(temporalClient, appservice.NewUserService(uow, luow, eventDispatcher))
+				w := worker.NewWorker(
+					temporalClient,
+					userService,
+					appservice.NewContactVerificationService(uow, luow, eventDispatcher, auth.NewBcryptPasswordHasher(cnf.Verification.CodeHashCost)),
+					activity.NewUserDataActivities(
+						userService,
+						appservice.NewUserDataService(luow, eventDispatcher),
+						tokenIssuer,
+						orderinternal.NewOrderInternalServiceClient(orderConn),
+						paymentClient,
+						notificationClient,
+						auth.NewPseudonymizer([]byte(cnf.Erasure.PseudonymSecret)),
+					),
+					activity.NewOnboardingActivities(
+						appservice.NewPreferencesService(luow, eventDispatcher),
+						tokenIssuer,
+						paymentClient,
+						notificationClient,
+					),
+				)
 				return w.Run(worker.InterruptChannel())
 			})
 
@@ -86,3 +144,8 @@ func workflowWorker(logger logging.Logger) *cli.Command {
 		},
 	}
 }
+
+// newGRPCClient connects to other service inside of internal network
+func newGRPCClient(address string) (*grpc.ClientConn, error) {
+	return grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+}
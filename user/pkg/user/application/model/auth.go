@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Registration struct {
+	Login    string
+	Email    *string
+	Telegram *string
+	Password string
+}
+
+// Session is a pair of tokens issued on login, refresh token is exchanged for a new pair when access token expires
+type Session struct {
+	UserID                uuid.UUID
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
@@ -1,11 +1,58 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type User struct {
 	UserID   uuid.UUID
 	Status   int
+	Role     string
 	Login    string
 	Email    *string
 	Telegram *string
+	// EmailVerified and TelegramVerified are set by contact verification
+	EmailVerified    bool
+	TelegramVerified bool
+	CreatedAt        time.Time
+}
+
+type ListUsersSpec struct {
+	Status *int
+	// CreatedFrom and CreatedTo bound user creation time inclusively
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// Cursor is an opaque value from UserList.NextCursor of the previous page
+	Cursor string
+	Limit  int
+}
+
+type UserList struct {
+	Users      []User
+	NextCursor string
+}
+
+// UserChange is audit record of changed field of the user, Field is one of model.UserField* constants
+type UserChange struct {
+	ChangeID uuid.UUID
+	Field    string
+	OldValue *string
+	NewValue *string
+	// ActorID is nil for changes made by the system
+	ActorID   *uuid.UUID
+	ChangedAt time.Time
+}
+
+type ListUserChangesSpec struct {
+	UserID uuid.UUID
+	// Cursor is an opaque value from UserChangeList.NextCursor of the previous page
+	Cursor string
+	Limit  int
+}
+
+type UserChangeList struct {
+	Changes    []UserChange
+	NextCursor string
 }
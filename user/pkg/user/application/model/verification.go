@@ -0,0 +1,14 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ContactVerification struct {
+	VerificationID uuid.UUID
+	UserID         uuid.UUID
+	ContactType    string
+	ExpiresAt      time.Time
+}
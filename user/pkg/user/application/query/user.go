@@ -2,12 +2,22 @@ package query
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 
 	appmodel "user/pkg/user/application/model"
+	"user/pkg/user/domain/model"
 )
 
+var ErrInvalidCursor = errors.New("invalid cursor")
+
 type UserQueryService interface {
 	FindUser(ctx context.Context, userID uuid.UUID) (*appmodel.User, error)
+	// FindUserBy finds user matching all set fields of the spec
+	FindUserBy(ctx context.Context, spec model.FindSpec) (*appmodel.User, error)
+	// ListUsers pages through users in order of creation
+	ListUsers(ctx context.Context, spec appmodel.ListUsersSpec) (appmodel.UserList, error)
+	// ListUserChanges pages through history of the user, newest changes first
+	ListUserChanges(ctx context.Context, spec appmodel.ListUserChangesSpec) (appmodel.UserChangeList, error)
 }
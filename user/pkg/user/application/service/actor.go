@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type actorKey struct{}
+
+// WithActor returns context of changes made by the user, changes made without actor are recorded as made by the system
+func WithActor(ctx context.Context, actorID uuid.UUID) context.Context {
+	return context.WithValue(ctx, actorKey{}, actorID)
+}
+
+func actorFromContext(ctx context.Context) *uuid.UUID {
+	actorID, ok := ctx.Value(actorKey{}).(uuid.UUID)
+	if !ok {
+		return nil
+	}
+	return &actorID
+}
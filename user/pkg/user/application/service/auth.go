@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+
+	appmodel "user/pkg/user/application/model"
+	"user/pkg/user/domain/model"
+	"user/pkg/user/domain/service"
+)
+
+// TokenIssuer signs tokens of the session, access token carries role and permissions of the user,
+// refresh token carries ID of stored model.RefreshToken
+type TokenIssuer interface {
+	IssueAccessToken(userID uuid.UUID, access model.Access) (string, time.Time, error)
+	IssueRefreshToken(token model.RefreshToken) (string, error)
+	// ParseRefreshToken checks signature of refresh token and returns its ID
+	ParseRefreshToken(refreshToken string) (uuid.UUID, error)
+}
+
+type AuthService interface {
+	// Register creates blocked user with password, session is started by Login after contact is confirmed.
+	// Verification of the contacts is started by caller
+	Register(ctx context.Context, registration appmodel.Registration) (uuid.UUID, error)
+	// Login authenticates user from the IP, failed logins are limited by model.LoginThrottling
+	Login(ctx context.Context, login, password, ip string) (appmodel.Session, error)
+	RefreshToken(ctx context.Context, refreshToken string) (appmodel.Session, error)
+	Logout(ctx context.Context, refreshToken string) error
+}
+
+func NewAuthService(
+	uow UnitOfWork,
+	luow LockableUnitOfWork,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+	passwordHasher model.PasswordHasher,
+	tokenIssuer TokenIssuer,
+	refreshTokenTTL time.Duration,
+	throttling model.LoginThrottling,
+) AuthService {
+	return &authService{
+		uow:             uow,
+		luow:            luow,
+		eventDispatcher: eventDispatcher,
+		passwordHasher:  passwordHasher,
+		tokenIssuer:     tokenIssuer,
+		refreshTokenTTL: refreshTokenTTL,
+		throttling:      throttling,
+	}
+}
+
+type authService struct {
+	uow             UnitOfWork
+	luow            LockableUnitOfWork
+	eventDispatcher outbox.EventDispatcher[outbox.Event]
+	passwordHasher  model.PasswordHasher
+	tokenIssuer     TokenIssuer
+	refreshTokenTTL time.Duration
+	throttling      model.LoginThrottling
+}
+
+func (s *authService) Register(ctx context.Context, registration appmodel.Registration) (uuid.UUID, error) {
+	if len(registration.Password) < model.MinPasswordLength || len(registration.Password) > model.MaxPasswordLength {
+		return uuid.Nil, model.ErrInvalidPassword
+	}
+	// user is activated by confirmation of a contact
+	if registration.Email == nil && registration.Telegram == nil {
+		return uuid.Nil, model.ErrContactRequired
+	}
+	lockNames := []string{userLoginLock(registration.Login)}
+	if registration.Email != nil {
+		lockNames = append(lockNames, userEmailLock(*registration.Email))
+	}
+	if registration.Telegram != nil {
+		lockNames = append(lockNames, userTelegramLock(*registration.Telegram))
+	}
+
+	var userID uuid.UUID
+	err := s.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
+		// registration is recorded as made by the system, the user does not exist before it
+		domainService := service.NewUserService(provider.UserRepository(ctx), provider.UserChangeRepository(ctx), nil, &domainEventDispatcher{
+			ctx:             ctx,
+			eventDispatcher: s.eventDispatcher,
+		})
+		var err error
+		userID, err = domainService.RegisterUser(registration.Login, registration.Email, registration.Telegram)
+		if err != nil {
+			return err
+		}
+
+		return s.domainService(ctx, provider).SetPassword(userID, registration.Password)
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return userID, nil
+}
+
+func (s *authService) Login(ctx context.Context, login, password, ip string) (appmodel.Session, error) {
+	var (
+		refreshToken model.RefreshToken
+		access       model.Access
+	)
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		throttlingService := s.throttlingService(ctx, provider)
+		err := throttlingService.CheckIP(ip)
+		if err != nil {
+			return err
+		}
+		authService := s.domainService(ctx, provider)
+		userID, err := authService.Authenticate(login, password)
+		if err != nil {
+			return err
+		}
+		err = throttlingService.Reset(login)
+		if err != nil {
+			return err
+		}
+		refreshToken, err = authService.StartSession(userID, s.refreshTokenTTL)
+		if err != nil {
+			return err
+		}
+		access, err = authService.Access(userID)
+		return err
+	})
+	if errors.Is(err, model.ErrInvalidCredentials) {
+		recordErr := s.recordFailedLogin(ctx, login, ip)
+		if recordErr != nil {
+			return appmodel.Session{}, recordErr
+		}
+	}
+	if err != nil {
+		return appmodel.Session{}, err
+	}
+	return s.session(refreshToken, access)
+}
+
+func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (appmodel.Session, error) {
+	tokenID, err := s.tokenIssuer.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return appmodel.Session{}, err
+	}
+
+	var (
+		newRefreshToken model.RefreshToken
+		access          model.Access
+		reused          bool
+	)
+	err = s.luow.Execute(ctx, []string{refreshTokenLock(tokenID)}, func(provider RepositoryProvider) error {
+		var err2 error
+		authService := s.domainService(ctx, provider)
+		newRefreshToken, err2 = authService.RotateSession(tokenID, s.refreshTokenTTL)
+		if errors.Is(err2, model.ErrRefreshTokenReused) {
+			// revocation of user sessions must be committed
+			reused = true
+			return nil
+		}
+		if err2 != nil {
+			return err2
+		}
+		// role may be changed since previous access token was issued
+		access, err2 = authService.Access(newRefreshToken.UserID)
+		return err2
+	})
+	if err != nil {
+		return appmodel.Session{}, err
+	}
+	if reused {
+		return appmodel.Session{}, model.ErrRefreshTokenReused
+	}
+	return s.session(newRefreshToken, access)
+}
+
+func (s *authService) Logout(ctx context.Context, refreshToken string) error {
+	tokenID, err := s.tokenIssuer.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return err
+	}
+	return s.luow.Execute(ctx, []string{refreshTokenLock(tokenID)}, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).EndSession(tokenID)
+	})
+}
+
+func (s *authService) session(refreshToken model.RefreshToken, access model.Access) (appmodel.Session, error) {
+	accessToken, accessTokenExpiresAt, err := s.tokenIssuer.IssueAccessToken(refreshToken.UserID, access)
+	if err != nil {
+		return appmodel.Session{}, err
+	}
+	signedRefreshToken, err := s.tokenIssuer.IssueRefreshToken(refreshToken)
+	if err != nil {
+		return appmodel.Session{}, err
+	}
+	return appmodel.Session{
+		UserID:                refreshToken.UserID,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessTokenExpiresAt,
+		RefreshToken:          signedRefreshToken,
+		RefreshTokenExpiresAt: refreshToken.ExpiresAt,
+	}, nil
+}
+
+func (s *authService) domainService(ctx context.Context, provider RepositoryProvider) service.AuthService {
+	return service.NewAuthService(
+		provider.UserRepository(ctx),
+		provider.RefreshTokenRepository(ctx),
+		provider.RolePermissionRepository(ctx),
+		s.passwordHasher,
+	)
+}
+
+// recordFailedLogin counts failure under lock of the login, so concurrent failures lock the account once,
+// and under lock of the user since lockout changes the user
+func (s *authService) recordFailedLogin(ctx context.Context, login, ip string) error {
+	lockNames := []string{userLoginLock(login)}
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		user, err := provider.UserRepository(ctx).Find(model.FindSpec{Login: &login})
+		if err != nil {
+			return err
+		}
+		lockNames = append(lockNames, userLock(user.UserID))
+		return nil
+	})
+	if err != nil && !errors.Is(err, model.ErrUserNotFound) {
+		return err
+	}
+	return s.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
+		return s.throttlingService(ctx, provider).RecordFailure(login, ip)
+	})
+}
+
+func (s *authService) throttlingService(ctx context.Context, provider RepositoryProvider) service.LoginThrottlingService {
+	// lockout is recorded as made by the system
+	userService := service.NewUserService(provider.UserRepository(ctx), provider.UserChangeRepository(ctx), nil, &domainEventDispatcher{
+		ctx:             ctx,
+		eventDispatcher: s.eventDispatcher,
+	})
+	return service.NewLoginThrottlingService(provider.LoginAttemptRepository(ctx), provider.UserRepository(ctx), userService, s.throttling)
+}
+
+func refreshTokenLock(tokenID uuid.UUID) string {
+	return "refresh_token_" + tokenID.String()
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+
+	"user/pkg/user/domain/service"
+)
+
+type PreferencesService interface {
+	InitPreferences(ctx context.Context, userID uuid.UUID) error
+	// UpdatePreferences changes set fields, changed preferences are synced to services by event
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, locale, timeZone *string) error
+	DeletePreferences(ctx context.Context, userID uuid.UUID) error
+}
+
+func NewPreferencesService(luow LockableUnitOfWork, eventDispatcher outbox.EventDispatcher[outbox.Event]) PreferencesService {
+	return &preferencesService{
+		luow:            luow,
+		eventDispatcher: eventDispatcher,
+	}
+}
+
+type preferencesService struct {
+	luow            LockableUnitOfWork
+	eventDispatcher outbox.EventDispatcher[outbox.Event]
+}
+
+func (s *preferencesService) InitPreferences(ctx context.Context, userID uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{userLock(userID)}, func(provider RepositoryProvider) error {
+		_, err := s.domainService(ctx, provider).InitPreferences(userID)
+		return err
+	})
+}
+
+func (s *preferencesService) UpdatePreferences(ctx context.Context, userID uuid.UUID, locale, timeZone *string) error {
+	return s.luow.Execute(ctx, []string{userLock(userID)}, func(provider RepositoryProvider) error {
+		_, err := s.domainService(ctx, provider).UpdatePreferences(userID, locale, timeZone)
+		return err
+	})
+}
+
+func (s *preferencesService) DeletePreferences(ctx context.Context, userID uuid.UUID) error {
+	return s.luow.Execute(ctx, []string{userLock(userID)}, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).DeletePreferences(userID)
+	})
+}
+
+func (s *preferencesService) domainService(ctx context.Context, provider RepositoryProvider) service.PreferencesService {
+	return service.NewPreferencesService(provider.UserRepository(ctx), provider.PreferencesRepository(ctx), &domainEventDispatcher{
+		ctx:             ctx,
+		eventDispatcher: s.eventDispatcher,
+	})
+}
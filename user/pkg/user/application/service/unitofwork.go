@@ -8,6 +8,12 @@ import (
 
 type RepositoryProvider interface {
 	UserRepository(ctx context.Context) model.UserRepository
+	RefreshTokenRepository(ctx context.Context) model.RefreshTokenRepository
+	RolePermissionRepository(ctx context.Context) model.RolePermissionRepository
+	ContactVerificationRepository(ctx context.Context) model.ContactVerificationRepository
+	PreferencesRepository(ctx context.Context) model.PreferencesRepository
+	UserChangeRepository(ctx context.Context) model.UserChangeRepository
+	LoginAttemptRepository(ctx context.Context) model.LoginAttemptRepository
 }
 
 type LockableUnitOfWork interface {
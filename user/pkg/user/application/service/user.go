@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
 	"github.com/google/uuid"
@@ -15,6 +16,9 @@ import (
 type UserService interface {
 	StoreUser(ctx context.Context, user appmodel.User) (uuid.UUID, error)
 	SetUserStatus(ctx context.Context, userID uuid.UUID, status int) error
+	// UnlockUser ends lockout expiring at lockedUntil unless the user was locked again or status was changed since
+	UnlockUser(ctx context.Context, userID uuid.UUID, lockedUntil time.Time) error
+	SetUserRole(ctx context.Context, userID uuid.UUID, role string) error
 	FindUser(ctx context.Context, userID uuid.UUID) (appmodel.User, error)
 	DeleteUser(ctx context.Context, userID uuid.UUID, hard bool) error
 }
@@ -53,7 +57,7 @@ func (s *userService) StoreUser(ctx context.Context, user appmodel.User) (uuid.U
 
 	userID := user.UserID
 	err := s.luow.Execute(ctx, lockNames, func(provider RepositoryProvider) error {
-		domainService := s.domainService(ctx, provider.UserRepository(ctx))
+		domainService := s.domainService(ctx, provider)
 		if user.UserID == uuid.Nil {
 			uID, err := domainService.CreateUser(model.UserStatus(user.Status), user.Login)
 			if err != nil {
@@ -84,7 +88,19 @@ func (s *userService) StoreUser(ctx context.Context, user appmodel.User) (uuid.U
 
 func (s *userService) SetUserStatus(ctx context.Context, userID uuid.UUID, status int) error {
 	return s.luow.Execute(ctx, []string{userLock(userID)}, func(provider RepositoryProvider) error {
-		return s.domainService(ctx, provider.UserRepository(ctx)).UpdateUserStatus(userID, model.UserStatus(status))
+		return s.domainService(ctx, provider).UpdateUserStatus(userID, model.UserStatus(status))
+	})
+}
+
+func (s *userService) UnlockUser(ctx context.Context, userID uuid.UUID, lockedUntil time.Time) error {
+	return s.luow.Execute(ctx, []string{userLock(userID)}, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).UnlockUser(userID, lockedUntil)
+	})
+}
+
+func (s *userService) SetUserRole(ctx context.Context, userID uuid.UUID, role string) error {
+	return s.luow.Execute(ctx, []string{userLock(userID)}, func(provider RepositoryProvider) error {
+		return s.domainService(ctx, provider).UpdateUserRole(userID, model.Role(role))
 	})
 }
 
@@ -98,9 +114,14 @@ func (s *userService) FindUser(ctx context.Context, userID uuid.UUID) (appmodel.
 		user = appmodel.User{
 			UserID:   domainUser.UserID,
 			Status:   int(domainUser.Status),
+			Role:     string(domainUser.Role),
 			Login:    domainUser.Login,
 			Email:    domainUser.Email,
 			Telegram: domainUser.Telegram,
+
+			EmailVerified:    domainUser.EmailVerified,
+			TelegramVerified: domainUser.TelegramVerified,
+			CreatedAt:        domainUser.CreatedAt,
 		}
 		return nil
 	})
@@ -109,12 +130,17 @@ func (s *userService) FindUser(ctx context.Context, userID uuid.UUID) (appmodel.
 
 func (s *userService) DeleteUser(ctx context.Context, userID uuid.UUID, hard bool) error {
 	return s.luow.Execute(ctx, []string{userLock(userID)}, func(provider RepositoryProvider) error {
-		return s.domainService(ctx, provider.UserRepository(ctx)).DeleteUser(userID, hard)
+		return s.domainService(ctx, provider).DeleteUser(userID, hard)
 	})
 }
 
-func (s *userService) domainService(ctx context.Context, repository model.UserRepository) service.UserService {
-	return service.NewUserService(repository, s.domainEventDispatcher(ctx))
+func (s *userService) domainService(ctx context.Context, provider RepositoryProvider) service.UserService {
+	return service.NewUserService(
+		provider.UserRepository(ctx),
+		provider.UserChangeRepository(ctx),
+		actorFromContext(ctx),
+		s.domainEventDispatcher(ctx),
+	)
 }
 
 func (s *userService) domainEventDispatcher(ctx context.Context) domain.EventDispatcher {
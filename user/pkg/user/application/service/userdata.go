@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+
+	"user/pkg/user/domain/service"
+)
+
+// UserDataService erases data kept by user service, data of other services is erased by their own API
+type UserDataService interface {
+	// EraseUser returns number of deleted records
+	EraseUser(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+func NewUserDataService(
+	luow LockableUnitOfWork,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+) UserDataService {
+	return &userDataService{
+		luow:            luow,
+		eventDispatcher: eventDispatcher,
+	}
+}
+
+type userDataService struct {
+	luow            LockableUnitOfWork
+	eventDispatcher outbox.EventDispatcher[outbox.Event]
+}
+
+func (s *userDataService) EraseUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	var deleted int
+	err := s.luow.Execute(ctx, []string{userLock(userID)}, func(provider RepositoryProvider) error {
+		var err error
+		deleted, err = service.NewUserDataService(
+			provider.UserRepository(ctx),
+			provider.RefreshTokenRepository(ctx),
+			provider.ContactVerificationRepository(ctx),
+			provider.PreferencesRepository(ctx),
+			provider.UserChangeRepository(ctx),
+			provider.LoginAttemptRepository(ctx),
+			&domainEventDispatcher{
+				ctx:             ctx,
+				eventDispatcher: s.eventDispatcher,
+			},
+		).EraseUser(userID)
+		return err
+	})
+	return deleted, err
+}
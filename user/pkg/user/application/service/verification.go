@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/application/outbox"
+	"github.com/google/uuid"
+
+	appmodel "user/pkg/user/application/model"
+	"user/pkg/user/domain/model"
+	"user/pkg/user/domain/service"
+)
+
+type ContactVerificationService interface {
+	StartVerification(ctx context.Context, verificationID, userID uuid.UUID, contactType string) (appmodel.ContactVerification, error)
+	ConfirmContact(ctx context.Context, verification appmodel.ContactVerification, code string) error
+	// RequestVerification returns ID of the user found by login whose contact can be sent a new code
+	RequestVerification(ctx context.Context, login, contactType string) (uuid.UUID, error)
+}
+
+func NewContactVerificationService(
+	uow UnitOfWork,
+	luow LockableUnitOfWork,
+	eventDispatcher outbox.EventDispatcher[outbox.Event],
+	codeHasher model.PasswordHasher,
+) ContactVerificationService {
+	return &contactVerificationService{
+		uow:             uow,
+		luow:            luow,
+		eventDispatcher: eventDispatcher,
+		codeHasher:      codeHasher,
+	}
+}
+
+type contactVerificationService struct {
+	uow             UnitOfWork
+	luow            LockableUnitOfWork
+	eventDispatcher outbox.EventDispatcher[outbox.Event]
+	codeHasher      model.PasswordHasher
+}
+
+func (s *contactVerificationService) StartVerification(ctx context.Context, verificationID, userID uuid.UUID, contactType string) (appmodel.ContactVerification, error) {
+	var verification model.ContactVerification
+	err := s.luow.Execute(ctx, []string{userLock(userID)}, func(provider RepositoryProvider) error {
+		var err error
+		verification, err = s.domainService(ctx, provider).StartVerification(verificationID, userID, model.ContactType(contactType))
+		return err
+	})
+	if err != nil {
+		return appmodel.ContactVerification{}, err
+	}
+	return toAppContactVerification(verification), nil
+}
+
+func (s *contactVerificationService) ConfirmContact(ctx context.Context, verification appmodel.ContactVerification, code string) error {
+	var confirmErr error
+	err := s.luow.Execute(ctx, []string{userLock(verification.UserID)}, func(provider RepositoryProvider) error {
+		err := s.domainService(ctx, provider).ConfirmContact(verification.VerificationID, code)
+		if errors.Is(err, model.ErrInvalidVerificationCode) || errors.Is(err, model.ErrVerificationAttemptsExceeded) {
+			// failed attempt must be committed
+			confirmErr = err
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return confirmErr
+}
+
+func (s *contactVerificationService) RequestVerification(ctx context.Context, login, contactType string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := s.uow.Execute(ctx, func(provider RepositoryProvider) error {
+		var err error
+		userID, err = s.domainService(ctx, provider).RequestVerification(login, model.ContactType(contactType))
+		return err
+	})
+	return userID, err
+}
+
+func (s *contactVerificationService) domainService(ctx context.Context, provider RepositoryProvider) service.ContactVerificationService {
+	eventDispatcher := &domainEventDispatcher{
+		ctx:             ctx,
+		eventDispatcher: s.eventDispatcher,
+	}
+	return service.NewContactVerificationService(
+		provider.UserRepository(ctx),
+		service.NewUserService(provider.UserRepository(ctx), provider.UserChangeRepository(ctx), actorFromContext(ctx), eventDispatcher),
+		provider.ContactVerificationRepository(ctx),
+		s.codeHasher,
+		eventDispatcher,
+	)
+}
+
+func toAppContactVerification(verification model.ContactVerification) appmodel.ContactVerification {
+	return appmodel.ContactVerification{
+		VerificationID: verification.VerificationID,
+		UserID:         verification.UserID,
+		ContactType:    string(verification.ContactType),
+		ExpiresAt:      verification.ExpiresAt,
+	}
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCredentials  = errors.New("invalid login or password")
+	ErrInvalidPassword     = errors.New("password must be from 8 to 72 bytes long")
+	ErrContactRequired     = errors.New("email or telegram is required")
+	ErrUserNotActive       = errors.New("user is not active")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used")
+)
+
+const (
+	MinPasswordLength = 8
+	// MaxPasswordLength is limited by bcrypt which ignores the rest of password
+	MaxPasswordLength = 72
+)
+
+// PasswordHasher hides hashing algorithm, hash contains algorithm parameters and salt
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Compare returns ErrInvalidCredentials when password does not match hash
+	Compare(hash, password string) error
+}
+
+// RefreshToken is a login session, refresh token is rotated on every use
+type RefreshToken struct {
+	TokenID   uuid.UUID
+	UserID    uuid.UUID
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+func (t RefreshToken) Active(at time.Time) bool {
+	return t.RevokedAt == nil && at.Before(t.ExpiresAt)
+}
+
+type RefreshTokenRepository interface {
+	NextID() (uuid.UUID, error)
+	Store(token RefreshToken) error
+	// Find returns ErrInvalidRefreshToken for unknown token
+	Find(tokenID uuid.UUID) (*RefreshToken, error)
+	// RevokeByUser revokes all active tokens of the user
+	RevokeByUser(userID uuid.UUID, at time.Time) error
+	// DeleteByUser deletes all tokens of the user and returns their number
+	DeleteByUser(userID uuid.UUID) (int, error)
+}
@@ -37,6 +37,68 @@ func (u UserUpdated) Type() string {
 	return "user_updated"
 }
 
+// UserLocked is lockout of the user after failed logins, contacts are set to alert the owner
+type UserLocked struct {
+	UserID      uuid.UUID
+	Login       string
+	Email       *string
+	Telegram    *string
+	LockedUntil time.Time
+	LockedAt    time.Time
+}
+
+func (u UserLocked) Type() string {
+	return "user_locked"
+}
+
+type UserRoleChanged struct {
+	UserID    uuid.UUID
+	Role      Role
+	UpdatedAt time.Time
+}
+
+func (u UserRoleChanged) Type() string {
+	return "user_role_changed"
+}
+
+// UserPreferencesChanged carries preferences of the user, notifications are rendered in the locale
+type UserPreferencesChanged struct {
+	UserID    uuid.UUID
+	Locale    string
+	TimeZone  string
+	UpdatedAt time.Time
+}
+
+func (u UserPreferencesChanged) Type() string {
+	return "user_preferences_changed"
+}
+
+// ContactVerificationRequested carries one-time code to be delivered to the contact
+type ContactVerificationRequested struct {
+	VerificationID uuid.UUID
+	UserID         uuid.UUID
+	Login          string
+	ContactType    ContactType
+	Contact        string
+	Code           string
+	ExpiresAt      time.Time
+}
+
+func (c ContactVerificationRequested) Type() string {
+	return "contact_verification_requested"
+}
+
+type ContactVerified struct {
+	UserID      uuid.UUID
+	ContactType ContactType
+	Contact     string
+	VerifiedAt  time.Time
+}
+
+func (c ContactVerified) Type() string {
+	return "contact_verified"
+}
+
 type UserDeleted struct {
 	UserID    uuid.UUID
 	Status    UserStatus
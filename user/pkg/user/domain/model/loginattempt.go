@@ -0,0 +1,42 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTooManyLoginAttempts = errors.New("too many failed login attempts, try again later")
+	ErrUserLocked           = errors.New("user is temporarily locked after failed login attempts")
+)
+
+// LoginThrottling limits failed logins in sliding windows: account is locked for LockoutDuration
+// after AccountMaxFailures failures within AccountWindow, IP is refused after IPMaxFailures failures within IPWindow
+type LoginThrottling struct {
+	AccountMaxFailures int
+	AccountWindow      time.Duration
+	IPMaxFailures      int
+	IPWindow           time.Duration
+	LockoutDuration    time.Duration
+}
+
+// LoginAttempt is failed login, Login may be unknown since failures of unknown logins are counted per IP
+type LoginAttempt struct {
+	AttemptID   uuid.UUID
+	Login       string
+	IP          string
+	AttemptedAt time.Time
+}
+
+type LoginAttemptRepository interface {
+	NextID() (uuid.UUID, error)
+	Store(attempt LoginAttempt) error
+	CountByLogin(login string, since time.Time) (int, error)
+	CountByIP(ip string, since time.Time) (int, error)
+	// DeleteByLogin deletes failed logins of the account and returns their number
+	DeleteByLogin(login string) (int, error)
+	// DeleteBefore deletes failed logins which are out of all windows
+	DeleteBefore(before time.Time) error
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPreferencesNotFound = errors.New("preferences not found")
+	ErrInvalidLocale       = errors.New("invalid locale")
+	ErrInvalidTimeZone     = errors.New("invalid time zone")
+)
+
+const (
+	DefaultLocale   = "en"
+	DefaultTimeZone = "UTC"
+)
+
+// Preferences are personal settings of the user, they are initialised with defaults on onboarding
+type Preferences struct {
+	UserID    uuid.UUID
+	Locale    string
+	TimeZone  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type PreferencesRepository interface {
+	Store(preferences Preferences) error
+	// Find returns ErrPreferencesNotFound when preferences are not initialised
+	Find(userID uuid.UUID) (*Preferences, error)
+	// Delete returns number of deleted preferences
+	Delete(userID uuid.UUID) (int, error)
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"errors"
+	"slices"
+)
+
+var ErrInvalidRole = errors.New("invalid role")
+
+type Role string
+
+const (
+	RoleCustomer  Role = "customer"
+	RoleSupport   Role = "support"
+	RoleWarehouse Role = "warehouse"
+	RoleAdmin     Role = "admin"
+)
+
+func (r Role) Valid() bool {
+	return slices.Contains([]Role{RoleCustomer, RoleSupport, RoleWarehouse, RoleAdmin}, r)
+}
+
+// Permission is checked by services for each RPC, permissions with ".any" suffix
+// allow access to resources of other users, without it only own resources are accessible
+type Permission string
+
+const (
+	PermissionUserRead      Permission = "user.read"
+	PermissionUserReadAny   Permission = "user.read.any"
+	PermissionUserWrite     Permission = "user.write"
+	PermissionUserWriteAny  Permission = "user.write.any"
+	PermissionUserRoleWrite Permission = "user.role.write"
+	// PermissionUserDataExport and PermissionUserDataErase allow data subject requests of any user across services
+	PermissionUserDataExport Permission = "user.data.export"
+	PermissionUserDataErase  Permission = "user.data.erase"
+	// PermissionUserOnboard is not granted to roles, it is carried only by tokens of user onboarding workflow
+	PermissionUserOnboard Permission = "user.onboard"
+
+	PermissionOrderCreate    Permission = "order.create"
+	PermissionOrderCreateAny Permission = "order.create.any"
+	PermissionOrderRead      Permission = "order.read"
+	PermissionOrderReadAny   Permission = "order.read.any"
+
+	PermissionPaymentRead         Permission = "payment.read"
+	PermissionPaymentReadAny      Permission = "payment.read.any"
+	PermissionPaymentBalanceWrite Permission = "payment.balance.write"
+
+	PermissionInventoryCatalogRead  Permission = "inventory.catalog.read"
+	PermissionInventoryCatalogWrite Permission = "inventory.catalog.write"
+	PermissionInventoryStockRead    Permission = "inventory.stock.read"
+	PermissionInventoryStockWrite   Permission = "inventory.stock.write"
+
+	PermissionNotificationRead          Permission = "notification.read"
+	PermissionNotificationReadAny       Permission = "notification.read.any"
+	PermissionNotificationWrite         Permission = "notification.write"
+	PermissionNotificationWriteAny      Permission = "notification.write.any"
+	PermissionNotificationTemplateRead  Permission = "notification.template.read"
+	PermissionNotificationTemplateWrite Permission = "notification.template.write"
+)
+
+// Access is role of the user with permissions granted to the role, it is carried in access token
+type Access struct {
+	Role        Role
+	Permissions []Permission
+}
+
+type RolePermissionRepository interface {
+	ListPermissions(role Role) ([]Permission, error)
+}
@@ -22,15 +22,35 @@ const (
 	Deleted
 )
 
+func (s UserStatus) String() string {
+	switch s {
+	case Blocked:
+		return "blocked"
+	case Active:
+		return "active"
+	case Deleted:
+		return "deleted"
+	}
+	return "unknown"
+}
+
 type User struct {
-	UserID    uuid.UUID
-	Status    UserStatus
-	Login     string
-	Email     *string
-	Telegram  *string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt *time.Time
+	UserID   uuid.UUID
+	Status   UserStatus
+	Role     Role
+	Login    string
+	Email    *string
+	Telegram *string
+	// EmailVerified and TelegramVerified are reset when contact is changed
+	EmailVerified    bool
+	TelegramVerified bool
+	// PasswordHash is nil for users without password credentials
+	PasswordHash *string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    *time.Time
+	// LockedUntil is set for user blocked after failed logins, explicit change of status removes the lockout
+	LockedUntil *time.Time
 }
 
 type FindSpec struct {
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fields of the user recorded in UserChange
+const (
+	UserFieldLogin    = "login"
+	UserFieldStatus   = "status"
+	UserFieldRole     = "role"
+	UserFieldEmail    = "email"
+	UserFieldTelegram = "telegram"
+)
+
+// UserChange is audit record of changed field of the user, nil value means the field was not set
+type UserChange struct {
+	ChangeID uuid.UUID
+	UserID   uuid.UUID
+	Field    string
+	OldValue *string
+	NewValue *string
+	// ActorID is user who made the change, nil for changes made by the system
+	ActorID   *uuid.UUID
+	ChangedAt time.Time
+}
+
+type UserChangeRepository interface {
+	NextID() (uuid.UUID, error)
+	Store(change UserChange) error
+	// DeleteByUser returns number of deleted changes
+	DeleteByUser(userID uuid.UUID) (int, error)
+}
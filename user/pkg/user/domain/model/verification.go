@@ -0,0 +1,76 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidContactType           = errors.New("invalid contact type")
+	ErrContactNotSet                = errors.New("contact not set")
+	ErrVerificationNotFound         = errors.New("contact verification not found")
+	ErrInvalidVerificationCode      = errors.New("invalid verification code")
+	ErrVerificationExpired          = errors.New("contact verification expired")
+	ErrVerificationAttemptsExceeded = errors.New("contact verification attempts exceeded")
+	ErrContactAlreadyVerified       = errors.New("contact already verified")
+	ErrTooManyVerificationRequests  = errors.New("too many contact verification requests")
+)
+
+const (
+	VerificationCodeTTL     = 15 * time.Minute
+	MaxVerificationAttempts = 5
+	// MaxVerificationRequests limits codes sent to the contact of the user within VerificationRequestWindow
+	MaxVerificationRequests   = 5
+	VerificationRequestWindow = time.Hour
+)
+
+type ContactType string
+
+const (
+	ContactEmail    ContactType = "email"
+	ContactTelegram ContactType = "telegram"
+)
+
+func (t ContactType) Valid() bool {
+	return t == ContactEmail || t == ContactTelegram
+}
+
+// Contact returns current contact of the user with the type
+func (u User) Contact(contactType ContactType) *string {
+	if contactType == ContactEmail {
+		return u.Email
+	}
+	return u.Telegram
+}
+
+func (u User) ContactVerified(contactType ContactType) bool {
+	if contactType == ContactEmail {
+		return u.EmailVerified
+	}
+	return u.TelegramVerified
+}
+
+// ContactVerification is one-time code sent to the contact, only hash of the code is stored
+type ContactVerification struct {
+	VerificationID uuid.UUID
+	UserID         uuid.UUID
+	ContactType    ContactType
+	Contact        string
+	CodeHash       string
+	Attempts       int
+	ExpiresAt      time.Time
+	VerifiedAt     *time.Time
+	CreatedAt      time.Time
+}
+
+type ContactVerificationRepository interface {
+	Store(verification ContactVerification) error
+	// Find returns ErrVerificationNotFound for unknown verification
+	Find(verificationID uuid.UUID) (*ContactVerification, error)
+	// CountCreatedSince returns number of verifications of the user contact created not earlier than since
+	CountCreatedSince(userID uuid.UUID, contactType ContactType, since time.Time) (int, error)
+	// DeleteByUser deletes all verifications of the user and returns their number
+	DeleteByUser(userID uuid.UUID) (int, error)
+}
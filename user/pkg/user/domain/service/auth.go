@@ -0,0 +1,184 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"user/pkg/user/domain/model"
+)
+
+type AuthService interface {
+	SetPassword(userID uuid.UUID, password string) error
+	// Authenticate returns ID of active user with the login and password,
+	// locked and inactive users get the same error whatever password is, so it is not guessed while they can not log in
+	Authenticate(login, password string) (uuid.UUID, error)
+	StartSession(userID uuid.UUID, ttl time.Duration) (model.RefreshToken, error)
+	// RotateSession replaces refresh token with a new one,
+	// reuse of revoked token ends all sessions of the user and returns ErrRefreshTokenReused
+	RotateSession(tokenID uuid.UUID, ttl time.Duration) (model.RefreshToken, error)
+	EndSession(tokenID uuid.UUID) error
+	// Access returns role of the user with its permissions to be put in access token
+	Access(userID uuid.UUID) (model.Access, error)
+}
+
+func NewAuthService(
+	userRepository model.UserRepository,
+	refreshTokenRepository model.RefreshTokenRepository,
+	rolePermissionRepository model.RolePermissionRepository,
+	passwordHasher model.PasswordHasher,
+) AuthService {
+	return &authService{
+		userRepository:           userRepository,
+		refreshTokenRepository:   refreshTokenRepository,
+		rolePermissionRepository: rolePermissionRepository,
+		passwordHasher:           passwordHasher,
+	}
+}
+
+type authService struct {
+	userRepository           model.UserRepository
+	refreshTokenRepository   model.RefreshTokenRepository
+	rolePermissionRepository model.RolePermissionRepository
+	passwordHasher           model.PasswordHasher
+}
+
+func (a authService) SetPassword(userID uuid.UUID, password string) error {
+	if len(password) < model.MinPasswordLength || len(password) > model.MaxPasswordLength {
+		return model.ErrInvalidPassword
+	}
+	user, err := a.userRepository.Find(model.FindSpec{
+		UserID: &userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	hash, err := a.passwordHasher.Hash(password)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = &hash
+	user.UpdatedAt = time.Now()
+	err = a.userRepository.Store(*user)
+	if err != nil {
+		return err
+	}
+
+	// sessions started with old password must not outlive it
+	return a.refreshTokenRepository.RevokeByUser(userID, user.UpdatedAt)
+}
+
+func (a authService) Authenticate(login, password string) (uuid.UUID, error) {
+	user, err := a.userRepository.Find(model.FindSpec{
+		Login: &login,
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrUserNotFound) {
+			return uuid.Nil, model.ErrInvalidCredentials
+		}
+		return uuid.Nil, err
+	}
+	if user.PasswordHash == nil {
+		return uuid.Nil, model.ErrInvalidCredentials
+	}
+
+	// status is revealed only with correct password, so it does not tell whether the login exists
+	err = a.passwordHasher.Compare(*user.PasswordHash, password)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if user.LockedUntil != nil {
+		return uuid.Nil, model.ErrUserLocked
+	}
+	if user.Status != model.Active {
+		return uuid.Nil, model.ErrUserNotActive
+	}
+	return user.UserID, nil
+}
+
+func (a authService) StartSession(userID uuid.UUID, ttl time.Duration) (model.RefreshToken, error) {
+	tokenID, err := a.refreshTokenRepository.NextID()
+	if err != nil {
+		return model.RefreshToken{}, err
+	}
+
+	currentTime := time.Now()
+	token := model.RefreshToken{
+		TokenID:   tokenID,
+		UserID:    userID,
+		ExpiresAt: currentTime.Add(ttl),
+		CreatedAt: currentTime,
+	}
+	return token, a.refreshTokenRepository.Store(token)
+}
+
+func (a authService) RotateSession(tokenID uuid.UUID, ttl time.Duration) (model.RefreshToken, error) {
+	token, err := a.refreshTokenRepository.Find(tokenID)
+	if err != nil {
+		return model.RefreshToken{}, err
+	}
+
+	currentTime := time.Now()
+	if token.RevokedAt != nil {
+		// revoked token is presented only when it was stolen or replayed
+		err = a.refreshTokenRepository.RevokeByUser(token.UserID, currentTime)
+		if err != nil {
+			return model.RefreshToken{}, err
+		}
+		return model.RefreshToken{}, model.ErrRefreshTokenReused
+	}
+	if !token.Active(currentTime) {
+		return model.RefreshToken{}, model.ErrInvalidRefreshToken
+	}
+
+	user, err := a.userRepository.Find(model.FindSpec{
+		UserID: &token.UserID,
+	})
+	if err != nil {
+		return model.RefreshToken{}, err
+	}
+	if user.Status != model.Active {
+		return model.RefreshToken{}, model.ErrUserNotActive
+	}
+
+	token.RevokedAt = &currentTime
+	err = a.refreshTokenRepository.Store(*token)
+	if err != nil {
+		return model.RefreshToken{}, err
+	}
+	return a.StartSession(token.UserID, ttl)
+}
+
+func (a authService) EndSession(tokenID uuid.UUID) error {
+	token, err := a.refreshTokenRepository.Find(tokenID)
+	if err != nil {
+		return err
+	}
+	if token.RevokedAt != nil {
+		return nil
+	}
+
+	currentTime := time.Now()
+	token.RevokedAt = &currentTime
+	return a.refreshTokenRepository.Store(*token)
+}
+
+func (a authService) Access(userID uuid.UUID) (model.Access, error) {
+	user, err := a.userRepository.Find(model.FindSpec{
+		UserID: &userID,
+	})
+	if err != nil {
+		return model.Access{}, err
+	}
+
+	permissions, err := a.rolePermissionRepository.ListPermissions(user.Role)
+	if err != nil {
+		return model.Access{}, err
+	}
+	return model.Access{
+		Role:        user.Role,
+		Permissions: permissions,
+	}, nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"user/pkg/user/domain/model"
+)
+
+type LoginThrottlingService interface {
+	// CheckIP returns ErrTooManyLoginAttempts when failed logins from the IP exceed the limit
+	CheckIP(ip string) error
+	// RecordFailure stores failed login and locks the account when its failed logins reach the limit
+	RecordFailure(login, ip string) error
+	// Reset forgets failed logins of the account after successful login
+	Reset(login string) error
+}
+
+func NewLoginThrottlingService(
+	attemptRepository model.LoginAttemptRepository,
+	userRepository model.UserRepository,
+	userService UserService,
+	throttling model.LoginThrottling,
+) LoginThrottlingService {
+	return &loginThrottlingService{
+		attemptRepository: attemptRepository,
+		userRepository:    userRepository,
+		userService:       userService,
+		throttling:        throttling,
+	}
+}
+
+type loginThrottlingService struct {
+	attemptRepository model.LoginAttemptRepository
+	userRepository    model.UserRepository
+	userService       UserService
+	throttling        model.LoginThrottling
+}
+
+func (s loginThrottlingService) CheckIP(ip string) error {
+	failures, err := s.attemptRepository.CountByIP(ip, time.Now().Add(-s.throttling.IPWindow))
+	if err != nil {
+		return err
+	}
+	if failures >= s.throttling.IPMaxFailures {
+		return model.ErrTooManyLoginAttempts
+	}
+	return nil
+}
+
+func (s loginThrottlingService) RecordFailure(login, ip string) error {
+	attemptID, err := s.attemptRepository.NextID()
+	if err != nil {
+		return err
+	}
+	currentTime := time.Now()
+	err = s.attemptRepository.Store(model.LoginAttempt{
+		AttemptID:   attemptID,
+		Login:       login,
+		IP:          ip,
+		AttemptedAt: currentTime,
+	})
+	if err != nil {
+		return err
+	}
+	err = s.attemptRepository.DeleteBefore(currentTime.Add(-max(s.throttling.AccountWindow, s.throttling.IPWindow)))
+	if err != nil {
+		return err
+	}
+
+	failures, err := s.attemptRepository.CountByLogin(login, currentTime.Add(-s.throttling.AccountWindow))
+	if err != nil {
+		return err
+	}
+	if failures < s.throttling.AccountMaxFailures {
+		return nil
+	}
+
+	user, err := s.userRepository.Find(model.FindSpec{
+		Login: &login,
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrUserNotFound) {
+			// unknown login has no account to lock, it is limited by IP only
+			return nil
+		}
+		return err
+	}
+	err = s.userService.LockUser(user.UserID, currentTime.Add(s.throttling.LockoutDuration))
+	if err != nil {
+		return err
+	}
+	// failures are counted anew after lockout
+	_, err = s.attemptRepository.DeleteByLogin(login)
+	return err
+}
+
+func (s loginThrottlingService) Reset(login string) error {
+	_, err := s.attemptRepository.DeleteByLogin(login)
+	return err
+}
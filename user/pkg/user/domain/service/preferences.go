@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+
+	"user/pkg/common/domain"
+	"user/pkg/user/domain/model"
+)
+
+// localePattern matches language code with optional region, e.g. en or pt-BR
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+type PreferencesService interface {
+	// InitPreferences stores default preferences of the user, existing preferences are kept
+	InitPreferences(userID uuid.UUID) (model.Preferences, error)
+	// UpdatePreferences changes set fields, preferences are initialised with defaults if they are missing
+	UpdatePreferences(userID uuid.UUID, locale, timeZone *string) (model.Preferences, error)
+	DeletePreferences(userID uuid.UUID) error
+}
+
+func NewPreferencesService(
+	userRepository model.UserRepository,
+	preferencesRepository model.PreferencesRepository,
+	eventDispatcher domain.EventDispatcher,
+) PreferencesService {
+	return &preferencesService{
+		userRepository:        userRepository,
+		preferencesRepository: preferencesRepository,
+		eventDispatcher:       eventDispatcher,
+	}
+}
+
+type preferencesService struct {
+	userRepository        model.UserRepository
+	preferencesRepository model.PreferencesRepository
+	eventDispatcher       domain.EventDispatcher
+}
+
+func (s preferencesService) InitPreferences(userID uuid.UUID) (model.Preferences, error) {
+	preferences, found, err := s.findPreferences(userID)
+	if err != nil || found {
+		return preferences, err
+	}
+	return preferences, s.storePreferences(preferences)
+}
+
+func (s preferencesService) UpdatePreferences(userID uuid.UUID, locale, timeZone *string) (model.Preferences, error) {
+	if locale != nil && !localePattern.MatchString(*locale) {
+		return model.Preferences{}, model.ErrInvalidLocale
+	}
+	if timeZone != nil && !validTimeZone(*timeZone) {
+		return model.Preferences{}, model.ErrInvalidTimeZone
+	}
+
+	preferences, found, err := s.findPreferences(userID)
+	if err != nil {
+		return model.Preferences{}, err
+	}
+	changed := !found
+	if locale != nil && *locale != preferences.Locale {
+		preferences.Locale = *locale
+		changed = true
+	}
+	if timeZone != nil && *timeZone != preferences.TimeZone {
+		preferences.TimeZone = *timeZone
+		changed = true
+	}
+	if !changed {
+		return preferences, nil
+	}
+	preferences.UpdatedAt = time.Now()
+	return preferences, s.storePreferences(preferences)
+}
+
+func (s preferencesService) DeletePreferences(userID uuid.UUID) error {
+	_, err := s.preferencesRepository.Delete(userID)
+	return err
+}
+
+// findPreferences returns stored preferences of the user or defaults if they are not initialised
+func (s preferencesService) findPreferences(userID uuid.UUID) (model.Preferences, bool, error) {
+	_, err := s.userRepository.Find(model.FindSpec{UserID: &userID})
+	if err != nil {
+		return model.Preferences{}, false, err
+	}
+
+	preferences, err := s.preferencesRepository.Find(userID)
+	if err == nil {
+		return *preferences, true, nil
+	}
+	if !errors.Is(err, model.ErrPreferencesNotFound) {
+		return model.Preferences{}, false, err
+	}
+
+	currentTime := time.Now()
+	return model.Preferences{
+		UserID:    userID,
+		Locale:    model.DefaultLocale,
+		TimeZone:  model.DefaultTimeZone,
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+	}, false, nil
+}
+
+func (s preferencesService) storePreferences(preferences model.Preferences) error {
+	err := s.preferencesRepository.Store(preferences)
+	if err != nil {
+		return err
+	}
+	return s.eventDispatcher.Dispatch(&model.UserPreferencesChanged{
+		UserID:    preferences.UserID,
+		Locale:    preferences.Locale,
+		TimeZone:  preferences.TimeZone,
+		UpdatedAt: preferences.UpdatedAt,
+	})
+}
+
+// validTimeZone accepts IANA time zone names, empty name and Local are resolved by server settings and are not allowed
+func validTimeZone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
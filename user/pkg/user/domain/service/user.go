@@ -13,28 +13,67 @@ import (
 
 type UserService interface {
 	CreateUser(status model.UserStatus, login string) (uuid.UUID, error)
+	// RegisterUser creates blocked user with contacts, contacts are sent with UserCreated
+	// so caller starts their verification itself
+	RegisterUser(login string, email, telegram *string) (uuid.UUID, error)
+	// UpdateUserStatus keeps lockout of the user in effect, only deletion ends it
 	UpdateUserStatus(userID uuid.UUID, status model.UserStatus) error
+	// LockUser blocks active user until lockedUntil, users with other statuses are left as is
+	LockUser(userID uuid.UUID, lockedUntil time.Time) error
+	// UnlockUser ends lockout expiring not later than lockedUntil if it is still in effect,
+	// user is activated if one of contacts is verified
+	UnlockUser(userID uuid.UUID, lockedUntil time.Time) error
+	// VerifyContact marks contact of the user verified, blocked user which is not locked out becomes active
+	VerifyContact(userID uuid.UUID, contactType model.ContactType) error
+	UpdateUserRole(userID uuid.UUID, role model.Role) error
 	UpdateUserEmail(userID uuid.UUID, email *string) error
 	UpdateUserTelegram(userID uuid.UUID, telegram *string) error
 	DeleteUser(userID uuid.UUID, hard bool) error
 }
 
+// NewUserService records every change of the user made by the actor, nil actor is the system
 func NewUserService(
 	userRepository model.UserRepository,
+	changeRepository model.UserChangeRepository,
+	actorID *uuid.UUID,
 	eventDispatcher domain.EventDispatcher,
 ) UserService {
 	return &userService{
-		userRepository:  userRepository,
-		eventDispatcher: eventDispatcher,
+		userRepository:   userRepository,
+		changeRepository: changeRepository,
+		actorID:          actorID,
+		eventDispatcher:  eventDispatcher,
 	}
 }
 
 type userService struct {
-	userRepository  model.UserRepository
-	eventDispatcher domain.EventDispatcher
+	userRepository   model.UserRepository
+	changeRepository model.UserChangeRepository
+	actorID          *uuid.UUID
+	eventDispatcher  domain.EventDispatcher
 }
 
 func (u userService) CreateUser(status model.UserStatus, login string) (uuid.UUID, error) {
+	return u.createUser(status, login, nil, nil)
+}
+
+func (u userService) RegisterUser(login string, email, telegram *string) (uuid.UUID, error) {
+	if email != nil {
+		err := u.checkContactUnused(uuid.Nil, model.FindSpec{Email: email}, model.ErrUserEmailAlreadyUsed)
+		if err != nil {
+			return uuid.Nil, err
+		}
+	}
+	if telegram != nil {
+		err := u.checkContactUnused(uuid.Nil, model.FindSpec{Telegram: telegram}, model.ErrUserTelegramAlreadyUsed)
+		if err != nil {
+			return uuid.Nil, err
+		}
+	}
+	return u.createUser(model.Blocked, login, email, telegram)
+}
+
+func (u userService) createUser(status model.UserStatus, login string, email, telegram *string) (uuid.UUID, error) {
 	_, err := u.userRepository.Find(model.FindSpec{
 		Login: &login,
 	})
@@ -54,18 +93,43 @@ func (u userService) CreateUser(status model.UserStatus, login string) (uuid.UUI
 	err = u.userRepository.Store(model.User{
 		UserID:    userID,
 		Status:    status,
+		Role:      model.RoleCustomer,
 		Login:     login,
+		Email:     email,
+		Telegram:  telegram,
 		CreatedAt: currentTime,
 		UpdatedAt: currentTime,
 	})
 	if err != nil {
 		return uuid.Nil, err
 	}
+	err = u.recordChange(userID, model.UserFieldLogin, nil, &login, currentTime)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	err = u.recordChange(userID, model.UserFieldStatus, nil, toPtr(status.String()), currentTime)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if email != nil {
+		err = u.recordChange(userID, model.UserFieldEmail, nil, email, currentTime)
+		if err != nil {
+			return uuid.Nil, err
+		}
+	}
+	if telegram != nil {
+		err = u.recordChange(userID, model.UserFieldTelegram, nil, telegram, currentTime)
+		if err != nil {
+			return uuid.Nil, err
+		}
+	}
 
 	return userID, u.eventDispatcher.Dispatch(&model.UserCreated{
 		UserID:    userID,
 		Status:    status,
 		Login:     login,
+		Email:     email,
+		Telegram:  telegram,
 		CreatedAt: currentTime,
 	})
 }
@@ -78,20 +142,102 @@ func (u userService) UpdateUserStatus(userID uuid.UUID, status model.UserStatus)
 		return err
 	}
 
+	// lockout is ended only by UnlockUser, which restores status from verified contacts
+	if user.LockedUntil != nil && status != model.Deleted {
+		return nil
+	}
 	if user.Status == status {
 		return nil
 	}
+	return u.setStatus(user, status, nil)
+}
 
+func (u userService) LockUser(userID uuid.UUID, lockedUntil time.Time) error {
+	user, err := u.userRepository.Find(model.FindSpec{
+		UserID: &userID,
+	})
+	if err != nil {
+		return err
+	}
+	if user.Status != model.Active {
+		return nil
+	}
+
+	// lockout is stored with precision of seconds, so it is compared with stored value on unlock
+	lockedUntil = lockedUntil.Truncate(time.Second)
+	err = u.setStatus(user, model.Blocked, &lockedUntil)
+	if err != nil {
+		return err
+	}
+	return u.eventDispatcher.Dispatch(&model.UserLocked{
+		UserID:      userID,
+		Login:       user.Login,
+		Email:       user.Email,
+		Telegram:    user.Telegram,
+		LockedUntil: lockedUntil,
+		LockedAt:    user.UpdatedAt,
+	})
+}
+
+func (u userService) UnlockUser(userID uuid.UUID, lockedUntil time.Time) error {
+	user, err := u.userRepository.Find(model.FindSpec{
+		UserID: &userID,
+	})
+	if err != nil {
+		return err
+	}
+	// status was changed explicitly or user was locked again for a longer time
+	if user.LockedUntil == nil || user.LockedUntil.After(lockedUntil) {
+		return nil
+	}
+	// contacts may be changed during lockout, user without verified contact stays blocked until verification
+	status := model.Blocked
+	if (user.Email != nil && user.EmailVerified) || (user.Telegram != nil && user.TelegramVerified) {
+		status = model.Active
+	}
+	return u.setStatus(user, status, nil)
+}
+
+func (u userService) VerifyContact(userID uuid.UUID, contactType model.ContactType) error {
+	user, err := u.userRepository.Find(model.FindSpec{
+		UserID: &userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if contactType == model.ContactEmail {
+		user.EmailVerified = true
+	} else {
+		user.TelegramVerified = true
+	}
+	if user.Status == model.Blocked && user.LockedUntil == nil {
+		return u.setStatus(user, model.Active, nil)
+	}
+	user.UpdatedAt = time.Now()
+	return u.userRepository.Store(*user)
+}
+
+func (u userService) setStatus(user *model.User, status model.UserStatus, lockedUntil *time.Time) error {
 	currentTime := time.Now()
+	oldStatus := user.Status
 	user.Status = status
+	user.LockedUntil = lockedUntil
 	user.UpdatedAt = currentTime
-	err = u.userRepository.Store(*user)
+	err := u.userRepository.Store(*user)
+	if err != nil {
+		return err
+	}
+	if oldStatus == status {
+		return nil
+	}
+	err = u.recordChange(user.UserID, model.UserFieldStatus, toPtr(oldStatus.String()), toPtr(status.String()), currentTime)
 	if err != nil {
 		return err
 	}
 
 	return u.eventDispatcher.Dispatch(&model.UserUpdated{
-		UserID:    userID,
+		UserID:    user.UserID,
 		UpdatedAt: currentTime,
 		UpdatedFields: &struct {
 			Status   *model.UserStatus
@@ -101,6 +247,41 @@ func (u userService) UpdateUserStatus(userID uuid.UUID, status model.UserStatus)
 	})
 }
 
+func (u userService) UpdateUserRole(userID uuid.UUID, role model.Role) error {
+	if !role.Valid() {
+		return model.ErrInvalidRole
+	}
+	user, err := u.userRepository.Find(model.FindSpec{
+		UserID: &userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if user.Role == role {
+		return nil
+	}
+
+	currentTime := time.Now()
+	oldRole := user.Role
+	user.Role = role
+	user.UpdatedAt = currentTime
+	err = u.userRepository.Store(*user)
+	if err != nil {
+		return err
+	}
+	err = u.recordChange(userID, model.UserFieldRole, toPtr(string(oldRole)), toPtr(string(role)), currentTime)
+	if err != nil {
+		return err
+	}
+
+	return u.eventDispatcher.Dispatch(&model.UserRoleChanged{
+		UserID:    userID,
+		Role:      role,
+		UpdatedAt: currentTime,
+	})
+}
+
 func (u userService) UpdateUserEmail(userID uuid.UUID, email *string) error {
 	user, err := u.userRepository.Find(model.FindSpec{
 		UserID: &userID,
@@ -113,24 +294,25 @@ func (u userService) UpdateUserEmail(userID uuid.UUID, email *string) error {
 	}
 
 	if email != nil {
-		userWithEmail, err := u.userRepository.Find(model.FindSpec{
-			Email: email,
-		})
-		if err != nil && !errors.Is(err, model.ErrUserNotFound) {
+		err = u.checkContactUnused(user.UserID, model.FindSpec{Email: email}, model.ErrUserEmailAlreadyUsed)
+		if err != nil {
 			return err
 		}
-		if userWithEmail != nil && userWithEmail.UserID != user.UserID {
-			return model.ErrUserEmailAlreadyUsed
-		}
 	}
 
 	currentTime := time.Now()
+	oldEmail := user.Email
 	user.Email = email
+	user.EmailVerified = false
 	user.UpdatedAt = currentTime
 	err = u.userRepository.Store(*user)
 	if err != nil {
 		return err
 	}
+	err = u.recordChange(userID, model.UserFieldEmail, oldEmail, email, currentTime)
+	if err != nil {
+		return err
+	}
 
 	if email == nil {
 		return u.eventDispatcher.Dispatch(&model.UserUpdated{
@@ -166,24 +348,25 @@ func (u userService) UpdateUserTelegram(userID uuid.UUID, telegram *string) erro
 	}
 
 	if telegram != nil {
-		userWithTelegram, err := u.userRepository.Find(model.FindSpec{
-			Telegram: telegram,
-		})
-		if err != nil && !errors.Is(err, model.ErrUserNotFound) {
+		err = u.checkContactUnused(user.UserID, model.FindSpec{Telegram: telegram}, model.ErrUserTelegramAlreadyUsed)
+		if err != nil {
 			return err
 		}
-		if userWithTelegram != nil && userWithTelegram.UserID != user.UserID {
-			return model.ErrUserTelegramAlreadyUsed
-		}
 	}
 
 	currentTime := time.Now()
+	oldTelegram := user.Telegram
 	user.Telegram = telegram
+	user.TelegramVerified = false
 	user.UpdatedAt = currentTime
 	err = u.userRepository.Store(*user)
 	if err != nil {
 		return err
 	}
+	err = u.recordChange(userID, model.UserFieldTelegram, oldTelegram, telegram, currentTime)
+	if err != nil {
+		return err
+	}
 
 	if telegram == nil {
 		return u.eventDispatcher.Dispatch(&model.UserUpdated{
@@ -215,27 +398,71 @@ func (u userService) DeleteUser(userID uuid.UUID, hard bool) error {
 		return err
 	}
 
+	currentTime := time.Now()
 	if hard {
 		err = u.userRepository.HardDelete(userID)
 		if err != nil {
 			return err
 		}
+		// history of the user is not kept after hard delete
+		_, err = u.changeRepository.DeleteByUser(userID)
+		if err != nil {
+			return err
+		}
+		return u.eventDispatcher.Dispatch(&model.UserDeleted{
+			UserID:    userID,
+			Status:    model.Deleted,
+			DeletedAt: currentTime,
+			Hard:      true,
+		})
 	}
 
-	currentTime := time.Now()
+	oldStatus := user.Status
 	user.Status = model.Deleted
 	user.UpdatedAt = currentTime
 	user.DeletedAt = &currentTime
+	user.LockedUntil = nil
 	err = u.userRepository.Store(*user)
 	if err != nil {
 		return err
 	}
+	err = u.recordChange(userID, model.UserFieldStatus, toPtr(oldStatus.String()), toPtr(model.Deleted.String()), currentTime)
+	if err != nil {
+		return err
+	}
 
 	return u.eventDispatcher.Dispatch(&model.UserDeleted{
 		UserID:    userID,
 		Status:    model.Deleted,
 		DeletedAt: currentTime,
-		Hard:      hard,
+	})
+}
+
+// checkContactUnused returns errContactUsed when user found by the spec is not the user with userID
+func (u userService) checkContactUnused(userID uuid.UUID, spec model.FindSpec, errContactUsed error) error {
+	userWithContact, err := u.userRepository.Find(spec)
+	if err != nil && !errors.Is(err, model.ErrUserNotFound) {
+		return err
+	}
+	if userWithContact != nil && userWithContact.UserID != userID {
+		return errContactUsed
+	}
+	return nil
+}
+
+func (u userService) recordChange(userID uuid.UUID, field string, oldValue, newValue *string, changedAt time.Time) error {
+	changeID, err := u.changeRepository.NextID()
+	if err != nil {
+		return err
+	}
+	return u.changeRepository.Store(model.UserChange{
+		ChangeID:  changeID,
+		UserID:    userID,
+		Field:     field,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		ActorID:   u.actorID,
+		ChangedAt: changedAt,
 	})
 }
 
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"user/pkg/common/domain"
+	"user/pkg/user/domain/model"
+)
+
+// UserDataService erases personal data of the user kept by user service,
+// nothing is kept as user service has no financial records
+type UserDataService interface {
+	// EraseUser deletes user with sessions, contact verifications, preferences, change history
+	// and failed logins and returns number of deleted records
+	EraseUser(userID uuid.UUID) (int, error)
+}
+
+func NewUserDataService(
+	userRepository model.UserRepository,
+	refreshTokenRepository model.RefreshTokenRepository,
+	verificationRepository model.ContactVerificationRepository,
+	preferencesRepository model.PreferencesRepository,
+	changeRepository model.UserChangeRepository,
+	attemptRepository model.LoginAttemptRepository,
+	eventDispatcher domain.EventDispatcher,
+) UserDataService {
+	return &userDataService{
+		userRepository:         userRepository,
+		refreshTokenRepository: refreshTokenRepository,
+		verificationRepository: verificationRepository,
+		preferencesRepository:  preferencesRepository,
+		changeRepository:       changeRepository,
+		attemptRepository:      attemptRepository,
+		eventDispatcher:        eventDispatcher,
+	}
+}
+
+type userDataService struct {
+	userRepository         model.UserRepository
+	refreshTokenRepository model.RefreshTokenRepository
+	verificationRepository model.ContactVerificationRepository
+	preferencesRepository  model.PreferencesRepository
+	changeRepository       model.UserChangeRepository
+	attemptRepository      model.LoginAttemptRepository
+	eventDispatcher        domain.EventDispatcher
+}
+
+func (s userDataService) EraseUser(userID uuid.UUID) (int, error) {
+	user, err := s.userRepository.Find(model.FindSpec{UserID: &userID})
+	if err != nil {
+		return 0, err
+	}
+
+	tokens, err := s.refreshTokenRepository.DeleteByUser(userID)
+	if err != nil {
+		return 0, err
+	}
+	verifications, err := s.verificationRepository.DeleteByUser(userID)
+	if err != nil {
+		return 0, err
+	}
+	preferences, err := s.preferencesRepository.Delete(userID)
+	if err != nil {
+		return 0, err
+	}
+	// history keeps old contacts of the user, so it is personal data too
+	changes, err := s.changeRepository.DeleteByUser(userID)
+	if err != nil {
+		return 0, err
+	}
+	// failed logins keep IP addresses the user logged in from
+	attempts, err := s.attemptRepository.DeleteByLogin(user.Login)
+	if err != nil {
+		return 0, err
+	}
+	err = s.userRepository.HardDelete(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return tokens + verifications + preferences + changes + attempts + 1, s.eventDispatcher.Dispatch(&model.UserDeleted{
+		UserID:    userID,
+		Status:    model.Deleted,
+		DeletedAt: time.Now(),
+		Hard:      true,
+	})
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+
+	"user/pkg/common/domain"
+	"user/pkg/user/domain/model"
+)
+
+type ContactVerificationService interface {
+	// RequestVerification returns ID of the user whose contact can be sent a new code,
+	// codes are limited by model.MaxVerificationRequests within model.VerificationRequestWindow
+	RequestVerification(login string, contactType model.ContactType) (uuid.UUID, error)
+	// StartVerification sends one-time code to current contact of the user,
+	// verificationID is assigned by caller to address the verification before it is stored
+	StartVerification(verificationID, userID uuid.UUID, contactType model.ContactType) (model.ContactVerification, error)
+	// ConfirmContact checks the code, on success contact becomes verified and blocked user becomes active.
+	// Failed attempt is counted even when error is returned
+	ConfirmContact(verificationID uuid.UUID, code string) error
+}
+
+// NewContactVerificationService changes verified user with userService, so status change is recorded
+func NewContactVerificationService(
+	userRepository model.UserRepository,
+	userService UserService,
+	verificationRepository model.ContactVerificationRepository,
+	codeHasher model.PasswordHasher,
+	eventDispatcher domain.EventDispatcher,
+) ContactVerificationService {
+	return &contactVerificationService{
+		userRepository:         userRepository,
+		userService:            userService,
+		verificationRepository: verificationRepository,
+		codeHasher:             codeHasher,
+		eventDispatcher:        eventDispatcher,
+	}
+}
+
+type contactVerificationService struct {
+	userRepository         model.UserRepository
+	userService            UserService
+	verificationRepository model.ContactVerificationRepository
+	codeHasher             model.PasswordHasher
+	eventDispatcher        domain.EventDispatcher
+}
+
+func (c contactVerificationService) RequestVerification(login string, contactType model.ContactType) (uuid.UUID, error) {
+	if !contactType.Valid() {
+		return uuid.Nil, model.ErrInvalidContactType
+	}
+	user, err := c.userRepository.Find(model.FindSpec{
+		Login: &login,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if user.Contact(contactType) == nil {
+		return uuid.Nil, model.ErrContactNotSet
+	}
+	if user.ContactVerified(contactType) {
+		return uuid.Nil, model.ErrContactAlreadyVerified
+	}
+	return user.UserID, c.checkVerificationRequests(user.UserID, contactType, time.Now())
+}
+
+func (c contactVerificationService) StartVerification(verificationID, userID uuid.UUID, contactType model.ContactType) (model.ContactVerification, error) {
+	if !contactType.Valid() {
+		return model.ContactVerification{}, model.ErrInvalidContactType
+	}
+	user, err := c.userRepository.Find(model.FindSpec{
+		UserID: &userID,
+	})
+	if err != nil {
+		return model.ContactVerification{}, err
+	}
+	contact := user.Contact(contactType)
+	if contact == nil {
+		return model.ContactVerification{}, model.ErrContactNotSet
+	}
+	currentTime := time.Now()
+	// limit is checked again since requests may be started concurrently
+	err = c.checkVerificationRequests(userID, contactType, currentTime)
+	if err != nil {
+		return model.ContactVerification{}, err
+	}
+
+	code, err := generateVerificationCode()
+	if err != nil {
+		return model.ContactVerification{}, err
+	}
+	codeHash, err := c.codeHasher.Hash(code)
+	if err != nil {
+		return model.ContactVerification{}, err
+	}
+
+	verification := model.ContactVerification{
+		VerificationID: verificationID,
+		UserID:         userID,
+		ContactType:    contactType,
+		Contact:        *contact,
+		CodeHash:       codeHash,
+		ExpiresAt:      currentTime.Add(model.VerificationCodeTTL),
+		CreatedAt:      currentTime,
+	}
+	err = c.verificationRepository.Store(verification)
+	if err != nil {
+		return model.ContactVerification{}, err
+	}
+
+	return verification, c.eventDispatcher.Dispatch(&model.ContactVerificationRequested{
+		VerificationID: verificationID,
+		UserID:         userID,
+		Login:          user.Login,
+		ContactType:    contactType,
+		Contact:        *contact,
+		Code:           code,
+		ExpiresAt:      verification.ExpiresAt,
+	})
+}
+
+func (c contactVerificationService) ConfirmContact(verificationID uuid.UUID, code string) error {
+	verification, err := c.verificationRepository.Find(verificationID)
+	if err != nil {
+		return err
+	}
+	if verification.VerifiedAt != nil {
+		return nil
+	}
+
+	currentTime := time.Now()
+	if !currentTime.Before(verification.ExpiresAt) {
+		return model.ErrVerificationExpired
+	}
+	if verification.Attempts >= model.MaxVerificationAttempts {
+		return model.ErrVerificationAttemptsExceeded
+	}
+
+	verification.Attempts++
+	err = c.codeHasher.Compare(verification.CodeHash, code)
+	if err != nil {
+		if !errors.Is(err, model.ErrInvalidCredentials) {
+			return err
+		}
+		err = c.verificationRepository.Store(*verification)
+		if err != nil {
+			return err
+		}
+		if verification.Attempts >= model.MaxVerificationAttempts {
+			return model.ErrVerificationAttemptsExceeded
+		}
+		return model.ErrInvalidVerificationCode
+	}
+
+	user, err := c.userRepository.Find(model.FindSpec{
+		UserID: &verification.UserID,
+	})
+	if err != nil {
+		return err
+	}
+	contact := user.Contact(verification.ContactType)
+	if contact == nil || *contact != verification.Contact {
+		// code was sent to contact replaced since then
+		return model.ErrVerificationExpired
+	}
+
+	verification.VerifiedAt = &currentTime
+	err = c.verificationRepository.Store(*verification)
+	if err != nil {
+		return err
+	}
+
+	err = c.userService.VerifyContact(user.UserID, verification.ContactType)
+	if err != nil {
+		return err
+	}
+
+	return c.eventDispatcher.Dispatch(&model.ContactVerified{
+		UserID:      user.UserID,
+		ContactType: verification.ContactType,
+		Contact:     verification.Contact,
+		VerifiedAt:  currentTime,
+	})
+}
+
+func (c contactVerificationService) checkVerificationRequests(userID uuid.UUID, contactType model.ContactType, currentTime time.Time) error {
+	count, err := c.verificationRepository.CountCreatedSince(userID, contactType, currentTime.Add(-model.VerificationRequestWindow))
+	if err != nil {
+		return err
+	}
+	if count >= model.MaxVerificationRequests {
+		return model.ErrTooManyVerificationRequests
+	}
+	return nil
+}
+
+func generateVerificationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
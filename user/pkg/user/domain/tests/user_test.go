@@ -2,6 +2,7 @@ package tests
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -16,7 +17,7 @@ import (
 func TestUserService_CreateUser_Success(t *testing.T) {
 	repo := new(MockUserRepository)
 	dispatcher := new(MockEventDispatcher)
-	userService := service.NewUserService(repo, dispatcher)
+	userService := service.NewUserService(repo, new(MockUserChangeRepository), nil, dispatcher)
 
 	login := "john_doe"
 	userID := uuid.New()
@@ -40,7 +41,7 @@ func TestUserService_CreateUser_Success(t *testing.T) {
 		return ok && evt.UserID == userID && evt.Login == login
 	})).Return(nil)
 
-	resultID, err := userService.CreateUser(model.Active, login)
+	resultID, err := userService.CreateUser(model.Blocked, login)
 	require.NoError(t, err)
 	assert.Equal(t, userID, resultID)
 
@@ -51,7 +52,7 @@ func TestUserService_CreateUser_Success(t *testing.T) {
 func TestUserService_CreateUser_LoginAlreadyUsed(t *testing.T) {
 	repo := new(MockUserRepository)
 	dispatcher := new(MockEventDispatcher)
-	userService := service.NewUserService(repo, dispatcher)
+	userService := service.NewUserService(repo, new(MockUserChangeRepository), nil, dispatcher)
 
 	login := "existing_user"
 	existingUser := &model.User{UserID: uuid.New(), Login: login}
@@ -65,10 +66,63 @@ func TestUserService_CreateUser_LoginAlreadyUsed(t *testing.T) {
 	dispatcher.AssertNotCalled(t, "Dispatch")
 }
 
+func TestUserService_RegisterUser(t *testing.T) {
+	repo := new(MockUserRepository)
+	changeRepo := new(MockUserChangeRepository)
+	dispatcher := new(MockEventDispatcher)
+	userService := service.NewUserService(repo, changeRepo, nil, dispatcher)
+
+	login := "john_doe"
+	email := "john@example.com"
+	userID := uuid.New()
+
+	repo.On("Find", mock.AnythingOfType("model.FindSpec")).Return((*model.User)(nil), model.ErrUserNotFound)
+	repo.On("NextID").Return(userID, nil)
+	repo.On("Store", mock.MatchedBy(func(u model.User) bool {
+		return u.UserID == userID &&
+			u.Status == model.Blocked &&
+			u.Email != nil && *u.Email == email &&
+			!u.EmailVerified &&
+			u.Telegram == nil
+	})).Return(nil)
+	dispatcher.On("Dispatch", mock.MatchedBy(func(e domain.Event) bool {
+		evt, ok := e.(*model.UserCreated)
+		return ok && evt.UserID == userID && evt.Email != nil && *evt.Email == email && evt.Telegram == nil
+	})).Return(nil)
+
+	resultID, err := userService.RegisterUser(login, &email, nil)
+	require.NoError(t, err)
+	assert.Equal(t, userID, resultID)
+
+	// verification of the contacts is started by caller, so no UserUpdated is dispatched
+	repo.AssertNumberOfCalls(t, "Store", 1)
+	dispatcher.AssertNumberOfCalls(t, "Dispatch", 1)
+	require.Len(t, changeRepo.Changes, 3)
+	assert.Equal(t, model.UserFieldEmail, changeRepo.Changes[2].Field)
+	assert.Equal(t, email, *changeRepo.Changes[2].NewValue)
+}
+
+func TestUserService_RegisterUser_EmailAlreadyUsed(t *testing.T) {
+	repo := new(MockUserRepository)
+	dispatcher := new(MockEventDispatcher)
+	userService := service.NewUserService(repo, new(MockUserChangeRepository), nil, dispatcher)
+
+	email := "taken@example.com"
+	repo.On("Find", mock.MatchedBy(func(spec model.FindSpec) bool {
+		return spec.Email != nil && *spec.Email == email
+	})).Return(&model.User{UserID: uuid.New(), Email: &email}, nil)
+
+	_, err := userService.RegisterUser("john_doe", &email, nil)
+	require.ErrorIs(t, err, model.ErrUserEmailAlreadyUsed)
+
+	repo.AssertNotCalled(t, "Store", mock.Anything)
+	dispatcher.AssertNotCalled(t, "Dispatch", mock.Anything)
+}
+
 func TestUserService_UpdateUserStatus_Success(t *testing.T) {
 	repo := new(MockUserRepository)
 	dispatcher := new(MockEventDispatcher)
-	userService := service.NewUserService(repo, dispatcher)
+	userService := service.NewUserService(repo, new(MockUserChangeRepository), nil, dispatcher)
 
 	userID := uuid.New()
 	oldStatus := model.Blocked
@@ -98,10 +152,33 @@ func TestUserService_UpdateUserStatus_Success(t *testing.T) {
 	dispatcher.AssertExpectations(t)
 }
 
+func TestUserService_UpdateUserStatus_LockedUser(t *testing.T) {
+	repo := new(MockUserRepository)
+	dispatcher := new(MockEventDispatcher)
+	userService := service.NewUserService(repo, new(MockUserChangeRepository), nil, dispatcher)
+
+	userID := uuid.New()
+	lockedUntil := time.Now().Add(30 * time.Minute)
+	user := &model.User{UserID: userID, Status: model.Blocked, LockedUntil: &lockedUntil}
+	repo.On("Find", mock.AnythingOfType("model.FindSpec")).Return(user, nil)
+
+	// status synced after contact change does not end lockout
+	require.NoError(t, userService.UpdateUserStatus(userID, model.Active))
+	require.NoError(t, userService.UpdateUserStatus(userID, model.Blocked))
+	repo.AssertNotCalled(t, "Store", mock.Anything)
+
+	repo.On("Store", mock.MatchedBy(func(u model.User) bool {
+		return u.Status == model.Deleted && u.LockedUntil == nil
+	})).Return(nil).Once()
+	dispatcher.On("Dispatch", mock.AnythingOfType("*model.UserUpdated")).Return(nil).Once()
+	require.NoError(t, userService.UpdateUserStatus(userID, model.Deleted))
+	repo.AssertExpectations(t)
+}
+
 func TestUserService_UpdateUserStatus_NoChange(t *testing.T) {
 	repo := new(MockUserRepository)
 	dispatcher := new(MockEventDispatcher)
-	userService := service.NewUserService(repo, dispatcher)
+	userService := service.NewUserService(repo, new(MockUserChangeRepository), nil, dispatcher)
 
 	userID := uuid.New()
 	status := model.Active
@@ -116,10 +193,37 @@ func TestUserService_UpdateUserStatus_NoChange(t *testing.T) {
 	dispatcher.AssertNotCalled(t, "Dispatch")
 }
 
+func TestUserService_UpdateUserRole(t *testing.T) {
+	repo := new(MockUserRepository)
+	dispatcher := new(MockEventDispatcher)
+	userService := service.NewUserService(repo, new(MockUserChangeRepository), nil, dispatcher)
+
+	userID := uuid.New()
+	user := &model.User{UserID: userID, Status: model.Active, Role: model.RoleCustomer}
+
+	repo.On("Find", mock.AnythingOfType("model.FindSpec")).Return(user, nil)
+	repo.On("Store", mock.MatchedBy(func(u model.User) bool {
+		return u.UserID == userID && u.Role == model.RoleSupport
+	})).Return(nil)
+	dispatcher.On("Dispatch", mock.MatchedBy(func(e domain.Event) bool {
+		evt, ok := e.(*model.UserRoleChanged)
+		return ok && evt.UserID == userID && evt.Role == model.RoleSupport
+	})).Return(nil)
+
+	err := userService.UpdateUserRole(userID, model.RoleSupport)
+	require.NoError(t, err)
+
+	err = userService.UpdateUserRole(userID, "superuser")
+	require.ErrorIs(t, err, model.ErrInvalidRole)
+
+	repo.AssertExpectations(t)
+	dispatcher.AssertNumberOfCalls(t, "Dispatch", 1)
+}
+
 func TestUserService_UpdateUserEmail_Success(t *testing.T) {
 	repo := new(MockUserRepository)
 	dispatcher := new(MockEventDispatcher)
-	userService := service.NewUserService(repo, dispatcher)
+	userService := service.NewUserService(repo, new(MockUserChangeRepository), nil, dispatcher)
 
 	userID := uuid.New()
 	email := "new@example.com"
@@ -155,7 +259,7 @@ func TestUserService_UpdateUserEmail_Success(t *testing.T) {
 func TestUserService_UpdateUserEmail_EmailAlreadyUsed(t *testing.T) {
 	repo := new(MockUserRepository)
 	dispatcher := new(MockEventDispatcher)
-	userService := service.NewUserService(repo, dispatcher)
+	userService := service.NewUserService(repo, new(MockUserChangeRepository), nil, dispatcher)
 
 	userID := uuid.New()
 	email := "taken@example.com"
@@ -180,7 +284,7 @@ func TestUserService_UpdateUserEmail_EmailAlreadyUsed(t *testing.T) {
 func TestUserService_UpdateUserEmail_Remove(t *testing.T) {
 	repo := new(MockUserRepository)
 	dispatcher := new(MockEventDispatcher)
-	userService := service.NewUserService(repo, dispatcher)
+	userService := service.NewUserService(repo, new(MockUserChangeRepository), nil, dispatcher)
 
 	userID := uuid.New()
 	oldEmail := "old@example.com"
@@ -206,7 +310,7 @@ func TestUserService_UpdateUserEmail_Remove(t *testing.T) {
 func TestUserService_UpdateUserTelegram_TelegramAlreadyUsed(t *testing.T) {
 	repo := new(MockUserRepository)
 	dispatcher := new(MockEventDispatcher)
-	userService := service.NewUserService(repo, dispatcher)
+	userService := service.NewUserService(repo, new(MockUserChangeRepository), nil, dispatcher)
 
 	userID := uuid.New()
 	telegram := "@taken"
@@ -228,7 +332,8 @@ func TestUserService_UpdateUserTelegram_TelegramAlreadyUsed(t *testing.T) {
 func TestUserService_DeleteUser_SoftDelete(t *testing.T) {
 	repo := new(MockUserRepository)
 	dispatcher := new(MockEventDispatcher)
-	userService := service.NewUserService(repo, dispatcher)
+	changeRepo := new(MockUserChangeRepository)
+	userService := service.NewUserService(repo, changeRepo, nil, dispatcher)
 
 	userID := uuid.New()
 	user := &model.User{UserID: userID, Status: model.Active}
@@ -251,26 +356,32 @@ func TestUserService_DeleteUser_SoftDelete(t *testing.T) {
 
 	repo.AssertExpectations(t)
 	dispatcher.AssertExpectations(t)
+	repo.AssertNotCalled(t, "HardDelete", mock.Anything)
+	require.Len(t, changeRepo.Changes, 1)
+	assert.Equal(t, model.UserFieldStatus, changeRepo.Changes[0].Field)
+	assert.Equal(t, model.Deleted.String(), *changeRepo.Changes[0].NewValue)
 }
 
 func TestUserService_DeleteUser_HardDelete(t *testing.T) {
 	repo := new(MockUserRepository)
 	dispatcher := new(MockEventDispatcher)
-	userService := service.NewUserService(repo, dispatcher)
+	changeRepo := &MockUserChangeRepository{}
+	userService := service.NewUserService(repo, changeRepo, nil, dispatcher)
 
 	userID := uuid.New()
 	user := &model.User{UserID: userID, Status: model.Active}
+	otherUserID := uuid.New()
+	changeRepo.Changes = []model.UserChange{
+		{ChangeID: uuid.New(), UserID: userID, Field: model.UserFieldLogin},
+		{ChangeID: uuid.New(), UserID: otherUserID, Field: model.UserFieldLogin},
+	}
 
 	repo.On("Find", mock.AnythingOfType("model.FindSpec")).Return(user, nil)
 	repo.On("HardDelete", userID).Return(nil)
 
-	repo.On("Store", mock.MatchedBy(func(u model.User) bool {
-		return u.UserID == userID && u.Status == model.Deleted
-	})).Return(nil)
-
 	dispatcher.On("Dispatch", mock.MatchedBy(func(e domain.Event) bool {
 		evt, ok := e.(*model.UserDeleted)
-		return ok && evt.Hard
+		return ok && evt.UserID == userID && evt.Hard && evt.Status == model.Deleted
 	})).Return(nil)
 
 	err := userService.DeleteUser(userID, true)
@@ -278,12 +389,16 @@ func TestUserService_DeleteUser_HardDelete(t *testing.T) {
 
 	repo.AssertExpectations(t)
 	dispatcher.AssertExpectations(t)
+	// deleted row is not stored again
+	repo.AssertNotCalled(t, "Store", mock.Anything)
+	require.Len(t, changeRepo.Changes, 1)
+	assert.Equal(t, otherUserID, changeRepo.Changes[0].UserID)
 }
 
 func TestUserService_UserNotFound(t *testing.T) {
 	repo := new(MockUserRepository)
 	dispatcher := new(MockEventDispatcher)
-	userService := service.NewUserService(repo, dispatcher)
+	userService := service.NewUserService(repo, new(MockUserChangeRepository), nil, dispatcher)
 
 	userID := uuid.New()
 	repo.On("Find", mock.AnythingOfType("model.FindSpec")).Return((*model.User)(nil), model.ErrUserNotFound)
@@ -305,6 +420,606 @@ func TestUserService_UserNotFound(t *testing.T) {
 	}
 }
 
+func TestAuthService_SetPassword_InvalidPassword(t *testing.T) {
+	repo := new(MockUserRepository)
+	tokenRepo := new(MockRefreshTokenRepository)
+	authService := service.NewAuthService(repo, tokenRepo, new(MockRolePermissionRepository), new(MockPasswordHasher))
+
+	err := authService.SetPassword(uuid.New(), "short")
+	require.ErrorIs(t, err, model.ErrInvalidPassword)
+
+	repo.AssertNotCalled(t, "Store")
+}
+
+func TestAuthService_Authenticate(t *testing.T) {
+	repo := new(MockUserRepository)
+	tokenRepo := new(MockRefreshTokenRepository)
+	hasher := new(MockPasswordHasher)
+	authService := service.NewAuthService(repo, tokenRepo, new(MockRolePermissionRepository), hasher)
+
+	login := "john_doe"
+	hash := "hash"
+	user := &model.User{UserID: uuid.New(), Status: model.Active, Login: login, PasswordHash: &hash}
+
+	repo.On("Find", mock.MatchedBy(func(spec model.FindSpec) bool {
+		return spec.Login != nil && *spec.Login == login
+	})).Return(user, nil)
+	repo.On("Find", mock.AnythingOfType("model.FindSpec")).Return((*model.User)(nil), model.ErrUserNotFound)
+	hasher.On("Compare", hash, "secret-password").Return(nil)
+	hasher.On("Compare", hash, mock.AnythingOfType("string")).Return(model.ErrInvalidCredentials)
+
+	userID, err := authService.Authenticate(login, "secret-password")
+	require.NoError(t, err)
+	assert.Equal(t, user.UserID, userID)
+
+	_, err = authService.Authenticate(login, "wrong-password")
+	require.ErrorIs(t, err, model.ErrInvalidCredentials)
+
+	_, err = authService.Authenticate("unknown", "secret-password")
+	require.ErrorIs(t, err, model.ErrInvalidCredentials)
+
+	// status is not revealed without correct password
+	user.Status = model.Blocked
+	_, err = authService.Authenticate(login, "secret-password")
+	require.ErrorIs(t, err, model.ErrUserNotActive)
+	_, err = authService.Authenticate(login, "wrong-password")
+	require.ErrorIs(t, err, model.ErrInvalidCredentials)
+}
+
+func TestAuthService_Authenticate_LockedUser(t *testing.T) {
+	repo := new(MockUserRepository)
+	hasher := new(MockPasswordHasher)
+	authService := service.NewAuthService(repo, new(MockRefreshTokenRepository), new(MockRolePermissionRepository), hasher)
+
+	login := "john_doe"
+	hash := "hash"
+	lockedUntil := time.Now().Add(30 * time.Minute)
+	user := &model.User{UserID: uuid.New(), Status: model.Blocked, Login: login, PasswordHash: &hash, LockedUntil: &lockedUntil}
+
+	repo.On("Find", mock.MatchedBy(func(spec model.FindSpec) bool {
+		return spec.Login != nil && *spec.Login == login
+	})).Return(user, nil)
+	repo.On("Find", mock.AnythingOfType("model.FindSpec")).Return((*model.User)(nil), model.ErrUserNotFound)
+	hasher.On("Compare", hash, "secret-password").Return(nil)
+	hasher.On("Compare", hash, mock.AnythingOfType("string")).Return(model.ErrInvalidCredentials)
+
+	// lockout is revealed only with correct password, so it does not tell whether the login exists
+	_, err := authService.Authenticate(login, "secret-password")
+	require.ErrorIs(t, err, model.ErrUserLocked)
+	_, err = authService.Authenticate(login, "wrong-password")
+	require.ErrorIs(t, err, model.ErrInvalidCredentials)
+	_, err = authService.Authenticate("unknown", "wrong-password")
+	require.ErrorIs(t, err, model.ErrInvalidCredentials)
+}
+
+func TestAuthService_RotateSession(t *testing.T) {
+	repo := new(MockUserRepository)
+	tokenRepo := new(MockRefreshTokenRepository)
+	authService := service.NewAuthService(repo, tokenRepo, new(MockRolePermissionRepository), new(MockPasswordHasher))
+
+	user := &model.User{UserID: uuid.New(), Status: model.Active}
+	token := &model.RefreshToken{TokenID: uuid.New(), UserID: user.UserID, ExpiresAt: time.Now().Add(time.Hour)}
+	newTokenID := uuid.New()
+
+	repo.On("Find", mock.AnythingOfType("model.FindSpec")).Return(user, nil)
+	tokenRepo.On("Find", token.TokenID).Return(token, nil)
+	tokenRepo.On("NextID").Return(newTokenID, nil)
+	tokenRepo.On("Store", mock.MatchedBy(func(t model.RefreshToken) bool {
+		return t.TokenID == token.TokenID && t.RevokedAt != nil
+	})).Return(nil)
+	tokenRepo.On("Store", mock.MatchedBy(func(t model.RefreshToken) bool {
+		return t.TokenID == newTokenID && t.UserID == user.UserID && t.RevokedAt == nil
+	})).Return(nil)
+
+	newToken, err := authService.RotateSession(token.TokenID, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, newTokenID, newToken.TokenID)
+
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestAuthService_RotateSession_ReusedToken(t *testing.T) {
+	repo := new(MockUserRepository)
+	tokenRepo := new(MockRefreshTokenRepository)
+	authService := service.NewAuthService(repo, tokenRepo, new(MockRolePermissionRepository), new(MockPasswordHasher))
+
+	revokedAt := time.Now()
+	token := &model.RefreshToken{TokenID: uuid.New(), UserID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}
+
+	tokenRepo.On("Find", token.TokenID).Return(token, nil)
+	tokenRepo.On("RevokeByUser", token.UserID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	_, err := authService.RotateSession(token.TokenID, time.Hour)
+	require.ErrorIs(t, err, model.ErrRefreshTokenReused)
+
+	tokenRepo.AssertExpectations(t)
+	tokenRepo.AssertNotCalled(t, "NextID")
+}
+
+func TestAuthService_Access(t *testing.T) {
+	repo := new(MockUserRepository)
+	permissionRepo := new(MockRolePermissionRepository)
+	authService := service.NewAuthService(repo, new(MockRefreshTokenRepository), permissionRepo, new(MockPasswordHasher))
+
+	user := &model.User{UserID: uuid.New(), Status: model.Active, Role: model.RoleWarehouse}
+	permissions := []model.Permission{model.PermissionInventoryStockRead, model.PermissionInventoryStockWrite}
+
+	repo.On("Find", mock.AnythingOfType("model.FindSpec")).Return(user, nil)
+	permissionRepo.On("ListPermissions", model.RoleWarehouse).Return(permissions, nil)
+
+	access, err := authService.Access(user.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, model.RoleWarehouse, access.Role)
+	assert.Equal(t, permissions, access.Permissions)
+}
+
+func TestContactVerificationService_StartVerification(t *testing.T) {
+	repo := new(MockUserRepository)
+	verificationRepo := new(MockContactVerificationRepository)
+	hasher := new(MockPasswordHasher)
+	dispatcher := new(MockEventDispatcher)
+	userService := service.NewUserService(repo, new(MockUserChangeRepository), nil, dispatcher)
+	verificationService := service.NewContactVerificationService(repo, userService, verificationRepo, hasher, dispatcher)
+
+	email := "john@example.com"
+	user := &model.User{UserID: uuid.New(), Status: model.Blocked, Login: "john_doe", Email: &email}
+	verificationID := uuid.New()
+	var code string
+
+	repo.On("Find", mock.AnythingOfType("model.FindSpec")).Return(user, nil)
+	verificationRepo.On("CountCreatedSince", user.UserID, model.ContactEmail, mock.AnythingOfType("time.Time")).Return(0, nil)
+	hasher.On("Hash", mock.AnythingOfType("string")).Return("code-hash", nil)
+	verificationRepo.On("Store", mock.MatchedBy(func(v model.ContactVerification) bool {
+		return v.VerificationID == verificationID && v.Contact == email && v.CodeHash == "code-hash" && v.Attempts == 0
+	})).Return(nil)
+	dispatcher.On("Dispatch", mock.MatchedBy(func(e domain.Event) bool {
+		evt, ok := e.(*model.ContactVerificationRequested)
+		if ok {
+			code = evt.Code
+		}
+		return ok && evt.Contact == email && evt.ContactType == model.ContactEmail
+	})).Return(nil)
+
+	verification, err := verificationService.StartVerification(verificationID, user.UserID, model.ContactEmail)
+	require.NoError(t, err)
+	assert.True(t, verification.ExpiresAt.After(time.Now()))
+	assert.Len(t, code, 6)
+	hasher.AssertCalled(t, "Hash", code)
+
+	_, err = verificationService.StartVerification(verificationID, user.UserID, model.ContactTelegram)
+	require.ErrorIs(t, err, model.ErrContactNotSet)
+}
+
+func TestContactVerificationService_StartVerification_TooManyRequests(t *testing.T) {
+	repo := new(MockUserRepository)
+	verificationRepo := new(MockContactVerificationRepository)
+	dispatcher := new(MockEventDispatcher)
+	userService := service.NewUserService(repo, new(MockUserChangeRepository), nil, dispatcher)
+	verificationService := service.NewContactVerificationService(repo, userService, verificationRepo, new(MockPasswordHasher), dispatcher)
+
+	email := "john@example.com"
+	user := &model.User{UserID: uuid.New(), Status: model.Blocked, Login: "john_doe", Email: &email}
+
+	repo.On("Find", mock.AnythingOfType("model.FindSpec")).Return(user, nil)
+	verificationRepo.On("CountCreatedSince", user.UserID, model.ContactEmail, mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since) >= model.VerificationRequestWindow
+	})).Return(model.MaxVerificationRequests, nil)
+
+	_, err := verificationService.StartVerification(uuid.New(), user.UserID, model.ContactEmail)
+	require.ErrorIs(t, err, model.ErrTooManyVerificationRequests)
+
+	_, err = verificationService.RequestVerification(user.Login, model.ContactEmail)
+	require.ErrorIs(t, err, model.ErrTooManyVerificationRequests)
+
+	verificationRepo.AssertNotCalled(t, "Store", mock.Anything)
+	dispatcher.AssertNotCalled(t, "Dispatch", mock.Anything)
+}
+
+func TestContactVerificationService_RequestVerification(t *testing.T) {
+	repo := new(MockUserRepository)
+	verificationRepo := new(MockContactVerificationRepository)
+	dispatcher := new(MockEventDispatcher)
+	userService := service.NewUserService(repo, new(MockUserChangeRepository), nil, dispatcher)
+	verificationService := service.NewContactVerificationService(repo, userService, verificationRepo, new(MockPasswordHasher), dispatcher)
+
+	email := "john@example.com"
+	telegram := "@john_doe"
+	user := &model.User{UserID: uuid.New(), Status: model.Active, Login: "john_doe", Email: &email, Telegram: &telegram, TelegramVerified: true}
+
+	repo.On("Find", mock.MatchedBy(func(spec model.FindSpec) bool {
+		return spec.Login != nil && *spec.Login == user.Login
+	})).Return(user, nil)
+	repo.On("Find", mock.AnythingOfType("model.FindSpec")).Return((*model.User)(nil), model.ErrUserNotFound)
+	verificationRepo.On("CountCreatedSince", user.UserID, model.ContactEmail, mock.AnythingOfType("time.Time")).
+		Return(model.MaxVerificationRequests-1, nil)
+
+	userID, err := verificationService.RequestVerification(user.Login, model.ContactEmail)
+	require.NoError(t, err)
+	assert.Equal(t, user.UserID, userID)
+
+	_, err = verificationService.RequestVerification(user.Login, model.ContactTelegram)
+	require.ErrorIs(t, err, model.ErrContactAlreadyVerified)
+
+	_, err = verificationService.RequestVerification("unknown", model.ContactEmail)
+	require.ErrorIs(t, err, model.ErrUserNotFound)
+
+	_, err = verificationService.RequestVerification(user.Login, "phone")
+	require.ErrorIs(t, err, model.ErrInvalidContactType)
+}
+
+func TestContactVerificationService_ConfirmContact(t *testing.T) {
+	repo := new(MockUserRepository)
+	verificationRepo := new(MockContactVerificationRepository)
+	hasher := new(MockPasswordHasher)
+	dispatcher := new(MockEventDispatcher)
+	changeRepo := new(MockUserChangeRepository)
+	userService := service.NewUserService(repo, changeRepo, nil, dispatcher)
+	verificationService := service.NewContactVerificationService(repo, userService, verificationRepo, hasher, dispatcher)
+
+	telegram := "@john_doe"
+	user := &model.User{UserID: uuid.New(), Status: model.Blocked, Telegram: &telegram}
+	verification := &model.ContactVerification{
+		VerificationID: uuid.New(),
+		UserID:         user.UserID,
+		ContactType:    model.ContactTelegram,
+		Contact:        telegram,
+		CodeHash:       "code-hash",
+		ExpiresAt:      time.Now().Add(time.Minute),
+	}
+
+	repo.On("Find", mock.AnythingOfType("model.FindSpec")).Return(user, nil)
+	verificationRepo.On("Find", verification.VerificationID).Return(verification, nil)
+	verificationRepo.On("Store", mock.AnythingOfType("model.ContactVerification")).Return(nil)
+	hasher.On("Compare", "code-hash", "123456").Return(nil)
+	hasher.On("Compare", "code-hash", mock.AnythingOfType("string")).Return(model.ErrInvalidCredentials)
+	repo.On("Store", mock.MatchedBy(func(u model.User) bool {
+		return u.UserID == user.UserID && u.TelegramVerified && u.Status == model.Active
+	})).Return(nil)
+	dispatcher.On("Dispatch", mock.AnythingOfType("*model.ContactVerified")).Return(nil)
+	dispatcher.On("Dispatch", mock.AnythingOfType("*model.UserUpdated")).Return(nil)
+
+	err := verificationService.ConfirmContact(verification.VerificationID, "000000")
+	require.ErrorIs(t, err, model.ErrInvalidVerificationCode)
+	assert.Equal(t, 1, verification.Attempts)
+
+	err = verificationService.ConfirmContact(verification.VerificationID, "123456")
+	require.NoError(t, err)
+	assert.NotNil(t, verification.VerifiedAt)
+	require.Len(t, changeRepo.Changes, 1)
+	assert.Equal(t, model.UserFieldStatus, changeRepo.Changes[0].Field)
+	assert.Equal(t, model.Blocked.String(), *changeRepo.Changes[0].OldValue)
+	assert.Equal(t, model.Active.String(), *changeRepo.Changes[0].NewValue)
+
+	repo.AssertExpectations(t)
+	dispatcher.AssertExpectations(t)
+}
+
+func TestContactVerificationService_ConfirmContact_Limits(t *testing.T) {
+	repo := new(MockUserRepository)
+	verificationRepo := new(MockContactVerificationRepository)
+	hasher := new(MockPasswordHasher)
+	dispatcher := new(MockEventDispatcher)
+	userService := service.NewUserService(repo, new(MockUserChangeRepository), nil, dispatcher)
+	verificationService := service.NewContactVerificationService(repo, userService, verificationRepo, hasher, dispatcher)
+
+	verification := &model.ContactVerification{
+		VerificationID: uuid.New(),
+		ContactType:    model.ContactEmail,
+		CodeHash:       "code-hash",
+		Attempts:       model.MaxVerificationAttempts - 1,
+		ExpiresAt:      time.Now().Add(time.Minute),
+	}
+	expiredVerification := &model.ContactVerification{
+		VerificationID: uuid.New(),
+		ContactType:    model.ContactEmail,
+		CodeHash:       "code-hash",
+		ExpiresAt:      time.Now().Add(-time.Minute),
+	}
+
+	verificationRepo.On("Find", verification.VerificationID).Return(verification, nil)
+	verificationRepo.On("Find", expiredVerification.VerificationID).Return(expiredVerification, nil)
+	verificationRepo.On("Store", mock.AnythingOfType("model.ContactVerification")).Return(nil)
+	hasher.On("Compare", "code-hash", mock.AnythingOfType("string")).Return(model.ErrInvalidCredentials)
+
+	err := verificationService.ConfirmContact(verification.VerificationID, "000000")
+	require.ErrorIs(t, err, model.ErrVerificationAttemptsExceeded)
+
+	err = verificationService.ConfirmContact(verification.VerificationID, "123456")
+	require.ErrorIs(t, err, model.ErrVerificationAttemptsExceeded)
+	hasher.AssertNumberOfCalls(t, "Compare", 1)
+
+	err = verificationService.ConfirmContact(expiredVerification.VerificationID, "123456")
+	require.ErrorIs(t, err, model.ErrVerificationExpired)
+	repo.AssertNotCalled(t, "Store")
+}
+
+func TestUserDataService_EraseUser(t *testing.T) {
+	repo := new(MockUserRepository)
+	tokenRepo := new(MockRefreshTokenRepository)
+	verificationRepo := new(MockContactVerificationRepository)
+	preferencesRepo := new(MockPreferencesRepository)
+	changeRepo := new(MockUserChangeRepository)
+	attemptRepo := new(MockLoginAttemptRepository)
+	dispatcher := new(MockEventDispatcher)
+	userDataService := service.NewUserDataService(repo, tokenRepo, verificationRepo, preferencesRepo, changeRepo, attemptRepo, dispatcher)
+
+	userID := uuid.New()
+	repo.On("Find", model.FindSpec{UserID: &userID}).Return(&model.User{UserID: userID, Status: model.Active, Login: "erased"}, nil)
+	tokenRepo.On("DeleteByUser", userID).Return(2, nil)
+	verificationRepo.On("DeleteByUser", userID).Return(1, nil)
+	preferencesRepo.On("Delete", userID).Return(1, nil)
+	repo.On("HardDelete", userID).Return(nil)
+	dispatcher.On("Dispatch", mock.MatchedBy(func(e domain.Event) bool {
+		evt, ok := e.(*model.UserDeleted)
+		return ok && evt.UserID == userID && evt.Hard
+	})).Return(nil)
+
+	changeRepo.Changes = []model.UserChange{
+		{ChangeID: uuid.New(), UserID: userID, Field: model.UserFieldLogin},
+		{ChangeID: uuid.New(), UserID: userID, Field: model.UserFieldStatus},
+		{ChangeID: uuid.New(), UserID: uuid.New(), Field: model.UserFieldLogin},
+	}
+	attemptRepo.Attempts = []model.LoginAttempt{
+		{AttemptID: uuid.New(), Login: "erased", IP: "10.0.0.1", AttemptedAt: time.Now()},
+		{AttemptID: uuid.New(), Login: "other", IP: "10.0.0.1", AttemptedAt: time.Now()},
+	}
+
+	deleted, err := userDataService.EraseUser(userID)
+	require.NoError(t, err)
+	assert.Equal(t, 8, deleted)
+	// history and failed logins of other users are kept
+	assert.Len(t, changeRepo.Changes, 1)
+	assert.Len(t, attemptRepo.Attempts, 1)
+	// erased user must not be stored back
+	repo.AssertNotCalled(t, "Store", mock.Anything)
+	repo.AssertExpectations(t)
+	tokenRepo.AssertExpectations(t)
+	verificationRepo.AssertExpectations(t)
+	preferencesRepo.AssertExpectations(t)
+	dispatcher.AssertExpectations(t)
+
+	missingRepo := new(MockUserRepository)
+	missingRepo.On("Find", mock.AnythingOfType("model.FindSpec")).Return((*model.User)(nil), model.ErrUserNotFound)
+	_, err = service.NewUserDataService(missingRepo, tokenRepo, verificationRepo, preferencesRepo, changeRepo, attemptRepo, dispatcher).EraseUser(userID)
+	require.ErrorIs(t, err, model.ErrUserNotFound)
+}
+
+func TestUserService_RecordsChanges(t *testing.T) {
+	repo := new(MockUserRepository)
+	changeRepo := new(MockUserChangeRepository)
+	dispatcher := new(MockEventDispatcher)
+	actorID := uuid.New()
+	userService := service.NewUserService(repo, changeRepo, &actorID, dispatcher)
+
+	userID := uuid.New()
+	oldEmail := "old@example.com"
+	email := "new@example.com"
+	user := &model.User{UserID: userID, Status: model.Active, Role: model.RoleCustomer, Email: &oldEmail}
+
+	repo.On("Find", model.FindSpec{UserID: &userID}).Return(user, nil)
+	repo.On("Find", model.FindSpec{Email: &email}).Return((*model.User)(nil), model.ErrUserNotFound)
+	repo.On("Store", mock.AnythingOfType("model.User")).Return(nil)
+	dispatcher.On("Dispatch", mock.Anything).Return(nil)
+
+	require.NoError(t, userService.UpdateUserEmail(userID, &email))
+	require.NoError(t, userService.UpdateUserStatus(userID, model.Blocked))
+	// unchanged value is not recorded
+	require.NoError(t, userService.UpdateUserRole(userID, model.RoleCustomer))
+
+	require.Len(t, changeRepo.Changes, 2)
+	emailChange := changeRepo.Changes[0]
+	assert.Equal(t, userID, emailChange.UserID)
+	assert.Equal(t, model.UserFieldEmail, emailChange.Field)
+	assert.Equal(t, &oldEmail, emailChange.OldValue)
+	assert.Equal(t, &email, emailChange.NewValue)
+	assert.Equal(t, &actorID, emailChange.ActorID)
+
+	statusChange := changeRepo.Changes[1]
+	assert.Equal(t, model.UserFieldStatus, statusChange.Field)
+	require.NotNil(t, statusChange.OldValue)
+	assert.Equal(t, "active", *statusChange.OldValue)
+	require.NotNil(t, statusChange.NewValue)
+	assert.Equal(t, "blocked", *statusChange.NewValue)
+}
+
+func TestLoginThrottlingService_LocksAccount(t *testing.T) {
+	repo := new(MockUserRepository)
+	changeRepo := new(MockUserChangeRepository)
+	attemptRepo := new(MockLoginAttemptRepository)
+	dispatcher := new(MockEventDispatcher)
+	throttling := model.LoginThrottling{
+		AccountMaxFailures: 3,
+		AccountWindow:      15 * time.Minute,
+		IPMaxFailures:      10,
+		IPWindow:           15 * time.Minute,
+		LockoutDuration:    30 * time.Minute,
+	}
+	throttlingService := service.NewLoginThrottlingService(
+		attemptRepo,
+		repo,
+		service.NewUserService(repo, changeRepo, nil, dispatcher),
+		throttling,
+	)
+
+	userID := uuid.New()
+	login := "locked"
+	email := "locked@example.com"
+	user := &model.User{UserID: userID, Login: login, Email: &email, Status: model.Active}
+	// failure out of the window is not counted and is cleaned up
+	attemptRepo.Attempts = []model.LoginAttempt{
+		{AttemptID: uuid.New(), Login: login, IP: "10.0.0.1", AttemptedAt: time.Now().Add(-time.Hour)},
+	}
+
+	repo.On("Find", model.FindSpec{Login: &login}).Return(user, nil)
+	repo.On("Find", model.FindSpec{UserID: &userID}).Return(user, nil)
+	repo.On("Store", mock.MatchedBy(func(u model.User) bool {
+		return u.Status == model.Blocked && u.LockedUntil != nil
+	})).Return(nil).Once()
+	dispatcher.On("Dispatch", mock.AnythingOfType("*model.UserUpdated")).Return(nil).Once()
+	dispatcher.On("Dispatch", mock.MatchedBy(func(e domain.Event) bool {
+		evt, ok := e.(*model.UserLocked)
+		return ok && evt.UserID == userID && evt.Email == &email && evt.LockedUntil.After(time.Now().Add(29*time.Minute))
+	})).Return(nil).Once()
+
+	for range throttling.AccountMaxFailures - 1 {
+		require.NoError(t, throttlingService.RecordFailure(login, "10.0.0.1"))
+	}
+	assert.Len(t, attemptRepo.Attempts, throttling.AccountMaxFailures-1)
+	repo.AssertNotCalled(t, "Store", mock.Anything)
+
+	require.NoError(t, throttlingService.RecordFailure(login, "10.0.0.2"))
+	repo.AssertExpectations(t)
+	dispatcher.AssertExpectations(t)
+	// failures are counted anew after lockout
+	assert.Empty(t, attemptRepo.Attempts)
+	require.Len(t, changeRepo.Changes, 1)
+	assert.Nil(t, changeRepo.Changes[0].ActorID)
+
+	// failures of unknown login do not lock anything
+	unknown := "unknown"
+	repo.On("Find", model.FindSpec{Login: &unknown}).Return((*model.User)(nil), model.ErrUserNotFound)
+	for range throttling.AccountMaxFailures {
+		require.NoError(t, throttlingService.RecordFailure(unknown, "10.0.0.3"))
+	}
+	repo.AssertNumberOfCalls(t, "Store", 1)
+}
+
+func TestLoginThrottlingService_CheckIP(t *testing.T) {
+	attemptRepo := new(MockLoginAttemptRepository)
+	throttlingService := service.NewLoginThrottlingService(attemptRepo, new(MockUserRepository), nil, model.LoginThrottling{
+		AccountMaxFailures: 100,
+		AccountWindow:      time.Minute,
+		IPMaxFailures:      2,
+		IPWindow:           time.Minute,
+	})
+
+	attemptRepo.Attempts = []model.LoginAttempt{
+		{AttemptID: uuid.New(), Login: "first", IP: "10.0.0.1", AttemptedAt: time.Now()},
+		{AttemptID: uuid.New(), Login: "second", IP: "10.0.0.1", AttemptedAt: time.Now()},
+		{AttemptID: uuid.New(), Login: "third", IP: "10.0.0.2", AttemptedAt: time.Now().Add(-time.Hour)},
+		{AttemptID: uuid.New(), Login: "third", IP: "10.0.0.2", AttemptedAt: time.Now()},
+	}
+	require.ErrorIs(t, throttlingService.CheckIP("10.0.0.1"), model.ErrTooManyLoginAttempts)
+	// window slides, so old failures are not counted
+	require.NoError(t, throttlingService.CheckIP("10.0.0.2"))
+}
+
+func TestUserService_UnlockUser(t *testing.T) {
+	repo := new(MockUserRepository)
+	dispatcher := new(MockEventDispatcher)
+	userService := service.NewUserService(repo, new(MockUserChangeRepository), nil, dispatcher)
+
+	userID := uuid.New()
+	lockedUntil := time.Now().Truncate(time.Second)
+	relockedUntil := lockedUntil.Add(time.Hour)
+	email := "john@example.com"
+	user := &model.User{UserID: userID, Status: model.Blocked, Email: &email, EmailVerified: true, LockedUntil: &relockedUntil}
+	repo.On("Find", model.FindSpec{UserID: &userID}).Return(user, nil)
+
+	// timer of the previous lockout does not end the new one
+	require.NoError(t, userService.UnlockUser(userID, lockedUntil))
+	repo.AssertNotCalled(t, "Store", mock.Anything)
+
+	repo.On("Store", mock.MatchedBy(func(u model.User) bool {
+		return u.Status == model.Active && u.LockedUntil == nil
+	})).Return(nil).Once()
+	dispatcher.On("Dispatch", mock.AnythingOfType("*model.UserUpdated")).Return(nil).Once()
+	require.NoError(t, userService.UnlockUser(userID, relockedUntil))
+	repo.AssertExpectations(t)
+
+	// user blocked explicitly stays blocked
+	blocked := &model.User{UserID: userID, Status: model.Blocked}
+	blockedRepo := new(MockUserRepository)
+	blockedRepo.On("Find", model.FindSpec{UserID: &userID}).Return(blocked, nil)
+	require.NoError(t, service.NewUserService(blockedRepo, new(MockUserChangeRepository), nil, dispatcher).UnlockUser(userID, relockedUntil))
+	blockedRepo.AssertNotCalled(t, "Store", mock.Anything)
+
+	// contact changed during lockout is verified before activation
+	unverified := &model.User{UserID: userID, Status: model.Blocked, Email: &email, LockedUntil: &relockedUntil}
+	unverifiedRepo := new(MockUserRepository)
+	unverifiedRepo.On("Find", model.FindSpec{UserID: &userID}).Return(unverified, nil)
+	unverifiedRepo.On("Store", mock.MatchedBy(func(u model.User) bool {
+		return u.Status == model.Blocked && u.LockedUntil == nil
+	})).Return(nil).Once()
+	require.NoError(t, service.NewUserService(unverifiedRepo, new(MockUserChangeRepository), nil, dispatcher).UnlockUser(userID, relockedUntil))
+	unverifiedRepo.AssertExpectations(t)
+}
+
+func TestPreferencesService_InitPreferences(t *testing.T) {
+	repo := new(MockUserRepository)
+	preferencesRepo := new(MockPreferencesRepository)
+	dispatcher := new(MockEventDispatcher)
+	preferencesService := service.NewPreferencesService(repo, preferencesRepo, dispatcher)
+
+	userID := uuid.New()
+	repo.On("Find", model.FindSpec{UserID: &userID}).Return(&model.User{UserID: userID, Status: model.Active}, nil)
+	preferencesRepo.On("Find", userID).Return((*model.Preferences)(nil), model.ErrPreferencesNotFound).Once()
+	preferencesRepo.On("Store", mock.MatchedBy(func(p model.Preferences) bool {
+		return p.UserID == userID && p.Locale == model.DefaultLocale && p.TimeZone == model.DefaultTimeZone
+	})).Return(nil).Once()
+	dispatcher.On("Dispatch", mock.MatchedBy(func(e domain.Event) bool {
+		evt, ok := e.(*model.UserPreferencesChanged)
+		return ok && evt.UserID == userID && evt.Locale == model.DefaultLocale
+	})).Return(nil).Once()
+
+	preferences, err := preferencesService.InitPreferences(userID)
+	require.NoError(t, err)
+	assert.Equal(t, model.DefaultTimeZone, preferences.TimeZone)
+
+	// repeated onboarding keeps preferences changed by user
+	changed := model.Preferences{UserID: userID, Locale: "ru", TimeZone: "Europe/Moscow"}
+	preferencesRepo.On("Find", userID).Return(&changed, nil).Once()
+	preferences, err = preferencesService.InitPreferences(userID)
+	require.NoError(t, err)
+	assert.Equal(t, changed, preferences)
+	preferencesRepo.AssertExpectations(t)
+	dispatcher.AssertExpectations(t)
+
+	missingRepo := new(MockUserRepository)
+	missingRepo.On("Find", mock.AnythingOfType("model.FindSpec")).Return((*model.User)(nil), model.ErrUserNotFound)
+	_, err = service.NewPreferencesService(missingRepo, preferencesRepo, dispatcher).InitPreferences(userID)
+	require.ErrorIs(t, err, model.ErrUserNotFound)
+}
+
+func TestPreferencesService_UpdatePreferences(t *testing.T) {
+	repo := new(MockUserRepository)
+	preferencesRepo := new(MockPreferencesRepository)
+	dispatcher := new(MockEventDispatcher)
+	preferencesService := service.NewPreferencesService(repo, preferencesRepo, dispatcher)
+
+	userID := uuid.New()
+	repo.On("Find", model.FindSpec{UserID: &userID}).Return(&model.User{UserID: userID, Status: model.Active}, nil)
+	stored := model.Preferences{UserID: userID, Locale: model.DefaultLocale, TimeZone: model.DefaultTimeZone}
+	preferencesRepo.On("Find", userID).Return(&stored, nil)
+
+	locale := "ru"
+	preferencesRepo.On("Store", mock.MatchedBy(func(p model.Preferences) bool {
+		return p.Locale == locale && p.TimeZone == model.DefaultTimeZone
+	})).Return(nil).Once()
+	dispatcher.On("Dispatch", mock.MatchedBy(func(e domain.Event) bool {
+		evt, ok := e.(*model.UserPreferencesChanged)
+		return ok && evt.UserID == userID && evt.Locale == locale && evt.TimeZone == model.DefaultTimeZone
+	})).Return(nil).Once()
+
+	preferences, err := preferencesService.UpdatePreferences(userID, &locale, nil)
+	require.NoError(t, err)
+	assert.Equal(t, locale, preferences.Locale)
+
+	// unchanged preferences are not stored again
+	_, err = preferencesService.UpdatePreferences(userID, nil, &stored.TimeZone)
+	require.NoError(t, err)
+	preferencesRepo.AssertNumberOfCalls(t, "Store", 1)
+	dispatcher.AssertExpectations(t)
+
+	invalidLocale := "russian"
+	_, err = preferencesService.UpdatePreferences(userID, &invalidLocale, nil)
+	require.ErrorIs(t, err, model.ErrInvalidLocale)
+	for _, timeZone := range []string{"", "Local", "Mars/Olympus"} {
+		_, err = preferencesService.UpdatePreferences(userID, nil, &timeZone)
+		require.ErrorIs(t, err, model.ErrInvalidTimeZone)
+	}
+}
+
 type MockUserRepository struct {
 	mock.Mock
 }
@@ -332,6 +1047,200 @@ func (m *MockUserRepository) HardDelete(userID uuid.UUID) error {
 	return args.Error(0)
 }
 
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) NextID() (uuid.UUID, error) {
+	args := m.Called()
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) Store(token model.RefreshToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) Find(tokenID uuid.UUID) (*model.RefreshToken, error) {
+	args := m.Called(tokenID)
+	if token, ok := args.Get(0).(*model.RefreshToken); ok {
+		return token, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) RevokeByUser(userID uuid.UUID, at time.Time) error {
+	args := m.Called(userID, at)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) DeleteByUser(userID uuid.UUID) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
+
+type MockRolePermissionRepository struct {
+	mock.Mock
+}
+
+func (m *MockRolePermissionRepository) ListPermissions(role model.Role) ([]model.Permission, error) {
+	args := m.Called(role)
+	if permissions, ok := args.Get(0).([]model.Permission); ok {
+		return permissions, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockContactVerificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockContactVerificationRepository) Store(verification model.ContactVerification) error {
+	args := m.Called(verification)
+	return args.Error(0)
+}
+
+func (m *MockContactVerificationRepository) Find(verificationID uuid.UUID) (*model.ContactVerification, error) {
+	args := m.Called(verificationID)
+	if verification, ok := args.Get(0).(*model.ContactVerification); ok {
+		return verification, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockContactVerificationRepository) CountCreatedSince(userID uuid.UUID, contactType model.ContactType, since time.Time) (int, error) {
+	args := m.Called(userID, contactType, since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockContactVerificationRepository) DeleteByUser(userID uuid.UUID) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
+
+type MockPreferencesRepository struct {
+	mock.Mock
+}
+
+func (m *MockPreferencesRepository) Store(preferences model.Preferences) error {
+	args := m.Called(preferences)
+	return args.Error(0)
+}
+
+func (m *MockPreferencesRepository) Find(userID uuid.UUID) (*model.Preferences, error) {
+	args := m.Called(userID)
+	if preferences, ok := args.Get(0).(*model.Preferences); ok {
+		return preferences, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPreferencesRepository) Delete(userID uuid.UUID) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
+
+// MockUserChangeRepository keeps stored changes in memory, so tests of any method may record them
+type MockUserChangeRepository struct {
+	Changes []model.UserChange
+}
+
+func (m *MockUserChangeRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (m *MockUserChangeRepository) Store(change model.UserChange) error {
+	m.Changes = append(m.Changes, change)
+	return nil
+}
+
+func (m *MockUserChangeRepository) DeleteByUser(userID uuid.UUID) (int, error) {
+	kept := m.Changes[:0]
+	for _, change := range m.Changes {
+		if change.UserID != userID {
+			kept = append(kept, change)
+		}
+	}
+	deleted := len(m.Changes) - len(kept)
+	m.Changes = kept
+	return deleted, nil
+}
+
+// MockLoginAttemptRepository keeps failed logins in memory, so sliding windows are counted by attempt time
+type MockLoginAttemptRepository struct {
+	Attempts []model.LoginAttempt
+}
+
+func (m *MockLoginAttemptRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (m *MockLoginAttemptRepository) Store(attempt model.LoginAttempt) error {
+	m.Attempts = append(m.Attempts, attempt)
+	return nil
+}
+
+func (m *MockLoginAttemptRepository) CountByLogin(login string, since time.Time) (int, error) {
+	return m.count(func(attempt model.LoginAttempt) bool {
+		return attempt.Login == login && !attempt.AttemptedAt.Before(since)
+	}), nil
+}
+
+func (m *MockLoginAttemptRepository) CountByIP(ip string, since time.Time) (int, error) {
+	return m.count(func(attempt model.LoginAttempt) bool {
+		return attempt.IP == ip && !attempt.AttemptedAt.Before(since)
+	}), nil
+}
+
+func (m *MockLoginAttemptRepository) DeleteByLogin(login string) (int, error) {
+	return m.delete(func(attempt model.LoginAttempt) bool {
+		return attempt.Login == login
+	}), nil
+}
+
+func (m *MockLoginAttemptRepository) DeleteBefore(before time.Time) error {
+	m.delete(func(attempt model.LoginAttempt) bool {
+		return attempt.AttemptedAt.Before(before)
+	})
+	return nil
+}
+
+func (m *MockLoginAttemptRepository) count(match func(attempt model.LoginAttempt) bool) int {
+	count := 0
+	for _, attempt := range m.Attempts {
+		if match(attempt) {
+			count++
+		}
+	}
+	return count
+}
+
+func (m *MockLoginAttemptRepository) delete(match func(attempt model.LoginAttempt) bool) int {
+	kept := m.Attempts[:0]
+	for _, attempt := range m.Attempts {
+		if !match(attempt) {
+			kept = append(kept, attempt)
+		}
+	}
+	deleted := len(m.Attempts) - len(kept)
+	m.Attempts = kept
+	return deleted
+}
+
+type MockPasswordHasher struct {
+	mock.Mock
+}
+
+func (m *MockPasswordHasher) Hash(password string) (string, error) {
+	args := m.Called(password)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockPasswordHasher) Compare(hash, password string) error {
+	args := m.Called(hash, password)
+	return args.Error(0)
+}
+
 type MockEventDispatcher struct {
 	mock.Mock
 }
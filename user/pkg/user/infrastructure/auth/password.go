@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+
+	"user/pkg/user/domain/model"
+)
+
+func NewBcryptPasswordHasher(cost int) model.PasswordHasher {
+	return &bcryptPasswordHasher{
+		cost: cost,
+	}
+}
+
+type bcryptPasswordHasher struct {
+	cost int
+}
+
+func (h *bcryptPasswordHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return string(hash), nil
+}
+
+func (h *bcryptPasswordHasher) Compare(hash, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return model.ErrInvalidCredentials
+	}
+	return errors.WithStack(err)
+}
@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+
+	"github.com/google/uuid"
+)
+
+// NewPseudonymizer derives pseudonyms of erased users with HMAC-SHA256 keyed by secret,
+// pseudonym can not be linked to the user without the secret
+func NewPseudonymizer(secret []byte) *Pseudonymizer {
+	return &Pseudonymizer{secret: secret}
+}
+
+type Pseudonymizer struct {
+	secret []byte
+}
+
+// PseudonymID is the same for every erasure of the user, so repeated erasure does not split
+// financial records of the user between pseudonyms. It is formatted as random UUID to not reveal its origin
+func (p *Pseudonymizer) PseudonymID(userID uuid.UUID) uuid.UUID {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(userID[:])
+	var pseudonymID uuid.UUID
+	copy(pseudonymID[:], mac.Sum(nil))
+	pseudonymID[6] = (pseudonymID[6] & 0x0f) | 0x40 // version 4
+	pseudonymID[8] = (pseudonymID[8] & 0x3f) | 0x80 // variant RFC 4122
+	return pseudonymID
+}
@@ -0,0 +1,25 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"user/pkg/user/infrastructure/auth"
+)
+
+func TestPseudonymizer(t *testing.T) {
+	pseudonymizer := auth.NewPseudonymizer([]byte("secret"))
+	userID := uuid.Must(uuid.NewV7())
+
+	pseudonymID := pseudonymizer.PseudonymID(userID)
+	require.Equal(t, pseudonymID, pseudonymizer.PseudonymID(userID))
+	require.Equal(t, pseudonymID, auth.NewPseudonymizer([]byte("secret")).PseudonymID(userID))
+	require.NotEqual(t, userID, pseudonymID)
+	require.Equal(t, uuid.Version(4), pseudonymID.Version())
+	require.Equal(t, uuid.RFC4122, pseudonymID.Variant())
+
+	require.NotEqual(t, pseudonymID, pseudonymizer.PseudonymID(uuid.Must(uuid.NewV7())))
+	require.NotEqual(t, pseudonymID, auth.NewPseudonymizer([]byte("other")).PseudonymID(userID))
+}
@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"user/pkg/user/application/service"
+	"user/pkg/user/domain/model"
+)
+
+// Issuer is "iss" claim of all tokens, other services accept only tokens of this issuer
+const Issuer = "user"
+
+const (
+	accessTokenType  = "access"
+	refreshTokenType = "refresh"
+)
+
+// SigningKeys are ed25519 keys by key ID, only current key signs new tokens,
+// the rest are kept to verify tokens issued before key rotation
+type SigningKeys struct {
+	CurrentKeyID string
+	Keys         map[string]ed25519.PrivateKey
+}
+
+// ParseSigningKeys decodes base64 encoded ed25519 seeds
+func ParseSigningKeys(seeds map[string]string, currentKeyID string) (SigningKeys, error) {
+	keys := make(map[string]ed25519.PrivateKey, len(seeds))
+	for keyID, seed := range seeds {
+		rawSeed, err := base64.StdEncoding.DecodeString(seed)
+		if err != nil || len(rawSeed) != ed25519.SeedSize {
+			return SigningKeys{}, errors.Errorf("invalid signing key %q", keyID)
+		}
+		keys[keyID] = ed25519.NewKeyFromSeed(rawSeed)
+	}
+	if _, ok := keys[currentKeyID]; !ok {
+		return SigningKeys{}, errors.Errorf("signing key %q not found", currentKeyID)
+	}
+	return SigningKeys{
+		CurrentKeyID: currentKeyID,
+		Keys:         keys,
+	}, nil
+}
+
+// PublicKeys returns keys to verify tokens, base64 encoded keys are configured in other services
+func (k SigningKeys) PublicKeys() map[string]ed25519.PublicKey {
+	publicKeys := make(map[string]ed25519.PublicKey, len(k.Keys))
+	for keyID, key := range k.Keys {
+		publicKeys[keyID] = key.Public().(ed25519.PublicKey)
+	}
+	return publicKeys
+}
+
+type claims struct {
+	jwt.RegisteredClaims
+	TokenType   string   `json:"token_type"`
+	Role        string   `json:"role,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+func NewTokenIssuer(keys SigningKeys, accessTokenTTL time.Duration) service.TokenIssuer {
+	return &tokenIssuer{
+		keys:           keys,
+		publicKeys:     keys.PublicKeys(),
+		accessTokenTTL: accessTokenTTL,
+	}
+}
+
+type tokenIssuer struct {
+	keys           SigningKeys
+	publicKeys     map[string]ed25519.PublicKey
+	accessTokenTTL time.Duration
+}
+
+func (t *tokenIssuer) IssueAccessToken(userID uuid.UUID, access model.Access) (string, time.Time, error) {
+	tokenID, err := uuid.NewV7()
+	if err != nil {
+		return "", time.Time{}, errors.WithStack(err)
+	}
+	permissions := make([]string, 0, len(access.Permissions))
+	for _, permission := range access.Permissions {
+		permissions = append(permissions, string(permission))
+	}
+
+	currentTime := time.Now()
+	expiresAt := currentTime.Add(t.accessTokenTTL)
+	token, err := t.sign(claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID.String(),
+			Issuer:    Issuer,
+			Subject:   userID.String(),
+			IssuedAt:  jwt.NewNumericDate(currentTime),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		TokenType:   accessTokenType,
+		Role:        string(access.Role),
+		Permissions: permissions,
+	})
+	return token, expiresAt, err
+}
+
+func (t *tokenIssuer) IssueRefreshToken(token model.RefreshToken) (string, error) {
+	return t.sign(claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        token.TokenID.String(),
+			Issuer:    Issuer,
+			Subject:   token.UserID.String(),
+			IssuedAt:  jwt.NewNumericDate(token.CreatedAt),
+			ExpiresAt: jwt.NewNumericDate(token.ExpiresAt),
+		},
+		TokenType: refreshTokenType,
+	})
+}
+
+func (t *tokenIssuer) ParseRefreshToken(refreshToken string) (uuid.UUID, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(refreshToken, &c, t.publicKey,
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(Issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || c.TokenType != refreshTokenType {
+		return uuid.Nil, model.ErrInvalidRefreshToken
+	}
+	tokenID, err := uuid.Parse(c.ID)
+	if err != nil {
+		return uuid.Nil, model.ErrInvalidRefreshToken
+	}
+	return tokenID, nil
+}
+
+func (t *tokenIssuer) sign(c claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, c)
+	token.Header["kid"] = t.keys.CurrentKeyID
+	signed, err := token.SignedString(t.keys.Keys[t.keys.CurrentKeyID])
+	return signed, errors.WithStack(err)
+}
+
+func (t *tokenIssuer) publicKey(token *jwt.Token) (interface{}, error) {
+	keyID, _ := token.Header["kid"].(string)
+	key, ok := t.publicKeys[keyID]
+	if !ok {
+		return nil, errors.Errorf("unknown key %q", keyID)
+	}
+	return key, nil
+}
@@ -41,6 +41,34 @@ func (t *amqpTransport) Handler() amqp.Handler {
 
 func (t *amqpTransport) handle(ctx context.Context, delivery amqp.Delivery) error {
 	switch delivery.Type {
+	case model.UserCreated{}.Type():
+		var e UserCreated
+		err := json.Unmarshal(delivery.Body, &e)
+		if err != nil {
+			return err
+		}
+		return t.workflowService.RunUserOnboardingWorkflow(ctx, model.UserCreated{
+			UserID:    uuid.MustParse(e.UserID),
+			Status:    model.UserStatus(e.Status),
+			Login:     e.Login,
+			Email:     e.Email,
+			Telegram:  e.Telegram,
+			CreatedAt: time.Unix(e.CreatedAt, 0),
+		})
+	case model.UserLocked{}.Type():
+		var e UserLocked
+		err := json.Unmarshal(delivery.Body, &e)
+		if err != nil {
+			return err
+		}
+		return t.workflowService.RunUserUnlockWorkflow(ctx, model.UserLocked{
+			UserID:      uuid.MustParse(e.UserID),
+			Login:       e.Login,
+			Email:       e.Email,
+			Telegram:    e.Telegram,
+			LockedUntil: time.Unix(e.LockedUntil, 0),
+			LockedAt:    time.Unix(e.LockedAt, 0),
+		})
 	case model.UserUpdated{}.Type():
 		var e UserUpdated
 		err := json.Unmarshal(delivery.Body, &e)
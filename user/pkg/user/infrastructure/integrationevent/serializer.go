@@ -54,6 +54,50 @@ func (s eventSerializer) Serialize(event outbox.Event) (string, error) {
 		}
 		b, err := json.Marshal(ie)
 		return string(b), errors.WithStack(err)
+	case *model.UserLocked:
+		b, err := json.Marshal(UserLocked{
+			UserID:      e.UserID.String(),
+			Login:       e.Login,
+			Email:       e.Email,
+			Telegram:    e.Telegram,
+			LockedUntil: e.LockedUntil.Unix(),
+			LockedAt:    e.LockedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.UserRoleChanged:
+		b, err := json.Marshal(UserRoleChanged{
+			UserID:    e.UserID.String(),
+			Role:      string(e.Role),
+			UpdatedAt: e.UpdatedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.UserPreferencesChanged:
+		b, err := json.Marshal(UserPreferencesChanged{
+			UserID:    e.UserID.String(),
+			Locale:    e.Locale,
+			TimeZone:  e.TimeZone,
+			UpdatedAt: e.UpdatedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.ContactVerificationRequested:
+		b, err := json.Marshal(ContactVerificationRequested{
+			VerificationID: e.VerificationID.String(),
+			UserID:         e.UserID.String(),
+			Login:          e.Login,
+			ContactType:    string(e.ContactType),
+			Contact:        e.Contact,
+			Code:           e.Code,
+			ExpiresAt:      e.ExpiresAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
+	case *model.ContactVerified:
+		b, err := json.Marshal(ContactVerified{
+			UserID:      e.UserID.String(),
+			ContactType: string(e.ContactType),
+			Contact:     e.Contact,
+			VerifiedAt:  e.VerifiedAt.Unix(),
+		})
+		return string(b), errors.WithStack(err)
 	case *model.UserDeleted:
 		b, err := json.Marshal(UserDeleted{
 			UserID:    e.UserID.String(),
@@ -90,6 +134,45 @@ type UserUpdated struct {
 	UpdatedAt int64 `json:"updated_at,omitempty"`
 }
 
+type UserLocked struct {
+	UserID      string  `json:"user_id"`
+	Login       string  `json:"login"`
+	Email       *string `json:"email,omitempty"`
+	Telegram    *string `json:"telegram,omitempty"`
+	LockedUntil int64   `json:"locked_until"`
+	LockedAt    int64   `json:"locked_at"`
+}
+
+type UserRoleChanged struct {
+	UserID    string `json:"user_id"`
+	Role      string `json:"role"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+type UserPreferencesChanged struct {
+	UserID    string `json:"user_id"`
+	Locale    string `json:"locale"`
+	TimeZone  string `json:"time_zone"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+type ContactVerificationRequested struct {
+	VerificationID string `json:"verification_id"`
+	UserID         string `json:"user_id"`
+	Login          string `json:"login"`
+	ContactType    string `json:"contact_type"`
+	Contact        string `json:"contact"`
+	Code           string `json:"code"`
+	ExpiresAt      int64  `json:"expires_at"`
+}
+
+type ContactVerified struct {
+	UserID      string `json:"user_id"`
+	ContactType string `json:"contact_type"`
+	Contact     string `json:"contact"`
+	VerifiedAt  int64  `json:"verified_at"`
+}
+
 type UserDeleted struct {
 	UserID    string `json:"user_id"`
 	Status    int    `json:"status"`
@@ -44,4 +44,12 @@ func NewDatabaseMigrator(
 
 var builderFunctions = []MigrationBuilderFunc{
 	NewVersion1722266003,
+	NewVersion1762770000,
+	NewVersion1762850000,
+	NewVersion1762930000,
+	NewVersion1763010000,
+	NewVersion1763090000,
+	NewVersion1763170000,
+	NewVersion1763250000,
+	NewVersion1763330000,
 }
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1762770000(client mysql.ClientContext) migrator.Migration {
+	return &version1762770000{
+		client: client,
+	}
+}
+
+type version1762770000 struct {
+	client mysql.ClientContext
+}
+
+func (v version1762770000) Version() int64 {
+	return 1762770000
+}
+
+func (v version1762770000) Description() string {
+	return "Add user password hash and 'refresh_token' table"
+}
+
+func (v version1762770000) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE user
+		    ADD COLUMN password_hash VARCHAR(255) AFTER telegram
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE refresh_token
+		(
+		    token_id   VARCHAR(64) NOT NULL,
+		    user_id    VARCHAR(64) NOT NULL,
+		    expires_at DATETIME    NOT NULL,
+		    created_at DATETIME    NOT NULL,
+		    revoked_at DATETIME,
+		    PRIMARY KEY (token_id),
+		    INDEX refresh_token_user_id_index (user_id)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
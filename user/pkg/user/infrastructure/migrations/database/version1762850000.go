@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1762850000(client mysql.ClientContext) migrator.Migration {
+	return &version1762850000{
+		client: client,
+	}
+}
+
+type version1762850000 struct {
+	client mysql.ClientContext
+}
+
+func (v version1762850000) Version() int64 {
+	return 1762850000
+}
+
+func (v version1762850000) Description() string {
+	return "Add user role and 'role_permission' table"
+}
+
+func (v version1762850000) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE user
+		    ADD COLUMN role VARCHAR(32) NOT NULL DEFAULT 'customer' AFTER status
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE role_permission
+		(
+		    role       VARCHAR(32) NOT NULL,
+		    permission VARCHAR(64) NOT NULL,
+		    PRIMARY KEY (role, permission)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		INSERT INTO role_permission (role, permission) VALUES
+		    ('customer', 'user.read'),
+		    ('customer', 'user.write'),
+		    ('customer', 'order.create'),
+		    ('customer', 'order.read'),
+		    ('customer', 'payment.read'),
+		    ('customer', 'inventory.catalog.read'),
+
+		    ('support', 'user.read'),
+		    ('support', 'user.read.any'),
+		    ('support', 'user.write'),
+		    ('support', 'order.create'),
+		    ('support', 'order.read'),
+		    ('support', 'order.read.any'),
+		    ('support', 'payment.read'),
+		    ('support', 'payment.read.any'),
+		    ('support', 'inventory.catalog.read'),
+		    ('support', 'inventory.stock.read'),
+
+		    ('warehouse', 'user.read'),
+		    ('warehouse', 'user.write'),
+		    ('warehouse', 'order.read'),
+		    ('warehouse', 'order.read.any'),
+		    ('warehouse', 'inventory.catalog.read'),
+		    ('warehouse', 'inventory.catalog.write'),
+		    ('warehouse', 'inventory.stock.read'),
+		    ('warehouse', 'inventory.stock.write'),
+
+		    ('admin', 'user.read'),
+		    ('admin', 'user.read.any'),
+		    ('admin', 'user.write'),
+		    ('admin', 'user.write.any'),
+		    ('admin', 'user.role.write'),
+		    ('admin', 'order.create'),
+		    ('admin', 'order.create.any'),
+		    ('admin', 'order.read'),
+		    ('admin', 'order.read.any'),
+		    ('admin', 'payment.read'),
+		    ('admin', 'payment.read.any'),
+		    ('admin', 'payment.balance.write'),
+		    ('admin', 'inventory.catalog.read'),
+		    ('admin', 'inventory.catalog.write'),
+		    ('admin', 'inventory.stock.read'),
+		    ('admin', 'inventory.stock.write')
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1762930000(client mysql.ClientContext) migrator.Migration {
+	return &version1762930000{
+		client: client,
+	}
+}
+
+type version1762930000 struct {
+	client mysql.ClientContext
+}
+
+func (v version1762930000) Version() int64 {
+	return 1762930000
+}
+
+func (v version1762930000) Description() string {
+	return "Add user contact verification flags and 'contact_verification' table"
+}
+
+func (v version1762930000) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE user
+		    ADD COLUMN email_verified    TINYINT(1) NOT NULL DEFAULT 0 AFTER telegram,
+		    ADD COLUMN telegram_verified TINYINT(1) NOT NULL DEFAULT 0 AFTER email_verified
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE contact_verification
+		(
+		    verification_id VARCHAR(64)  NOT NULL,
+		    user_id         VARCHAR(64)  NOT NULL,
+		    contact_type    VARCHAR(16)  NOT NULL,
+		    contact         VARCHAR(255) NOT NULL,
+		    code_hash       VARCHAR(255) NOT NULL,
+		    attempts        INT          NOT NULL DEFAULT 0,
+		    expires_at      DATETIME     NOT NULL,
+		    verified_at     DATETIME,
+		    created_at      DATETIME     NOT NULL,
+		    PRIMARY KEY (verification_id),
+		    INDEX contact_verification_user_id_index (user_id, contact_type, created_at)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1763010000(client mysql.ClientContext) migrator.Migration {
+	return &version1763010000{
+		client: client,
+	}
+}
+
+type version1763010000 struct {
+	client mysql.ClientContext
+}
+
+func (v version1763010000) Version() int64 {
+	return 1763010000
+}
+
+func (v version1763010000) Description() string {
+	return "Grant data subject request permissions to admin"
+}
+
+func (v version1763010000) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		INSERT INTO role_permission (role, permission) VALUES
+		    ('admin', 'user.data.export'),
+		    ('admin', 'user.data.erase')
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1763090000(client mysql.ClientContext) migrator.Migration {
+	return &version1763090000{
+		client: client,
+	}
+}
+
+type version1763090000 struct {
+	client mysql.ClientContext
+}
+
+func (v version1763090000) Version() int64 {
+	return 1763090000
+}
+
+func (v version1763090000) Description() string {
+	return "Add 'user_preferences' table"
+}
+
+func (v version1763090000) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE user_preferences
+		(
+		    user_id    VARCHAR(64) NOT NULL,
+		    locale     VARCHAR(16) NOT NULL,
+		    time_zone  VARCHAR(64) NOT NULL,
+		    created_at DATETIME    NOT NULL,
+		    updated_at DATETIME    NOT NULL,
+		    PRIMARY KEY (user_id)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1763170000(client mysql.ClientContext) migrator.Migration {
+	return &version1763170000{
+		client: client,
+	}
+}
+
+type version1763170000 struct {
+	client mysql.ClientContext
+}
+
+func (v version1763170000) Version() int64 {
+	return 1763170000
+}
+
+func (v version1763170000) Description() string {
+	return "Add 'user_change' audit table"
+}
+
+func (v version1763170000) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		CREATE TABLE user_change
+		(
+		    change_id  VARCHAR(64)  NOT NULL,
+		    user_id    VARCHAR(64)  NOT NULL,
+		    field      VARCHAR(32)  NOT NULL,
+		    old_value  VARCHAR(255),
+		    new_value  VARCHAR(255),
+		    actor_id   VARCHAR(64),
+		    changed_at DATETIME     NOT NULL,
+		    PRIMARY KEY (change_id),
+		    INDEX user_change_user_id_index (user_id, change_id)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
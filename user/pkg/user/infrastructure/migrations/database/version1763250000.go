@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1763250000(client mysql.ClientContext) migrator.Migration {
+	return &version1763250000{
+		client: client,
+	}
+}
+
+type version1763250000 struct {
+	client mysql.ClientContext
+}
+
+func (v version1763250000) Version() int64 {
+	return 1763250000
+}
+
+func (v version1763250000) Description() string {
+	return "Add user lockout and 'login_attempt' table"
+}
+
+func (v version1763250000) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		ALTER TABLE user
+		    ADD COLUMN locked_until DATETIME AFTER password_hash
+	`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = v.client.ExecContext(ctx, `
+		CREATE TABLE login_attempt
+		(
+		    attempt_id   VARCHAR(64)  NOT NULL,
+		    login        VARCHAR(255) NOT NULL,
+		    ip           VARCHAR(64)  NOT NULL,
+		    attempted_at DATETIME     NOT NULL,
+		    PRIMARY KEY (attempt_id),
+		    INDEX login_attempt_login_index (login, attempted_at),
+		    INDEX login_attempt_ip_index (ip, attempted_at),
+		    INDEX login_attempt_attempted_at_index (attempted_at)
+		)
+		    ENGINE = InnoDB
+		    CHARACTER SET = utf8mb4
+		    COLLATE utf8mb4_unicode_ci
+	`)
+	return errors.WithStack(err)
+}
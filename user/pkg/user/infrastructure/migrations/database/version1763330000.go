@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/migrator"
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+)
+
+func NewVersion1763330000(client mysql.ClientContext) migrator.Migration {
+	return &version1763330000{
+		client: client,
+	}
+}
+
+type version1763330000 struct {
+	client mysql.ClientContext
+}
+
+func (v version1763330000) Version() int64 {
+	return 1763330000
+}
+
+func (v version1763330000) Description() string {
+	return "Grant notification permissions to roles"
+}
+
+func (v version1763330000) Up(ctx context.Context) error {
+	_, err := v.client.ExecContext(ctx, `
+		INSERT INTO role_permission (role, permission) VALUES
+		    ('customer', 'notification.read'),
+		    ('customer', 'notification.write'),
+
+		    ('support', 'notification.read'),
+		    ('support', 'notification.read.any'),
+		    ('support', 'notification.write'),
+
+		    ('warehouse', 'notification.read'),
+		    ('warehouse', 'notification.write'),
+
+		    ('admin', 'notification.read'),
+		    ('admin', 'notification.read.any'),
+		    ('admin', 'notification.write'),
+		    ('admin', 'notification.write.any'),
+		    ('admin', 'notification.template.read'),
+		    ('admin', 'notification.template.write')
+	`)
+	return errors.WithStack(err)
+}
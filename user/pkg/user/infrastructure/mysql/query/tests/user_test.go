@@ -0,0 +1,256 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appmodel "user/pkg/user/application/model"
+	appquery "user/pkg/user/application/query"
+	"user/pkg/user/domain/model"
+	"user/pkg/user/infrastructure/mysql/query"
+)
+
+func TestUserQueryService_FindUserBy(t *testing.T) {
+	email := "john@example.com"
+	telegram := "@jane"
+	client := newFakeClient(
+		fakeUser(uuid.Must(uuid.NewV7()), model.Active, "john_doe", &email, nil, time.Now()),
+		fakeUser(uuid.Must(uuid.NewV7()), model.Blocked, "jane_doe", nil, &telegram, time.Now()),
+	)
+	queryService := query.NewUserQueryService(client)
+
+	user, err := queryService.FindUserBy(context.Background(), model.FindSpec{Email: &email})
+	require.NoError(t, err)
+	assert.Equal(t, "john_doe", user.Login)
+	assert.Equal(t, int(model.Active), user.Status)
+	require.NotNil(t, user.Email)
+	assert.Equal(t, email, *user.Email)
+	assert.Nil(t, user.Telegram)
+
+	login := "jane_doe"
+	user, err = queryService.FindUserBy(context.Background(), model.FindSpec{Login: &login, Telegram: &telegram})
+	require.NoError(t, err)
+	assert.Equal(t, login, user.Login)
+
+	// all set fields of the spec must match
+	_, err = queryService.FindUserBy(context.Background(), model.FindSpec{Login: &login, Email: &email})
+	require.ErrorIs(t, err, model.ErrUserNotFound)
+
+	_, err = queryService.FindUserBy(context.Background(), model.FindSpec{})
+	require.ErrorIs(t, err, model.ErrUserNotFound)
+}
+
+func TestUserQueryService_ListUsers_Cursor(t *testing.T) {
+	var users []map[string]interface{}
+	createdAt := time.Now().Add(-time.Hour)
+	for i := 0; i < 5; i++ {
+		users = append(users, fakeUser(uuid.Must(uuid.NewV7()), model.Active, "user"+string(rune('a'+i)), nil, nil, createdAt))
+	}
+	queryService := query.NewUserQueryService(newFakeClient(users...))
+
+	var logins []string
+	spec := appmodel.ListUsersSpec{Limit: 2}
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3)
+		list, err := queryService.ListUsers(context.Background(), spec)
+		require.NoError(t, err)
+		for _, user := range list.Users {
+			logins = append(logins, user.Login)
+		}
+		if list.NextCursor == "" {
+			break
+		}
+		require.Len(t, list.Users, 2)
+		spec.Cursor = list.NextCursor
+	}
+	assert.Equal(t, []string{"usera", "userb", "userc", "userd", "usere"}, logins)
+
+	_, err := queryService.ListUsers(context.Background(), appmodel.ListUsersSpec{Cursor: "not a cursor"})
+	require.ErrorIs(t, err, appquery.ErrInvalidCursor)
+}
+
+func TestUserQueryService_ListUsers_Filters(t *testing.T) {
+	now := time.Now()
+	queryService := query.NewUserQueryService(newFakeClient(
+		fakeUser(uuid.Must(uuid.NewV7()), model.Active, "old_active", nil, nil, now.Add(-48*time.Hour)),
+		fakeUser(uuid.Must(uuid.NewV7()), model.Active, "active", nil, nil, now.Add(-time.Hour)),
+		fakeUser(uuid.Must(uuid.NewV7()), model.Blocked, "blocked", nil, nil, now.Add(-time.Hour)),
+		fakeUser(uuid.Must(uuid.NewV7()), model.Active, "new_active", nil, nil, now),
+	))
+
+	status := int(model.Active)
+	createdFrom := now.Add(-24 * time.Hour)
+	createdTo := now.Add(-time.Minute)
+	list, err := queryService.ListUsers(context.Background(), appmodel.ListUsersSpec{
+		Status:      &status,
+		CreatedFrom: &createdFrom,
+		CreatedTo:   &createdTo,
+	})
+	require.NoError(t, err)
+	require.Len(t, list.Users, 1)
+	assert.Equal(t, "active", list.Users[0].Login)
+	assert.Empty(t, list.NextCursor)
+
+	blocked := int(model.Blocked)
+	list, err = queryService.ListUsers(context.Background(), appmodel.ListUsersSpec{Status: &blocked})
+	require.NoError(t, err)
+	require.Len(t, list.Users, 1)
+	assert.Equal(t, "blocked", list.Users[0].Login)
+}
+
+func fakeUser(userID uuid.UUID, status model.UserStatus, login string, email, telegram *string, createdAt time.Time) map[string]interface{} {
+	user := map[string]interface{}{
+		"user_id":           userID,
+		"status":            int(status),
+		"role":              string(model.RoleCustomer),
+		"login":             login,
+		"email":             nil,
+		"telegram":          nil,
+		"email_verified":    false,
+		"telegram_verified": false,
+		"created_at":        createdAt,
+	}
+	if email != nil {
+		user["email"] = *email
+	}
+	if telegram != nil {
+		user["telegram"] = *telegram
+	}
+	return user
+}
+
+func newFakeClient(users ...map[string]interface{}) *fakeClient {
+	return &fakeClient{users: users}
+}
+
+// fakeClient evaluates queries of the user query service on rows kept in memory:
+// conditions joined by AND, ORDER BY user_id and LIMIT are supported
+type fakeClient struct {
+	users []map[string]interface{}
+}
+
+func (c *fakeClient) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	panic("not implemented")
+}
+
+func (c *fakeClient) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	panic("not implemented")
+}
+
+func (c *fakeClient) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	panic("not implemented")
+}
+
+func (c *fakeClient) SelectContext(_ context.Context, dest interface{}, query string, args ...interface{}) error {
+	rows := c.query(query, args)
+	slice := reflect.ValueOf(dest).Elem()
+	for _, row := range rows {
+		item := reflect.New(slice.Type().Elem()).Elem()
+		scanRow(item, row)
+		slice.Set(reflect.Append(slice, item))
+	}
+	return nil
+}
+
+func (c *fakeClient) GetContext(_ context.Context, dest interface{}, query string, args ...interface{}) error {
+	rows := c.query(query, args)
+	if len(rows) == 0 {
+		return sql.ErrNoRows
+	}
+	scanRow(reflect.ValueOf(dest).Elem(), rows[0])
+	return nil
+}
+
+func (c *fakeClient) query(query string, args []interface{}) []map[string]interface{} {
+	limit := -1
+	if strings.HasSuffix(query, " LIMIT ?") {
+		limit = args[len(args)-1].(int)
+		args = args[:len(args)-1]
+	}
+
+	var conditions []string
+	if _, where, found := strings.Cut(query, " WHERE "); found {
+		where, _, _ = strings.Cut(where, " ORDER BY ")
+		conditions = strings.Split(where, " AND ")
+	}
+
+	var result []map[string]interface{}
+	for _, row := range c.users {
+		matched := true
+		for i, condition := range conditions {
+			fields := strings.Fields(condition)
+			if !compare(row[fields[0]], fields[1], args[i]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			result = append(result, row)
+		}
+	}
+	if strings.Contains(query, " ORDER BY user_id") {
+		sort.Slice(result, func(i, j int) bool {
+			return compare(result[i]["user_id"], "<", result[j]["user_id"])
+		})
+	}
+	if limit >= 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+func compare(value interface{}, op string, arg interface{}) bool {
+	var cmp int
+	switch v := value.(type) {
+	case uuid.UUID:
+		cmp = strings.Compare(v.String(), arg.(uuid.UUID).String())
+	case time.Time:
+		cmp = v.Compare(arg.(time.Time))
+	case int:
+		cmp = v - arg.(int)
+	case string:
+		cmp = strings.Compare(v, arg.(string))
+	default:
+		// NULL does not match any condition
+		return false
+	}
+	switch op {
+	case "=":
+		return cmp == 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	}
+	panic("unsupported operator " + op)
+}
+
+func scanRow(dest reflect.Value, row map[string]interface{}) {
+	for i := 0; i < dest.NumField(); i++ {
+		column := row[dest.Type().Field(i).Tag.Get("db")]
+		value := reflect.ValueOf(column)
+		field := dest.Field(i)
+		if value.IsValid() && value.Type().AssignableTo(field.Type()) {
+			field.Set(value)
+			continue
+		}
+		// nullable columns are scanned from nil or value
+		err := field.Addr().Interface().(sql.Scanner).Scan(column)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package query
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"strings"
 	"time"
 
 	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
@@ -15,6 +17,13 @@ import (
 	"user/pkg/user/infrastructure/metrics"
 )
 
+const (
+	defaultListUsersLimit = 20
+	maxListUsersLimit     = 100
+)
+
+const selectUser = `SELECT user_id, status, role, login, email, telegram, email_verified, telegram_verified, created_at FROM user`
+
 func NewUserQueryService(client mysql.ClientContext) query.UserQueryService {
 	return &userQueryService{
 		client: client,
@@ -25,7 +34,79 @@ type userQueryService struct {
 	client mysql.ClientContext
 }
 
-func (u *userQueryService) FindUser(ctx context.Context, userID uuid.UUID) (_ *appmodel.User, err error) {
+func (u *userQueryService) FindUser(ctx context.Context, userID uuid.UUID) (*appmodel.User, error) {
+	return u.findUser(ctx, model.FindSpec{UserID: &userID})
+}
+
+func (u *userQueryService) FindUserBy(ctx context.Context, spec model.FindSpec) (*appmodel.User, error) {
+	return u.findUser(ctx, spec)
+}
+
+func (u *userQueryService) ListUsers(ctx context.Context, spec appmodel.ListUsersSpec) (_ appmodel.UserList, err error) {
+	start := time.Now()
+	defer func() {
+		status := "success"
+		if err != nil && !errors.Is(err, query.ErrInvalidCursor) {
+			status = "error"
+		}
+		metrics.DatabaseDuration.WithLabelValues("list_query", "user", status).Observe(time.Since(start).Seconds())
+	}()
+
+	limit := spec.Limit
+	if limit <= 0 || limit > maxListUsersLimit {
+		limit = defaultListUsersLimit
+	}
+
+	var conditions []string
+	var args []interface{}
+	if spec.Status != nil {
+		conditions = append(conditions, "status = ?")
+		args = append(args, *spec.Status)
+	}
+	if spec.CreatedFrom != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *spec.CreatedFrom)
+	}
+	if spec.CreatedTo != nil {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, *spec.CreatedTo)
+	}
+	if spec.Cursor != "" {
+		afterID, err2 := decodeCursor(spec.Cursor)
+		if err2 != nil {
+			return appmodel.UserList{}, err2
+		}
+		// user IDs are UUIDv7, so order of IDs is order of creation
+		conditions = append(conditions, "user_id > ?")
+		args = append(args, afterID)
+	}
+	// fetch one extra row to know whether next page exists
+	args = append(args, limit+1)
+
+	var rows []sqlxUser
+	err = u.client.SelectContext(
+		ctx,
+		&rows,
+		selectUser+whereClause(conditions)+` ORDER BY user_id LIMIT ?`,
+		args...,
+	)
+	if err != nil {
+		return appmodel.UserList{}, errors.WithStack(err)
+	}
+
+	var result appmodel.UserList
+	if len(rows) > limit {
+		rows = rows[:limit]
+		result.NextCursor = encodeCursor(rows[limit-1].UserID)
+	}
+	result.Users = make([]appmodel.User, 0, len(rows))
+	for _, row := range rows {
+		result.Users = append(result.Users, row.toAppModel())
+	}
+	return result, nil
+}
+
+func (u *userQueryService) findUser(ctx context.Context, spec model.FindSpec) (_ *appmodel.User, err error) {
 	start := time.Now()
 	defer func() {
 		status := "success"
@@ -35,19 +116,17 @@ func (u *userQueryService) FindUser(ctx context.Context, userID uuid.UUID) (_ *a
 		metrics.DatabaseDuration.WithLabelValues("find_query", "user", status).Observe(time.Since(start).Seconds())
 	}()
 
-	user := struct {
-		UserID   uuid.UUID        `db:"user_id"`
-		Status   int              `db:"status"`
-		Login    string           `db:"login"`
-		Email    sql.Null[string] `db:"email"`
-		Telegram sql.Null[string] `db:"telegram"`
-	}{}
+	conditions, args := findSpecConditions(spec)
+	if len(conditions) == 0 {
+		return nil, errors.WithStack(model.ErrUserNotFound)
+	}
 
+	var user sqlxUser
 	err = u.client.GetContext(
 		ctx,
 		&user,
-		`SELECT user_id, status, login, email, telegram FROM user WHERE user_id = ?`,
-		userID,
+		selectUser+whereClause(conditions),
+		args...,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -56,13 +135,79 @@ func (u *userQueryService) FindUser(ctx context.Context, userID uuid.UUID) (_ *a
 		return nil, errors.WithStack(err)
 	}
 
-	return &appmodel.User{
-		UserID:   user.UserID,
-		Status:   user.Status,
-		Login:    user.Login,
-		Email:    fromSQLNull(user.Email),
-		Telegram: fromSQLNull(user.Telegram),
-	}, nil
+	result := user.toAppModel()
+	return &result, nil
+}
+
+type sqlxUser struct {
+	UserID   uuid.UUID        `db:"user_id"`
+	Status   int              `db:"status"`
+	Role     string           `db:"role"`
+	Login    string           `db:"login"`
+	Email    sql.Null[string] `db:"email"`
+	Telegram sql.Null[string] `db:"telegram"`
+
+	EmailVerified    bool      `db:"email_verified"`
+	TelegramVerified bool      `db:"telegram_verified"`
+	CreatedAt        time.Time `db:"created_at"`
+}
+
+func (u sqlxUser) toAppModel() appmodel.User {
+	return appmodel.User{
+		UserID:   u.UserID,
+		Status:   u.Status,
+		Role:     u.Role,
+		Login:    u.Login,
+		Email:    fromSQLNull(u.Email),
+		Telegram: fromSQLNull(u.Telegram),
+
+		EmailVerified:    u.EmailVerified,
+		TelegramVerified: u.TelegramVerified,
+		CreatedAt:        u.CreatedAt,
+	}
+}
+
+func findSpecConditions(spec model.FindSpec) (conditions []string, args []interface{}) {
+	if spec.UserID != nil {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, *spec.UserID)
+	}
+	if spec.Login != nil {
+		conditions = append(conditions, "login = ?")
+		args = append(args, *spec.Login)
+	}
+	if spec.Email != nil {
+		conditions = append(conditions, "email = ?")
+		args = append(args, *spec.Email)
+	}
+	if spec.Telegram != nil {
+		conditions = append(conditions, "telegram = ?")
+		args = append(args, *spec.Telegram)
+	}
+	return conditions, args
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return ` WHERE ` + strings.Join(conditions, " AND ")
+}
+
+func encodeCursor(id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString(id[:])
+}
+
+func decodeCursor(cursor string) (uuid.UUID, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return uuid.Nil, errors.WithStack(query.ErrInvalidCursor)
+	}
+	id, err := uuid.FromBytes(b)
+	if err != nil {
+		return uuid.Nil, errors.WithStack(query.ErrInvalidCursor)
+	}
+	return id, nil
 }
 
 func fromSQLNull[T any](v sql.Null[T]) *T {
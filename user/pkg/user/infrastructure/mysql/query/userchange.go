@@ -0,0 +1,82 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	appmodel "user/pkg/user/application/model"
+	"user/pkg/user/application/query"
+	"user/pkg/user/infrastructure/metrics"
+)
+
+func (u *userQueryService) ListUserChanges(ctx context.Context, spec appmodel.ListUserChangesSpec) (_ appmodel.UserChangeList, err error) {
+	start := time.Now()
+	defer func() {
+		status := "success"
+		if err != nil && !errors.Is(err, query.ErrInvalidCursor) {
+			status = "error"
+		}
+		metrics.DatabaseDuration.WithLabelValues("list_query", "user_change", status).Observe(time.Since(start).Seconds())
+	}()
+
+	limit := spec.Limit
+	if limit <= 0 || limit > maxListUsersLimit {
+		limit = defaultListUsersLimit
+	}
+
+	conditions := []string{"user_id = ?"}
+	args := []interface{}{spec.UserID}
+	if spec.Cursor != "" {
+		beforeID, err2 := decodeCursor(spec.Cursor)
+		if err2 != nil {
+			return appmodel.UserChangeList{}, err2
+		}
+		// change IDs are UUIDv7, so reverse order of IDs is newest first
+		conditions = append(conditions, "change_id < ?")
+		args = append(args, beforeID)
+	}
+	// fetch one extra row to know whether next page exists
+	args = append(args, limit+1)
+
+	var rows []sqlxUserChange
+	err = u.client.SelectContext(
+		ctx,
+		&rows,
+		`SELECT change_id, field, old_value, new_value, actor_id, changed_at FROM user_change`+whereClause(conditions)+` ORDER BY change_id DESC LIMIT ?`,
+		args...,
+	)
+	if err != nil {
+		return appmodel.UserChangeList{}, errors.WithStack(err)
+	}
+
+	var result appmodel.UserChangeList
+	if len(rows) > limit {
+		rows = rows[:limit]
+		result.NextCursor = encodeCursor(rows[limit-1].ChangeID)
+	}
+	result.Changes = make([]appmodel.UserChange, 0, len(rows))
+	for _, row := range rows {
+		result.Changes = append(result.Changes, appmodel.UserChange{
+			ChangeID:  row.ChangeID,
+			Field:     row.Field,
+			OldValue:  fromSQLNull(row.OldValue),
+			NewValue:  fromSQLNull(row.NewValue),
+			ActorID:   fromSQLNull(row.ActorID),
+			ChangedAt: row.ChangedAt,
+		})
+	}
+	return result, nil
+}
+
+type sqlxUserChange struct {
+	ChangeID  uuid.UUID           `db:"change_id"`
+	Field     string              `db:"field"`
+	OldValue  sql.Null[string]    `db:"old_value"`
+	NewValue  sql.Null[string]    `db:"new_value"`
+	ActorID   sql.Null[uuid.UUID] `db:"actor_id"`
+	ChangedAt time.Time           `db:"changed_at"`
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"user/pkg/user/domain/model"
+	"user/pkg/user/infrastructure/metrics"
+)
+
+func NewContactVerificationRepository(ctx context.Context, client mysql.ClientContext) model.ContactVerificationRepository {
+	return &contactVerificationRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type contactVerificationRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+type sqlContactVerification struct {
+	VerificationID uuid.UUID           `db:"verification_id"`
+	UserID         uuid.UUID           `db:"user_id"`
+	ContactType    string              `db:"contact_type"`
+	Contact        string              `db:"contact"`
+	CodeHash       string              `db:"code_hash"`
+	Attempts       int                 `db:"attempts"`
+	ExpiresAt      time.Time           `db:"expires_at"`
+	VerifiedAt     sql.Null[time.Time] `db:"verified_at"`
+	CreatedAt      time.Time           `db:"created_at"`
+}
+
+const selectContactVerification = `SELECT verification_id, user_id, contact_type, contact, code_hash, attempts, expires_at, verified_at, created_at FROM contact_verification`
+
+func (r *contactVerificationRepository) Store(verification model.ContactVerification) (err error) {
+	start := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil {
+			status = statusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("store", "contact_verification", status).Observe(time.Since(start).Seconds())
+	}()
+
+	_, err = r.client.ExecContext(r.ctx,
+		`
+	INSERT INTO contact_verification (verification_id, user_id, contact_type, contact, code_hash, attempts, expires_at, verified_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE
+	    attempts=VALUES(attempts),
+	    verified_at=VALUES(verified_at)
+	`,
+		verification.VerificationID,
+		verification.UserID,
+		verification.ContactType,
+		verification.Contact,
+		verification.CodeHash,
+		verification.Attempts,
+		verification.ExpiresAt,
+		toSQLNull(verification.VerifiedAt),
+		verification.CreatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (r *contactVerificationRepository) Find(verificationID uuid.UUID) (*model.ContactVerification, error) {
+	return r.find("find", selectContactVerification+` WHERE verification_id = ?`, verificationID)
+}
+
+func (r *contactVerificationRepository) CountCreatedSince(userID uuid.UUID, contactType model.ContactType, since time.Time) (_ int, err error) {
+	start := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil {
+			status = statusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("count", "contact_verification", status).Observe(time.Since(start).Seconds())
+	}()
+
+	var count int
+	err = r.client.GetContext(r.ctx, &count,
+		`SELECT COUNT(*) FROM contact_verification WHERE user_id = ? AND contact_type = ? AND created_at >= ?`,
+		userID,
+		contactType,
+		since,
+	)
+	return count, errors.WithStack(err)
+}
+
+func (r *contactVerificationRepository) DeleteByUser(userID uuid.UUID) (_ int, err error) {
+	start := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil {
+			status = statusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("delete", "contact_verification", status).Observe(time.Since(start).Seconds())
+	}()
+
+	result, err := r.client.ExecContext(r.ctx, `DELETE FROM contact_verification WHERE user_id = ?`, userID)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), errors.WithStack(err)
+}
+
+func (r *contactVerificationRepository) find(op, query string, args ...interface{}) (_ *model.ContactVerification, err error) {
+	start := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil && !errors.Is(err, model.ErrVerificationNotFound) {
+			status = statusError
+		}
+		metrics.DatabaseDuration.WithLabelValues(op, "contact_verification", status).Observe(time.Since(start).Seconds())
+	}()
+
+	var verification sqlContactVerification
+	err = r.client.GetContext(r.ctx, &verification, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrVerificationNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	return &model.ContactVerification{
+		VerificationID: verification.VerificationID,
+		UserID:         verification.UserID,
+		ContactType:    model.ContactType(verification.ContactType),
+		Contact:        verification.Contact,
+		CodeHash:       verification.CodeHash,
+		Attempts:       verification.Attempts,
+		ExpiresAt:      verification.ExpiresAt,
+		VerifiedAt:     fromSQLNull(verification.VerifiedAt),
+		CreatedAt:      verification.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"user/pkg/user/domain/model"
+	"user/pkg/user/infrastructure/metrics"
+)
+
+func NewLoginAttemptRepository(ctx context.Context, client mysql.ClientContext) model.LoginAttemptRepository {
+	return &loginAttemptRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type loginAttemptRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (r *loginAttemptRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (r *loginAttemptRepository) Store(attempt model.LoginAttempt) (err error) {
+	start := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil {
+			status = statusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("store", "login_attempt", status).Observe(time.Since(start).Seconds())
+	}()
+
+	_, err = r.client.ExecContext(r.ctx,
+		`INSERT INTO login_attempt (attempt_id, login, ip, attempted_at) VALUES (?, ?, ?, ?)`,
+		attempt.AttemptID,
+		attempt.Login,
+		attempt.IP,
+		attempt.AttemptedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (r *loginAttemptRepository) CountByLogin(login string, since time.Time) (int, error) {
+	return r.count("count_by_login", `SELECT COUNT(*) FROM login_attempt WHERE login = ? AND attempted_at >= ?`, login, since)
+}
+
+func (r *loginAttemptRepository) CountByIP(ip string, since time.Time) (int, error) {
+	return r.count("count_by_ip", `SELECT COUNT(*) FROM login_attempt WHERE ip = ? AND attempted_at >= ?`, ip, since)
+}
+
+func (r *loginAttemptRepository) DeleteByLogin(login string) (_ int, err error) {
+	start := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil {
+			status = statusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("delete", "login_attempt", status).Observe(time.Since(start).Seconds())
+	}()
+
+	result, err := r.client.ExecContext(r.ctx, `DELETE FROM login_attempt WHERE login = ?`, login)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), errors.WithStack(err)
+}
+
+func (r *loginAttemptRepository) DeleteBefore(before time.Time) (err error) {
+	start := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil {
+			status = statusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("delete_expired", "login_attempt", status).Observe(time.Since(start).Seconds())
+	}()
+
+	_, err = r.client.ExecContext(r.ctx, `DELETE FROM login_attempt WHERE attempted_at < ?`, before)
+	return errors.WithStack(err)
+}
+
+func (r *loginAttemptRepository) count(op, query string, args ...interface{}) (_ int, err error) {
+	start := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil {
+			status = statusError
+		}
+		metrics.DatabaseDuration.WithLabelValues(op, "login_attempt", status).Observe(time.Since(start).Seconds())
+	}()
+
+	var count int
+	err = r.client.GetContext(r.ctx, &count, query, args...)
+	return count, errors.WithStack(err)
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"user/pkg/user/domain/model"
+	"user/pkg/user/infrastructure/metrics"
+)
+
+func NewPreferencesRepository(ctx context.Context, client mysql.ClientContext) model.PreferencesRepository {
+	return &preferencesRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type preferencesRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (r *preferencesRepository) Store(preferences model.Preferences) (err error) {
+	start := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil {
+			status = statusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("store", "user_preferences", status).Observe(time.Since(start).Seconds())
+	}()
+
+	_, err = r.client.ExecContext(r.ctx,
+		`
+	INSERT INTO user_preferences (user_id, locale, time_zone, created_at, updated_at) VALUES (?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE
+	    locale=VALUES(locale),
+	    time_zone=VALUES(time_zone),
+	    updated_at=VALUES(updated_at)
+	`,
+		preferences.UserID,
+		preferences.Locale,
+		preferences.TimeZone,
+		preferences.CreatedAt,
+		preferences.UpdatedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (r *preferencesRepository) Find(userID uuid.UUID) (_ *model.Preferences, err error) {
+	start := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil && !errors.Is(err, model.ErrPreferencesNotFound) {
+			status = statusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("find", "user_preferences", status).Observe(time.Since(start).Seconds())
+	}()
+
+	preferences := struct {
+		UserID    uuid.UUID `db:"user_id"`
+		Locale    string    `db:"locale"`
+		TimeZone  string    `db:"time_zone"`
+		CreatedAt time.Time `db:"created_at"`
+		UpdatedAt time.Time `db:"updated_at"`
+	}{}
+	err = r.client.GetContext(
+		r.ctx,
+		&preferences,
+		`SELECT user_id, locale, time_zone, created_at, updated_at FROM user_preferences WHERE user_id = ?`,
+		userID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrPreferencesNotFound)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	return &model.Preferences{
+		UserID:    preferences.UserID,
+		Locale:    preferences.Locale,
+		TimeZone:  preferences.TimeZone,
+		CreatedAt: preferences.CreatedAt,
+		UpdatedAt: preferences.UpdatedAt,
+	}, nil
+}
+
+func (r *preferencesRepository) Delete(userID uuid.UUID) (_ int, err error) {
+	start := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil {
+			status = statusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("delete", "user_preferences", status).Observe(time.Since(start).Seconds())
+	}()
+
+	result, err := r.client.ExecContext(r.ctx, `DELETE FROM user_preferences WHERE user_id = ?`, userID)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), errors.WithStack(err)
+}
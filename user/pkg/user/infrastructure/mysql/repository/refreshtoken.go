@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"user/pkg/user/domain/model"
+	"user/pkg/user/infrastructure/metrics"
+)
+
+func NewRefreshTokenRepository(ctx context.Context, client mysql.ClientContext) model.RefreshTokenRepository {
+	return &refreshTokenRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type refreshTokenRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (r *refreshTokenRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (r *refreshTokenRepository) Store(token model.RefreshToken) (err error) {
+	start := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil {
+			status = statusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("store", "refresh_token", status).Observe(time.Since(start).Seconds())
+	}()
+
+	_, err = r.client.ExecContext(r.ctx,
+		`
+	INSERT INTO refresh_token (token_id, user_id, expires_at, created_at, revoked_at) VALUES (?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE
+	    revoked_at=VALUES(revoked_at)
+	`,
+		token.TokenID,
+		token.UserID,
+		token.ExpiresAt,
+		token.CreatedAt,
+		toSQLNull(token.RevokedAt),
+	)
+	return errors.WithStack(err)
+}
+
+func (r *refreshTokenRepository) Find(tokenID uuid.UUID) (_ *model.RefreshToken, err error) {
+	start := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil && !errors.Is(err, model.ErrInvalidRefreshToken) {
+			status = statusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("find", "refresh_token", status).Observe(time.Since(start).Seconds())
+	}()
+
+	token := struct {
+		TokenID   uuid.UUID           `db:"token_id"`
+		UserID    uuid.UUID           `db:"user_id"`
+		ExpiresAt time.Time           `db:"expires_at"`
+		CreatedAt time.Time           `db:"created_at"`
+		RevokedAt sql.Null[time.Time] `db:"revoked_at"`
+	}{}
+	err = r.client.GetContext(
+		r.ctx,
+		&token,
+		`SELECT token_id, user_id, expires_at, created_at, revoked_at FROM refresh_token WHERE token_id = ?`,
+		tokenID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.WithStack(model.ErrInvalidRefreshToken)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	return &model.RefreshToken{
+		TokenID:   token.TokenID,
+		UserID:    token.UserID,
+		ExpiresAt: token.ExpiresAt,
+		CreatedAt: token.CreatedAt,
+		RevokedAt: fromSQLNull(token.RevokedAt),
+	}, nil
+}
+
+func (r *refreshTokenRepository) RevokeByUser(userID uuid.UUID, at time.Time) (err error) {
+	start := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil {
+			status = statusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("revoke", "refresh_token", status).Observe(time.Since(start).Seconds())
+	}()
+
+	_, err = r.client.ExecContext(r.ctx,
+		`UPDATE refresh_token SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`,
+		at,
+		userID,
+	)
+	return errors.WithStack(err)
+}
+
+func (r *refreshTokenRepository) DeleteByUser(userID uuid.UUID) (_ int, err error) {
+	start := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil {
+			status = statusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("delete", "refresh_token", status).Observe(time.Since(start).Seconds())
+	}()
+
+	result, err := r.client.ExecContext(r.ctx, `DELETE FROM refresh_token WHERE user_id = ?`, userID)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), errors.WithStack(err)
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+
+	"user/pkg/user/domain/model"
+	"user/pkg/user/infrastructure/metrics"
+)
+
+func NewRolePermissionRepository(ctx context.Context, client mysql.ClientContext) model.RolePermissionRepository {
+	return &rolePermissionRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type rolePermissionRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (r *rolePermissionRepository) ListPermissions(role model.Role) (_ []model.Permission, err error) {
+	start := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil {
+			status = statusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("list", "role_permission", status).Observe(time.Since(start).Seconds())
+	}()
+
+	var permissions []string
+	err = r.client.SelectContext(r.ctx,
+		&permissions,
+		`SELECT permission FROM role_permission WHERE role = ? ORDER BY permission`,
+		role,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]model.Permission, 0, len(permissions))
+	for _, permission := range permissions {
+		result = append(result, model.Permission(permission))
+	}
+	return result, nil
+}
@@ -47,20 +47,30 @@ func (u *userRepository) Store(user model.User) (err error) {
 
 	_, err = u.client.ExecContext(u.ctx,
 		`
-	INSERT INTO user (user_id, status, login, email, telegram, created_at, updated_at, deleted_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	INSERT INTO user (user_id, status, role, login, email, telegram, email_verified, telegram_verified, password_hash, locked_until, created_at, updated_at, deleted_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	ON DUPLICATE KEY UPDATE
 		status=VALUES(status),
+	    role=VALUES(role),
 	    login=VALUES(login),
 	    email=VALUES(email),
 	    telegram=VALUES(telegram),
+	    email_verified=VALUES(email_verified),
+	    telegram_verified=VALUES(telegram_verified),
+	    password_hash=VALUES(password_hash),
+	    locked_until=VALUES(locked_until),
 	    updated_at=VALUES(updated_at),
 	    deleted_at=VALUES(deleted_at)
 	`,
 		user.UserID,
 		user.Status,
+		user.Role,
 		user.Login,
 		toSQLNull(user.Email),
 		toSQLNull(user.Telegram),
+		user.EmailVerified,
+		user.TelegramVerified,
+		toSQLNull(user.PasswordHash),
+		toSQLNull(user.LockedUntil),
 		user.CreatedAt,
 		user.UpdatedAt,
 		toSQLNull(user.DeletedAt),
@@ -79,21 +89,26 @@ func (u *userRepository) Find(spec model.FindSpec) (_ *model.User, err error) {
 	}()
 
 	user := struct {
-		UserID    uuid.UUID           `db:"user_id"`
-		Status    int                 `db:"status"`
-		Login     string              `db:"login"`
-		Email     sql.Null[string]    `db:"email"`
-		Telegram  sql.Null[string]    `db:"telegram"`
-		CreatedAt time.Time           `db:"created_at"`
-		UpdatedAt time.Time           `db:"updated_at"`
-		DeletedAt sql.Null[time.Time] `db:"deleted_at"`
+		UserID           uuid.UUID           `db:"user_id"`
+		Status           int                 `db:"status"`
+		Role             string              `db:"role"`
+		Login            string              `db:"login"`
+		Email            sql.Null[string]    `db:"email"`
+		Telegram         sql.Null[string]    `db:"telegram"`
+		EmailVerified    bool                `db:"email_verified"`
+		TelegramVerified bool                `db:"telegram_verified"`
+		PasswordHash     sql.Null[string]    `db:"password_hash"`
+		LockedUntil      sql.Null[time.Time] `db:"locked_until"`
+		CreatedAt        time.Time           `db:"created_at"`
+		UpdatedAt        time.Time           `db:"updated_at"`
+		DeletedAt        sql.Null[time.Time] `db:"deleted_at"`
 	}{}
 	query, args := u.buildSpecArgs(spec)
 
 	err = u.client.GetContext(
 		u.ctx,
 		&user,
-		`SELECT user_id, status, login, email, telegram, created_at, updated_at, deleted_at FROM user WHERE `+query,
+		`SELECT user_id, status, role, login, email, telegram, email_verified, telegram_verified, password_hash, locked_until, created_at, updated_at, deleted_at FROM user WHERE `+query,
 		args...,
 	)
 	if err != nil {
@@ -104,14 +119,19 @@ func (u *userRepository) Find(spec model.FindSpec) (_ *model.User, err error) {
 	}
 
 	return &model.User{
-		UserID:    user.UserID,
-		Status:    model.UserStatus(user.Status),
-		Login:     user.Login,
-		Email:     fromSQLNull(user.Email),
-		Telegram:  fromSQLNull(user.Telegram),
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-		DeletedAt: fromSQLNull(user.DeletedAt),
+		UserID:           user.UserID,
+		Status:           model.UserStatus(user.Status),
+		Role:             model.Role(user.Role),
+		Login:            user.Login,
+		Email:            fromSQLNull(user.Email),
+		Telegram:         fromSQLNull(user.Telegram),
+		EmailVerified:    user.EmailVerified,
+		TelegramVerified: user.TelegramVerified,
+		PasswordHash:     fromSQLNull(user.PasswordHash),
+		LockedUntil:      fromSQLNull(user.LockedUntil),
+		CreatedAt:        user.CreatedAt,
+		UpdatedAt:        user.UpdatedAt,
+		DeletedAt:        fromSQLNull(user.DeletedAt),
 	}, nil
 }
 
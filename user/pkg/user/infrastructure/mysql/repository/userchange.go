@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gitea.xscloud.ru/xscloud/golib/pkg/infrastructure/mysql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"user/pkg/user/domain/model"
+	"user/pkg/user/infrastructure/metrics"
+)
+
+func NewUserChangeRepository(ctx context.Context, client mysql.ClientContext) model.UserChangeRepository {
+	return &userChangeRepository{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+type userChangeRepository struct {
+	ctx    context.Context
+	client mysql.ClientContext
+}
+
+func (r *userChangeRepository) NextID() (uuid.UUID, error) {
+	return uuid.NewV7()
+}
+
+func (r *userChangeRepository) Store(change model.UserChange) (err error) {
+	start := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil {
+			status = statusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("store", "user_change", status).Observe(time.Since(start).Seconds())
+	}()
+
+	_, err = r.client.ExecContext(r.ctx,
+		`INSERT INTO user_change (change_id, user_id, field, old_value, new_value, actor_id, changed_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		change.ChangeID,
+		change.UserID,
+		change.Field,
+		toSQLNull(change.OldValue),
+		toSQLNull(change.NewValue),
+		toSQLNull(change.ActorID),
+		change.ChangedAt,
+	)
+	return errors.WithStack(err)
+}
+
+func (r *userChangeRepository) DeleteByUser(userID uuid.UUID) (_ int, err error) {
+	start := time.Now()
+	defer func() {
+		status := statusSuccess
+		if err != nil {
+			status = statusError
+		}
+		metrics.DatabaseDuration.WithLabelValues("delete", "user_change", status).Observe(time.Since(start).Seconds())
+	}()
+
+	result, err := r.client.ExecContext(r.ctx, `DELETE FROM user_change WHERE user_id = ?`, userID)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), errors.WithStack(err)
+}
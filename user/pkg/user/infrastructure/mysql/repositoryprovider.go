@@ -21,3 +21,27 @@ type repositoryProvider struct {
 func (r *repositoryProvider) UserRepository(ctx context.Context) model.UserRepository {
 	return repository.NewUserRepository(ctx, r.client)
 }
+
+func (r *repositoryProvider) RefreshTokenRepository(ctx context.Context) model.RefreshTokenRepository {
+	return repository.NewRefreshTokenRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) RolePermissionRepository(ctx context.Context) model.RolePermissionRepository {
+	return repository.NewRolePermissionRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) ContactVerificationRepository(ctx context.Context) model.ContactVerificationRepository {
+	return repository.NewContactVerificationRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) PreferencesRepository(ctx context.Context) model.PreferencesRepository {
+	return repository.NewPreferencesRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) UserChangeRepository(ctx context.Context) model.UserChangeRepository {
+	return repository.NewUserChangeRepository(ctx, r.client)
+}
+
+func (r *repositoryProvider) LoginAttemptRepository(ctx context.Context) model.LoginAttemptRepository {
+	return repository.NewLoginAttemptRepository(ctx, r.client)
+}
@@ -0,0 +1,73 @@
+package activity
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/temporal"
+
+	appmodel "user/pkg/user/application/model"
+	"user/pkg/user/application/service"
+	"user/pkg/user/domain/model"
+)
+
+// verificationErrorType marks application errors carrying domain errors of contact verification,
+// they are not retried since retry only counts one more failed attempt
+const verificationErrorType = "ContactVerificationError"
+
+var verificationErrors = []error{
+	model.ErrUserNotFound,
+	model.ErrInvalidContactType,
+	model.ErrContactNotSet,
+	model.ErrVerificationNotFound,
+	model.ErrInvalidVerificationCode,
+	model.ErrVerificationExpired,
+	model.ErrVerificationAttemptsExceeded,
+	model.ErrTooManyVerificationRequests,
+}
+
+func NewContactVerificationActivities(verificationService service.ContactVerificationService) *ContactVerificationActivities {
+	return &ContactVerificationActivities{verificationService: verificationService}
+}
+
+type ContactVerificationActivities struct {
+	verificationService service.ContactVerificationService
+}
+
+func (a *ContactVerificationActivities) StartContactVerification(
+	ctx context.Context,
+	verificationID, userID uuid.UUID,
+	contactType string,
+) (appmodel.ContactVerification, error) {
+	verification, err := a.verificationService.StartVerification(ctx, verificationID, userID, contactType)
+	return verification, toVerificationError(err)
+}
+
+func (a *ContactVerificationActivities) ConfirmContact(ctx context.Context, verification appmodel.ContactVerification, code string) error {
+	return toVerificationError(a.verificationService.ConfirmContact(ctx, verification, code))
+}
+
+// ParseVerificationError restores domain error of contact verification returned by activity,
+// other errors are returned as is
+func ParseVerificationError(err error) error {
+	var applicationErr *temporal.ApplicationError
+	if !errors.As(err, &applicationErr) || applicationErr.Type() != verificationErrorType {
+		return err
+	}
+	for _, verificationErr := range verificationErrors {
+		if applicationErr.Message() == verificationErr.Error() {
+			return verificationErr
+		}
+	}
+	return err
+}
+
+func toVerificationError(err error) error {
+	for _, verificationErr := range verificationErrors {
+		if errors.Is(err, verificationErr) {
+			return temporal.NewNonRetryableApplicationError(verificationErr.Error(), verificationErrorType, nil)
+		}
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+package activity
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"user/api/client/notificationinternal"
+	"user/api/client/paymentpublicapi"
+	"user/pkg/user/application/service"
+	"user/pkg/user/domain/model"
+)
+
+// onboardingErrorType marks errors of onboarding step which are not fixed by retry
+const onboardingErrorType = "UserOnboardingError"
+
+// OnboardedUser is new user with contacts it has when welcome message is sent
+type OnboardedUser struct {
+	UserID   uuid.UUID
+	Login    string
+	Email    *string
+	Telegram *string
+}
+
+func NewOnboardingActivities(
+	preferencesService service.PreferencesService,
+	tokenIssuer service.TokenIssuer,
+	paymentClient paymentpublicapi.PaymentPublicAPIClient,
+	notificationClient notificationinternal.NotificationInternalServiceClient,
+) *OnboardingActivities {
+	return &OnboardingActivities{
+		preferencesService: preferencesService,
+		tokenIssuer:        tokenIssuer,
+		paymentClient:      paymentClient,
+		notificationClient: notificationClient,
+	}
+}
+
+type OnboardingActivities struct {
+	preferencesService service.PreferencesService
+	tokenIssuer        service.TokenIssuer
+	paymentClient      paymentpublicapi.PaymentPublicAPIClient
+	notificationClient notificationinternal.NotificationInternalServiceClient
+}
+
+func (a *OnboardingActivities) InitPreferences(ctx context.Context, userID uuid.UUID) error {
+	return toOnboardingError(a.preferencesService.InitPreferences(ctx, userID))
+}
+
+func (a *OnboardingActivities) DeletePreferences(ctx context.Context, userID uuid.UUID) error {
+	return toOnboardingError(a.preferencesService.DeletePreferences(ctx, userID))
+}
+
+func (a *OnboardingActivities) OpenWallet(ctx context.Context, userID uuid.UUID) error {
+	ctx, err := a.authorize(ctx, userID)
+	if err != nil {
+		return err
+	}
+	_, err = a.paymentClient.OpenCustomerAccount(ctx, &paymentpublicapi.CustomerAccountRequest{
+		CustomerID: userID.String(),
+	})
+	return toOnboardingError(err)
+}
+
+func (a *OnboardingActivities) CloseWallet(ctx context.Context, userID uuid.UUID) error {
+	ctx, err := a.authorize(ctx, userID)
+	if err != nil {
+		return err
+	}
+	_, err = a.paymentClient.CloseCustomerAccount(ctx, &paymentpublicapi.CustomerAccountRequest{
+		CustomerID: userID.String(),
+	})
+	return toOnboardingError(err)
+}
+
+func (a *OnboardingActivities) SendWelcomeMessage(ctx context.Context, user OnboardedUser) error {
+	ctx, err := a.authorize(ctx, user.UserID)
+	if err != nil {
+		return err
+	}
+	_, err = a.notificationClient.SendWelcomeMessage(ctx, &notificationinternal.RecipientDataRequest{
+		Name:     user.Login,
+		Email:    user.Email,
+		Telegram: user.Telegram,
+	})
+	return toOnboardingError(err)
+}
+
+// authorize issues access token of the new user with the only permission of onboarding
+func (a *OnboardingActivities) authorize(ctx context.Context, userID uuid.UUID) (context.Context, error) {
+	return withAccessToken(ctx, a.tokenIssuer, userID, model.Access{
+		Role:        model.RoleCustomer,
+		Permissions: []model.Permission{model.PermissionUserOnboard},
+	})
+}
+
+func toOnboardingError(err error) error {
+	return toServiceCallError(err, onboardingErrorType)
+}
@@ -0,0 +1,40 @@
+package activity
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/temporal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"user/pkg/user/application/service"
+	"user/pkg/user/domain/model"
+)
+
+// withAccessToken issues short-lived access token for the call to other service,
+// token is issued on every attempt since workflow may outlive token TTL
+func withAccessToken(ctx context.Context, tokenIssuer service.TokenIssuer, userID uuid.UUID, access model.Access) (context.Context, error) {
+	accessToken, _, err := tokenIssuer.IssueAccessToken(userID, access)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+accessToken), nil
+}
+
+// toServiceCallError makes errors of activity which are not fixed by retry non-retryable with the error type
+func toServiceCallError(err error, errorType string) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, model.ErrUserNotFound) {
+		return temporal.NewNonRetryableApplicationError(err.Error(), errorType, nil)
+	}
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.Unauthenticated, codes.PermissionDenied, codes.Unimplemented:
+		return temporal.NewNonRetryableApplicationError(err.Error(), errorType, nil)
+	}
+	return err
+}
@@ -0,0 +1,231 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"user/api/client/notificationinternal"
+	"user/api/client/orderinternal"
+	"user/api/client/paymentpublicapi"
+	"user/pkg/user/application/service"
+	"user/pkg/user/domain/model"
+)
+
+// userDataErrorType marks errors of data subject request which are not fixed by retry
+const userDataErrorType = "UserDataRequestError"
+
+// Requester is administrator who made data subject request, other services are called on behalf of the requester
+type Requester struct {
+	UserID uuid.UUID
+	Role   string
+}
+
+// ExportedUser is user profile as it is exported to the user, contacts also find user in notification service
+type ExportedUser struct {
+	UserID           uuid.UUID `json:"user_id"`
+	Login            string    `json:"login"`
+	Email            *string   `json:"email,omitempty"`
+	Telegram         *string   `json:"telegram,omitempty"`
+	EmailVerified    bool      `json:"email_verified"`
+	TelegramVerified bool      `json:"telegram_verified"`
+	Role             string    `json:"role"`
+	Status           int       `json:"status"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// ServiceErasure is number of records erased by service, deleted records contained personal data,
+// pseudonymized records are financial ones kept with user replaced by pseudonym
+type ServiceErasure struct {
+	Service              string `json:"service"`
+	DeletedRecords       int    `json:"deleted_records"`
+	PseudonymizedRecords int    `json:"pseudonymized_records"`
+}
+
+// Pseudonymizer derives pseudonym replacing erased user in records kept by services
+type Pseudonymizer interface {
+	PseudonymID(userID uuid.UUID) uuid.UUID
+}
+
+func NewUserDataActivities(
+	userService service.UserService,
+	userDataService service.UserDataService,
+	tokenIssuer service.TokenIssuer,
+	orderClient orderinternal.OrderInternalServiceClient,
+	paymentClient paymentpublicapi.PaymentPublicAPIClient,
+	notificationClient notificationinternal.NotificationInternalServiceClient,
+	pseudonymizer Pseudonymizer,
+) *UserDataActivities {
+	return &UserDataActivities{
+		userService:        userService,
+		userDataService:    userDataService,
+		tokenIssuer:        tokenIssuer,
+		orderClient:        orderClient,
+		paymentClient:      paymentClient,
+		notificationClient: notificationClient,
+		pseudonymizer:      pseudonymizer,
+	}
+}
+
+type UserDataActivities struct {
+	userService        service.UserService
+	userDataService    service.UserDataService
+	tokenIssuer        service.TokenIssuer
+	orderClient        orderinternal.OrderInternalServiceClient
+	paymentClient      paymentpublicapi.PaymentPublicAPIClient
+	notificationClient notificationinternal.NotificationInternalServiceClient
+	pseudonymizer      Pseudonymizer
+}
+
+func (a *UserDataActivities) ExportUser(ctx context.Context, userID uuid.UUID) (ExportedUser, error) {
+	user, err := a.userService.FindUser(ctx, userID)
+	if err != nil {
+		return ExportedUser{}, toUserDataError(err)
+	}
+	return ExportedUser{
+		UserID:           user.UserID,
+		Login:            user.Login,
+		Email:            user.Email,
+		Telegram:         user.Telegram,
+		EmailVerified:    user.EmailVerified,
+		TelegramVerified: user.TelegramVerified,
+		Role:             user.Role,
+		Status:           user.Status,
+		CreatedAt:        user.CreatedAt,
+	}, nil
+}
+
+func (a *UserDataActivities) ExportOrders(ctx context.Context, requester Requester, userID uuid.UUID) (json.RawMessage, error) {
+	ctx, err := a.authorize(ctx, requester, model.PermissionUserDataExport)
+	if err != nil {
+		return nil, err
+	}
+	response, err := a.orderClient.ExportUserData(ctx, &orderinternal.ExportUserDataRequest{
+		UserID: userID.String(),
+	})
+	if err != nil {
+		return nil, toUserDataError(err)
+	}
+	return marshalExport(response)
+}
+
+func (a *UserDataActivities) ExportPayments(ctx context.Context, requester Requester, userID uuid.UUID) (json.RawMessage, error) {
+	ctx, err := a.authorize(ctx, requester, model.PermissionUserDataExport)
+	if err != nil {
+		return nil, err
+	}
+	response, err := a.paymentClient.ExportCustomerData(ctx, &paymentpublicapi.ExportCustomerDataRequest{
+		CustomerID: userID.String(),
+	})
+	if err != nil {
+		return nil, toUserDataError(err)
+	}
+	return marshalExport(response)
+}
+
+func (a *UserDataActivities) ExportNotifications(ctx context.Context, requester Requester, user ExportedUser) (json.RawMessage, error) {
+	ctx, err := a.authorize(ctx, requester, model.PermissionUserDataExport)
+	if err != nil {
+		return nil, err
+	}
+	response, err := a.notificationClient.ExportRecipientData(ctx, recipientDataRequest(user))
+	if err != nil {
+		return nil, toUserDataError(err)
+	}
+	return marshalExport(response)
+}
+
+// PseudonymID returns pseudonym of the user, it is the same when erasure is requested again
+func (a *UserDataActivities) PseudonymID(_ context.Context, userID uuid.UUID) (uuid.UUID, error) {
+	return a.pseudonymizer.PseudonymID(userID), nil
+}
+
+func (a *UserDataActivities) EraseOrders(ctx context.Context, requester Requester, userID, pseudonymID uuid.UUID) (ServiceErasure, error) {
+	ctx, err := a.authorize(ctx, requester, model.PermissionUserDataErase)
+	if err != nil {
+		return ServiceErasure{}, err
+	}
+	response, err := a.orderClient.EraseUserData(ctx, &orderinternal.EraseUserDataRequest{
+		UserID:      userID.String(),
+		PseudonymID: pseudonymID.String(),
+	})
+	if err != nil {
+		return ServiceErasure{}, toUserDataError(err)
+	}
+	return ServiceErasure{
+		Service:              "order",
+		DeletedRecords:       int(response.DeletedRecords),
+		PseudonymizedRecords: int(response.PseudonymizedRecords),
+	}, nil
+}
+
+func (a *UserDataActivities) ErasePayments(ctx context.Context, requester Requester, userID, pseudonymID uuid.UUID) (ServiceErasure, error) {
+	ctx, err := a.authorize(ctx, requester, model.PermissionUserDataErase)
+	if err != nil {
+		return ServiceErasure{}, err
+	}
+	response, err := a.paymentClient.EraseCustomerData(ctx, &paymentpublicapi.EraseCustomerDataRequest{
+		CustomerID:  userID.String(),
+		PseudonymID: pseudonymID.String(),
+	})
+	if err != nil {
+		return ServiceErasure{}, toUserDataError(err)
+	}
+	return ServiceErasure{
+		Service:              "payment",
+		PseudonymizedRecords: int(response.PseudonymizedRecords),
+	}, nil
+}
+
+func (a *UserDataActivities) EraseNotifications(ctx context.Context, requester Requester, user ExportedUser) (ServiceErasure, error) {
+	ctx, err := a.authorize(ctx, requester, model.PermissionUserDataErase)
+	if err != nil {
+		return ServiceErasure{}, err
+	}
+	response, err := a.notificationClient.EraseRecipientData(ctx, recipientDataRequest(user))
+	if err != nil {
+		return ServiceErasure{}, toUserDataError(err)
+	}
+	return ServiceErasure{
+		Service:        "notification",
+		DeletedRecords: int(response.DeletedRecords),
+	}, nil
+}
+
+func (a *UserDataActivities) EraseUser(ctx context.Context, userID uuid.UUID) (ServiceErasure, error) {
+	deleted, err := a.userDataService.EraseUser(ctx, userID)
+	if err != nil {
+		return ServiceErasure{}, toUserDataError(err)
+	}
+	return ServiceErasure{
+		Service:        "user",
+		DeletedRecords: deleted,
+	}, nil
+}
+
+// authorize issues access token of the requester with the only permission needed by the call
+func (a *UserDataActivities) authorize(ctx context.Context, requester Requester, permission model.Permission) (context.Context, error) {
+	return withAccessToken(ctx, a.tokenIssuer, requester.UserID, model.Access{
+		Role:        model.Role(requester.Role),
+		Permissions: []model.Permission{permission},
+	})
+}
+
+func recipientDataRequest(user ExportedUser) *notificationinternal.RecipientDataRequest {
+	return &notificationinternal.RecipientDataRequest{
+		Name:     user.Login,
+		Email:    user.Email,
+		Telegram: user.Telegram,
+	}
+}
+
+func marshalExport(response interface{}) (json.RawMessage, error) {
+	return json.Marshal(response)
+}
+
+// toUserDataError makes errors which are not fixed by retry non-retryable
+func toUserDataError(err error) error {
+	return toServiceCallError(err, userDataErrorType)
+}
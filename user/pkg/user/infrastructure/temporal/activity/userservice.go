@@ -2,11 +2,14 @@ package activity
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 
 	appmodel "user/pkg/user/application/model"
 	"user/pkg/user/application/service"
+	"user/pkg/user/domain/model"
 )
 
 func NewUserServiceActivities(userService service.UserService) *UserServiceActivities {
@@ -24,3 +27,12 @@ func (a *UserServiceActivities) FindUser(ctx context.Context, userID uuid.UUID)
 func (a *UserServiceActivities) SetUserStatus(ctx context.Context, userID uuid.UUID, status int) error {
 	return a.userService.SetUserStatus(ctx, userID, status)
 }
+
+func (a *UserServiceActivities) UnlockUser(ctx context.Context, userID uuid.UUID, lockedUntil time.Time) error {
+	err := a.userService.UnlockUser(ctx, userID, lockedUntil)
+	if errors.Is(err, model.ErrUserNotFound) {
+		// erased user has nothing to unlock
+		return nil
+	}
+	return err
+}
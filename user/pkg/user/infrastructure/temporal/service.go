@@ -2,17 +2,40 @@ package temporal
 
 import (
 	"context"
+	"errors"
 
+	"github.com/google/uuid"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/sdk/client"
 
 	"user/pkg/user/domain/model"
+	"user/pkg/user/infrastructure/temporal/activity"
 	"user/pkg/user/infrastructure/temporal/workflows"
 )
 
 const TaskQueue = "userservice_task_queue"
 
+var (
+	ErrUserDataRequestNotFound = errors.New("user data request not found")
+	ErrUserDataRequestFailed   = errors.New("user data request failed")
+	ErrUserOnboardingNotFound  = errors.New("user onboarding not found")
+)
+
 type WorkflowService interface {
 	RunUserUpdatedWorkflow(ctx context.Context, id string, event model.UserUpdated) error
+	// RunUserOnboardingWorkflow starts onboarding once per user, repeated events are ignored
+	RunUserOnboardingWorkflow(ctx context.Context, event model.UserCreated) error
+	FindUserOnboarding(ctx context.Context, userID uuid.UUID) (workflows.UserOnboardingStatus, error)
+	// RunUserUnlockWorkflow starts timer of the lockout, repeated events are ignored
+	RunUserUnlockWorkflow(ctx context.Context, event model.UserLocked) error
+	// StartContactVerification starts ContactVerificationWorkflow sending new code to the contact
+	StartContactVerification(ctx context.Context, verificationID, userID uuid.UUID, contactType model.ContactType) error
+	// ConfirmContact passes code to running ContactVerificationWorkflow and returns domain error of confirmation
+	ConfirmContact(ctx context.Context, verificationID uuid.UUID, code string) error
+	StartUserDataRequest(ctx context.Context, request workflows.UserDataRequest) error
+	// FindUserDataReport returns nil report while request is running
+	FindUserDataReport(ctx context.Context, requestID uuid.UUID) (*workflows.UserDataReport, error)
 }
 
 func NewWorkflowService(temporalClient client.Client) WorkflowService {
@@ -36,3 +59,120 @@ func (s *workflowService) RunUserUpdatedWorkflow(ctx context.Context, id string,
 	)
 	return err
 }
+
+func (s *workflowService) RunUserOnboardingWorkflow(ctx context.Context, event model.UserCreated) error {
+	_, err := s.temporalClient.ExecuteWorkflow(
+		ctx,
+		client.StartWorkflowOptions{
+			ID:                    workflows.UserOnboardingWorkflowID(event.UserID),
+			TaskQueue:             TaskQueue,
+			WorkflowIDReusePolicy: enums.WORKFLOW_ID_REUSE_POLICY_REJECT_DUPLICATE,
+		},
+		workflows.UserOnboardingWorkflow, event,
+	)
+	var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+	if errors.As(err, &alreadyStarted) {
+		return nil
+	}
+	return err
+}
+
+func (s *workflowService) FindUserOnboarding(ctx context.Context, userID uuid.UUID) (workflows.UserOnboardingStatus, error) {
+	value, err := s.temporalClient.QueryWorkflow(ctx, workflows.UserOnboardingWorkflowID(userID), "", workflows.UserOnboardingStatusQuery)
+	if err != nil {
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			// users created before onboarding workflow have no onboarding
+			return workflows.UserOnboardingStatus{}, ErrUserOnboardingNotFound
+		}
+		return workflows.UserOnboardingStatus{}, err
+	}
+	var status workflows.UserOnboardingStatus
+	err = value.Get(&status)
+	return status, err
+}
+
+func (s *workflowService) RunUserUnlockWorkflow(ctx context.Context, event model.UserLocked) error {
+	_, err := s.temporalClient.ExecuteWorkflow(
+		ctx,
+		client.StartWorkflowOptions{
+			ID:                    workflows.UserUnlockWorkflowID(event.UserID, event.LockedUntil),
+			TaskQueue:             TaskQueue,
+			WorkflowIDReusePolicy: enums.WORKFLOW_ID_REUSE_POLICY_REJECT_DUPLICATE,
+		},
+		workflows.UserUnlockWorkflow, event.UserID, event.LockedUntil,
+	)
+	var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+	if errors.As(err, &alreadyStarted) {
+		return nil
+	}
+	return err
+}
+
+func (s *workflowService) StartContactVerification(ctx context.Context, verificationID, userID uuid.UUID, contactType model.ContactType) error {
+	_, err := s.temporalClient.ExecuteWorkflow(
+		ctx,
+		client.StartWorkflowOptions{
+			ID:        workflows.ContactVerificationWorkflowID(verificationID),
+			TaskQueue: TaskQueue,
+		},
+		workflows.ContactVerificationWorkflow, verificationID, userID, string(contactType),
+	)
+	return err
+}
+
+func (s *workflowService) ConfirmContact(ctx context.Context, verificationID uuid.UUID, code string) error {
+	handle, err := s.temporalClient.UpdateWorkflow(ctx, client.UpdateWorkflowOptions{
+		WorkflowID:   workflows.ContactVerificationWorkflowID(verificationID),
+		UpdateName:   workflows.ConfirmContactUpdate,
+		Args:         []interface{}{code},
+		WaitForStage: client.WorkflowUpdateStageCompleted,
+	})
+	if err != nil {
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			// workflow is completed when code expired or attempts exceeded
+			return model.ErrVerificationExpired
+		}
+		return err
+	}
+	return activity.ParseVerificationError(handle.Get(ctx, nil))
+}
+
+func (s *workflowService) StartUserDataRequest(ctx context.Context, request workflows.UserDataRequest) error {
+	_, err := s.temporalClient.ExecuteWorkflow(
+		ctx,
+		client.StartWorkflowOptions{
+			ID:        workflows.UserDataRequestWorkflowID(request.RequestID),
+			TaskQueue: TaskQueue,
+		},
+		workflows.UserDataRequestWorkflow, request,
+	)
+	return err
+}
+
+func (s *workflowService) FindUserDataReport(ctx context.Context, requestID uuid.UUID) (*workflows.UserDataReport, error) {
+	workflowID := workflows.UserDataRequestWorkflowID(requestID)
+	execution, err := s.temporalClient.DescribeWorkflowExecution(ctx, workflowID, "")
+	if err != nil {
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			return nil, ErrUserDataRequestNotFound
+		}
+		return nil, err
+	}
+
+	switch execution.WorkflowExecutionInfo.Status {
+	case enums.WORKFLOW_EXECUTION_STATUS_RUNNING:
+		return nil, nil
+	case enums.WORKFLOW_EXECUTION_STATUS_COMPLETED:
+		var report workflows.UserDataReport
+		err = s.temporalClient.GetWorkflow(ctx, workflowID, "").Get(ctx, &report)
+		if err != nil {
+			return nil, err
+		}
+		return &report, nil
+	default:
+		return nil, ErrUserDataRequestFailed
+	}
+}
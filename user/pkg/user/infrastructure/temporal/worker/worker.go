@@ -17,9 +17,19 @@ func InterruptChannel() <-chan interface{} {
 func NewWorker(
 	temporalClient client.Client,
 	userService service.UserService,
+	verificationService service.ContactVerificationService,
+	userDataActivities *activity.UserDataActivities,
+	onboardingActivities *activity.OnboardingActivities,
 ) worker.Worker {
 	w := worker.New(temporalClient, temporal.TaskQueue, worker.Options{})
 	w.RegisterActivity(activity.NewUserServiceActivities(userService))
+	w.RegisterActivity(activity.NewContactVerificationActivities(verificationService))
+	w.RegisterActivity(userDataActivities)
+	w.RegisterActivity(onboardingActivities)
 	w.RegisterWorkflow(workflows.UserUpdatedWorkflow)
+	w.RegisterWorkflow(workflows.ContactVerificationWorkflow)
+	w.RegisterWorkflow(workflows.UserDataRequestWorkflow)
+	w.RegisterWorkflow(workflows.UserOnboardingWorkflow)
+	w.RegisterWorkflow(workflows.UserUnlockWorkflow)
 	return w
 }
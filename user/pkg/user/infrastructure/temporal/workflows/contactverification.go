@@ -0,0 +1,63 @@
+package workflows
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/workflow"
+
+	appmodel "user/pkg/user/application/model"
+	"user/pkg/user/domain/model"
+	"user/pkg/user/infrastructure/temporal/activity"
+)
+
+// ConfirmContactUpdate is update of ContactVerificationWorkflow with code received by user
+const ConfirmContactUpdate = "confirm_contact"
+
+var contactVerificationActivities *activity.ContactVerificationActivities
+
+func ContactVerificationWorkflowID(verificationID uuid.UUID) string {
+	return "contact_verification_" + verificationID.String()
+}
+
+// ContactVerificationWorkflow sends one-time code to the contact and waits for ConfirmContactUpdate until the code expires,
+// workflow completes on successful confirmation or when attempts are exceeded
+func ContactVerificationWorkflow(ctx workflow.Context, verificationID, userID uuid.UUID, contactType string) error {
+	activityOptions := workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+	}
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	var verification appmodel.ContactVerification
+	err := workflow.ExecuteActivity(ctx, contactVerificationActivities.StartContactVerification, verificationID, userID, contactType).Get(ctx, &verification)
+	if err != nil {
+		if activity.ParseVerificationError(err) != err {
+			// user is deleted or contact is removed before verification started
+			return nil
+		}
+		return err
+	}
+
+	completed := false
+	err = workflow.SetUpdateHandler(ctx, ConfirmContactUpdate, func(ctx workflow.Context, code string) error {
+		ctx = workflow.WithActivityOptions(ctx, activityOptions)
+		err := workflow.ExecuteActivity(ctx, contactVerificationActivities.ConfirmContact, verification, code).Get(ctx, nil)
+		// only wrong code leaves verification open for the next attempt
+		completed = completed || !errors.Is(activity.ParseVerificationError(err), model.ErrInvalidVerificationCode)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = workflow.AwaitWithTimeout(ctx, verification.ExpiresAt.Sub(workflow.Now(ctx)), func() bool {
+		return completed
+	})
+	if err != nil {
+		return err
+	}
+	return workflow.Await(ctx, func() bool {
+		return workflow.AllHandlersFinished(ctx)
+	})
+}
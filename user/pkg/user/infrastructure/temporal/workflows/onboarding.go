@@ -0,0 +1,160 @@
+package workflows
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	appmodel "user/pkg/user/application/model"
+	"user/pkg/user/domain/model"
+	"user/pkg/user/infrastructure/temporal/activity"
+)
+
+// UserOnboardingStatusQuery is query of UserOnboardingWorkflow returning UserOnboardingStatus
+const UserOnboardingStatusQuery = "onboarding_status"
+
+const (
+	OnboardingRunning     = "running"
+	OnboardingCompleted   = "completed"
+	OnboardingCompensated = "compensated"
+)
+
+const (
+	OnboardingStepPending     = "pending"
+	OnboardingStepCompleted   = "completed"
+	OnboardingStepSkipped     = "skipped"
+	OnboardingStepFailed      = "failed"
+	OnboardingStepCompensated = "compensated"
+)
+
+var onboardingActivities *activity.OnboardingActivities
+
+func UserOnboardingWorkflowID(userID uuid.UUID) string {
+	return "user_onboarding_" + userID.String()
+}
+
+type OnboardingStep struct {
+	Name   string
+	Status string
+	// Error is set for failed step and for step which failed to compensate
+	Error string
+}
+
+type UserOnboardingStatus struct {
+	UserID uuid.UUID
+	Status string
+	Steps  []OnboardingStep
+}
+
+// onboardingStep is activity of onboarding with optional compensation undoing it when one of next steps fails
+type onboardingStep struct {
+	name         string
+	activity     interface{}
+	compensation interface{}
+	arg          interface{}
+	skip         bool
+}
+
+// UserOnboardingWorkflow prepares new user in all services: initialises preferences, opens wallet
+// and sends welcome message to current contacts of the user. Steps are retried, when step still fails
+// completed steps are compensated in reverse order and workflow fails
+func UserOnboardingWorkflow(ctx workflow.Context, event model.UserCreated) error {
+	user := activity.OnboardedUser{
+		UserID: event.UserID,
+		Login:  event.Login,
+	}
+	steps := []onboardingStep{
+		{
+			name:         "preferences",
+			activity:     onboardingActivities.InitPreferences,
+			compensation: onboardingActivities.DeletePreferences,
+			arg:          user.UserID,
+		},
+		{
+			name:         "wallet",
+			activity:     onboardingActivities.OpenWallet,
+			compensation: onboardingActivities.CloseWallet,
+			arg:          user.UserID,
+		},
+		{
+			name:     "welcome_message",
+			activity: onboardingActivities.SendWelcomeMessage,
+		},
+	}
+	welcomeMessage := &steps[len(steps)-1]
+
+	status := UserOnboardingStatus{
+		UserID: user.UserID,
+		Status: OnboardingRunning,
+		Steps:  make([]OnboardingStep, 0, len(steps)),
+	}
+	for _, step := range steps {
+		status.Steps = append(status.Steps, OnboardingStep{Name: step.name, Status: OnboardingStepPending})
+	}
+	err := workflow.SetQueryHandler(ctx, UserOnboardingStatusQuery, func() (UserOnboardingStatus, error) {
+		return status, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    10,
+		},
+	})
+
+	// user created by StoreUser gets contacts after UserCreated, so they are loaded when workflow runs
+	var currentUser appmodel.User
+	err = workflow.ExecuteActivity(ctx, userServiceActivities.FindUser, user.UserID).Get(ctx, &currentUser)
+	if err != nil {
+		return err
+	}
+	user.Email = currentUser.Email
+	user.Telegram = currentUser.Telegram
+	welcomeMessage.arg = user
+	welcomeMessage.skip = user.Email == nil && user.Telegram == nil
+
+	for i, step := range steps {
+		if step.skip {
+			status.Steps[i].Status = OnboardingStepSkipped
+			continue
+		}
+		err = workflow.ExecuteActivity(ctx, step.activity, step.arg).Get(ctx, nil)
+		if err != nil {
+			status.Steps[i].Status = OnboardingStepFailed
+			status.Steps[i].Error = err.Error()
+			compensateOnboarding(ctx, steps[:i], status.Steps[:i])
+			status.Status = OnboardingCompensated
+			return err
+		}
+		status.Steps[i].Status = OnboardingStepCompleted
+	}
+
+	status.Status = OnboardingCompleted
+	return nil
+}
+
+// compensateOnboarding undoes completed steps in reverse order, failed compensation does not stop others
+func compensateOnboarding(ctx workflow.Context, steps []onboardingStep, statuses []OnboardingStep) {
+	// compensation runs even when workflow is cancelled
+	ctx, _ = workflow.NewDisconnectedContext(ctx)
+	for i := len(steps) - 1; i >= 0; i-- {
+		if steps[i].compensation == nil || statuses[i].Status != OnboardingStepCompleted {
+			continue
+		}
+		err := workflow.ExecuteActivity(ctx, steps[i].compensation, steps[i].arg).Get(ctx, nil)
+		if err != nil {
+			workflow.GetLogger(ctx).Error("failed to compensate onboarding step", "step", steps[i].name, "error", err)
+			statuses[i].Error = err.Error()
+			continue
+		}
+		statuses[i].Status = OnboardingStepCompensated
+	}
+}
@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	appmodel "user/pkg/user/application/model"
+	"user/pkg/user/domain/model"
+	"user/pkg/user/infrastructure/temporal/activity"
+	"user/pkg/user/infrastructure/temporal/workflows"
+)
+
+func TestUserOnboardingWorkflow_WelcomeMessage(t *testing.T) {
+	email := "john@example.com"
+	event := model.UserCreated{
+		UserID:    uuid.Must(uuid.NewV7()),
+		Status:    model.Blocked,
+		Login:     "john_doe",
+		CreatedAt: time.Now(),
+	}
+
+	t.Run("Sent to contacts set after user creation", func(t *testing.T) {
+		env, onboardingActivities, userServiceActivities := newOnboardingEnvironment()
+		env.OnActivity(userServiceActivities.FindUser, mock.Anything, event.UserID).
+			Return(appmodel.User{UserID: event.UserID, Login: event.Login, Email: &email}, nil)
+		env.OnActivity(onboardingActivities.SendWelcomeMessage, mock.Anything, activity.OnboardedUser{
+			UserID: event.UserID,
+			Login:  event.Login,
+			Email:  &email,
+		}).Return(nil).Once()
+
+		status := executeOnboarding(t, env, event)
+		require.Equal(t, workflows.OnboardingCompleted, status.Status)
+		require.Equal(t, workflows.OnboardingStepCompleted, status.Steps[2].Status)
+		env.AssertExpectations(t)
+	})
+
+	t.Run("Skipped without contacts", func(t *testing.T) {
+		env, onboardingActivities, userServiceActivities := newOnboardingEnvironment()
+		env.OnActivity(userServiceActivities.FindUser, mock.Anything, event.UserID).
+			Return(appmodel.User{UserID: event.UserID, Login: event.Login}, nil)
+		env.OnActivity(onboardingActivities.SendWelcomeMessage, mock.Anything, mock.Anything).Return(nil)
+
+		status := executeOnboarding(t, env, event)
+		require.Equal(t, workflows.OnboardingCompleted, status.Status)
+		require.Equal(t, workflows.OnboardingStepSkipped, status.Steps[2].Status)
+		env.AssertActivityNumberOfCalls(t, "SendWelcomeMessage", 0)
+	})
+}
+
+func newOnboardingEnvironment() (*testsuite.TestWorkflowEnvironment, *activity.OnboardingActivities, *activity.UserServiceActivities) {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	onboardingActivities := activity.NewOnboardingActivities(nil, nil, nil, nil)
+	userServiceActivities := activity.NewUserServiceActivities(nil)
+	env.RegisterActivity(onboardingActivities)
+	env.RegisterActivity(userServiceActivities)
+	env.OnActivity(onboardingActivities.InitPreferences, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(onboardingActivities.OpenWallet, mock.Anything, mock.Anything).Return(nil)
+	return env, onboardingActivities, userServiceActivities
+}
+
+func executeOnboarding(t *testing.T, env *testsuite.TestWorkflowEnvironment, event model.UserCreated) workflows.UserOnboardingStatus {
+	env.ExecuteWorkflow(workflows.UserOnboardingWorkflow, event)
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	value, err := env.QueryWorkflow(workflows.UserOnboardingStatusQuery)
+	require.NoError(t, err)
+	var status workflows.UserOnboardingStatus
+	require.NoError(t, value.Get(&status))
+	return status
+}
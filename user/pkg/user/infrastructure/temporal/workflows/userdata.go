@@ -0,0 +1,136 @@
+package workflows
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"user/pkg/user/infrastructure/temporal/activity"
+)
+
+const (
+	UserDataExport  = "export"
+	UserDataErasure = "erasure"
+)
+
+var userDataActivities *activity.UserDataActivities
+
+func UserDataRequestWorkflowID(requestID uuid.UUID) string {
+	return "user_data_request_" + requestID.String()
+}
+
+// UserDataRequest is data subject request, Type is UserDataExport or UserDataErasure
+type UserDataRequest struct {
+	RequestID uuid.UUID
+	UserID    uuid.UUID
+	Type      string
+	Requester activity.Requester
+}
+
+// UserDataReport is result of completed UserDataRequest
+type UserDataReport struct {
+	RequestID uuid.UUID
+	UserID    uuid.UUID
+	Type      string
+	// Export is data of the user by service, set for export
+	Export map[string]json.RawMessage
+	// PseudonymID replaces user in financial records kept after erasure, it is the same in all services
+	PseudonymID uuid.UUID
+	Erasure     []activity.ServiceErasure
+	CompletedAt time.Time
+}
+
+// UserDataRequestWorkflow exports or erases data of the user in all services. Retention policy of erasure:
+// personal data (profile, sessions, synced copies of profile, notifications) is deleted,
+// financial records (orders, balance, transactions, statements) are kept with user replaced by pseudonym.
+// User is erased last, so failed erasure can be requested again
+func UserDataRequestWorkflow(ctx workflow.Context, request UserDataRequest) (UserDataReport, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			MaximumAttempts: 10,
+		},
+	})
+
+	var user activity.ExportedUser
+	err := workflow.ExecuteActivity(ctx, userDataActivities.ExportUser, request.UserID).Get(ctx, &user)
+	if err != nil {
+		return UserDataReport{}, err
+	}
+
+	report := UserDataReport{
+		RequestID: request.RequestID,
+		UserID:    request.UserID,
+		Type:      request.Type,
+	}
+	if request.Type == UserDataExport {
+		report.Export, err = exportUserData(ctx, request, user)
+	} else {
+		report.PseudonymID, report.Erasure, err = eraseUserData(ctx, request, user)
+	}
+	if err != nil {
+		return UserDataReport{}, err
+	}
+	report.CompletedAt = workflow.Now(ctx)
+	return report, nil
+}
+
+func exportUserData(ctx workflow.Context, request UserDataRequest, user activity.ExportedUser) (map[string]json.RawMessage, error) {
+	userData, err := json.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+
+	// services are independent, so they are exported concurrently
+	futures := map[string]workflow.Future{
+		"order":        workflow.ExecuteActivity(ctx, userDataActivities.ExportOrders, request.Requester, request.UserID),
+		"payment":      workflow.ExecuteActivity(ctx, userDataActivities.ExportPayments, request.Requester, request.UserID),
+		"notification": workflow.ExecuteActivity(ctx, userDataActivities.ExportNotifications, request.Requester, user),
+	}
+	export := map[string]json.RawMessage{
+		"user": userData,
+	}
+	for _, service := range []string{"order", "payment", "notification"} {
+		var data json.RawMessage
+		err = futures[service].Get(ctx, &data)
+		if err != nil {
+			return nil, err
+		}
+		export[service] = data
+	}
+	return export, nil
+}
+
+func eraseUserData(ctx workflow.Context, request UserDataRequest, user activity.ExportedUser) (uuid.UUID, []activity.ServiceErasure, error) {
+	// pseudonym is derived from the user, so retried or repeated erasure replaces the user with the same pseudonym
+	var pseudonymID uuid.UUID
+	err := workflow.ExecuteActivity(ctx, userDataActivities.PseudonymID, request.UserID).Get(ctx, &pseudonymID)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	steps := []workflow.Future{
+		workflow.ExecuteActivity(ctx, userDataActivities.EraseNotifications, request.Requester, user),
+		workflow.ExecuteActivity(ctx, userDataActivities.ErasePayments, request.Requester, request.UserID, pseudonymID),
+		workflow.ExecuteActivity(ctx, userDataActivities.EraseOrders, request.Requester, request.UserID, pseudonymID),
+	}
+	erasure := make([]activity.ServiceErasure, 0, len(steps)+1)
+	for _, step := range steps {
+		var serviceErasure activity.ServiceErasure
+		err = step.Get(ctx, &serviceErasure)
+		if err != nil {
+			return uuid.Nil, nil, err
+		}
+		erasure = append(erasure, serviceErasure)
+	}
+
+	var userErasure activity.ServiceErasure
+	err = workflow.ExecuteActivity(ctx, userDataActivities.EraseUser, request.UserID).Get(ctx, &userErasure)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	return pseudonymID, append(erasure, userErasure), nil
+}
@@ -0,0 +1,26 @@
+package workflows
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/workflow"
+)
+
+func UserUnlockWorkflowID(userID uuid.UUID, lockedUntil time.Time) string {
+	return "user_unlock_" + userID.String() + "_" + strconv.FormatInt(lockedUntil.Unix(), 10)
+}
+
+// UserUnlockWorkflow activates locked user when lockout expires, the timer is kept by Temporal so it survives restarts
+func UserUnlockWorkflow(ctx workflow.Context, userID uuid.UUID, lockedUntil time.Time) error {
+	err := workflow.Sleep(ctx, max(lockedUntil.Sub(workflow.Now(ctx)), 0))
+	if err != nil {
+		return err
+	}
+
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+	})
+	return workflow.ExecuteActivity(ctx, userServiceActivities.UnlockUser, userID, lockedUntil).Get(ctx, nil)
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"time"
 
+	"github.com/google/uuid"
+	"go.temporal.io/api/enums/v1"
 	"go.temporal.io/sdk/workflow"
 
 	appmodel "user/pkg/user/application/model"
@@ -34,8 +36,9 @@ func UserUpdatedWorkflow(ctx workflow.Context, event model.UserUpdated) error {
 		return err
 	}
 
+	// changed contact is trusted only after verification
 	status := model.Blocked
-	if user.Email != nil || user.Telegram != nil {
+	if (user.Email != nil && user.EmailVerified) || (user.Telegram != nil && user.TelegramVerified) {
 		status = model.Active
 	}
 
@@ -47,5 +50,36 @@ func UserUpdatedWorkflow(ctx workflow.Context, event model.UserUpdated) error {
 		return err
 	}
 
+	if event.UpdatedFields == nil {
+		return nil
+	}
+	if event.UpdatedFields.Email != nil {
+		err = startContactVerification(ctx, event.UserID, model.ContactEmail)
+		if err != nil {
+			return err
+		}
+	}
+	if event.UpdatedFields.Telegram != nil {
+		return startContactVerification(ctx, event.UserID, model.ContactTelegram)
+	}
 	return nil
 }
+
+// startContactVerification starts ContactVerificationWorkflow without waiting for user to confirm the contact
+func startContactVerification(ctx workflow.Context, userID uuid.UUID, contactType model.ContactType) error {
+	var verificationID uuid.UUID
+	err := workflow.SideEffect(ctx, func(workflow.Context) interface{} {
+		return uuid.Must(uuid.NewV7())
+	}).Get(&verificationID)
+	if err != nil {
+		return err
+	}
+
+	ctx = workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+		WorkflowID:        ContactVerificationWorkflowID(verificationID),
+		ParentClosePolicy: enums.PARENT_CLOSE_POLICY_ABANDON,
+	})
+	return workflow.ExecuteChildWorkflow(ctx, ContactVerificationWorkflow, verificationID, userID, string(contactType)).
+		GetChildWorkflowExecution().
+		Get(ctx, nil)
+}
@@ -0,0 +1,119 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"user/api/server/userpublicapi"
+	appmodel "user/pkg/user/application/model"
+	"user/pkg/user/domain/model"
+)
+
+func (u userInternalAPI) Register(ctx context.Context, request *userpublicapi.RegisterRequest) (*userpublicapi.RegisterResponse, error) {
+	if request.Login == "" {
+		return nil, status.Error(codes.InvalidArgument, "login is required")
+	}
+	userID, err := u.authService.Register(ctx, appmodel.Registration{
+		Login:    request.Login,
+		Email:    request.Email,
+		Telegram: request.Telegram,
+		Password: request.Password,
+	})
+	if err != nil {
+		return nil, authError(err)
+	}
+
+	response := &userpublicapi.RegisterResponse{
+		UserID: userID.String(),
+	}
+	// code is requested again by SendVerificationCode when verification is not started
+	for _, contactType := range []model.ContactType{model.ContactEmail, model.ContactTelegram} {
+		if (contactType == model.ContactEmail && request.Email == nil) ||
+			(contactType == model.ContactTelegram && request.Telegram == nil) {
+			continue
+		}
+		verificationID, err := u.startContactVerification(ctx, userID, contactType)
+		if err != nil {
+			return nil, err
+		}
+		response.Verifications = append(response.Verifications, &userpublicapi.ContactVerification{
+			ContactType:    string(contactType),
+			VerificationID: verificationID.String(),
+		})
+	}
+	return response, nil
+}
+
+func (u userInternalAPI) Login(ctx context.Context, request *userpublicapi.LoginRequest) (*userpublicapi.SessionResponse, error) {
+	session, err := u.authService.Login(ctx, request.Login, request.Password, clientIP(ctx))
+	if err != nil {
+		return nil, authError(err)
+	}
+	return toAPISession(session), nil
+}
+
+func (u userInternalAPI) RefreshToken(ctx context.Context, request *userpublicapi.RefreshTokenRequest) (*userpublicapi.SessionResponse, error) {
+	session, err := u.authService.RefreshToken(ctx, request.RefreshToken)
+	if err != nil {
+		return nil, authError(err)
+	}
+	return toAPISession(session), nil
+}
+
+func (u userInternalAPI) Logout(ctx context.Context, request *userpublicapi.LogoutRequest) (*userpublicapi.LogoutResponse, error) {
+	err := u.authService.Logout(ctx, request.RefreshToken)
+	if err != nil {
+		return nil, authError(err)
+	}
+	return &userpublicapi.LogoutResponse{}, nil
+}
+
+func authError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidCredentials),
+		errors.Is(err, model.ErrInvalidRefreshToken),
+		errors.Is(err, model.ErrRefreshTokenReused):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, model.ErrUserNotActive),
+		errors.Is(err, model.ErrUserLocked):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, model.ErrTooManyLoginAttempts):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, model.ErrInvalidPassword),
+		errors.Is(err, model.ErrContactRequired):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, model.ErrUserLoginAlreadyUsed),
+		errors.Is(err, model.ErrUserEmailAlreadyUsed),
+		errors.Is(err, model.ErrUserTelegramAlreadyUsed):
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	return err
+}
+
+// clientIP returns address of the peer, forwarded headers are not trusted since they are set by the client
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+func toAPISession(session appmodel.Session) *userpublicapi.SessionResponse {
+	return &userpublicapi.SessionResponse{
+		UserID:                session.UserID.String(),
+		AccessToken:           session.AccessToken,
+		AccessTokenExpiresAt:  session.AccessTokenExpiresAt.Unix(),
+		RefreshToken:          session.RefreshToken,
+		RefreshTokenExpiresAt: session.RefreshTokenExpiresAt.Unix(),
+	}
+}
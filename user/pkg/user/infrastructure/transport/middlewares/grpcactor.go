@@ -0,0 +1,19 @@
+package middlewares
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+)
+
+// NewGRPCActorMiddleware passes user authenticated by access token to withActor,
+// so changes made by the call are recorded as made by the user. Must be chained after auth middleware
+func NewGRPCActorMiddleware(withActor func(ctx context.Context, actorID uuid.UUID) context.Context) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		if userID, ok := UserIDFromContext(ctx); ok {
+			ctx = withActor(ctx, userID)
+		}
+		return handler(ctx, req)
+	}
+}
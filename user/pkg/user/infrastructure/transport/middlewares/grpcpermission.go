@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"context"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewGRPCPermissionMiddleware requires permission of called method in access token,
+// methods missing in methodPermissions are denied unless they are public
+func NewGRPCPermissionMiddleware(methodPermissions map[string]string, publicMethods ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		if slices.Contains(publicMethods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+		err = authorize(ctx, methodPermissions, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func authorize(ctx context.Context, methodPermissions map[string]string, method string) error {
+	permission, found := methodPermissions[method]
+	if !found {
+		return status.Errorf(codes.PermissionDenied, "method %s is not allowed", method)
+	}
+	if !HasPermission(ctx, permission) {
+		return status.Errorf(codes.PermissionDenied, "permission %q is required", permission)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package transport
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"user/api/server/userpublicapi"
+	"user/pkg/user/domain/model"
+	"user/pkg/user/infrastructure/temporal"
+	"user/pkg/user/infrastructure/temporal/workflows"
+	"user/pkg/user/infrastructure/transport/middlewares"
+)
+
+var onboardingStatuses = map[string]userpublicapi.OnboardingStatus{
+	workflows.OnboardingRunning:     userpublicapi.OnboardingStatus_OnboardingRunning,
+	workflows.OnboardingCompleted:   userpublicapi.OnboardingStatus_OnboardingCompleted,
+	workflows.OnboardingCompensated: userpublicapi.OnboardingStatus_OnboardingCompensated,
+}
+
+func (u userInternalAPI) GetUserOnboarding(ctx context.Context, request *userpublicapi.GetUserOnboardingRequest) (*userpublicapi.GetUserOnboardingResponse, error) {
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.UserID)
+	}
+	if !isOwner(ctx, userID) && !middlewares.HasPermission(ctx, string(model.PermissionUserReadAny)) {
+		return nil, status.Errorf(codes.NotFound, "onboarding of user %q not found", request.UserID)
+	}
+
+	onboarding, err := u.workflowService.FindUserOnboarding(ctx, userID)
+	if err != nil {
+		if errors.Is(err, temporal.ErrUserOnboardingNotFound) {
+			return nil, status.Errorf(codes.NotFound, "onboarding of user %q not found", request.UserID)
+		}
+		return nil, err
+	}
+
+	steps := make([]*userpublicapi.OnboardingStep, 0, len(onboarding.Steps))
+	for _, step := range onboarding.Steps {
+		steps = append(steps, &userpublicapi.OnboardingStep{
+			Name:   step.Name,
+			Status: step.Status,
+			Error:  step.Error,
+		})
+	}
+	return &userpublicapi.GetUserOnboardingResponse{
+		UserID: onboarding.UserID.String(),
+		Status: onboardingStatuses[onboarding.Status],
+		Steps:  steps,
+	}, nil
+}
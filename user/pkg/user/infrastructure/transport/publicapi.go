@@ -2,6 +2,8 @@ package transport
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
@@ -11,21 +13,36 @@ import (
 	appmodel "user/pkg/user/application/model"
 	"user/pkg/user/application/query"
 	"user/pkg/user/application/service"
+	"user/pkg/user/domain/model"
+	"user/pkg/user/infrastructure/temporal"
+	"user/pkg/user/infrastructure/transport/middlewares"
 )
 
 func NewUserInternalAPI(
 	userQueryService query.UserQueryService,
 	userService service.UserService,
+	authService service.AuthService,
+	verificationService service.ContactVerificationService,
+	preferencesService service.PreferencesService,
+	workflowService temporal.WorkflowService,
 ) userpublicapi.UserPublicAPIServer {
 	return &userInternalAPI{
-		userQueryService: userQueryService,
-		userService:      userService,
+		userQueryService:    userQueryService,
+		userService:         userService,
+		authService:         authService,
+		verificationService: verificationService,
+		preferencesService:  preferencesService,
+		workflowService:     workflowService,
 	}
 }
 
 type userInternalAPI struct {
-	userQueryService query.UserQueryService
-	userService      service.UserService
+	userQueryService    query.UserQueryService
+	userService         service.UserService
+	authService         service.AuthService
+	verificationService service.ContactVerificationService
+	preferencesService  service.PreferencesService
+	workflowService     temporal.WorkflowService
 
 	userpublicapi.UnimplementedUserPublicAPIServer
 }
@@ -41,6 +58,10 @@ func (u userInternalAPI) StoreUser(ctx context.Context, request *userpublicapi.S
 			return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.UserID)
 		}
 	}
+	// new users are created only with access to any user
+	if !isOwner(ctx, userID) && !middlewares.HasPermission(ctx, string(model.PermissionUserWriteAny)) {
+		return nil, status.Error(codes.PermissionDenied, "user can change only own profile")
+	}
 
 	userID, err = u.userService.StoreUser(ctx, appmodel.User{
 		UserID:   userID,
@@ -62,6 +83,9 @@ func (u userInternalAPI) FindUser(ctx context.Context, request *userpublicapi.Fi
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.UserID)
 	}
+	if !isOwner(ctx, userID) && !middlewares.HasPermission(ctx, string(model.PermissionUserReadAny)) {
+		return nil, status.Errorf(codes.NotFound, "user %q not found", request.UserID)
+	}
 	user, err := u.userQueryService.FindUser(ctx, userID)
 	if err != nil {
 		return nil, err
@@ -69,11 +93,142 @@ func (u userInternalAPI) FindUser(ctx context.Context, request *userpublicapi.Fi
 	if user == nil {
 		return nil, status.Errorf(codes.NotFound, "user %q not found", request.UserID)
 	}
+	return toAPIUser(*user), nil
+}
+
+func (u userInternalAPI) FindUserBy(ctx context.Context, request *userpublicapi.FindUserByRequest) (*userpublicapi.FindUserResponse, error) {
+	if request.Login == nil && request.Email == nil && request.Telegram == nil {
+		return nil, status.Error(codes.InvalidArgument, "login, email or telegram is required")
+	}
+	user, err := u.userQueryService.FindUserBy(ctx, model.FindSpec{
+		Login:    request.Login,
+		Email:    request.Email,
+		Telegram: request.Telegram,
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		return nil, err
+	}
+	if !isOwner(ctx, user.UserID) && !middlewares.HasPermission(ctx, string(model.PermissionUserReadAny)) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return toAPIUser(*user), nil
+}
+
+func (u userInternalAPI) ListUsers(ctx context.Context, request *userpublicapi.ListUsersRequest) (*userpublicapi.ListUsersResponse, error) {
+	spec := appmodel.ListUsersSpec{
+		Cursor: request.Cursor,
+		Limit:  int(request.Limit),
+	}
+	if request.Status != nil {
+		userStatus := int(*request.Status)
+		spec.Status = &userStatus
+	}
+	if request.CreatedFrom != nil {
+		createdFrom := time.Unix(*request.CreatedFrom, 0)
+		spec.CreatedFrom = &createdFrom
+	}
+	if request.CreatedTo != nil {
+		createdTo := time.Unix(*request.CreatedTo, 0)
+		spec.CreatedTo = &createdTo
+	}
+
+	users, err := u.userQueryService.ListUsers(ctx, spec)
+	if err != nil {
+		if errors.Is(err, query.ErrInvalidCursor) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid cursor %q", request.Cursor)
+		}
+		return nil, err
+	}
+
+	result := make([]*userpublicapi.FindUserResponse, 0, len(users.Users))
+	for _, user := range users.Users {
+		result = append(result, toAPIUser(user))
+	}
+	return &userpublicapi.ListUsersResponse{
+		Users:      result,
+		NextCursor: users.NextCursor,
+	}, nil
+}
+
+func (u userInternalAPI) DeleteUser(ctx context.Context, request *userpublicapi.DeleteUserRequest) (*userpublicapi.DeleteUserResponse, error) {
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.UserID)
+	}
+	// hard delete erases user data, so it is not available even for own profile
+	if (request.Hard || !isOwner(ctx, userID)) && !middlewares.HasPermission(ctx, string(model.PermissionUserWriteAny)) {
+		return nil, status.Error(codes.PermissionDenied, "user can delete only own profile")
+	}
+	err = u.userService.DeleteUser(ctx, userID, request.Hard)
+	if err != nil {
+		if errors.Is(err, model.ErrUserNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user %q not found", request.UserID)
+		}
+		return nil, err
+	}
+	return &userpublicapi.DeleteUserResponse{}, nil
+}
+
+func (u userInternalAPI) SetUserRole(ctx context.Context, request *userpublicapi.SetUserRoleRequest) (*userpublicapi.SetUserRoleResponse, error) {
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.UserID)
+	}
+	err = u.userService.SetUserRole(ctx, userID, request.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidRole):
+			return nil, status.Errorf(codes.InvalidArgument, "invalid role %q", request.Role)
+		case errors.Is(err, model.ErrUserNotFound):
+			return nil, status.Errorf(codes.NotFound, "user %q not found", request.UserID)
+		}
+		return nil, err
+	}
+	return &userpublicapi.SetUserRoleResponse{}, nil
+}
+
+func (u userInternalAPI) SetUserPreferences(ctx context.Context, request *userpublicapi.SetUserPreferencesRequest) (*userpublicapi.SetUserPreferencesResponse, error) {
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.UserID)
+	}
+	if !isOwner(ctx, userID) && !middlewares.HasPermission(ctx, string(model.PermissionUserWriteAny)) {
+		return nil, status.Error(codes.PermissionDenied, "user can change only own preferences")
+	}
+	err = u.preferencesService.UpdatePreferences(ctx, userID, request.Locale, request.TimeZone)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidLocale):
+			return nil, status.Errorf(codes.InvalidArgument, "invalid locale %q", request.GetLocale())
+		case errors.Is(err, model.ErrInvalidTimeZone):
+			return nil, status.Errorf(codes.InvalidArgument, "invalid time zone %q", request.GetTimeZone())
+		case errors.Is(err, model.ErrUserNotFound):
+			return nil, status.Errorf(codes.NotFound, "user %q not found", request.UserID)
+		}
+		return nil, err
+	}
+	return &userpublicapi.SetUserPreferencesResponse{}, nil
+}
+
+func toAPIUser(user appmodel.User) *userpublicapi.FindUserResponse {
 	return &userpublicapi.FindUserResponse{
-		UserID:   userID.String(),
+		UserID:   user.UserID.String(),
 		Status:   userpublicapi.UserStatus(user.Status), // nolint:gosec
 		Login:    user.Login,
 		Email:    user.Email,
 		Telegram: user.Telegram,
-	}, nil
+		Role:     user.Role,
+
+		EmailVerified:    user.EmailVerified,
+		TelegramVerified: user.TelegramVerified,
+		CreatedAt:        user.CreatedAt.Unix(),
+	}
+}
+
+func isOwner(ctx context.Context, userID uuid.UUID) bool {
+	callerID, ok := middlewares.UserIDFromContext(ctx)
+	return ok && callerID == userID
 }
@@ -0,0 +1,108 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"user/api/server/userpublicapi"
+	"user/pkg/user/infrastructure/temporal"
+	"user/pkg/user/infrastructure/temporal/activity"
+	"user/pkg/user/infrastructure/temporal/workflows"
+	"user/pkg/user/infrastructure/transport/middlewares"
+)
+
+func (u userInternalAPI) RequestUserDataExport(ctx context.Context, request *userpublicapi.UserDataRequest) (*userpublicapi.UserDataRequestResponse, error) {
+	return u.startUserDataRequest(ctx, request.UserID, workflows.UserDataExport)
+}
+
+func (u userInternalAPI) RequestUserDataErasure(ctx context.Context, request *userpublicapi.UserDataRequest) (*userpublicapi.UserDataRequestResponse, error) {
+	return u.startUserDataRequest(ctx, request.UserID, workflows.UserDataErasure)
+}
+
+func (u userInternalAPI) GetUserDataRequest(ctx context.Context, request *userpublicapi.GetUserDataRequestRequest) (*userpublicapi.GetUserDataRequestResponse, error) {
+	requestID, err := uuid.Parse(request.RequestID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.RequestID)
+	}
+	report, err := u.workflowService.FindUserDataReport(ctx, requestID)
+	switch {
+	case errors.Is(err, temporal.ErrUserDataRequestNotFound):
+		return nil, status.Errorf(codes.NotFound, "user data request %q not found", request.RequestID)
+	case errors.Is(err, temporal.ErrUserDataRequestFailed):
+		return &userpublicapi.GetUserDataRequestResponse{
+			RequestID: request.RequestID,
+			Status:    userpublicapi.UserDataRequestStatus_Failed,
+		}, nil
+	case err != nil:
+		return nil, err
+	case report == nil:
+		return &userpublicapi.GetUserDataRequestResponse{
+			RequestID: request.RequestID,
+			Status:    userpublicapi.UserDataRequestStatus_Running,
+		}, nil
+	}
+
+	response := &userpublicapi.GetUserDataRequestResponse{
+		RequestID:   request.RequestID,
+		UserID:      report.UserID.String(),
+		Type:        report.Type,
+		Status:      userpublicapi.UserDataRequestStatus_Completed,
+		CompletedAt: report.CompletedAt.Unix(),
+	}
+	if report.Type == workflows.UserDataExport {
+		response.Export, err = json.Marshal(report.Export)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		response.PseudonymID = report.PseudonymID.String()
+		for _, erasure := range report.Erasure {
+			response.Erasure = append(response.Erasure, &userpublicapi.ServiceErasure{
+				Service:              erasure.Service,
+				DeletedRecords:       int32(erasure.DeletedRecords),
+				PseudonymizedRecords: int32(erasure.PseudonymizedRecords),
+			})
+		}
+	}
+	return response, nil
+}
+
+func (u userInternalAPI) startUserDataRequest(ctx context.Context, rawUserID, requestType string) (*userpublicapi.UserDataRequestResponse, error) {
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", rawUserID)
+	}
+	user, err := u.userQueryService.FindUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, status.Errorf(codes.NotFound, "user %q not found", rawUserID)
+	}
+
+	requestID, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+	principal, _ := middlewares.PrincipalFromContext(ctx)
+	err = u.workflowService.StartUserDataRequest(ctx, workflows.UserDataRequest{
+		RequestID: requestID,
+		UserID:    userID,
+		Type:      requestType,
+		Requester: activity.Requester{
+			UserID: principal.UserID,
+			Role:   principal.Role,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &userpublicapi.UserDataRequestResponse{
+		RequestID: requestID.String(),
+	}, nil
+}
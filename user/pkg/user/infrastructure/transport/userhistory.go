@@ -0,0 +1,53 @@
+package transport
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"user/api/server/userpublicapi"
+	appmodel "user/pkg/user/application/model"
+	"user/pkg/user/application/query"
+)
+
+func (u userInternalAPI) GetUserHistory(ctx context.Context, request *userpublicapi.GetUserHistoryRequest) (*userpublicapi.GetUserHistoryResponse, error) {
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.UserID)
+	}
+
+	changes, err := u.userQueryService.ListUserChanges(ctx, appmodel.ListUserChangesSpec{
+		UserID: userID,
+		Cursor: request.Cursor,
+		Limit:  int(request.Limit),
+	})
+	if err != nil {
+		if errors.Is(err, query.ErrInvalidCursor) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid cursor %q", request.Cursor)
+		}
+		return nil, err
+	}
+
+	result := make([]*userpublicapi.UserChange, 0, len(changes.Changes))
+	for _, change := range changes.Changes {
+		var actorID string
+		if change.ActorID != nil {
+			actorID = change.ActorID.String()
+		}
+		result = append(result, &userpublicapi.UserChange{
+			ChangeID:  change.ChangeID.String(),
+			Field:     change.Field,
+			OldValue:  change.OldValue,
+			NewValue:  change.NewValue,
+			ActorID:   actorID,
+			ChangedAt: change.ChangedAt.Unix(),
+		})
+	}
+	return &userpublicapi.GetUserHistoryResponse{
+		Changes:    result,
+		NextCursor: changes.NextCursor,
+	}, nil
+}
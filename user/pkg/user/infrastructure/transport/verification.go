@@ -0,0 +1,71 @@
+package transport
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"user/api/server/userpublicapi"
+	"user/pkg/user/domain/model"
+)
+
+func (u userInternalAPI) ConfirmContact(ctx context.Context, request *userpublicapi.ConfirmContactRequest) (*userpublicapi.ConfirmContactResponse, error) {
+	verificationID, err := uuid.Parse(request.VerificationID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid uuid %q", request.VerificationID)
+	}
+
+	err = u.workflowService.ConfirmContact(ctx, verificationID, request.Code)
+	if err != nil {
+		return nil, verificationError(err)
+	}
+	return &userpublicapi.ConfirmContactResponse{}, nil
+}
+
+func (u userInternalAPI) SendVerificationCode(ctx context.Context, request *userpublicapi.SendVerificationCodeRequest) (*userpublicapi.SendVerificationCodeResponse, error) {
+	if !model.ContactType(request.ContactType).Valid() {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid contact type %q", request.ContactType)
+	}
+
+	userID, err := u.verificationService.RequestVerification(ctx, request.Login, request.ContactType)
+	if err != nil {
+		return nil, verificationError(err)
+	}
+	verificationID, err := u.startContactVerification(ctx, userID, model.ContactType(request.ContactType))
+	if err != nil {
+		return nil, err
+	}
+	return &userpublicapi.SendVerificationCodeResponse{
+		VerificationID: verificationID.String(),
+	}, nil
+}
+
+// startContactVerification starts ContactVerificationWorkflow and returns ID of the verification to confirm the contact with
+func (u userInternalAPI) startContactVerification(ctx context.Context, userID uuid.UUID, contactType model.ContactType) (uuid.UUID, error) {
+	verificationID, err := uuid.NewV7()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return verificationID, u.workflowService.StartContactVerification(ctx, verificationID, userID, contactType)
+}
+
+func verificationError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrVerificationNotFound),
+		errors.Is(err, model.ErrUserNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, model.ErrInvalidVerificationCode):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, model.ErrVerificationExpired),
+		errors.Is(err, model.ErrVerificationAttemptsExceeded),
+		errors.Is(err, model.ErrContactNotSet),
+		errors.Is(err, model.ErrContactAlreadyVerified):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, model.ErrTooManyVerificationRequests):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return err
+}